@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -11,6 +12,13 @@ type Config struct {
 	Server          ServerConfig
 	Database        DatabaseConfig
 	DataDirectories DirectoryConfig
+	Ingest          IngestConfig
+	Devices         DeviceConfig
+	Occupancy       OccupancyConfig
+	Alerts          AlertConfig
+	Notifications   NotificationConfig
+	Evidence        EvidenceConfig
+	CameraHealth    CameraHealthConfig
 }
 
 // ServerConfig holds server-related configuration
@@ -37,6 +45,109 @@ type DirectoryConfig struct {
 	FaceRecognitionDir string
 	StreamDir          string
 	VehicleCountDir    string
+	FrameDir           string
+	ClipDir            string
+	FloorPlanDir       string
+}
+
+// IngestConfig holds configuration for HTTP and folder ingestion
+type IngestConfig struct {
+	APIKeys             []string
+	MaxWorkers          int
+	MaxWorkersPerFolder int
+	MaxAttempts         int
+	RetryInitialBackoff time.Duration
+	RetryMaxBackoff     time.Duration
+	Trigger             string
+	PollInterval        time.Duration
+	ReconcileInterval   time.Duration
+	// SourcesFile declares the watched folders, see sources.example.yaml
+	SourcesFile string
+}
+
+// DeviceConfig holds configuration for mapping device identifiers to cameras
+type DeviceConfig struct {
+	// UnknownDevicePolicy is reject, quarantine, or auto-register
+	UnknownDevicePolicy string
+}
+
+// OccupancyConfig holds configuration for live occupancy
+type OccupancyConfig struct {
+	// ResetTime is the HH:MM time of day at which occupancy restarts from zero
+	ResetTime string
+	Timezone  string
+}
+
+// AlertConfig holds configuration for alert processing
+type AlertConfig struct {
+	// CorrelationWindow folds detections of the same camera, alert type and object into an
+	// open alert last seen within this window, zero disables correlation
+	CorrelationWindow time.Duration
+	// RuleInterval is how often threshold alert rules are evaluated, zero disables evaluation
+	RuleInterval time.Duration
+	// EscalationInterval is how often unacknowledged alerts are checked against their escalation
+	// policy, zero disables escalation
+	EscalationInterval time.Duration
+	// Language of the messages of detected alerts, en or id. API clients can ask for another
+	// language through Accept-Language.
+	Language string
+}
+
+// EvidenceConfig holds configuration for the frame buffer and the evidence clips of alerts
+type EvidenceConfig struct {
+	// FrameInterval is how often the stream image of each camera is copied to its frame buffer,
+	// zero disables the buffer and evidence clips
+	FrameInterval time.Duration
+	// FrameRetention is how long buffered frames are kept
+	FrameRetention time.Duration
+	// ClipBefore and ClipAfter are how much of the buffer around the detection time goes into
+	// the clip of an alert
+	ClipBefore time.Duration
+	ClipAfter  time.Duration
+	// ClipFormat is gif or mjpeg
+	ClipFormat string
+}
+
+// CameraHealthConfig holds configuration for the camera health monitor
+type CameraHealthConfig struct {
+	// Interval is how often cameras are checked, zero disables the monitor
+	Interval time.Duration
+	// FrameStaleAfter is how old the stream image of a camera may get before it is unhealthy
+	FrameStaleAfter time.Duration
+	// DataStaleAfter is how long a camera without stream images may go without people counts,
+	// vehicle counts or alerts before it is unhealthy
+	DataStaleAfter time.Duration
+	// DecodeFailures is how many stream images in a row may fail to decode
+	DecodeFailures int
+	// FailChecks unhealthy checks in a row flag an active camera as issue, RecoverChecks healthy
+	// checks in a row bring it back
+	FailChecks    int
+	RecoverChecks int
+	// SLATarget is the availability percentage agreed on, cameras below it are marked in the
+	// availability report
+	SLATarget float64
+}
+
+// NotificationConfig holds configuration for outbound alert notifications
+type NotificationConfig struct {
+	// Failed deliveries are retried with exponential backoff until MaxAttempts
+	MaxAttempts         int
+	RetryInitialBackoff time.Duration
+	RetryMaxBackoff     time.Duration
+	RetryInterval       time.Duration
+	Timeout             time.Duration
+	// PublicURL is the address of this API as seen by recipients, used for image links
+	PublicURL string
+	SMTP      SMTPConfig
+}
+
+// SMTPConfig holds the SMTP server used by email notification channels
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
 }
 
 // Load loads configuration from environment variables
@@ -61,6 +172,65 @@ func Load() *Config {
 			FaceRecognitionDir: "face_log",
 			VehicleCountDir:    "car-count",
 			StreamDir:          "stream",
+			FrameDir:           "frames",
+			ClipDir:            "clips",
+			FloorPlanDir:       "floorplans",
+		},
+		Ingest: IngestConfig{
+			APIKeys:             getSliceEnv("INGEST_API_KEYS", nil),
+			MaxWorkers:          getIntEnv("INGEST_MAX_WORKERS", 8),
+			MaxWorkersPerFolder: getIntEnv("INGEST_MAX_WORKERS_PER_FOLDER", 2),
+			MaxAttempts:         getIntEnv("INGEST_MAX_ATTEMPTS", 5),
+			RetryInitialBackoff: getDurationEnv("INGEST_RETRY_INITIAL_BACKOFF", 5*time.Second),
+			RetryMaxBackoff:     getDurationEnv("INGEST_RETRY_MAX_BACKOFF", 5*time.Minute),
+			Trigger:             getEnv("INGEST_TRIGGER", "hybrid"),
+			PollInterval:        getDurationEnv("INGEST_POLL_INTERVAL", 5*time.Second),
+			ReconcileInterval:   getDurationEnv("INGEST_RECONCILE_INTERVAL", time.Minute),
+			SourcesFile:         getEnv("INGEST_SOURCES_FILE", "config/sources.yaml"),
+		},
+		Devices: DeviceConfig{
+			UnknownDevicePolicy: getEnv("CAMERA_UNKNOWN_DEVICE_POLICY", "auto-register"),
+		},
+		Occupancy: OccupancyConfig{
+			ResetTime: getEnv("OCCUPANCY_RESET_TIME", "00:00"),
+			Timezone:  getEnv("OCCUPANCY_TIMEZONE", "Asia/Jakarta"),
+		},
+		Alerts: AlertConfig{
+			CorrelationWindow:  getDurationEnv("ALERT_CORRELATION_WINDOW", 5*time.Minute),
+			RuleInterval:       getDurationEnv("ALERT_RULE_INTERVAL", time.Minute),
+			EscalationInterval: getDurationEnv("ALERT_ESCALATION_INTERVAL", 15*time.Second),
+			Language:           getEnv("ALERT_LANGUAGE", "en"),
+		},
+		Notifications: NotificationConfig{
+			MaxAttempts:         getIntEnv("NOTIFY_MAX_ATTEMPTS", 5),
+			RetryInitialBackoff: getDurationEnv("NOTIFY_RETRY_INITIAL_BACKOFF", 30*time.Second),
+			RetryMaxBackoff:     getDurationEnv("NOTIFY_RETRY_MAX_BACKOFF", 30*time.Minute),
+			RetryInterval:       getDurationEnv("NOTIFY_RETRY_INTERVAL", 15*time.Second),
+			Timeout:             getDurationEnv("NOTIFY_TIMEOUT", 10*time.Second),
+			PublicURL:           getEnv("NOTIFY_PUBLIC_URL", ""),
+			SMTP: SMTPConfig{
+				Host:     getEnv("SMTP_HOST", ""),
+				Port:     getIntEnv("SMTP_PORT", 587),
+				Username: getEnv("SMTP_USERNAME", ""),
+				Password: getEnv("SMTP_PASSWORD", ""),
+				From:     getEnv("SMTP_FROM", ""),
+			},
+		},
+		Evidence: EvidenceConfig{
+			FrameInterval:  getDurationEnv("EVIDENCE_FRAME_INTERVAL", time.Second),
+			FrameRetention: getDurationEnv("EVIDENCE_FRAME_RETENTION", 5*time.Minute),
+			ClipBefore:     getDurationEnv("EVIDENCE_CLIP_BEFORE", 30*time.Second),
+			ClipAfter:      getDurationEnv("EVIDENCE_CLIP_AFTER", 30*time.Second),
+			ClipFormat:     getEnv("EVIDENCE_CLIP_FORMAT", "gif"),
+		},
+		CameraHealth: CameraHealthConfig{
+			Interval:        getDurationEnv("CAMERA_HEALTH_INTERVAL", 30*time.Second),
+			FrameStaleAfter: getDurationEnv("CAMERA_HEALTH_FRAME_STALE_AFTER", 2*time.Minute),
+			DataStaleAfter:  getDurationEnv("CAMERA_HEALTH_DATA_STALE_AFTER", time.Hour),
+			DecodeFailures:  getIntEnv("CAMERA_HEALTH_DECODE_FAILURES", 10),
+			FailChecks:      getIntEnv("CAMERA_HEALTH_FAIL_CHECKS", 3),
+			RecoverChecks:   getIntEnv("CAMERA_HEALTH_RECOVER_CHECKS", 2),
+			SLATarget:       getFloatEnv("CAMERA_SLA_TARGET", 99),
 		},
 	}
 }
@@ -89,6 +259,21 @@ func getIntEnv(key string, defaultValue int) int {
 	return intValue
 }
 
+// getFloatEnv gets an environment variable as float or returns a default value
+func getFloatEnv(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	floatValue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return defaultValue
+	}
+
+	return floatValue
+}
+
 // getDurationEnv gets an environment variable as duration or returns a default value
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	value := os.Getenv(key)
@@ -103,3 +288,21 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 
 	return duration
 }
+
+// getSliceEnv gets a comma-separated environment variable as a slice or returns a default value
+func getSliceEnv(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			values = append(values, item)
+		}
+	}
+
+	return values
+}
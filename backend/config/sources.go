@@ -0,0 +1,169 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Source handler kinds
+const (
+	SourceHandlerAlert           = "alert"
+	SourceHandlerPeopleCount     = "people-count"
+	SourceHandlerVehicleCount    = "vehicle-count"
+	SourceHandlerFaceRecognition = "face-recognition"
+)
+
+// Duration is a time.Duration read from strings such as "30s" or "72h"
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\": %w", err)
+	}
+	return d.parse(value)
+}
+
+// UnmarshalYAML parses a duration string
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	return d.parse(node.Value)
+}
+
+// MarshalJSON formats the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) parse(value string) error {
+	if value == "" {
+		*d = 0
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", value, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// SourceConfig declares a folder to ingest and how to process it
+type SourceConfig struct {
+	// Folder is relative to the data root unless absolute
+	Folder  string `yaml:"folder" json:"folder"`
+	Pattern string `yaml:"pattern" json:"pattern"`
+	// Handler is one of alert, people-count, vehicle-count, or face-recognition
+	Handler string `yaml:"handler" json:"handler"`
+	// AlertType is the alert type name for alert sources, also used in /api/images/alerts/{type}
+	AlertType string `yaml:"alert_type" json:"alert_type,omitempty"`
+	// ImageFolder is the subfolder holding alert images, detected from "images" or "image" when empty
+	ImageFolder        string   `yaml:"image_folder" json:"image_folder,omitempty"`
+	PollInterval       Duration `yaml:"poll_interval" json:"poll_interval,omitempty"`
+	ProcessedRetention Duration `yaml:"processed_retention" json:"processed_retention,omitempty"`
+}
+
+// SourcesConfig is the content of the ingestion sources file
+type SourcesConfig struct {
+	Sources []SourceConfig `yaml:"sources" json:"sources"`
+}
+
+// LoadSources reads ingestion sources from a YAML or JSON file. When the file does not exist
+// the default sources are returned, matching the built-in folder layout.
+func LoadSources(path string, dirs DirectoryConfig) ([]SourceConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultSources(dirs), nil
+		}
+		return nil, fmt.Errorf("failed to read sources file %s: %w", path, err)
+	}
+
+	var sourcesConfig SourcesConfig
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &sourcesConfig)
+	default:
+		err = yaml.Unmarshal(data, &sourcesConfig)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sources file %s: %w", path, err)
+	}
+
+	if err := ValidateSources(sourcesConfig.Sources); err != nil {
+		return nil, fmt.Errorf("invalid sources file %s: %w", path, err)
+	}
+
+	return sourcesConfig.Sources, nil
+}
+
+// ValidateSources checks sources for missing fields and duplicates, and fills in defaults
+func ValidateSources(sources []SourceConfig) error {
+	if len(sources) == 0 {
+		return errors.New("at least one source is required")
+	}
+
+	folders := make(map[string]bool)
+	alertTypes := make(map[string]bool)
+
+	for i := range sources {
+		source := &sources[i]
+
+		if source.Folder == "" {
+			return fmt.Errorf("source %d: folder is required", i+1)
+		}
+		if folders[source.Folder] {
+			return fmt.Errorf("source %d: folder %s is declared more than once", i+1, source.Folder)
+		}
+		folders[source.Folder] = true
+
+		if source.Pattern == "" {
+			source.Pattern = "*.json"
+		}
+		if _, err := filepath.Match(source.Pattern, ""); err != nil {
+			return fmt.Errorf("source %d: invalid pattern %q", i+1, source.Pattern)
+		}
+
+		switch source.Handler {
+		case SourceHandlerAlert:
+			if source.AlertType == "" {
+				return fmt.Errorf("source %d: alert_type is required for alert sources", i+1)
+			}
+			if alertTypes[source.AlertType] {
+				return fmt.Errorf("source %d: alert_type %s is declared more than once", i+1, source.AlertType)
+			}
+			alertTypes[source.AlertType] = true
+		case SourceHandlerPeopleCount, SourceHandlerVehicleCount, SourceHandlerFaceRecognition:
+		default:
+			return fmt.Errorf("source %d: invalid handler %q, must be alert, people-count, vehicle-count, or face-recognition", i+1, source.Handler)
+		}
+
+		if source.PollInterval < 0 || source.ProcessedRetention < 0 {
+			return fmt.Errorf("source %d: durations must not be negative", i+1)
+		}
+	}
+
+	return nil
+}
+
+// DefaultSources returns the sources used when no sources file exists
+func DefaultSources(dirs DirectoryConfig) []SourceConfig {
+	return []SourceConfig{
+		{Folder: dirs.AlertDir, Pattern: "*.json", Handler: SourceHandlerAlert, AlertType: "restricted"},
+		{Folder: "fall_log", Pattern: "*.json", Handler: SourceHandlerAlert, AlertType: "fall-detection"},
+		{Folder: "loitering", Pattern: "*.json", Handler: SourceHandlerAlert, AlertType: "loitering"},
+		{Folder: "safety_log", Pattern: "*.json", Handler: SourceHandlerAlert, AlertType: "personal-protective-equipment"},
+		{Folder: "fire_smoke", Pattern: "*.json", Handler: SourceHandlerAlert, AlertType: "fire-smoke-detection"},
+		{Folder: dirs.PeopleCountDir, Pattern: "*.json", Handler: SourceHandlerPeopleCount},
+		{Folder: dirs.VehicleCountDir, Pattern: "*.json", Handler: SourceHandlerVehicleCount},
+		{Folder: dirs.FaceRecognitionDir, Pattern: "*.json", Handler: SourceHandlerFaceRecognition},
+	}
+}
@@ -4,6 +4,7 @@ go 1.24.0
 
 require (
 	github.com/gofiber/websocket/v2 v2.2.1
+	gopkg.in/yaml.v3 v3.0.1
 	gorm.io/gorm v1.26.1
 )
 
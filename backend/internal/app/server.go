@@ -7,15 +7,19 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sync"
 	"syscall"
 	"time"
 
 	"people-counting/config"
+	"people-counting/internal/domain/entity"
+	domainservice "people-counting/internal/domain/service"
 	"people-counting/internal/handler"
+	"people-counting/internal/middleware"
 	"people-counting/internal/repository/postgres"
 	"people-counting/internal/service"
 	"people-counting/pkg/database"
-	"people-counting/pkg/polling"
+	"people-counting/pkg/watcher"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -24,23 +28,36 @@ import (
 	"gorm.io/gorm"
 )
 
-// alertTypeFolders defines the mapping between alert types and their folder paths
-var alertTypeFolders = map[string]string{
-	"restricted":                    "alert",
-	"fall-detection":                "fall_log",
-	"loitering":                     "loitering",
-	"personal-protective-equipment": "safety_log",
-	"fire-smoke-detection":          "fire_smoke",
-}
-
 // Server represents the API server
 type Server struct {
 	config           *config.Config
 	db               *gorm.DB
 	app              *fiber.App
-	syncManager      *polling.PollingManager
+	syncManager      *watcher.Manager
 	streamService    *service.CameraStreamService // Menambahkan field untuk menyimpan reference ke streamService
 	webSocketService *service.WebSocketService    // WebSocket service untuk mengelola koneksi WebSocket
+
+	// Declarative ingestion sources, reloaded on SIGHUP
+	dataRootDir    string
+	sources        []config.SourceConfig
+	alertImageDirs map[string]string
+	sourcesMu      sync.RWMutex
+	ingestHandler  *handler.IngestHandler
+	handlers       *sourceHandlers
+
+	occupancyReset entity.OccupancyReset
+
+	// Evaluate threshold alert rules, escalate alerts and retry notifications in the background
+	alertRuleService    domainservice.AlertRuleService
+	notificationService domainservice.NotificationService
+	escalationService   domainservice.EscalationService
+
+	// Buffer camera frames and freeze them into the evidence clips of alerts in the background
+	frameBuffer     *service.CameraFrameBuffer
+	evidenceService domainservice.EvidenceService
+
+	// Flag cameras whose stream or data went stale in the background
+	cameraHealthService domainservice.CameraHealthService
 }
 
 // NewServer creates a new server instance
@@ -76,6 +93,22 @@ func (s *Server) Initialize() error {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	// Records from unmapped devices are rejected, quarantined or auto-registered
+	if !entity.IsUnknownDevicePolicy(s.config.Devices.UnknownDevicePolicy) {
+		return fmt.Errorf("invalid CAMERA_UNKNOWN_DEVICE_POLICY %q, must be reject, quarantine, or auto-register", s.config.Devices.UnknownDevicePolicy)
+	}
+
+	s.occupancyReset, err = entity.ParseOccupancyReset(s.config.Occupancy.ResetTime, s.config.Occupancy.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid OCCUPANCY_RESET_TIME or OCCUPANCY_TIMEZONE: %w", err)
+	}
+
+	// Resolve the data root once, all ingestion sources are relative to it
+	s.dataRootDir, err = s.resolveDataRoot()
+	if err != nil {
+		return err
+	}
+
 	// Initialize Fiber app
 	s.app = fiber.New(fiber.Config{
 		AppName:      "People Counting API",
@@ -84,6 +117,14 @@ func (s *Server) Initialize() error {
 		ErrorHandler: customErrorHandler,
 	})
 
+	// Create the folder watcher before routes so the ingestion APIs can reach its folders,
+	// with bounded concurrency so a backlog cannot exhaust the DB pool
+	trigger, err := watcher.NewTrigger(s.config.Ingest.Trigger, s.config.Ingest.PollInterval, s.config.Ingest.ReconcileInterval)
+	if err != nil {
+		return fmt.Errorf("invalid INGEST_TRIGGER: %w", err)
+	}
+	s.syncManager = watcher.NewManagerWithLimits(trigger, s.config.Ingest.MaxWorkers, s.config.Ingest.MaxWorkersPerFolder)
+
 	// Register middleware
 	s.registerMiddleware()
 
@@ -100,102 +141,27 @@ func (s *Server) Initialize() error {
 
 // initializeSyncManager sets up the file sync manager
 func (s *Server) initializeSyncManager() error {
-	// Create new sync manager
-	s.syncManager = polling.NewPollingManager(5 * time.Second)
-
-	// Ensure data directories exist and are absolute paths
-	dataRootDir := s.config.DataDirectories.Root
-
-	// If data root is not absolute, make it relative to the current directory
-	if !filepath.IsAbs(dataRootDir) {
-		currentDir, err := os.Getwd()
-		if err != nil {
-			return fmt.Errorf("failed to get current directory: %v", err)
-		}
-		dataRootDir = filepath.Join(currentDir, dataRootDir)
-	}
-
-	// Create directories for various data types
-	countingDataDir := filepath.Join(dataRootDir, s.config.DataDirectories.PeopleCountDir)
-	faceRecognitionDataDir := filepath.Join(dataRootDir, s.config.DataDirectories.FaceRecognitionDir)
-	vehicleCountDataDir := filepath.Join(dataRootDir, s.config.DataDirectories.VehicleCountDir)
-
-	// Create alert directories for each alert type
-	for _, folderPath := range alertTypeFolders {
-		alertDir := filepath.Join(dataRootDir, folderPath)
-
-		// Create alert type directory
-		if err := os.MkdirAll(alertDir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %v", alertDir, err)
-		}
-
-		// Create both "image" and "images" directories to support both naming conventions
-		imageFolders := []string{"image", "images"}
-		for _, imageFolder := range imageFolders {
-			imagesDir := filepath.Join(alertDir, imageFolder)
-			if err := os.MkdirAll(imagesDir, 0755); err != nil {
-				return fmt.Errorf("failed to create images directory %s: %v", imagesDir, err)
-			}
-		}
-	}
-
-	// Create directories for other data types
-	for _, dir := range []string{countingDataDir, faceRecognitionDataDir, vehicleCountDataDir} {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %v", dir, err)
-		}
-	}
-
-	// Create images directory for face recognition
-	faceImagesDir := filepath.Join(faceRecognitionDataDir, "images")
-	if err := os.MkdirAll(faceImagesDir, 0755); err != nil {
-		return fmt.Errorf("failed to create face images directory %s: %v", faceImagesDir, err)
-	}
-
-	// Get repositories - use the ones already created in registerRoutes to avoid duplication
-	cameraRepository := postgres.NewCameraRepository(s.db)
-	alertTypeRepository := postgres.NewAlertTypeRepository(s.db)
-	alertRepository := postgres.NewAlertRepository(s.db)
-	peopleCountRepository := postgres.NewPeopleCountRepository(s.db)
-	faceRecognitionRepository := postgres.NewFaceRecognitionRepository(s.db)
-	vehicleRepository := postgres.NewVehicleCountRepository(s.db)
-
-	// Create services using the same repositories
-	cameraService := service.NewCameraService(cameraRepository, dataRootDir)
-	alertTypeService := service.NewAlertTypeService(alertTypeRepository)
-	alertService := service.NewAlertService(alertRepository, alertTypeRepository, cameraRepository)
-	peopleCountService := service.NewPeopleCountService(peopleCountRepository)
-	faceRecognitionService := service.NewFaceRecognitionService(faceRecognitionRepository, cameraRepository)
-	vehicleService := service.NewVehicleCountService(vehicleRepository)
-
-	// Create handlers for file sync
-	syncCountingHandler := handler.NewPeopleCountHandler(peopleCountService, cameraService)
-	syncRecognitionHandler := handler.NewFaceRecognitionHandler(faceRecognitionService, cameraService)
-	syncVehicleCountingHandler := handler.NewVehicleCountHandler(vehicleService, cameraService)
-
-	// Add alert folders to watch - one handler per alert type folder
-	for alertType, folderPath := range alertTypeFolders {
-		alertDir := filepath.Join(dataRootDir, folderPath)
-		syncAlertHandler := handler.NewAlertHandlerWithType(alertTypeService, alertService, cameraService, s.webSocketService, alertDir, alertType)
-
-		if err := s.syncManager.AddFolder(alertDir, "*.json", syncAlertHandler); err != nil {
-			return fmt.Errorf("failed to add alert folder %s for type %s: %v", alertDir, alertType, err)
-		}
-		log.Printf("Added alert folder watcher: %s -> %s", alertType, alertDir)
-	}
-
-	// Add other folders to watch
-	if err := s.syncManager.AddFolder(countingDataDir, "*.json", syncCountingHandler); err != nil {
-		return fmt.Errorf("failed to add people counting folder: %v", err)
-	}
-
-	if err := s.syncManager.AddFolder(faceRecognitionDataDir, "*.json", syncRecognitionHandler); err != nil {
-		return fmt.Errorf("failed to add face recognition folder: %v", err)
+	// Watch the folders declared in the sources file
+	sources, err := config.LoadSources(s.config.Ingest.SourcesFile, s.config.DataDirectories)
+	if err != nil {
+		return err
 	}
-
-	if err := s.syncManager.AddFolder(vehicleCountDataDir, "*.json", syncVehicleCountingHandler); err != nil {
-		return fmt.Errorf("failed to add vehicle count folder: %v", err)
+	if err := s.applySources(sources); err != nil {
+		return err
 	}
+	log.Printf("Loaded %d ingestion sources", len(sources))
+
+	// Record every ingested file in the durable ledger so restarts are exactly-once
+	ledgerService := service.NewIngestionLedgerService(postgres.NewIngestionLedgerRepository(s.db))
+	s.syncManager.SetLedger(ledgerService)
+
+	// Retry transient failures with exponential backoff before giving up on a file
+	s.syncManager.SetRetryPolicy(watcher.RetryPolicy{
+		MaxAttempts:    s.config.Ingest.MaxAttempts,
+		InitialBackoff: s.config.Ingest.RetryInitialBackoff,
+		MaxBackoff:     s.config.Ingest.RetryMaxBackoff,
+		Multiplier:     2,
+	})
 
 	// Start the sync manager
 	if err := s.syncManager.Start(); err != nil {
@@ -213,7 +179,7 @@ func (s *Server) registerMiddleware() {
 	s.app.Use(cors.New(cors.Config{
 		AllowOrigins:     "*",
 		AllowMethods:     "GET,POST,PUT,DELETE",
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-API-Key",
 		AllowCredentials: false,
 	}))
 }
@@ -243,6 +209,14 @@ func (s *Server) registerRoutes() {
 	alertRepository := postgres.NewAlertRepository(s.db)
 	faceRecognitionRepository := postgres.NewFaceRecognitionRepository(s.db)
 	vehicleRepository := postgres.NewVehicleCountRepository(s.db)
+	ingestionLedgerRepository := postgres.NewIngestionLedgerRepository(s.db)
+	cameraDeviceRepository := postgres.NewCameraDeviceRepository(s.db)
+	alertRuleRepository := postgres.NewAlertRuleRepository(s.db)
+	notificationRepository := postgres.NewNotificationRepository(s.db)
+	escalationRepository := postgres.NewEscalationRepository(s.db)
+	maintenanceWindowRepository := postgres.NewMaintenanceWindowRepository(s.db)
+	zoneRepository := postgres.NewZoneRepository(s.db)
+	cameraConfigRepository := postgres.NewCameraConfigRepository(s.db)
 
 	// Ensure stream directory exists
 	streamDir := filepath.Join(s.config.DataDirectories.Root, s.config.DataDirectories.StreamDir)
@@ -251,12 +225,51 @@ func (s *Server) registerRoutes() {
 	}
 
 	// Set up services
-	cameraService := service.NewCameraService(cameraRepository, streamDir)
-	peopleCountService := service.NewPeopleCountService(peopleCountRepository)
+	cameraService := service.NewCameraService(cameraRepository, streamDir, s.webSocketService)
+	cameraDeviceService := service.NewCameraDeviceService(cameraDeviceRepository, cameraRepository, s.config.Devices.UnknownDevicePolicy)
+	cameraConfigService := service.NewCameraConfigService(cameraConfigRepository, cameraRepository, cameraDeviceService)
+	peopleCountService := service.NewPeopleCountService(peopleCountRepository, zoneRepository, cameraConfigService, s.occupancyReset)
+	zoneService := service.NewZoneService(zoneRepository, cameraRepository,
+		filepath.Join(s.config.DataDirectories.Root, s.config.DataDirectories.FloorPlanDir))
+	cameraMapService := service.NewCameraMapService(cameraRepository, zoneRepository, alertRepository, peopleCountService)
+	analyticsService := service.NewAnalyticsService(peopleCountRepository, cameraRepository, s.occupancyReset)
 	alertTypeService := service.NewAlertTypeService(alertTypeRepository)
-	alertService := service.NewAlertService(alertRepository, alertTypeRepository, cameraRepository)
+	cameraAvailabilityService := service.NewCameraAvailabilityService(cameraRepository, s.occupancyReset.Location, s.config.CameraHealth.SLATarget)
+	alertStatsService := service.NewAlertStatsService(alertRepository, alertTypeRepository, cameraRepository, zoneRepository, s.occupancyReset.Location)
+	s.notificationService = service.NewNotificationService(notificationRepository, alertRepository, alertTypeRepository, cameraRepository, service.NotificationOptions{
+		MaxAttempts:         s.config.Notifications.MaxAttempts,
+		RetryInitialBackoff: s.config.Notifications.RetryInitialBackoff,
+		RetryMaxBackoff:     s.config.Notifications.RetryMaxBackoff,
+		Timeout:             s.config.Notifications.Timeout,
+		PublicURL:           s.config.Notifications.PublicURL,
+		SMTP: service.SMTPOptions{
+			Host:     s.config.Notifications.SMTP.Host,
+			Port:     s.config.Notifications.SMTP.Port,
+			Username: s.config.Notifications.SMTP.Username,
+			Password: s.config.Notifications.SMTP.Password,
+			From:     s.config.Notifications.SMTP.From,
+		},
+	})
+	s.frameBuffer = service.NewCameraFrameBuffer(streamDir, filepath.Join(s.config.DataDirectories.Root, s.config.DataDirectories.FrameDir),
+		s.config.Evidence.FrameInterval, s.config.Evidence.FrameRetention)
+	s.evidenceService = service.NewEvidenceService(alertRepository, s.frameBuffer,
+		filepath.Join(s.config.DataDirectories.Root, s.config.DataDirectories.ClipDir), service.EvidenceOptions{
+			Before: s.config.Evidence.ClipBefore,
+			After:  s.config.Evidence.ClipAfter,
+			Format: s.config.Evidence.ClipFormat,
+		})
+	maintenanceService := service.NewMaintenanceService(maintenanceWindowRepository, cameraRepository, alertTypeRepository, s.occupancyReset.Location)
+	alertService := service.NewAlertService(alertRepository, alertTypeRepository, cameraRepository, zoneRepository, s.config.Alerts.CorrelationWindow,
+		s.config.Alerts.Language, maintenanceService, s.notificationService, s.evidenceService, cameraConfigService)
 	faceRecognitionService := service.NewFaceRecognitionService(faceRecognitionRepository, cameraRepository)
-	vehicleService := service.NewVehicleCountService(vehicleRepository)
+	vehicleService := service.NewVehicleCountService(vehicleRepository, cameraConfigService)
+	ingestionLedgerService := service.NewIngestionLedgerService(ingestionLedgerRepository)
+	deadLetterService := service.NewDeadLetterService(s.syncManager)
+	watcherService := service.NewWatcherService(s.syncManager)
+	s.alertRuleService = service.NewAlertRuleService(alertRuleRepository, alertTypeRepository, cameraRepository, zoneRepository,
+		peopleCountRepository, vehicleRepository, alertService, s.webSocketService, s.occupancyReset)
+	s.escalationService = service.NewEscalationService(escalationRepository, alertRepository, alertTypeRepository,
+		alertService, s.notificationService, s.webSocketService)
 
 	// Initialize camera stream service
 	s.streamService = service.NewCameraStreamService(cameraService, streamDir)
@@ -270,6 +283,15 @@ func (s *Server) registerRoutes() {
 		log.Println("Camera streaming service started successfully")
 	}
 
+	s.cameraHealthService = service.NewCameraHealthService(cameraRepository, s.streamService, s.webSocketService, streamDir,
+		service.CameraHealthOptions{
+			FrameStaleAfter: s.config.CameraHealth.FrameStaleAfter,
+			DataStaleAfter:  s.config.CameraHealth.DataStaleAfter,
+			DecodeFailures:  s.config.CameraHealth.DecodeFailures,
+			FailChecks:      s.config.CameraHealth.FailChecks,
+			RecoverChecks:   s.config.CameraHealth.RecoverChecks,
+		})
+
 	// Auto-start streams for active cameras
 	go func() {
 		time.Sleep(2 * time.Second) // Short delay to allow server startup
@@ -277,56 +299,15 @@ func (s *Server) registerRoutes() {
 	}()
 
 	// Set up static file serving
-	dataRootDir := s.config.DataDirectories.Root
-
-	// If data root is not absolute, make it relative to the current directory
-	if !filepath.IsAbs(dataRootDir) {
-		currentDir, err := os.Getwd()
-		if err != nil {
-			log.Printf("WARNING: Failed to get current directory for static files: %v", err)
-			dataRootDir = s.config.DataDirectories.Root
-		} else {
-			dataRootDir = filepath.Join(currentDir, dataRootDir)
-		}
-	}
+	dataRootDir := s.dataRootDir
 
 	faceImagesPath := filepath.Join(dataRootDir, s.config.DataDirectories.FaceRecognitionDir, "images")
 	log.Printf("Setting up static file serving for faces: route='/images/faces' -> path='%s'", faceImagesPath)
 	api.Static("/images/faces", faceImagesPath)
 
-	// Create static routes for each alert type
-	for alertType, folderPath := range alertTypeFolders {
-		basePath := filepath.Join(dataRootDir, folderPath)
-		routePath := fmt.Sprintf("/images/alerts/%s", alertType)
-
-		// Try both "image" and "images" folders, prioritize the one with files
-		imageFolders := []string{"images", "image"} // Try "images" first
-		var alertImagesPath string
-
-		for _, folder := range imageFolders {
-			testPath := filepath.Join(basePath, folder)
-			if _, err := os.Stat(testPath); err == nil {
-				// Check if folder has any files
-				entries, err := os.ReadDir(testPath)
-				if err == nil && len(entries) > 0 {
-					// Folder exists and has files, use this one
-					alertImagesPath = testPath
-					break
-				} else if alertImagesPath == "" {
-					// Folder exists but might be empty, keep as fallback
-					alertImagesPath = testPath
-				}
-			}
-		}
-
-		// If neither exists, default to "images" folder (plural)
-		if alertImagesPath == "" {
-			alertImagesPath = filepath.Join(basePath, "images")
-		}
-
-		api.Static(routePath, alertImagesPath)
-		log.Printf("Serving alert images for %s from: %s", alertType, alertImagesPath)
-	}
+	// Serve alert images per alert type from the image folder of its source,
+	// resolved on every request so reloaded sources take effect immediately
+	api.Get("/images/alerts/:type/*", s.serveAlertImage)
 
 	// Keep backward compatibility - serve general alerts folder
 	alertBasePath := filepath.Join(dataRootDir, s.config.DataDirectories.AlertDir)
@@ -362,19 +343,79 @@ func (s *Server) registerRoutes() {
 
 	// Set up handlers
 	cameraHandler := handler.NewCameraHandler(cameraService)
-	peopleCountHandler := handler.NewPeopleCountHandler(peopleCountService, cameraService)
+	cameraHealthHandler := handler.NewCameraHealthHandler(s.cameraHealthService)
+	cameraAvailabilityHandler := handler.NewCameraAvailabilityHandler(cameraAvailabilityService)
+	cameraMapHandler := handler.NewCameraMapHandler(cameraMapService)
+	cameraDeviceHandler := handler.NewCameraDeviceHandler(cameraDeviceService)
+	peopleCountHandler := handler.NewPeopleCountHandler(peopleCountService, cameraDeviceService, s.webSocketService)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
 	alertTypeHandler := handler.NewAlertTypeHandler(alertTypeService)
-	alertHandler := handler.NewAlertHandler(alertTypeService, alertService, cameraService, s.webSocketService)
-	faceRecognitionHandler := handler.NewFaceRecognitionHandler(faceRecognitionService, cameraService)
-	vehicleCountingHandler := handler.NewVehicleCountHandler(vehicleService, cameraService)
+	alertHandler := handler.NewAlertHandler(alertTypeService, alertService, cameraDeviceService, s.webSocketService)
+	alertStatsHandler := handler.NewAlertStatsHandler(alertStatsService)
+	faceRecognitionHandler := handler.NewFaceRecognitionHandler(faceRecognitionService, cameraDeviceService)
+	vehicleCountingHandler := handler.NewVehicleCountHandler(vehicleService, cameraDeviceService)
+	ingestionLedgerHandler := handler.NewIngestionLedgerHandler(ingestionLedgerService)
+	deadLetterHandler := handler.NewDeadLetterHandler(deadLetterService)
+	watcherHandler := handler.NewWatcherHandler(watcherService)
+	alertRuleHandler := handler.NewAlertRuleHandler(s.alertRuleService)
+	notificationHandler := handler.NewNotificationHandler(s.notificationService)
+	escalationHandler := handler.NewEscalationHandler(s.escalationService)
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenanceService)
+	zoneHandler := handler.NewZoneHandler(zoneService)
+	evidenceHandler := handler.NewEvidenceHandler(s.evidenceService)
+
+	// Set up HTTP ingestion, reusing the same conversion code as the folder watchers
+	if len(s.config.Ingest.APIKeys) == 0 {
+		log.Println("WARNING: INGEST_API_KEYS is not set, all requests to /api/ingest and /api/edge will be rejected")
+	}
+	ingestAuth := middleware.APIKeyAuth(s.config.Ingest.APIKeys)
+	ingestHandler := handler.NewIngestHandler(ingestAuth)
+	ingestHandler.RegisterKind("alert", alertHandler)
+	ingestHandler.RegisterKind("people-count", peopleCountHandler)
+	ingestHandler.RegisterKind("vehicle-count", vehicleCountingHandler)
+	ingestHandler.RegisterKind("face-recognition", faceRecognitionHandler)
+
+	// Edge devices pull the configuration of their camera with the ingestion API keys
+	cameraConfigHandler := handler.NewCameraConfigHandler(cameraConfigService, ingestAuth)
+
+	// Alert types declared in the sources file are registered as kinds when the sources are
+	// applied. Watched folders use the same services, so detections from both are correlated together.
+	s.ingestHandler = ingestHandler
+	s.handlers = &sourceHandlers{
+		alertTypeService:       alertTypeService,
+		alertService:           alertService,
+		cameraDeviceService:    cameraDeviceService,
+		peopleCountService:     peopleCountService,
+		vehicleService:         vehicleService,
+		faceRecognitionService: faceRecognitionService,
+		webSocketService:       s.webSocketService,
+	}
 
-	// Register handler routes
+	// Register handler routes, camera health, availability and map before the camera routes
+	// matching /cameras/:id
+	cameraHealthHandler.RegisterRoutes(api)
+	cameraAvailabilityHandler.RegisterRoutes(api)
+	cameraMapHandler.RegisterRoutes(api)
 	cameraHandler.RegisterRoutes(api)
+	cameraDeviceHandler.RegisterRoutes(api)
+	cameraConfigHandler.RegisterRoutes(api)
+	zoneHandler.RegisterRoutes(api)
 	peopleCountHandler.RegisterRoutes(api)
+	analyticsHandler.RegisterRoutes(api)
 	alertTypeHandler.RegisterRoutes(api)
 	alertHandler.RegisterRoutes(api)
+	alertStatsHandler.RegisterRoutes(api)
+	evidenceHandler.RegisterRoutes(api)
+	alertRuleHandler.RegisterRoutes(api)
+	notificationHandler.RegisterRoutes(api)
+	escalationHandler.RegisterRoutes(api)
+	maintenanceHandler.RegisterRoutes(api)
 	faceRecognitionHandler.RegisterRoutes(api)
 	vehicleCountingHandler.RegisterRoutes(api)
+	ingestHandler.RegisterRoutes(api)
+	ingestionLedgerHandler.RegisterRoutes(api)
+	deadLetterHandler.RegisterRoutes(api)
+	watcherHandler.RegisterRoutes(api)
 	webSocketHandler.RegisterRoutes(api)
 }
 
@@ -390,17 +431,55 @@ func (s *Server) Run() error {
 		}
 	}()
 
+	// Evaluate threshold alert rules, escalate alerts and retry notifications until shutdown
+	background, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	if s.alertRuleService != nil && s.config.Alerts.RuleInterval > 0 {
+		go s.alertRuleService.Run(background, s.config.Alerts.RuleInterval)
+		log.Printf("Evaluating alert rules every %s", s.config.Alerts.RuleInterval)
+	}
+	if s.escalationService != nil && s.config.Alerts.EscalationInterval > 0 {
+		go s.escalationService.Run(background, s.config.Alerts.EscalationInterval)
+	}
+	if s.notificationService != nil && s.config.Notifications.RetryInterval > 0 {
+		go s.notificationService.Run(background, s.config.Notifications.RetryInterval)
+	}
+	if s.cameraHealthService != nil && s.config.CameraHealth.Interval > 0 {
+		go s.cameraHealthService.Run(background, s.config.CameraHealth.Interval)
+	}
+	if s.frameBuffer != nil && s.frameBuffer.Enabled() {
+		go s.frameBuffer.Run(background)
+		go s.evidenceService.Run(background)
+	}
+
 	// Channel to listen for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
-	// Block until we receive a signal or an error
-	select {
-	case <-quit:
-		log.Println("Shutting down server...")
-	case <-serverShutdown:
-		log.Println("Server stopped unexpectedly")
-	}
+	// Channel to listen for reload signal
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	defer signal.Stop(reload)
+
+	// Block until we receive a signal or an error, reloading ingestion sources on SIGHUP
+	func() {
+		for {
+			select {
+			case <-reload:
+				log.Println("Reloading ingestion sources...")
+				s.reloadSources()
+			case <-quit:
+				log.Println("Shutting down server...")
+				return
+			case <-serverShutdown:
+				log.Println("Server stopped unexpectedly")
+				return
+			}
+		}
+	}()
+
+	// Stop evaluating alert rules and retrying notifications
+	stopBackground()
 
 	// Stop the sync manager
 	if s.syncManager != nil {
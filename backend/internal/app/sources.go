@@ -0,0 +1,282 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"people-counting/config"
+	"people-counting/internal/domain/service"
+	"people-counting/internal/handler"
+	"people-counting/pkg/watcher"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// sourceHandlers builds the handler of an ingestion source from a set of services
+type sourceHandlers struct {
+	alertTypeService       service.AlertTypeService
+	alertService           service.AlertService
+	cameraDeviceService    service.CameraDeviceService
+	peopleCountService     service.PeopleCountService
+	vehicleService         service.VehicleCountService
+	faceRecognitionService service.FaceRecognitionService
+	webSocketService       service.WebSocketService
+}
+
+// build returns the handler processing the files of a source
+func (h *sourceHandlers) build(source config.SourceConfig, folder string) (interface {
+	watcher.FileHandler
+	handler.PayloadProcessor
+}, error) {
+	switch source.Handler {
+	case config.SourceHandlerAlert:
+		return handler.NewAlertHandlerWithType(h.alertTypeService, h.alertService, h.cameraDeviceService, h.webSocketService, folder, source.AlertType), nil
+	case config.SourceHandlerPeopleCount:
+		return handler.NewPeopleCountHandler(h.peopleCountService, h.cameraDeviceService, h.webSocketService), nil
+	case config.SourceHandlerVehicleCount:
+		return handler.NewVehicleCountHandler(h.vehicleService, h.cameraDeviceService), nil
+	case config.SourceHandlerFaceRecognition:
+		return handler.NewFaceRecognitionHandler(h.faceRecognitionService, h.cameraDeviceService), nil
+	default:
+		return nil, fmt.Errorf("unknown source handler %q", source.Handler)
+	}
+}
+
+// resolveDataRoot returns the data root as an absolute path
+func (s *Server) resolveDataRoot() (string, error) {
+	dataRootDir := s.config.DataDirectories.Root
+
+	// If data root is not absolute, make it relative to the current directory
+	if !filepath.IsAbs(dataRootDir) {
+		currentDir, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current directory: %v", err)
+		}
+		dataRootDir = filepath.Join(currentDir, dataRootDir)
+	}
+
+	return dataRootDir, nil
+}
+
+// sourceFolder returns the absolute folder of a source
+func (s *Server) sourceFolder(source config.SourceConfig) string {
+	if filepath.IsAbs(source.Folder) {
+		return source.Folder
+	}
+	return filepath.Join(s.dataRootDir, source.Folder)
+}
+
+// prepareSourceFolder creates the folder of a source, and its image folders for alert sources
+func (s *Server) prepareSourceFolder(source config.SourceConfig) error {
+	folder := s.sourceFolder(source)
+
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %v", folder, err)
+	}
+
+	imageFolders := []string{}
+	switch {
+	case source.Handler == config.SourceHandlerAlert && source.ImageFolder != "":
+		imageFolders = append(imageFolders, source.ImageFolder)
+	case source.Handler == config.SourceHandlerAlert:
+		// Create both "image" and "images" directories to support both naming conventions
+		imageFolders = append(imageFolders, "image", "images")
+	case source.Handler == config.SourceHandlerFaceRecognition:
+		imageFolders = append(imageFolders, "images")
+	}
+
+	for _, imageFolder := range imageFolders {
+		imagesDir := filepath.Join(folder, imageFolder)
+		if err := os.MkdirAll(imagesDir, 0755); err != nil {
+			return fmt.Errorf("failed to create images directory %s: %v", imagesDir, err)
+		}
+	}
+
+	return nil
+}
+
+// alertImageDir returns the folder serving the images of an alert source
+func (s *Server) alertImageDir(source config.SourceConfig) string {
+	basePath := s.sourceFolder(source)
+
+	if source.ImageFolder != "" {
+		return filepath.Join(basePath, source.ImageFolder)
+	}
+
+	// Try both "image" and "images" folders, prioritize the one with files
+	var alertImagesPath string
+	for _, folder := range []string{"images", "image"} {
+		testPath := filepath.Join(basePath, folder)
+		if _, err := os.Stat(testPath); err == nil {
+			entries, err := os.ReadDir(testPath)
+			if err == nil && len(entries) > 0 {
+				return testPath
+			} else if alertImagesPath == "" {
+				alertImagesPath = testPath
+			}
+		}
+	}
+
+	// If neither exists, default to "images" folder (plural)
+	if alertImagesPath == "" {
+		alertImagesPath = filepath.Join(basePath, "images")
+	}
+
+	return alertImagesPath
+}
+
+// serveAlertImage serves /api/images/alerts/{type}/* from the image folder of the
+// matching alert source, falling through to the general alert images otherwise
+func (s *Server) serveAlertImage(c *fiber.Ctx) error {
+	s.sourcesMu.RLock()
+	imageDir, ok := s.alertImageDirs[c.Params("type")]
+	s.sourcesMu.RUnlock()
+
+	if !ok {
+		return c.Next()
+	}
+
+	name := filepath.Clean("/" + c.Params("*"))
+	if name == "/" {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+
+	filePath := filepath.Join(imageDir, name)
+	if !strings.HasPrefix(filePath, imageDir+string(os.PathSeparator)) {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+
+	if info, err := os.Stat(filePath); err != nil || info.IsDir() {
+		return c.SendStatus(fiber.StatusNotFound)
+	}
+
+	return c.SendFile(filePath)
+}
+
+// applySources watches the folders of the given sources, replacing the current sources.
+// Folders no longer declared are unwatched and their ingestion kinds removed. Every handler is
+// built before anything is changed, so an invalid source leaves the current sources in place.
+func (s *Server) applySources(sources []config.SourceConfig) error {
+	s.sourcesMu.RLock()
+	current := make(map[string]config.SourceConfig, len(s.sources))
+	for _, source := range s.sources {
+		current[s.sourceFolder(source)] = source
+	}
+	s.sourcesMu.RUnlock()
+
+	type change struct {
+		source  config.SourceConfig
+		folder  string
+		handler watcher.FileHandler
+	}
+	var changes []change
+
+	alertImageDirs := make(map[string]string)
+	declared := make(map[string]bool)
+	// Alert sources are also accepted over HTTP under their alert type
+	declaredKinds := make(map[string]bool)
+	kinds := make(map[string]handler.PayloadProcessor)
+
+	for _, source := range sources {
+		folder := s.sourceFolder(source)
+		declared[folder] = true
+
+		if err := s.prepareSourceFolder(source); err != nil {
+			return err
+		}
+
+		if source.Handler == config.SourceHandlerAlert {
+			alertImageDirs[source.AlertType] = s.alertImageDir(source)
+			declaredKinds[strings.ToLower(source.AlertType)] = true
+		}
+
+		if previous, ok := current[folder]; ok && previous == source {
+			continue
+		}
+
+		// The folder and the ingestion API of an alert source share one handler
+		sourceHandler, err := s.handlers.build(source, folder)
+		if err != nil {
+			return err
+		}
+		if source.Handler == config.SourceHandlerAlert {
+			kinds[source.AlertType] = sourceHandler
+		}
+
+		changes = append(changes, change{source: source, folder: folder, handler: sourceHandler})
+	}
+
+	// Kinds are removed before the new ones are registered, so a kind moved to another folder stays
+	for _, source := range current {
+		if source.Handler == config.SourceHandlerAlert && !declaredKinds[strings.ToLower(source.AlertType)] {
+			s.ingestHandler.UnregisterKind(source.AlertType)
+		}
+	}
+	for kind, ingestHandler := range kinds {
+		s.ingestHandler.RegisterKind(kind, ingestHandler)
+	}
+
+	for folder, source := range current {
+		if declared[folder] {
+			continue
+		}
+
+		if err := s.syncManager.RemoveFolder(folder); err != nil {
+			log.Printf("WARNING: Failed to remove folder %s: %v", folder, err)
+		}
+		log.Printf("Stopped watching %s source: %s", source.Handler, folder)
+	}
+
+	// A folder that cannot be watched is left out of the current sources, so the next reload
+	// tries it again
+	failed := make(map[string]bool)
+	var errs []error
+	for _, c := range changes {
+		options := watcher.FolderOptions{
+			PollInterval:       time.Duration(c.source.PollInterval),
+			ProcessedRetention: time.Duration(c.source.ProcessedRetention),
+		}
+		if err := s.syncManager.AddFolderWithOptions(c.folder, c.source.Pattern, c.handler, options); err != nil {
+			failed[c.folder] = true
+			errs = append(errs, fmt.Errorf("failed to add folder %s: %v", c.folder, err))
+			continue
+		}
+
+		log.Printf("Watching %s source: %s", c.source.Handler, c.folder)
+	}
+
+	applied := make([]config.SourceConfig, 0, len(sources))
+	for _, source := range sources {
+		if !failed[s.sourceFolder(source)] {
+			applied = append(applied, source)
+		}
+	}
+
+	s.sourcesMu.Lock()
+	s.sources = applied
+	s.alertImageDirs = alertImageDirs
+	s.sourcesMu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// reloadSources reloads the sources file, keeping the current sources if it is invalid
+func (s *Server) reloadSources() {
+	sources, err := config.LoadSources(s.config.Ingest.SourcesFile, s.config.DataDirectories)
+	if err != nil {
+		log.Printf("ERROR: Failed to reload ingestion sources, keeping current sources: %v", err)
+		return
+	}
+
+	if err := s.applySources(sources); err != nil {
+		log.Printf("ERROR: Failed to apply ingestion sources: %v", err)
+		return
+	}
+
+	log.Printf("Reloaded %d ingestion sources from %s", len(sources), s.config.Ingest.SourcesFile)
+}
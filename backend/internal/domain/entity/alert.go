@@ -4,6 +4,60 @@ import (
 	"time"
 )
 
+// Alert statuses
+const (
+	AlertStatusNew           = "new"
+	AlertStatusAcknowledged  = "acknowledged"
+	AlertStatusInProgress    = "in_progress"
+	AlertStatusResolved      = "resolved"
+	AlertStatusFalsePositive = "false_positive"
+	AlertStatusReopened      = "reopened"
+)
+
+// alertTransitions lists the statuses each status can move to
+var alertTransitions = map[string][]string{
+	AlertStatusNew:           {AlertStatusAcknowledged, AlertStatusInProgress, AlertStatusResolved, AlertStatusFalsePositive},
+	AlertStatusAcknowledged:  {AlertStatusInProgress, AlertStatusResolved, AlertStatusFalsePositive},
+	AlertStatusInProgress:    {AlertStatusResolved, AlertStatusFalsePositive},
+	AlertStatusResolved:      {AlertStatusReopened},
+	AlertStatusFalsePositive: {AlertStatusReopened},
+	AlertStatusReopened:      {AlertStatusAcknowledged, AlertStatusInProgress, AlertStatusResolved, AlertStatusFalsePositive},
+}
+
+// IsAlertStatus reports whether status is a valid alert status
+func IsAlertStatus(status string) bool {
+	_, ok := alertTransitions[status]
+	return ok
+}
+
+// CanTransitionAlert reports whether an alert may move from one status to another
+func CanTransitionAlert(from, to string) bool {
+	for _, status := range alertTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpenAlertStatus reports whether an alert in this status still needs attention
+func IsOpenAlertStatus(status string) bool {
+	return status != AlertStatusResolved && status != AlertStatusFalsePositive
+}
+
+// Alert severities, from lowest to highest
+var AlertSeverities = []string{"low", "medium", "high", "critical"}
+
+// AlertSeverityRank returns the position of a severity in AlertSeverities, -1 when unknown
+func AlertSeverityRank(severity string) int {
+	for i, s := range AlertSeverities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
 // Alert model represents alerts triggered in the system
 type Alert struct {
 	ID             string     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
@@ -12,14 +66,31 @@ type Alert struct {
 	Message        string     `gorm:"type:text;not null;column:message" json:"message"`
 	Severity       string     `gorm:"size:20;not null;column:severity" json:"severity"`
 	ImageURL       string     `gorm:"size:255;column:image_url" json:"image_url"`
+	ClipPath       string     `gorm:"size:255;column:clip_path" json:"clip_path"` // Evidence clip, set shortly after detection
+	ObjectID       string     `gorm:"size:100;column:object_id" json:"object_id"`
+	ObjectName     string     `gorm:"size:100;column:object_name" json:"object_name"`
+	MissingItem    string     `gorm:"size:255;column:missing_item" json:"missing_item"`
+	Language       string     `gorm:"size:10;column:language" json:"language"` // Of templated messages, empty for given ones
+	Suppressed     bool       `gorm:"not null;default:false;index;column:suppressed" json:"suppressed"`
+	SuppressedBy   string     `gorm:"size:150;column:suppressed_by" json:"suppressed_by"` // Maintenance window or camera maintenance
 	IsActive       bool       `gorm:"default:true;column:is_active" json:"is_active"`
+	Status         string     `gorm:"size:20;default:new;index;column:status" json:"status"`
+	AssignedTo     string     `gorm:"size:100;column:assigned_to" json:"assigned_to"`
+	AcknowledgedAt *time.Time `gorm:"type:timestamp with time zone;column:acknowledged_at" json:"acknowledged_at"`
+	AcknowledgedBy string     `gorm:"size:100;column:acknowledged_by" json:"acknowledged_by"`
 	DetectedAt     time.Time  `gorm:"type:timestamp with time zone;not null;default:CURRENT_TIMESTAMP;column:detected_at" json:"detected_at"`
+	Occurrences    int        `gorm:"default:1;column:occurrence_count" json:"occurrence_count"`
+	FirstSeenAt    *time.Time `gorm:"type:timestamp with time zone;column:first_seen_at" json:"first_seen_at"`
+	LastSeenAt     *time.Time `gorm:"type:timestamp with time zone;column:last_seen_at" json:"last_seen_at"`
 	ResolvedAt     *time.Time `gorm:"type:timestamp with time zone;column:resolved_at" json:"resolved_at"`
 	ResolvedBy     string     `gorm:"size:100;column:resolved_by" json:"resolved_by"`
 	ResolutionNote string     `gorm:"type:text;column:resolution_note" json:"resolution_note"`
 	CreatedAt      time.Time  `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
 	UpdatedAt      time.Time  `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:updated_at" json:"updated_at"`
 
+	// Version of the camera configuration that made the first detection, nil when unknown
+	ConfigVersion *int `gorm:"column:config_version" json:"config_version"`
+
 	// Relationships
 	AlertType AlertType `gorm:"foreignKey:AlertTypeID" json:"alert_type,omitempty"`
 	Camera    *Camera   `gorm:"foreignKey:CameraID" json:"camera,omitempty"`
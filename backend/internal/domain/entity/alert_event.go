@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"time"
+)
+
+// Alert event actions
+const (
+	AlertEventCreated       = "created"
+	AlertEventStatusChanged = "status_changed"
+	AlertEventAssigned      = "assigned"
+	AlertEventEscalated     = "escalated"
+	// AlertEventEscalationStep records a step of the escalation policy of the alert type firing
+	// because the alert was not acknowledged in time
+	AlertEventEscalationStep = "escalation_step"
+)
+
+// AlertEvent records a change in the lifecycle of an alert
+type AlertEvent struct {
+	ID           uint      `gorm:"primaryKey;column:id" json:"id"`
+	AlertID      string    `gorm:"type:uuid;not null;index;column:alert_id" json:"alert_id"`
+	Action       string    `gorm:"size:30;not null;column:action" json:"action"`
+	FromStatus   string    `gorm:"size:20;column:from_status" json:"from_status"`
+	ToStatus     string    `gorm:"size:20;column:to_status" json:"to_status"`
+	FromSeverity string    `gorm:"size:20;column:from_severity" json:"from_severity,omitempty"`
+	ToSeverity   string    `gorm:"size:20;column:to_severity" json:"to_severity,omitempty"`
+	AssignedTo   string    `gorm:"size:100;column:assigned_to" json:"assigned_to,omitempty"`
+	Actor        string    `gorm:"size:100;column:actor" json:"actor"`
+	Note         string    `gorm:"type:text;column:note" json:"note"`
+	CreatedAt    time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;index;column:created_at" json:"created_at"`
+
+	// The alert after the change, not stored
+	Alert *Alert `gorm:"-" json:"alert,omitempty"`
+}
+
+// TableName returns the table name for the AlertEvent model
+func (AlertEvent) TableName() string {
+	return "alert_events"
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"time"
+)
+
+// Alert message languages, picked through the Accept-Language header
+const (
+	AlertLanguageEnglish    = "en"
+	AlertLanguageIndonesian = "id"
+)
+
+// AlertLanguages lists the languages alert messages are written in
+var AlertLanguages = []string{AlertLanguageEnglish, AlertLanguageIndonesian}
+
+// IsAlertLanguage reports whether language is a supported alert message language
+func IsAlertLanguage(language string) bool {
+	for _, l := range AlertLanguages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
+
+// AlertTypeMessage is the message template of an alert type in one language. Templates use Go
+// text/template syntax over AlertMessageData, such as
+// "Missing {{.MissingItem}} at {{.Camera}}".
+type AlertTypeMessage struct {
+	ID          uint      `gorm:"primaryKey;column:id" json:"id"`
+	AlertTypeID uint      `gorm:"not null;uniqueIndex:idx_alert_type_messages_language;column:alert_type_id" json:"alert_type_id"`
+	Language    string    `gorm:"size:10;not null;uniqueIndex:idx_alert_type_messages_language;column:language" json:"language"`
+	Template    string    `gorm:"type:text;not null;column:template" json:"template"`
+	CreatedAt   time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:updated_at" json:"updated_at"`
+}
+
+// TableName returns the table name for the AlertTypeMessage model
+func (AlertTypeMessage) TableName() string {
+	return "alert_type_messages"
+}
+
+// AlertMessageData holds the placeholders of alert message templates
+type AlertMessageData struct {
+	AlertType   string // Display name of the alert type, or its name
+	Camera      string
+	MissingItem string // Missing protective equipment, comma separated
+	ObjectName  string // Kind of object detected, such as person
+	Time        string // When the alert was detected
+}
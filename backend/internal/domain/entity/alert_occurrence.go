@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"time"
+)
+
+// AlertOccurrence is a detection folded into an alert, with its evidence image. The first
+// occurrence of an alert is the detection that created it.
+type AlertOccurrence struct {
+	ID         uint      `gorm:"primaryKey;column:id" json:"id"`
+	AlertID    string    `gorm:"type:uuid;not null;index;column:alert_id" json:"alert_id"`
+	SourceID   string    `gorm:"size:100;uniqueIndex:idx_alert_occurrences_source_id,where:source_id <> '';column:source_id" json:"source_id"`
+	ImageURL   string    `gorm:"size:255;column:image_url" json:"image_url"`
+	Severity   string    `gorm:"size:20;column:severity" json:"severity"`
+	DetectedAt time.Time `gorm:"type:timestamp with time zone;not null;column:detected_at" json:"detected_at"`
+	CreatedAt  time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
+
+	// Version of the camera configuration that made the detection, nil when unknown
+	ConfigVersion *int `gorm:"column:config_version" json:"config_version"`
+}
+
+// TableName returns the table name for the AlertOccurrence model
+func (AlertOccurrence) TableName() string {
+	return "alert_occurrences"
+}
+
+// AlertCorrelation is the outcome of recording a detection
+type AlertCorrelation struct {
+	Alert *Alert
+	// Created is set when the detection opened a new alert
+	Created bool
+	// Escalated is set when the detection raised the severity of an open alert
+	Escalated bool
+	// Duplicate is set when the detection was already recorded
+	Duplicate bool
+	// Suppressed is set when the detection fell in a maintenance window
+	Suppressed bool
+}
+
+// ShouldNotify reports whether operators should be notified of the detection
+func (c *AlertCorrelation) ShouldNotify() bool {
+	return (c.Created || c.Escalated) && !c.Suppressed
+}
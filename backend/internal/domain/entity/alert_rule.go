@@ -0,0 +1,147 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Alert rule metrics
+const (
+	RuleMetricOccupancy   = "occupancy"
+	RuleMetricPeopleIn    = "people_in"
+	RuleMetricPeopleOut   = "people_out"
+	RuleMetricPeopleTotal = "people_total"
+	RuleMetricVehicleIn   = "vehicle_in"
+	RuleMetricCarIn       = "car_in"
+	RuleMetricTruckIn     = "truck_in"
+	RuleMetricVehicleOut  = "vehicle_out"
+)
+
+// Alert rule conditions
+const (
+	// RuleConditionAbove matches when the value is above the threshold
+	RuleConditionAbove = "above"
+	// RuleConditionAtMost matches when the value is at or below the threshold, such as no
+	// people at all with a zero threshold
+	RuleConditionAtMost = "at_most"
+	// RuleConditionSurge matches when the value is at least threshold times the value of the
+	// previous window
+	RuleConditionSurge = "surge"
+)
+
+// IsRuleMetric reports whether metric is a valid alert rule metric
+func IsRuleMetric(metric string) bool {
+	switch metric {
+	case RuleMetricOccupancy, RuleMetricPeopleIn, RuleMetricPeopleOut, RuleMetricPeopleTotal,
+		RuleMetricVehicleIn, RuleMetricCarIn, RuleMetricTruckIn, RuleMetricVehicleOut:
+		return true
+	}
+	return false
+}
+
+// IsVehicleRuleMetric reports whether metric is read from vehicle counts
+func IsVehicleRuleMetric(metric string) bool {
+	switch metric {
+	case RuleMetricVehicleIn, RuleMetricCarIn, RuleMetricTruckIn, RuleMetricVehicleOut:
+		return true
+	}
+	return false
+}
+
+// IsRuleCondition reports whether condition is a valid alert rule condition
+func IsRuleCondition(condition string) bool {
+	switch condition {
+	case RuleConditionAbove, RuleConditionAtMost, RuleConditionSurge:
+		return true
+	}
+	return false
+}
+
+// AlertRule raises an alert when a people or vehicle count metric of a camera or zone meets a
+// condition. Counts are summed over the window, occupancy is the live occupancy since the
+// daily reset and a zero threshold means the capacity of the target. A zone rule raises an
+// alert on every camera of the zone.
+type AlertRule struct {
+	ID          uint   `gorm:"primaryKey;column:id" json:"id"`
+	Name        string `gorm:"size:100;not null;uniqueIndex;column:name" json:"name"`
+	Description string `gorm:"type:text;column:description" json:"description"`
+	Enabled     bool   `gorm:"not null;column:enabled" json:"enabled"`
+
+	// Target, a camera, the cameras of a zone and of the zones inside it, or the cameras at a
+	// location as set before zones existed
+	CameraID *uint  `gorm:"index;column:camera_id" json:"camera_id"`
+	ZoneID   *uint  `gorm:"index;column:zone_id" json:"zone_id"`
+	Zone     string `gorm:"size:100;column:zone" json:"zone"`
+
+	Metric        string  `gorm:"size:30;not null;column:metric" json:"metric"`
+	Condition     string  `gorm:"size:20;not null;column:condition" json:"condition"`
+	Threshold     float64 `gorm:"not null;default:0;column:threshold" json:"threshold"`
+	WindowMinutes int     `gorm:"not null;default:60;column:window_minutes" json:"window_minutes"`
+
+	// Schedule in the site time zone. Days are comma separated (mon,tue,...), empty for every
+	// day. Start and end are HH:MM, empty for the whole day, an end before the start spans
+	// midnight. With OutsideSchedule the rule applies when the schedule does not.
+	ScheduleDays    string `gorm:"size:50;column:schedule_days" json:"schedule_days"`
+	ScheduleStart   string `gorm:"size:5;column:schedule_start" json:"schedule_start"`
+	ScheduleEnd     string `gorm:"size:5;column:schedule_end" json:"schedule_end"`
+	OutsideSchedule bool   `gorm:"default:false;column:outside_schedule" json:"outside_schedule"`
+
+	Severity        string     `gorm:"size:20;not null;default:medium;column:severity" json:"severity"`
+	CooldownMinutes int        `gorm:"not null;default:0;column:cooldown_minutes" json:"cooldown_minutes"`
+	AlertTypeID     uint       `gorm:"column:alert_type_id" json:"alert_type_id"`
+	LastTriggeredAt *time.Time `gorm:"type:timestamp with time zone;column:last_triggered_at" json:"last_triggered_at"`
+	CreatedAt       time.Time  `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:updated_at" json:"updated_at"`
+}
+
+// TableName returns the table name for the AlertRule model
+func (AlertRule) TableName() string {
+	return "alert_rules"
+}
+
+// Window returns the evaluation window of the rule
+func (r *AlertRule) Window() time.Duration {
+	return time.Duration(r.WindowMinutes) * time.Minute
+}
+
+// Cooldown returns how long the rule stays quiet after raising an alert, the window by default
+func (r *AlertRule) Cooldown() time.Duration {
+	if r.CooldownMinutes > 0 {
+		return time.Duration(r.CooldownMinutes) * time.Minute
+	}
+	return r.Window()
+}
+
+// Target describes the camera or zone of the rule
+func (r *AlertRule) Target() string {
+	if r.CameraID != nil {
+		return fmt.Sprintf("camera %d", *r.CameraID)
+	}
+	if r.ZoneID != nil {
+		return fmt.Sprintf("zone %d", *r.ZoneID)
+	}
+	return fmt.Sprintf("zone %s", r.Zone)
+}
+
+// ValidateSchedule checks the schedule days and times
+func (r *AlertRule) ValidateSchedule() error {
+	return validateSchedule(r.ScheduleDays, r.ScheduleStart, r.ScheduleEnd)
+}
+
+// Applies reports whether the rule applies at the given time in the given time zone
+func (r *AlertRule) Applies(t time.Time, location *time.Location) bool {
+	return inSchedule(t.In(location), r.ScheduleDays, r.ScheduleStart, r.ScheduleEnd) != r.OutsideSchedule
+}
+
+// RuleEvaluation is the outcome of evaluating a rule
+type RuleEvaluation struct {
+	RuleID    uint    `json:"rule_id"`
+	RuleName  string  `json:"rule_name"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+	Matched   bool    `json:"matched"`
+	Skipped   string  `json:"skipped,omitempty"` // Why the rule was not evaluated
+	AlertID   string  `json:"alert_id,omitempty"`
+	// Alerts raised, one per camera of a zone rule
+	AlertIDs []string `json:"alert_ids,omitempty"`
+}
@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"time"
+)
+
+// AlertDailyCount is a row of the alerts_daily aggregate summed over a date range
+type AlertDailyCount struct {
+	CameraID        uint   `json:"camera_id"`
+	AlertTypeID     uint   `json:"alert_type_id"`
+	Severity        string `json:"severity"`
+	AlertCount      int64  `json:"alert_count"`
+	SuppressedCount int64  `json:"suppressed_count"`
+}
+
+// AlertCameraOutcome is how the alerts of a camera were handled over a date range.
+// Durations are summed in seconds so that means can be taken over several cameras.
+type AlertCameraOutcome struct {
+	CameraID           uint    `json:"camera_id"`
+	Alerts             int64   `json:"alerts"`
+	Acknowledged       int64   `json:"acknowledged"`
+	AcknowledgeSeconds float64 `json:"acknowledge_seconds"`
+	Resolved           int64   `json:"resolved"`
+	ResolveSeconds     float64 `json:"resolve_seconds"`
+	FalsePositives     int64   `json:"false_positives"`
+	Closed             int64   `json:"closed"` // Resolved or false positive
+}
+
+// AlertTypeStat is the number of alerts of an alert type
+type AlertTypeStat struct {
+	AlertTypeID uint   `json:"alert_type_id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Count       int64  `json:"count"`
+}
+
+// AlertCameraStat is the number of alerts of a camera, and how many were false positives
+type AlertCameraStat struct {
+	CameraID          uint     `json:"camera_id"`
+	CameraName        string   `json:"camera_name"`
+	Count             int64    `json:"count"`
+	FalsePositives    int64    `json:"false_positives"`
+	FalsePositiveRate *float64 `json:"false_positive_rate"` // Percentage of closed alerts, null when none are closed
+}
+
+// AlertZoneStat is the number of alerts of the cameras of a zone and of the zones inside it
+type AlertZoneStat struct {
+	ZoneID uint   `json:"zone_id"`
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Count  int64  `json:"count"`
+}
+
+// AlertSeverityStat is the number of alerts of a severity
+type AlertSeverityStat struct {
+	Severity string `json:"severity"`
+	Count    int64  `json:"count"`
+}
+
+// AlertHourStat is the number of alerts detected in an hour of the day, in the site time zone
+type AlertHourStat struct {
+	Hour  int   `json:"hour"`
+	Count int64 `json:"count"`
+}
+
+// AlertStats summarizes the alerts of a date range. Suppressed alerts are only counted in
+// Suppressed. Counts by type, camera, zone and severity cover whole days.
+type AlertStats struct {
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	Total      int64     `json:"total"`
+	Suppressed int64     `json:"suppressed"`
+
+	ByType     []AlertTypeStat     `json:"by_type"`
+	ByCamera   []AlertCameraStat   `json:"by_camera"`
+	ByZone     []AlertZoneStat     `json:"by_zone"` // Zones directly inside the zone filtered on, or sites
+	BySeverity []AlertSeverityStat `json:"by_severity"`
+	ByHour     []AlertHourStat     `json:"by_hour"`
+
+	// Response times in seconds from detection, null when no alert got there
+	MeanTimeToAcknowledge *float64 `json:"mean_time_to_acknowledge"`
+	MeanTimeToResolve     *float64 `json:"mean_time_to_resolve"`
+
+	Acknowledged      int64    `json:"acknowledged"`
+	Resolved          int64    `json:"resolved"`
+	FalsePositives    int64    `json:"false_positives"`
+	FalsePositiveRate *float64 `json:"false_positive_rate"` // Percentage of closed alerts, null when none are closed
+
+	NoisyCameras []AlertCameraStat `json:"noisy_cameras"`
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"strings"
 	"time"
 )
 
@@ -15,11 +16,53 @@ type AlertType struct {
 	CreatedAt   time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
 	UpdatedAt   time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:updated_at" json:"updated_at"`
 
+	// Severity is the severity of detections of this type
+	Severity string `gorm:"size:255;default:high;column:severity" json:"severity"`
+
+	// ArchivedAt hides the type from lists. Its alerts are kept and detections still map to it.
+	ArchivedAt *time.Time `gorm:"type:timestamp with time zone;column:archived_at" json:"archived_at"`
+
+	// EscalationPolicyID escalates alerts of this type that are not acknowledged in time
+	EscalationPolicyID *uint `gorm:"column:escalation_policy_id" json:"escalation_policy_id"`
+
+	// EscalationPolicyAttachedAt is when the policy was attached. Alerts raised before only
+	// escalate from then on.
+	EscalationPolicyAttachedAt *time.Time `gorm:"type:timestamp with time zone;column:escalation_policy_attached_at" json:"escalation_policy_attached_at"`
+
 	// Relationships
-	Alerts []Alert `gorm:"foreignKey:AlertTypeID" json:"alerts,omitempty"`
+	Alerts   []Alert            `gorm:"foreignKey:AlertTypeID" json:"alerts,omitempty"`
+	Messages []AlertTypeMessage `gorm:"foreignKey:AlertTypeID" json:"messages,omitempty"`
+	Aliases  []AlertTypeAlias   `gorm:"foreignKey:AlertTypeID" json:"aliases,omitempty"`
 }
 
 // TableName returns the table name for the AlertType model
 func (AlertType) TableName() string {
 	return "alert_types"
 }
+
+// NormalizeAlertTypeName normalizes an alert type name for matching, so that "Loitering " and
+// "loitering" are the same type
+func NormalizeAlertTypeName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// AlertTypeAlias maps another name sent by detectors, such as a misspelling or the name of a
+// merged type, to an alert type
+type AlertTypeAlias struct {
+	ID          uint      `gorm:"primaryKey;column:id" json:"id"`
+	Name        string    `gorm:"size:50;not null;uniqueIndex;column:name" json:"name"` // Normalized
+	AlertTypeID uint      `gorm:"not null;index;column:alert_type_id" json:"alert_type_id"`
+	CreatedAt   time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
+}
+
+// TableName returns the table name for the AlertTypeAlias model
+func (AlertTypeAlias) TableName() string {
+	return "alert_type_aliases"
+}
+
+// AlertTypeMerge is the outcome of merging an alert type into another
+type AlertTypeMerge struct {
+	Source      string     `json:"source"` // Name of the merged type, now an alias of the target
+	Target      *AlertType `json:"target"`
+	AlertsMoved int64      `json:"alerts_moved"`
+}
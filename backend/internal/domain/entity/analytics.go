@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"time"
+)
+
+// CameraOccupancyRate is the occupancy of a camera relative to its capacity
+type CameraOccupancyRate struct {
+	CameraID   uint     `json:"camera_id"`
+	CameraName string   `json:"camera_name"`
+	Occupancy  int      `json:"occupancy"`
+	Capacity   int      `json:"capacity"`
+	Rate       *float64 `json:"rate"` // Percentage, null when the capacity is unknown
+}
+
+// OccupancyRate is the live occupancy relative to capacity since the last daily reset.
+// The overall rate only covers cameras with a known capacity.
+type OccupancyRate struct {
+	Since     time.Time             `json:"since"`
+	Occupancy int                   `json:"occupancy"`
+	Capacity  int                   `json:"capacity"`
+	Rate      *float64              `json:"rate"`
+	Cameras   []CameraOccupancyRate `json:"cameras"`
+}
+
+// MetricComparison compares a metric between the current and the previous period
+type MetricComparison struct {
+	Current    int      `json:"current"`
+	Previous   int      `json:"previous"`
+	Delta      int      `json:"delta"`
+	Percentage *float64 `json:"percentage"` // Null when the previous value is zero
+}
+
+// ComparisonMetrics holds the compared people count metrics
+type ComparisonMetrics struct {
+	Visitors MetricComparison `json:"visitors"`
+	In       MetricComparison `json:"in"`
+	Out      MetricComparison `json:"out"`
+	Male     MetricComparison `json:"male"`
+	Female   MetricComparison `json:"female"`
+	Child    MetricComparison `json:"child"`
+	Adult    MetricComparison `json:"adult"`
+	Elderly  MetricComparison `json:"elderly"`
+}
+
+// ComparisonWindow is one side of a historical comparison
+type ComparisonWindow struct {
+	From   time.Time    `json:"from"`
+	To     time.Time    `json:"to"`
+	Series []TrendPoint `json:"series"`
+}
+
+// HistoricalComparison compares today with the same weekday last week, or this month with
+// last month, both up to the same elapsed time
+type HistoricalComparison struct {
+	Period   string            `json:"period"`
+	Current  ComparisonWindow  `json:"current"`
+	Previous ComparisonWindow  `json:"previous"`
+	Metrics  ComparisonMetrics `json:"metrics"`
+}
+
+// DemographicShare is the percentage of each group in the visitors
+type DemographicShare struct {
+	Male    float64 `json:"male"`
+	Female  float64 `json:"female"`
+	Child   float64 `json:"child"`
+	Adult   float64 `json:"adult"`
+	Elderly float64 `json:"elderly"`
+}
+
+// DemographicInsights summarizes visitor demographics over a time window
+type DemographicInsights struct {
+	Window           string           `json:"window"`
+	From             time.Time        `json:"from"`
+	To               time.Time        `json:"to"`
+	Totals           TotalCounts      `json:"totals"`
+	Share            DemographicShare `json:"share"`
+	DominantGender   string           `json:"dominant_gender"`
+	DominantAgeGroup string           `json:"dominant_age_group"`
+	Daily            []TrendPoint     `json:"daily"`
+}
@@ -8,12 +8,21 @@ import (
 type Camera struct {
 	ID        uint      `gorm:"primaryKey;column:id" json:"id"`
 	Name      string    `gorm:"size:100;not null;column:name" json:"name"`
-	IPAddress string    `gorm:"size:15;column:ip_address" json:"ip_address"`
+	IPAddress string    `gorm:"size:45;column:ip_address" json:"ip_address"` // IPv4 or IPv6
 	Location  string    `gorm:"size:100;column:location" json:"location"`
 	Status    string    `gorm:"size:20;default:active;column:status" json:"status"`
+	Capacity  int       `gorm:"default:0;column:capacity" json:"capacity"` // Maximum occupancy, 0 when unknown
 	CreatedAt time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
 	UpdatedAt time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:updated_at" json:"updated_at"`
 
+	// Position on the map, nil when unknown. Bearing is the direction the camera faces in degrees
+	// clockwise from north and FieldOfView the horizontal angle it sees, both in degrees.
+	Latitude    *float64 `gorm:"column:latitude" json:"latitude"`
+	Longitude   *float64 `gorm:"column:longitude" json:"longitude"`
+	Floor       *int     `gorm:"column:floor" json:"floor"`
+	Bearing     *float64 `gorm:"column:bearing" json:"bearing"`
+	FieldOfView *float64 `gorm:"column:field_of_view" json:"field_of_view"`
+
 	WsURL     string `gorm:"size:100;column:ws_url" json:"ws_url"`
 	StreamURL string `gorm:"-" json:"stream_url,omitempty"`
 	ImageURL  string `gorm:"-" json:"image_url,omitempty"`
@@ -21,6 +30,7 @@ type Camera struct {
 	// Relationships
 	PeopleCounts []PeopleCount `gorm:"foreignKey:CameraID" json:"people_counts,omitempty"`
 	Alerts       []Alert       `gorm:"foreignKey:CameraID" json:"alerts,omitempty"`
+	Zones        []ZoneCamera  `gorm:"foreignKey:CameraID" json:"zones,omitempty"`
 }
 
 // TableName returns the table name for the Camera model
@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Kinds of the polygons of a camera configuration
+const (
+	ConfigPolygonKindROI        = "roi"        // Detections outside every region of interest are ignored
+	ConfigPolygonKindRestricted = "restricted" // Objects entering raise a restricted area alert
+	ConfigPolygonKindLoitering  = "loitering"  // Objects staying longer than the loitering seconds raise a loitering alert
+)
+
+// Directions of the tripwires of a camera configuration. Looking from the start of a tripwire to
+// its end, left to right counts objects crossing from the left side as in.
+const (
+	ConfigDirectionLeftToRight = "left_to_right"
+	ConfigDirectionRightToLeft = "right_to_left"
+)
+
+// IsConfigPolygonKind reports whether kind is a known polygon kind
+func IsConfigPolygonKind(kind string) bool {
+	switch kind {
+	case ConfigPolygonKindROI, ConfigPolygonKindRestricted, ConfigPolygonKindLoitering:
+		return true
+	}
+	return false
+}
+
+// IsConfigDirection reports whether direction is a known tripwire direction
+func IsConfigDirection(direction string) bool {
+	return direction == ConfigDirectionLeftToRight || direction == ConfigDirectionRightToLeft
+}
+
+// ConfigPoint is a point of the camera frame, as fractions of its width and height from the top
+// left corner so it does not depend on the stream resolution
+type ConfigPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// ConfigPolygon is an area of the camera frame
+type ConfigPolygon struct {
+	Name   string        `json:"name"`
+	Kind   string        `json:"kind"`
+	Points []ConfigPoint `json:"points"`
+}
+
+// ConfigTripwire is a counting line of the camera frame
+type ConfigTripwire struct {
+	Name  string      `json:"name"`
+	Start ConfigPoint `json:"start"`
+	End   ConfigPoint `json:"end"`
+}
+
+// CameraConfig is a version of the detection configuration an edge device runs for a camera.
+// Versions are never changed, saving a configuration adds the next version and records ingested
+// from the camera keep the version they were detected with. The latest version that is not
+// archived is the current one.
+type CameraConfig struct {
+	ID               uint             `gorm:"primaryKey;column:id" json:"id"`
+	CameraID         uint             `gorm:"not null;uniqueIndex:idx_camera_configs_camera_version;column:camera_id" json:"camera_id"`
+	Version          int              `gorm:"not null;uniqueIndex:idx_camera_configs_camera_version;column:version" json:"version"`
+	Polygons         []ConfigPolygon  `gorm:"type:text;serializer:json;column:polygons" json:"polygons"`
+	Tripwires        []ConfigTripwire `gorm:"type:text;serializer:json;column:tripwires" json:"tripwires"`
+	Direction        string           `gorm:"size:20;not null;default:left_to_right;column:direction" json:"direction"`
+	Classes          []string         `gorm:"type:text;serializer:json;column:classes" json:"classes"` // Object classes detected, every class when empty
+	LoiteringSeconds int              `gorm:"default:0;column:loitering_seconds" json:"loitering_seconds"`
+	Note             string           `gorm:"type:text;column:note" json:"note"` // What changed from the previous version
+	CreatedBy        string           `gorm:"size:100;column:created_by" json:"created_by"`
+	CreatedAt        time.Time        `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
+	ArchivedAt       *time.Time       `gorm:"type:timestamp with time zone;column:archived_at" json:"archived_at"`
+}
+
+// TableName returns the table name for the CameraConfig model
+func (CameraConfig) TableName() string {
+	return "camera_configs"
+}
+
+// ETag returns the entity tag edge devices send back to poll for a newer version
+func (c *CameraConfig) ETag() string {
+	return fmt.Sprintf(`"%d-%d"`, c.CameraID, c.Version)
+}
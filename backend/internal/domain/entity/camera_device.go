@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Camera device statuses
+const (
+	CameraDeviceStatusMapped      = "mapped"
+	CameraDeviceStatusQuarantined = "quarantined"
+)
+
+// Policies applied to records from devices without a mapping
+const (
+	UnknownDevicePolicyReject       = "reject"
+	UnknownDevicePolicyQuarantine   = "quarantine"
+	UnknownDevicePolicyAutoRegister = "auto-register"
+)
+
+// IsUnknownDevicePolicy reports whether policy is a valid unknown device policy
+func IsUnknownDevicePolicy(policy string) bool {
+	switch policy {
+	case UnknownDevicePolicyReject, UnknownDevicePolicyQuarantine, UnknownDevicePolicyAutoRegister:
+		return true
+	}
+	return false
+}
+
+// CameraDevice maps an external device identifier to a camera. A device is identified by its
+// serial and channel, or by the legacy cctv_id sent by older devices.
+type CameraDevice struct {
+	ID          uint       `gorm:"primaryKey;column:id" json:"id"`
+	Serial      string     `gorm:"size:100;uniqueIndex:idx_camera_devices_serial_channel,where:serial <> '';column:serial" json:"serial"`
+	Channel     int        `gorm:"default:0;uniqueIndex:idx_camera_devices_serial_channel,where:serial <> '';column:channel" json:"channel"`
+	LegacyID    *uint      `gorm:"uniqueIndex:idx_camera_devices_legacy_id;column:legacy_id" json:"legacy_id"`
+	CameraID    *uint      `gorm:"index;column:camera_id" json:"camera_id"`
+	Status      string     `gorm:"size:20;not null;default:mapped;index;column:status" json:"status"`
+	Name        string     `gorm:"size:100;column:name" json:"name"`
+	SeenCount   int64      `gorm:"default:0;column:seen_count" json:"seen_count"`
+	FirstSeenAt *time.Time `gorm:"type:timestamp with time zone;column:first_seen_at" json:"first_seen_at"`
+	LastSeenAt  *time.Time `gorm:"type:timestamp with time zone;column:last_seen_at" json:"last_seen_at"`
+	CreatedAt   time.Time  `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:updated_at" json:"updated_at"`
+}
+
+// TableName returns the table name for the CameraDevice model
+func (CameraDevice) TableName() string {
+	return "camera_devices"
+}
+
+// DeviceIdentity identifies the device that produced a record
+type DeviceIdentity struct {
+	Serial   string
+	Channel  int
+	LegacyID *uint
+}
+
+// IsZero reports whether the record carried no device identifier at all
+func (d DeviceIdentity) IsZero() bool {
+	return d.Serial == "" && d.LegacyID == nil
+}
+
+// String formats the identity for logs and error messages
+func (d DeviceIdentity) String() string {
+	switch {
+	case d.Serial != "" && d.LegacyID != nil:
+		return fmt.Sprintf("%s/%d (cctv_id %d)", d.Serial, d.Channel, *d.LegacyID)
+	case d.Serial != "":
+		return fmt.Sprintf("%s/%d", d.Serial, d.Channel)
+	case d.LegacyID != nil:
+		return fmt.Sprintf("cctv_id %d", *d.LegacyID)
+	default:
+		return "unidentified device"
+	}
+}
@@ -0,0 +1,50 @@
+package entity
+
+// CameraMap is a GeoJSON feature collection with a feature per camera, to draw cameras on a map
+type CameraMap struct {
+	Type     string          `json:"type"` // Always FeatureCollection
+	Features []CameraFeature `json:"features"`
+}
+
+// CameraFeature is the GeoJSON feature of a camera. Its geometry is null when the camera has no
+// latitude and longitude, cameras may still be drawn on the floor plans of their zones.
+type CameraFeature struct {
+	Type       string                  `json:"type"` // Always Feature
+	ID         uint                    `json:"id"`
+	Geometry   *PointGeometry          `json:"geometry"`
+	Properties CameraFeatureProperties `json:"properties"`
+}
+
+// PointGeometry is a GeoJSON point, its coordinates are longitude then latitude
+type PointGeometry struct {
+	Type        string     `json:"type"` // Always Point
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// CameraFeatureProperties is the live state of a camera on the map
+type CameraFeatureProperties struct {
+	Name         string   `json:"name"`
+	Location     string   `json:"location"`
+	Status       string   `json:"status"`
+	Floor        *int     `json:"floor"`
+	Bearing      *float64 `json:"bearing"`
+	FieldOfView  *float64 `json:"field_of_view"`
+	Capacity     int      `json:"capacity"`
+	ActiveAlerts int64    `json:"active_alerts"`
+
+	// Occupancy since the last reset, nil when the camera counted no one since
+	Occupancy *CameraOccupancy `json:"occupancy"`
+
+	Zones []CameraFeatureZone `json:"zones"`
+}
+
+// CameraFeatureZone is a zone of a camera with the camera's position on its floor plan
+type CameraFeatureZone struct {
+	ZoneID    uint     `json:"zone_id"`
+	Name      string   `json:"name"`
+	Kind      string   `json:"kind"`
+	Direction string   `json:"direction"`
+	FloorPlan bool     `json:"floor_plan"`
+	PlanX     *float64 `json:"plan_x"`
+	PlanY     *float64 `json:"plan_y"`
+}
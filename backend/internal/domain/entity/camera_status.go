@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"time"
+)
+
+// Camera statuses. Only active and issue are set by the health monitor.
+const (
+	CameraStatusActive      = "active"
+	CameraStatusInactive    = "inactive"
+	CameraStatusMaintenance = "maintenance"
+	CameraStatusIssue       = "issue"
+)
+
+// Sources of camera status changes
+const (
+	CameraStatusSourceManual  = "manual"
+	CameraStatusSourceMonitor = "monitor"
+)
+
+// CameraStatusEvent records a change of the status of a camera
+type CameraStatusEvent struct {
+	ID         uint      `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	CameraID   uint      `gorm:"not null;index;column:camera_id" json:"camera_id"`
+	FromStatus string    `gorm:"size:20;column:from_status" json:"from_status"`
+	ToStatus   string    `gorm:"size:20;not null;column:to_status" json:"to_status"`
+	Source     string    `gorm:"size:20;not null;column:source" json:"source"`
+	Reason     string    `gorm:"type:text;column:reason" json:"reason"`
+	ChangedAt  time.Time `gorm:"type:timestamp with time zone;not null;default:CURRENT_TIMESTAMP;primaryKey;column:changed_at" json:"changed_at"`
+}
+
+// TableName returns the table name for the CameraStatusEvent model
+func (CameraStatusEvent) TableName() string {
+	return "camera_status_events"
+}
+
+// CameraActivity is the last time data of a camera was ingested
+type CameraActivity struct {
+	CameraID     uint      `json:"camera_id"`
+	LastActiveAt time.Time `json:"last_active_at"`
+}
+
+// CameraHealth is the outcome of the last health check of a camera
+type CameraHealth struct {
+	CameraID   uint       `json:"camera_id"`
+	CameraName string     `json:"camera_name"`
+	Status     string     `json:"status"`
+	Healthy    *bool      `json:"healthy"` // Nil when the camera gave no signal to judge by
+	Reason     string     `json:"reason,omitempty"`
+	LastFrame  *time.Time `json:"last_frame_at"`
+	LastData   *time.Time `json:"last_data_at"`
+	// DecodeFailures counts the stream images in a row that failed to decode while streaming
+	DecodeFailures int       `json:"decode_failures"`
+	FailedChecks   int       `json:"failed_checks"`
+	HealthyChecks  int       `json:"healthy_checks"`
+	CheckedAt      time.Time `json:"checked_at"`
+}
+
+// CameraAvailability is how long a camera was up over a date range. Time in maintenance or
+// inactive is planned and left out of the monitored time, time in issue is an outage.
+// Durations are in seconds.
+type CameraAvailability struct {
+	CameraID         uint     `json:"camera_id"`
+	CameraName       string   `json:"camera_name"`
+	Location         string   `json:"location"`
+	Status           string   `json:"status"`
+	MonitoredSeconds float64  `json:"monitored_seconds"`
+	DowntimeSeconds  float64  `json:"downtime_seconds"`
+	Availability     *float64 `json:"availability"` // Percentage of the monitored time, null when never monitored
+	Outages          int      `json:"outages"`
+	LongestOutage    float64  `json:"longest_outage_seconds"`
+	// MeanTimeToRepair is the mean length of the outages that ended in the range, counted from
+	// when they started even before the range. Null when none ended.
+	MeanTimeToRepair *float64 `json:"mttr_seconds"`
+	MeetsTarget      *bool    `json:"meets_target"`
+}
+
+// CameraAvailabilityReport is the availability of cameras over a date range
+type CameraAvailabilityReport struct {
+	From         time.Time            `json:"from"`
+	To           time.Time            `json:"to"`
+	Target       float64              `json:"target"` // Availability percentage agreed on
+	Availability *float64             `json:"availability"`
+	Cameras      []CameraAvailability `json:"cameras"`
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+)
+
+// DeadLetterFolder summarises the failed folder of a watched ingestion folder
+type DeadLetterFolder struct {
+	Name           string     `json:"name"`
+	Path           string     `json:"path"`
+	FailedFolder   string     `json:"failed_folder"`
+	Handler        string     `json:"handler"`
+	Count          int        `json:"count"`
+	OldestFailedAt *time.Time `json:"oldest_failed_at"`
+}
+
+// DeadLetter is a file that could not be ingested, together with its error sidecar
+type DeadLetter struct {
+	Folder         string    `json:"folder"`
+	Name           string    `json:"name"`
+	Size           int64     `json:"size"`
+	FailedAt       time.Time `json:"failed_at"`
+	Handler        string    `json:"handler,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	ErrorChain     []string  `json:"error_chain,omitempty"`
+	Classification string    `json:"classification,omitempty"`
+	Attempts       int       `json:"attempts,omitempty"`
+	ParseError     string    `json:"parse_error,omitempty"`
+	Preview        string    `json:"preview"`
+	Payload        string    `json:"payload,omitempty"`
+}
+
+// RequeueResult is the outcome of requeuing a single dead letter
+type RequeueResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"time"
+)
+
+// Alert escalation statuses
+const (
+	// AlertEscalationActive is waiting for the next step
+	AlertEscalationActive = "active"
+	// AlertEscalationCompleted fired every step of its policy
+	AlertEscalationCompleted = "completed"
+	// AlertEscalationStopped ended because the alert was acknowledged or closed
+	AlertEscalationStopped = "stopped"
+)
+
+// EscalationPolicy escalates alerts that stay unacknowledged through ordered steps, such as
+// notifying the shift supervisor after 2 minutes and the building manager after 5 minutes
+type EscalationPolicy struct {
+	ID          uint      `gorm:"primaryKey;column:id" json:"id"`
+	Name        string    `gorm:"size:100;not null;uniqueIndex;column:name" json:"name"`
+	Description string    `gorm:"type:text;column:description" json:"description"`
+	Enabled     bool      `gorm:"not null;column:enabled" json:"enabled"`
+	CreatedAt   time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:updated_at" json:"updated_at"`
+
+	// Relationships
+	Steps []EscalationStep `gorm:"foreignKey:PolicyID" json:"steps"`
+}
+
+// TableName returns the table name for the EscalationPolicy model
+func (EscalationPolicy) TableName() string {
+	return "escalation_policies"
+}
+
+// EscalationStep fires once an alert has been unacknowledged for its delay. It notifies a
+// notification channel, raises the severity of the alert, or both.
+type EscalationStep struct {
+	ID           uint   `gorm:"primaryKey;column:id" json:"id"`
+	PolicyID     uint   `gorm:"not null;index;column:policy_id" json:"policy_id"`
+	Position     int    `gorm:"not null;column:position" json:"position"`
+	DelayMinutes int    `gorm:"not null;default:0;column:delay_minutes" json:"delay_minutes"`
+	Target       string `gorm:"size:100;column:target" json:"target"` // Who is notified, such as "Shift supervisor"
+	ChannelID    *uint  `gorm:"column:channel_id" json:"channel_id"`
+	Severity     string `gorm:"size:20;column:severity" json:"severity"` // Raised to, when higher than the current severity
+}
+
+// TableName returns the table name for the EscalationStep model
+func (EscalationStep) TableName() string {
+	return "escalation_steps"
+}
+
+// Delay returns how long an alert must stay unacknowledged before the step fires
+func (s *EscalationStep) Delay() time.Duration {
+	return time.Duration(s.DelayMinutes) * time.Minute
+}
+
+// AlertEscalation tracks the progress of an alert through the escalation policy of its type
+type AlertEscalation struct {
+	AlertID  string `gorm:"type:uuid;primaryKey;column:alert_id" json:"alert_id"`
+	PolicyID uint   `gorm:"not null;column:policy_id" json:"policy_id"`
+	Status   string `gorm:"size:20;not null;index;column:status" json:"status"`
+	// Level is the number of steps fired since StartedAt
+	Level      int        `gorm:"not null;default:0;column:level" json:"level"`
+	StartedAt  time.Time  `gorm:"type:timestamp with time zone;not null;column:started_at" json:"started_at"`
+	LastStepAt *time.Time `gorm:"type:timestamp with time zone;column:last_step_at" json:"last_step_at"`
+	UpdatedAt  time.Time  `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:updated_at" json:"updated_at"`
+}
+
+// TableName returns the table name for the AlertEscalation model
+func (AlertEscalation) TableName() string {
+	return "alert_escalations"
+}
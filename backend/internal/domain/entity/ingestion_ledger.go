@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+)
+
+// Ingestion ledger statuses
+const (
+	IngestionStatusProcessing = "processing"
+	IngestionStatusRetrying   = "retrying"
+	IngestionStatusProcessed  = "processed"
+	IngestionStatusFailed     = "failed"
+)
+
+// IngestionLedgerEntry records the ingestion of a single file, keyed by path and content hash
+type IngestionLedgerEntry struct {
+	ID          uint       `gorm:"primaryKey;column:id" json:"id"`
+	FilePath    string     `gorm:"type:text;not null;uniqueIndex:idx_ingestion_ledger_path_hash;column:file_path" json:"file_path"`
+	ContentHash string     `gorm:"size:64;not null;uniqueIndex:idx_ingestion_ledger_path_hash;column:content_hash" json:"content_hash"`
+	Handler     string     `gorm:"size:100;column:handler" json:"handler"`
+	Status      string     `gorm:"size:20;not null;index;column:status" json:"status"`
+	Attempts    int        `gorm:"default:0;column:attempts" json:"attempts"`
+	Error       string     `gorm:"type:text;column:error" json:"error,omitempty"`
+	StartedAt   *time.Time `gorm:"type:timestamp with time zone;column:started_at" json:"started_at"`
+	FinishedAt  *time.Time `gorm:"type:timestamp with time zone;column:finished_at" json:"finished_at"`
+	CreatedAt   time.Time  `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:updated_at" json:"updated_at"`
+}
+
+// TableName returns the table name for the IngestionLedgerEntry model
+func (IngestionLedgerEntry) TableName() string {
+	return "ingestion_ledger"
+}
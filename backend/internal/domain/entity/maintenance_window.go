@@ -0,0 +1,100 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AlertSuppressedByCameraMaintenance explains alerts of cameras whose status is maintenance
+const AlertSuppressedByCameraMaintenance = "camera maintenance"
+
+// MaintenanceWindow suppresses the alerts of a camera, zone or alert type, once between
+// StartsAt and EndsAt or every week on a schedule, such as cleaning crews at night. Suppressed
+// detections are stored and flagged but nobody is notified.
+type MaintenanceWindow struct {
+	ID          uint   `gorm:"primaryKey;column:id" json:"id"`
+	Name        string `gorm:"size:100;not null;uniqueIndex;column:name" json:"name"`
+	Description string `gorm:"type:text;column:description" json:"description"`
+	Enabled     bool   `gorm:"not null;column:enabled" json:"enabled"`
+
+	// Scope, every criterion set must match the alert
+	CameraID    *uint  `gorm:"index;column:camera_id" json:"camera_id"`
+	Zone        string `gorm:"size:100;column:zone" json:"zone"`
+	AlertTypeID *uint  `gorm:"column:alert_type_id" json:"alert_type_id"`
+
+	// One-off window, or the period a recurring window is in force. Either may be left open.
+	StartsAt *time.Time `gorm:"type:timestamp with time zone;column:starts_at" json:"starts_at"`
+	EndsAt   *time.Time `gorm:"type:timestamp with time zone;column:ends_at" json:"ends_at"`
+
+	// Recurring window in the site time zone, see AlertRule for the format. A window without a
+	// schedule lasts from StartsAt to EndsAt.
+	ScheduleDays  string `gorm:"size:50;column:schedule_days" json:"schedule_days"`
+	ScheduleStart string `gorm:"size:5;column:schedule_start" json:"schedule_start"`
+	ScheduleEnd   string `gorm:"size:5;column:schedule_end" json:"schedule_end"`
+
+	CreatedAt time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:updated_at" json:"updated_at"`
+}
+
+// TableName returns the table name for the MaintenanceWindow model
+func (MaintenanceWindow) TableName() string {
+	return "maintenance_windows"
+}
+
+// Recurring reports whether the window repeats on a weekly schedule
+func (w *MaintenanceWindow) Recurring() bool {
+	return w.ScheduleDays != "" || w.ScheduleStart != ""
+}
+
+// ValidateSchedule checks the period and the schedule of the window
+func (w *MaintenanceWindow) ValidateSchedule() error {
+	if !w.Recurring() && (w.StartsAt == nil || w.EndsAt == nil) {
+		return fmt.Errorf("invalid schedule, set starts_at and ends_at or a weekly schedule")
+	}
+
+	if w.StartsAt != nil && w.EndsAt != nil && !w.EndsAt.After(*w.StartsAt) {
+		return fmt.Errorf("invalid schedule, ends_at must be after starts_at")
+	}
+
+	return validateSchedule(w.ScheduleDays, w.ScheduleStart, w.ScheduleEnd)
+}
+
+// Active reports whether the window is in force at the given time in the given time zone
+func (w *MaintenanceWindow) Active(t time.Time, location *time.Location) bool {
+	if !w.Enabled {
+		return false
+	}
+
+	if w.StartsAt != nil && t.Before(*w.StartsAt) {
+		return false
+	}
+
+	if w.EndsAt != nil && !t.Before(*w.EndsAt) {
+		return false
+	}
+
+	if !w.Recurring() {
+		return true
+	}
+
+	return inSchedule(t.In(location), w.ScheduleDays, w.ScheduleStart, w.ScheduleEnd)
+}
+
+// Covers reports whether an alert of a camera falls within the scope of the window. camera may
+// be nil for alerts without a camera.
+func (w *MaintenanceWindow) Covers(alert *Alert, camera *Camera) bool {
+	if w.AlertTypeID != nil && *w.AlertTypeID != alert.AlertTypeID {
+		return false
+	}
+
+	if w.CameraID != nil && *w.CameraID != alert.CameraID {
+		return false
+	}
+
+	if w.Zone != "" && (camera == nil || !strings.EqualFold(camera.Location, w.Zone)) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,209 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// Notification channel types
+const (
+	// NotificationChannelWebhook posts a JSON payload signed with HMAC-SHA256
+	NotificationChannelWebhook = "webhook"
+	// NotificationChannelEmail sends an email through the configured SMTP server
+	NotificationChannelEmail = "email"
+	// NotificationChannelHTTP sends a templated HTTP request, for chat gateways such as
+	// Telegram or WhatsApp
+	NotificationChannelHTTP = "http"
+)
+
+// Notification events
+const (
+	NotificationEventCreated   = "created"
+	NotificationEventEscalated = "escalated"
+	NotificationEventResolved  = "resolved"
+	NotificationEventTest      = "test"
+	// NotificationEventEscalation is sent straight to a channel by an escalation policy step
+	NotificationEventEscalation = "escalation"
+)
+
+// Notification delivery statuses
+const (
+	NotificationStatusPending = "pending"
+	NotificationStatusSent    = "sent"
+	NotificationStatusFailed  = "failed"
+)
+
+// IsNotificationChannelType reports whether channelType is a valid channel type
+func IsNotificationChannelType(channelType string) bool {
+	switch channelType {
+	case NotificationChannelWebhook, NotificationChannelEmail, NotificationChannelHTTP:
+		return true
+	}
+	return false
+}
+
+// IsNotificationEvent reports whether event can be routed
+func IsNotificationEvent(event string) bool {
+	switch event {
+	case NotificationEventCreated, NotificationEventEscalated, NotificationEventResolved:
+		return true
+	}
+	return false
+}
+
+// IsNotificationStatus reports whether status is a valid delivery status
+func IsNotificationStatus(status string) bool {
+	switch status {
+	case NotificationStatusPending, NotificationStatusSent, NotificationStatusFailed:
+		return true
+	}
+	return false
+}
+
+// NotificationChannel is a destination for alert notifications. Templates use Go text/template
+// syntax over NotificationMessage, such as {{.AlertType}} at {{.Camera}}.
+type NotificationChannel struct {
+	ID      uint   `gorm:"primaryKey;column:id" json:"id"`
+	Name    string `gorm:"size:100;not null;uniqueIndex;column:name" json:"name"`
+	Type    string `gorm:"size:20;not null;column:type" json:"type"`
+	Enabled bool   `gorm:"not null;column:enabled" json:"enabled"`
+
+	// Webhook and HTTP channels
+	URL     string            `gorm:"size:500;column:url" json:"url"`
+	Method  string            `gorm:"size:10;column:method" json:"method"`
+	Headers map[string]string `gorm:"type:text;serializer:json;column:headers" json:"headers"`
+	Secret  string            `gorm:"size:255;column:secret" json:"-"` // Webhook signing key, never returned
+
+	// Email channels, recipients are comma separated
+	Recipients      string `gorm:"type:text;column:recipients" json:"recipients"`
+	SubjectTemplate string `gorm:"type:text;column:subject_template" json:"subject_template"`
+
+	// Body of email and HTTP channels, webhooks always post the JSON payload
+	BodyTemplate string `gorm:"type:text;column:body_template" json:"body_template"`
+
+	CreatedAt time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:updated_at" json:"updated_at"`
+}
+
+// TableName returns the table name for the NotificationChannel model
+func (NotificationChannel) TableName() string {
+	return "notification_channels"
+}
+
+// RecipientList returns the email recipients of the channel
+func (c *NotificationChannel) RecipientList() []string {
+	var recipients []string
+	for _, recipient := range strings.Split(c.Recipients, ",") {
+		recipient = strings.TrimSpace(recipient)
+		if recipient != "" {
+			recipients = append(recipients, recipient)
+		}
+	}
+	return recipients
+}
+
+// NotificationRoute sends the alerts it matches to a channel. Empty criteria match everything.
+type NotificationRoute struct {
+	ID          uint   `gorm:"primaryKey;column:id" json:"id"`
+	Name        string `gorm:"size:100;not null;column:name" json:"name"`
+	ChannelID   uint   `gorm:"not null;index;column:channel_id" json:"channel_id"`
+	AlertTypeID *uint  `gorm:"column:alert_type_id" json:"alert_type_id"`
+	CameraID    *uint  `gorm:"column:camera_id" json:"camera_id"`
+	MinSeverity string `gorm:"size:20;column:min_severity" json:"min_severity"`
+	// Events is a comma separated list of created, escalated and resolved, created and
+	// escalated when empty
+	Events    string    `gorm:"size:100;column:events" json:"events"`
+	Enabled   bool      `gorm:"not null;column:enabled" json:"enabled"`
+	CreatedAt time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:updated_at" json:"updated_at"`
+
+	Channel *NotificationChannel `gorm:"-" json:"channel,omitempty"`
+}
+
+// TableName returns the table name for the NotificationRoute model
+func (NotificationRoute) TableName() string {
+	return "notification_routes"
+}
+
+// EventList returns the events routed, created and escalated by default
+func (r *NotificationRoute) EventList() []string {
+	var events []string
+	for _, event := range strings.Split(r.Events, ",") {
+		event = strings.ToLower(strings.TrimSpace(event))
+		if event != "" {
+			events = append(events, event)
+		}
+	}
+
+	if len(events) == 0 {
+		return []string{NotificationEventCreated, NotificationEventEscalated}
+	}
+	return events
+}
+
+// Matches reports whether an event of an alert is sent through the route
+func (r *NotificationRoute) Matches(alert *Alert, event string) bool {
+	if !r.Enabled {
+		return false
+	}
+
+	if r.AlertTypeID != nil && *r.AlertTypeID != alert.AlertTypeID {
+		return false
+	}
+
+	if r.CameraID != nil && *r.CameraID != alert.CameraID {
+		return false
+	}
+
+	if r.MinSeverity != "" && AlertSeverityRank(alert.Severity) < AlertSeverityRank(r.MinSeverity) {
+		return false
+	}
+
+	for _, routed := range r.EventList() {
+		if routed == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NotificationDelivery records sending one alert event through one channel
+type NotificationDelivery struct {
+	ID             uint       `gorm:"primaryKey;column:id" json:"id"`
+	ChannelID      uint       `gorm:"not null;index;column:channel_id" json:"channel_id"`
+	RouteID        *uint      `gorm:"column:route_id" json:"route_id"` // Empty for test and escalation deliveries
+	AlertID        string     `gorm:"size:36;index;column:alert_id" json:"alert_id"`
+	Event          string     `gorm:"size:20;not null;column:event" json:"event"`
+	Note           string     `gorm:"type:text;column:note" json:"note,omitempty"`
+	Status         string     `gorm:"size:20;not null;default:pending;index;column:status" json:"status"`
+	Attempts       int        `gorm:"not null;default:0;column:attempts" json:"attempts"`
+	LastError      string     `gorm:"type:text;column:last_error" json:"last_error"`
+	ResponseStatus int        `gorm:"column:response_status" json:"response_status"`
+	NextAttemptAt  *time.Time `gorm:"type:timestamp with time zone;index;column:next_attempt_at" json:"next_attempt_at"`
+	DeliveredAt    *time.Time `gorm:"type:timestamp with time zone;column:delivered_at" json:"delivered_at"`
+	CreatedAt      time.Time  `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:updated_at" json:"updated_at"`
+}
+
+// TableName returns the table name for the NotificationDelivery model
+func (NotificationDelivery) TableName() string {
+	return "notification_deliveries"
+}
+
+// NotificationMessage is the content of a notification, the data of channel templates and the
+// alert of webhook payloads
+type NotificationMessage struct {
+	Event       string    `json:"event"`
+	AlertID     string    `json:"alert_id"`
+	AlertType   string    `json:"alert_type"`
+	CameraID    uint      `json:"camera_id"`
+	Camera      string    `json:"camera"`
+	Severity    string    `json:"severity"`
+	Status      string    `json:"status"`
+	Message     string    `json:"message"`
+	Note        string    `json:"note,omitempty"` // Why the notification was sent, such as an escalation step
+	Occurrences int       `json:"occurrence_count"`
+	DetectedAt  time.Time `json:"detected_at"`
+	ImageURL    string    `json:"image_url,omitempty"`
+}
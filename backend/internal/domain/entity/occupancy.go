@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// OccupancyReset is the time of day at which live occupancy restarts from zero
+type OccupancyReset struct {
+	Hour     int
+	Minute   int
+	Location *time.Location
+}
+
+// ParseOccupancyReset parses a reset time such as "04:30" in the given time zone
+func ParseOccupancyReset(resetTime, timezone string) (OccupancyReset, error) {
+	parsed, err := time.Parse("15:04", resetTime)
+	if err != nil {
+		return OccupancyReset{}, fmt.Errorf("invalid reset time %q, use HH:MM", resetTime)
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return OccupancyReset{}, fmt.Errorf("invalid time zone %q: %w", timezone, err)
+	}
+
+	return OccupancyReset{
+		Hour:     parsed.Hour(),
+		Minute:   parsed.Minute(),
+		Location: location,
+	}, nil
+}
+
+// LastReset returns the most recent reset at or before now
+func (r OccupancyReset) LastReset(now time.Time) time.Time {
+	location := r.Location
+	if location == nil {
+		location = time.Local
+	}
+
+	local := now.In(location)
+	reset := time.Date(local.Year(), local.Month(), local.Day(), r.Hour, r.Minute, 0, 0, location)
+	if reset.After(local) {
+		reset = reset.AddDate(0, 0, -1)
+	}
+
+	return reset
+}
+
+// String formats the reset time as HH:MM
+func (r OccupancyReset) String() string {
+	return fmt.Sprintf("%02d:%02d", r.Hour, r.Minute)
+}
+
+// CameraOccupancy is the people flow through a camera since the last reset
+type CameraOccupancy struct {
+	CameraID    uint      `json:"camera_id"`
+	CameraName  string    `json:"camera_name"`
+	Zone        string    `json:"zone"`
+	InCount     int       `json:"in_count"`
+	OutCount    int       `json:"out_count"`
+	Occupancy   int       `json:"occupancy"`
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+// ZoneOccupancy is the occupancy of a zone. For zones of the hierarchy it is the people who
+// entered less those who left through its entrance and exit cameras, including the zones inside
+// it. Cameras in no zone are grouped by location instead, summing their occupancy, and have no
+// zone ID.
+type ZoneOccupancy struct {
+	ZoneID      *uint     `json:"zone_id"`
+	ParentID    *uint     `json:"parent_id,omitempty"`
+	Kind        string    `json:"kind,omitempty"`
+	Zone        string    `json:"zone"`
+	Capacity    int       `json:"capacity,omitempty"`
+	InCount     int       `json:"in_count"`
+	OutCount    int       `json:"out_count"`
+	Occupancy   int       `json:"occupancy"`
+	CameraIDs   []uint    `json:"camera_ids"`
+	LastUpdated time.Time `json:"last_updated"`
+}
+
+// OccupancySummary is the live occupancy per camera and per zone
+type OccupancySummary struct {
+	Since     time.Time         `json:"since"`
+	ResetTime string            `json:"reset_time"`
+	Cameras   []CameraOccupancy `json:"cameras"`
+	Zones     []ZoneOccupancy   `json:"zones"`
+	Total     int               `json:"total"`
+}
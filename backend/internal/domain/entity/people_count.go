@@ -14,9 +14,14 @@ type PeopleCount struct {
 	ChildCount   int       `gorm:"default:0;column:child_count" json:"child_count"`
 	AdultCount   int       `gorm:"default:0;column:adult_count" json:"adult_count"`
 	ElderlyCount int       `gorm:"default:0;column:elderly_count" json:"elderly_count"`
+	InCount      int       `gorm:"default:0;column:in_count" json:"in_count"`
+	OutCount     int       `gorm:"default:0;column:out_count" json:"out_count"`
 	TotalCount   int       `gorm:"->;column:total_count" json:"total_count"`
 	CreatedAt    time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
 
+	// Version of the camera configuration the count was made with, nil when unknown
+	ConfigVersion *int `gorm:"column:config_version" json:"config_version"`
+
 	// Relationships
 	Camera Camera `gorm:"foreignKey:CameraID" json:"camera,omitempty"`
 }
@@ -46,8 +51,18 @@ type TotalCounts struct {
 
 // CountSummary represents the current count summary across all areas
 type CountSummary struct {
-	Cameras []CameraSummary `json:"cameras"`
-	Totals  TotalCounts     `json:"totals"`
+	Cameras []CameraSummary    `json:"cameras"`
+	Zones   []ZoneCountSummary `json:"zones"`
+	Totals  TotalCounts        `json:"totals"`
+}
+
+// ZoneCountSummary is the people counted by the cameras of a zone and the zones inside it
+type ZoneCountSummary struct {
+	ZoneID    uint        `json:"zone_id"`
+	Name      string      `json:"name"`
+	Kind      string      `json:"kind"`
+	CameraIDs []uint      `json:"camera_ids"`
+	Totals    TotalCounts `json:"totals"`
 }
 
 type CameraSummary struct {
@@ -71,6 +86,8 @@ type TrendPoint struct {
 	ChildCount   int       `json:"child_count"`
 	AdultCount   int       `json:"adult_count"`
 	ElderlyCount int       `json:"elderly_count"`
+	InCount      int       `json:"in_count"`
+	OutCount     int       `json:"out_count"`
 }
 
 // CountsByTimeResult is a time-based grouping of counts
@@ -0,0 +1,95 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var scheduleDays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// validateSchedule checks the days and times of a weekly schedule
+func validateSchedule(days, start, end string) error {
+	for _, day := range splitScheduleDays(days) {
+		if _, ok := scheduleDays[day]; !ok {
+			return fmt.Errorf("invalid schedule day %q, use sun, mon, tue, wed, thu, fri, or sat", day)
+		}
+	}
+
+	if (start == "") != (end == "") {
+		return fmt.Errorf("invalid schedule, start and end must be set together")
+	}
+
+	for _, value := range []string{start, end} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse("15:04", value); err != nil {
+			return fmt.Errorf("invalid schedule time %q, use HH:MM", value)
+		}
+	}
+
+	return nil
+}
+
+// inSchedule reports whether a time falls in a weekly schedule. Days are comma separated
+// (mon,tue,...), empty for every day. Start and end are HH:MM, empty for the whole day, an end
+// before the start spans midnight.
+func inSchedule(t time.Time, days, start, end string) bool {
+	day := t.Weekday()
+
+	if start != "" && end != "" {
+		startTime, _ := time.Parse("15:04", start)
+		endTime, _ := time.Parse("15:04", end)
+
+		minute := t.Hour()*60 + t.Minute()
+		startMinute := startTime.Hour()*60 + startTime.Minute()
+		endMinute := endTime.Hour()*60 + endTime.Minute()
+
+		if startMinute <= endMinute {
+			if minute < startMinute || minute >= endMinute {
+				return false
+			}
+		} else {
+			// Overnight, the part after midnight belongs to the schedule of the previous day
+			if minute < startMinute && minute >= endMinute {
+				return false
+			}
+			if minute < endMinute {
+				day = (day + 6) % 7
+			}
+		}
+	}
+
+	dayNames := splitScheduleDays(days)
+	if len(dayNames) == 0 {
+		return true
+	}
+
+	for _, name := range dayNames {
+		if scheduleDays[name] == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+func splitScheduleDays(days string) []string {
+	var result []string
+	for _, day := range strings.Split(days, ",") {
+		day = strings.ToLower(strings.TrimSpace(day))
+		if day != "" {
+			result = append(result, day)
+		}
+	}
+	return result
+}
@@ -20,6 +20,9 @@ type VehicleCount struct {
 	TotalVehicleInCount int       `gorm:"->;column:total_vehicle_in_count" json:"total_vehicle_in_count"`
 	CreatedAt           time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
 
+	// Version of the camera configuration the count was made with, nil when unknown
+	ConfigVersion *int `gorm:"column:config_version" json:"config_version"`
+
 	// Relationships
 	Cctv Camera `gorm:"foreignKey:CctvID" json:"cctv,omitempty"`
 }
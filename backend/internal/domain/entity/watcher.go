@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+)
+
+// WatcherStats is a snapshot of the folder watcher metrics
+type WatcherStats struct {
+	Trigger             string               `json:"trigger"`
+	Running             bool                 `json:"running"`
+	MaxWorkers          int                  `json:"max_workers"`
+	MaxWorkersPerFolder int                  `json:"max_workers_per_folder"`
+	ActiveWorkers       int                  `json:"active_workers"`
+	Folders             []WatcherFolderStats `json:"folders"`
+}
+
+// WatcherFolderStats holds the metrics of a single watched folder
+type WatcherFolderStats struct {
+	Name            string     `json:"name"`
+	Path            string     `json:"path"`
+	Handler         string     `json:"handler"`
+	InFlight        int        `json:"in_flight"`
+	Pending         int        `json:"pending"`
+	Retrying        int        `json:"retrying"`
+	Processed       int64      `json:"processed"`
+	Failed          int64      `json:"failed"`
+	Retried         int64      `json:"retried"`
+	AvgDurationMs   float64    `json:"avg_duration_ms"`
+	LastScanAt      *time.Time `json:"last_scan_at"`
+	LastProcessedAt *time.Time `json:"last_processed_at"`
+}
@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"time"
+)
+
+// Zone kinds, from the top of the hierarchy down
+const (
+	ZoneKindSite     = "site"
+	ZoneKindBuilding = "building"
+	ZoneKindFloor    = "floor"
+	ZoneKindZone     = "zone"
+)
+
+// ZoneKinds lists the zone kinds from the top of the hierarchy down
+var ZoneKinds = []string{ZoneKindSite, ZoneKindBuilding, ZoneKindFloor, ZoneKindZone}
+
+// Directions of a counting camera relative to a zone
+const (
+	ZoneDirectionEntrance = "entrance" // People counted in enter the zone
+	ZoneDirectionExit     = "exit"     // People counted in leave the zone
+	ZoneDirectionInternal = "internal" // People move within the zone
+)
+
+// Zone is a site, building, floor or zone. Every zone but a site is inside a zone of a higher
+// kind, the kinds in between may be skipped.
+type Zone struct {
+	ID          uint   `gorm:"primaryKey;column:id" json:"id"`
+	ParentID    *uint  `gorm:"index;column:parent_id" json:"parent_id"`
+	Kind        string `gorm:"size:20;not null;column:kind" json:"kind"`
+	Name        string `gorm:"size:100;not null;column:name" json:"name"`
+	Description string `gorm:"type:text;column:description" json:"description"`
+	Capacity    int    `gorm:"default:0;column:capacity" json:"capacity"` // Maximum occupancy, 0 when unknown
+
+	// Floor plan image of the zone, the file name in the floor plan directory and its size in
+	// pixels. Cameras of the zone are positioned on it.
+	FloorPlan       string `gorm:"size:255;column:floor_plan" json:"floor_plan,omitempty"`
+	FloorPlanWidth  int    `gorm:"default:0;column:floor_plan_width" json:"floor_plan_width,omitempty"`
+	FloorPlanHeight int    `gorm:"default:0;column:floor_plan_height" json:"floor_plan_height,omitempty"`
+
+	CreatedAt time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:updated_at" json:"updated_at"`
+
+	// Relationships
+	Cameras  []ZoneCamera `gorm:"foreignKey:ZoneID" json:"cameras,omitempty"`
+	Children []Zone       `gorm:"-" json:"children,omitempty"`
+}
+
+// TableName returns the table name for the Zone model
+func (Zone) TableName() string {
+	return "zones"
+}
+
+// ZoneKindLevel returns the depth of a zone kind in the hierarchy, sites first, and 0 for
+// unknown kinds
+func ZoneKindLevel(kind string) int {
+	for i, k := range ZoneKinds {
+		if k == kind {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// ZoneCamera places a camera in a zone. A camera on the boundary of two zones belongs to both,
+// as the entrance of one and the exit of the other.
+type ZoneCamera struct {
+	ID        uint      `gorm:"primaryKey;column:id" json:"id"`
+	ZoneID    uint      `gorm:"not null;uniqueIndex:idx_zone_cameras_zone_camera;column:zone_id" json:"zone_id"`
+	CameraID  uint      `gorm:"not null;uniqueIndex:idx_zone_cameras_zone_camera;index;column:camera_id" json:"camera_id"`
+	Direction string    `gorm:"size:20;not null;default:internal;column:direction" json:"direction"`
+	CreatedAt time.Time `gorm:"type:timestamp with time zone;default:CURRENT_TIMESTAMP;column:created_at" json:"created_at"`
+
+	// Position of the camera on the floor plan of the zone in pixels from its top left corner,
+	// nil when it is not placed
+	PlanX *float64 `gorm:"column:plan_x" json:"plan_x"`
+	PlanY *float64 `gorm:"column:plan_y" json:"plan_y"`
+
+	// Relationships
+	Camera *Camera `gorm:"foreignKey:CameraID" json:"camera,omitempty"`
+	Zone   *Zone   `gorm:"foreignKey:ZoneID" json:"zone,omitempty"`
+}
+
+// TableName returns the table name for the ZoneCamera model
+func (ZoneCamera) TableName() string {
+	return "zone_cameras"
+}
+
+// IsZoneDirection reports whether direction is a known camera direction
+func IsZoneDirection(direction string) bool {
+	switch direction {
+	case ZoneDirectionEntrance, ZoneDirectionExit, ZoneDirectionInternal:
+		return true
+	}
+	return false
+}
+
+// Flow returns how many people entered and left the zone given the people the camera counted
+// in and out. Internal cameras see no one enter or leave.
+func (m ZoneCamera) Flow(in, out int) (entered, left int) {
+	switch m.Direction {
+	case ZoneDirectionEntrance:
+		return in, out
+	case ZoneDirectionExit:
+		return out, in
+	}
+	return 0, 0
+}
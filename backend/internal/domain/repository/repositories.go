@@ -11,10 +11,14 @@ import (
 type CameraRepository interface {
 	FindAll(ctx context.Context, filters map[string]interface{}) ([]entity.Camera, error)
 	FindByID(ctx context.Context, id uint) (*entity.Camera, error)
-	FindByArea(ctx context.Context, areaID uint) ([]entity.Camera, error)
+	FindByZone(ctx context.Context, zoneID uint) ([]entity.Camera, error)
 	Create(ctx context.Context, camera *entity.Camera) error
 	Update(ctx context.Context, camera *entity.Camera) error
-	UpdateStatus(ctx context.Context, id uint, status string) error
+	ChangeStatus(ctx context.Context, event *entity.CameraStatusEvent) (bool, error)
+	FindStatusEvents(ctx context.Context, page, limit int, filters map[string]interface{}) ([]entity.CameraStatusEvent, int64, error)
+	FindLatestStatusEvents(ctx context.Context) ([]entity.CameraStatusEvent, error)
+	FindStatusTimeline(ctx context.Context, from, to time.Time, filters map[string]interface{}) ([]entity.CameraStatusEvent, error)
+	FindLastActivity(ctx context.Context, since time.Time) ([]entity.CameraActivity, error)
 	Delete(ctx context.Context, id uint) error
 }
 
@@ -22,7 +26,7 @@ type CameraRepository interface {
 type PeopleCountRepository interface {
 	FindAll(ctx context.Context, page, limit int, filters map[string]interface{}) ([]entity.PeopleCount, int64, error)
 	FindByID(ctx context.Context, id string) (*entity.PeopleCount, error)
-	FindByArea(ctx context.Context, areaID uint, from, to time.Time, limit int) ([]entity.PeopleCount, error)
+	FindByZone(ctx context.Context, zoneID uint, from, to time.Time, limit int) ([]entity.PeopleCount, error)
 	Create(ctx context.Context, count *entity.PeopleCount) error
 	Update(ctx context.Context, count *entity.PeopleCount) error
 	GetSummary(ctx context.Context, filters map[string]interface{}) (*entity.CountSummary, error)
@@ -31,6 +35,9 @@ type PeopleCountRepository interface {
 	GetDistributionByGender(ctx context.Context, timeWindow time.Time) (*entity.TotalCounts, error)
 	GetDistributionByAge(ctx context.Context, timeWindow time.Time) (*entity.TotalCounts, error)
 	GetPeakHoursAnalysis(ctx context.Context, filters map[string]interface{}) (*entity.PeakHoursAnalysis, error)
+	GetFlowSince(ctx context.Context, since time.Time, filters map[string]interface{}) ([]entity.CameraOccupancy, error)
+	GetTotalsBetween(ctx context.Context, from, to time.Time, filters map[string]interface{}) (*entity.TrendPoint, error)
+	GetAggregates(ctx context.Context, interval string, from, to time.Time, filters map[string]interface{}) ([]entity.TrendPoint, error)
 }
 
 type VehicleCountRepository interface {
@@ -48,6 +55,31 @@ type VehicleCountRepository interface {
 	GetLatestByCctv(ctx context.Context, cctvID uint) (*entity.VehicleCount, error)
 	GetCountsByTimeRange(ctx context.Context, from, to time.Time, cctvID *uint) ([]entity.VehicleCount, error)
 	GetPeakHours(ctx context.Context, cctvID *uint, days int) ([]entity.VehicleTrendPoint, error)
+	GetTotalsBetween(ctx context.Context, from, to time.Time, filters map[string]interface{}) (*entity.VehicleTotalCounts, error)
+}
+
+// ZoneRepository defines the interface for zone and zone camera data operations
+type ZoneRepository interface {
+	FindAll(ctx context.Context, filters map[string]interface{}) ([]entity.Zone, error)
+	FindByID(ctx context.Context, id uint) (*entity.Zone, error)
+	FindByName(ctx context.Context, parentID *uint, name string) (*entity.Zone, error)
+	Create(ctx context.Context, zone *entity.Zone) error
+	Update(ctx context.Context, zone *entity.Zone) error
+	Delete(ctx context.Context, id uint) error
+	SetFloorPlan(ctx context.Context, id uint, floorPlan string, width, height int) error
+	CountChildren(ctx context.Context, id uint) (int64, error)
+	SetCameras(ctx context.Context, zoneID uint, cameras []entity.ZoneCamera) error
+	SaveCamera(ctx context.Context, membership *entity.ZoneCamera) error
+	RemoveCamera(ctx context.Context, zoneID, cameraID uint) error
+}
+
+// CameraConfigRepository defines the interface for camera configuration data operations
+type CameraConfigRepository interface {
+	FindCurrent(ctx context.Context, cameraID uint) (*entity.CameraConfig, error)
+	FindVersions(ctx context.Context, cameraID uint) ([]entity.CameraConfig, error)
+	FindVersion(ctx context.Context, cameraID uint, version int) (*entity.CameraConfig, error)
+	Create(ctx context.Context, config *entity.CameraConfig) error
+	Archive(ctx context.Context, cameraID uint) error
 }
 
 // AlertTypeRepository defines the interface for alert type data operations
@@ -56,6 +88,19 @@ type AlertTypeRepository interface {
 	FindByID(ctx context.Context, id uint) (*entity.AlertType, error)
 	Create(ctx context.Context, alertType *entity.AlertType) (*entity.AlertType, error)
 	FindByName(ctx context.Context, typeName string) (*entity.AlertType, error)
+	FindMessages(ctx context.Context, alertTypeID uint) ([]entity.AlertTypeMessage, error)
+	FindMessagesByLanguage(ctx context.Context, language string) ([]entity.AlertTypeMessage, error)
+	UpdateMessages(ctx context.Context, alertTypeID uint, severity string, messages []entity.AlertTypeMessage) error
+	Update(ctx context.Context, alertType *entity.AlertType) error
+	SetArchived(ctx context.Context, id uint, archivedAt *time.Time) error
+	Delete(ctx context.Context, id uint) error
+	CountReferences(ctx context.Context, id uint) (int64, error)
+	Merge(ctx context.Context, sourceID, targetID uint) (int64, error)
+	RefreshDailyCounts(ctx context.Context) error
+	FindAliases(ctx context.Context) ([]entity.AlertTypeAlias, error)
+	FindAliasByName(ctx context.Context, name string) (*entity.AlertTypeAlias, error)
+	CreateAlias(ctx context.Context, alias *entity.AlertTypeAlias) error
+	DeleteAlias(ctx context.Context, id uint) error
 }
 
 // AlertRepository defines the interface for alert data operations
@@ -63,9 +108,20 @@ type AlertRepository interface {
 	FindAll(ctx context.Context, page, limit int, filters map[string]interface{}) ([]entity.Alert, int64, error)
 	FindByID(ctx context.Context, id string) (*entity.Alert, error)
 	FindActive(ctx context.Context, page, limit int, filters map[string]interface{}) ([]entity.Alert, int64, int64, error)
+	CountActiveByCamera(ctx context.Context) (map[uint]int64, error)
 	Create(ctx context.Context, alert *entity.Alert) error
 	Update(ctx context.Context, alert *entity.Alert) error
-	Resolve(ctx context.Context, id string, resolvedBy, note string) error
+	SetClipPath(ctx context.Context, id, clipPath string) error
+	ApplyEvent(ctx context.Context, id, expectedStatus string, updates map[string]interface{}, event *entity.AlertEvent) error
+	CreateEvent(ctx context.Context, event *entity.AlertEvent) error
+	FindEvents(ctx context.Context, alertID string) ([]entity.AlertEvent, error)
+	FindCorrelated(ctx context.Context, cameraID, alertTypeID uint, objectID string, from, to time.Time) (*entity.Alert, error)
+	FindOccurrenceBySource(ctx context.Context, sourceID string) (*entity.AlertOccurrence, error)
+	AddOccurrence(ctx context.Context, id string, occurrence *entity.AlertOccurrence, escalation *entity.AlertEvent) error
+	FindOccurrences(ctx context.Context, alertID string) ([]entity.AlertOccurrence, error)
+	GetDailyCounts(ctx context.Context, from, to time.Time, filters map[string]interface{}) ([]entity.AlertDailyCount, error)
+	GetHourOfDayCounts(ctx context.Context, from, to time.Time, timezone string, filters map[string]interface{}) ([]entity.AlertHourStat, error)
+	GetCameraOutcomes(ctx context.Context, from, to time.Time, filters map[string]interface{}) ([]entity.AlertCameraOutcome, error)
 }
 
 type FaceRecognitionRepository interface {
@@ -74,3 +130,84 @@ type FaceRecognitionRepository interface {
 	Create(ctx context.Context, alert *entity.FaceRecognition) error
 	Update(ctx context.Context, alert *entity.FaceRecognition) error
 }
+
+// IngestionLedgerRepository defines the interface for ingestion ledger data operations
+type IngestionLedgerRepository interface {
+	FindAll(ctx context.Context, page, limit int, filters map[string]interface{}) ([]entity.IngestionLedgerEntry, int64, error)
+	FindByPathAndHash(ctx context.Context, filePath, contentHash string) (*entity.IngestionLedgerEntry, error)
+	Begin(ctx context.Context, filePath, contentHash, handler string) (*entity.IngestionLedgerEntry, error)
+	Finish(ctx context.Context, filePath, contentHash, status, errText string) error
+	Delete(ctx context.Context, filePath, contentHash string) error
+}
+
+// CameraDeviceRepository defines the interface for camera device mapping data operations
+type CameraDeviceRepository interface {
+	FindAll(ctx context.Context, page, limit int, filters map[string]interface{}) ([]entity.CameraDevice, int64, error)
+	FindByID(ctx context.Context, id uint) (*entity.CameraDevice, error)
+	FindBySerial(ctx context.Context, serial string, channel int) (*entity.CameraDevice, error)
+	FindByLegacyID(ctx context.Context, legacyID uint) (*entity.CameraDevice, error)
+	Create(ctx context.Context, device *entity.CameraDevice) error
+	Register(ctx context.Context, device *entity.CameraDevice, camera *entity.Camera) error
+	Update(ctx context.Context, device *entity.CameraDevice) error
+	Touch(ctx context.Context, id uint, seenAt time.Time) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// AlertRuleRepository defines the interface for alert rule data operations
+type AlertRuleRepository interface {
+	FindAll(ctx context.Context, filters map[string]interface{}) ([]entity.AlertRule, error)
+	FindByID(ctx context.Context, id uint) (*entity.AlertRule, error)
+	FindByName(ctx context.Context, name string) (*entity.AlertRule, error)
+	Create(ctx context.Context, rule *entity.AlertRule) error
+	Update(ctx context.Context, rule *entity.AlertRule) error
+	MarkTriggered(ctx context.Context, id uint, triggeredAt time.Time) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// NotificationRepository defines the interface for notification channel, route and delivery
+// data operations
+type NotificationRepository interface {
+	FindChannels(ctx context.Context) ([]entity.NotificationChannel, error)
+	FindChannelByID(ctx context.Context, id uint) (*entity.NotificationChannel, error)
+	FindChannelByName(ctx context.Context, name string) (*entity.NotificationChannel, error)
+	CreateChannel(ctx context.Context, channel *entity.NotificationChannel) error
+	UpdateChannel(ctx context.Context, channel *entity.NotificationChannel) error
+	DeleteChannel(ctx context.Context, id uint) error
+	FindRoutes(ctx context.Context, filters map[string]interface{}) ([]entity.NotificationRoute, error)
+	FindRouteByID(ctx context.Context, id uint) (*entity.NotificationRoute, error)
+	CreateRoute(ctx context.Context, route *entity.NotificationRoute) error
+	UpdateRoute(ctx context.Context, route *entity.NotificationRoute) error
+	DeleteRoute(ctx context.Context, id uint) error
+	FindDeliveries(ctx context.Context, page, limit int, filters map[string]interface{}) ([]entity.NotificationDelivery, int64, error)
+	FindDeliveryByID(ctx context.Context, id uint) (*entity.NotificationDelivery, error)
+	FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.NotificationDelivery, error)
+	CreateDelivery(ctx context.Context, delivery *entity.NotificationDelivery) error
+	ClaimDelivery(ctx context.Context, id uint, attempts int, leaseUntil time.Time) (bool, error)
+	UpdateDelivery(ctx context.Context, delivery *entity.NotificationDelivery) error
+}
+
+// EscalationRepository defines the interface for escalation policy and alert escalation data
+// operations
+type EscalationRepository interface {
+	FindPolicies(ctx context.Context) ([]entity.EscalationPolicy, error)
+	FindPolicyByID(ctx context.Context, id uint) (*entity.EscalationPolicy, error)
+	FindPolicyByName(ctx context.Context, name string) (*entity.EscalationPolicy, error)
+	CreatePolicy(ctx context.Context, policy *entity.EscalationPolicy) error
+	UpdatePolicy(ctx context.Context, policy *entity.EscalationPolicy) error
+	DeletePolicy(ctx context.Context, id uint) error
+	SetAlertTypePolicy(ctx context.Context, alertTypeID uint, policyID *uint) error
+	FindUnacknowledgedAlerts(ctx context.Context, alertTypeIDs []uint) ([]entity.Alert, error)
+	FindActiveEscalations(ctx context.Context) ([]entity.AlertEscalation, error)
+	FindEscalation(ctx context.Context, alertID string) (*entity.AlertEscalation, error)
+	SaveEscalation(ctx context.Context, escalation *entity.AlertEscalation) error
+}
+
+// MaintenanceWindowRepository defines the interface for maintenance window data operations
+type MaintenanceWindowRepository interface {
+	FindAll(ctx context.Context, filters map[string]interface{}) ([]entity.MaintenanceWindow, error)
+	FindByID(ctx context.Context, id uint) (*entity.MaintenanceWindow, error)
+	FindByName(ctx context.Context, name string) (*entity.MaintenanceWindow, error)
+	Create(ctx context.Context, window *entity.MaintenanceWindow) error
+	Update(ctx context.Context, window *entity.MaintenanceWindow) error
+	Delete(ctx context.Context, id uint) error
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"people-counting/internal/domain/entity"
@@ -25,16 +26,17 @@ type CameraService interface {
 
 // PeopleCountService defines the interface for people count business logic
 type PeopleCountService interface {
-	GetAllCounts(ctx context.Context, page, limit int, areaID, from, to string, includeArea bool) ([]entity.PeopleCount, int64, error)
+	GetAllCounts(ctx context.Context, page, limit int, zoneID, from, to string, includeZones bool) ([]entity.PeopleCount, int64, error)
 	RecordCount(ctx context.Context, count *entity.PeopleCount) error
-	GetCountsSummary(ctx context.Context, from, to string) (*entity.CountSummary, error)
-	GetCountsTrend(ctx context.Context, interval string, areaID, from, to string) (*entity.CountsByTimeResult, error)
+	GetCountsSummary(ctx context.Context, zoneID, from, to string) (*entity.CountSummary, error)
+	GetCountsTrend(ctx context.Context, interval string, zoneID, from, to string) (*entity.CountsByTimeResult, error)
 	GetCountsDistribution(ctx context.Context, distType, timeWindow string) (interface{}, error)
 	CreatePeopleCount(ctx context.Context, counting *entity.PeopleCount) error
 	UpdatePeopleCount(ctx context.Context, counting *entity.PeopleCount) error
 	GetByID(ctx context.Context, id string) (*entity.PeopleCount, error)
 	GetAlertByID(ctx context.Context, id string) (*entity.PeopleCount, error)
 	GetPeakHoursAnalysis(ctx context.Context, cameraID string, from, to string) (*entity.PeakHoursAnalysis, error)
+	GetOccupancy(ctx context.Context, cameraID, zone, zoneID string) (*entity.OccupancySummary, error)
 }
 
 // VehicleCountService defines the interface for vehicle count service operations
@@ -63,27 +65,108 @@ type VehicleCountService interface {
 
 // AlertTypeService defines the interface for alert type business logic
 type AlertTypeService interface {
-	GetAllAlertTypes(ctx context.Context) ([]entity.AlertType, error)
+	GetAllAlertTypes(ctx context.Context, includeArchived bool) ([]entity.AlertType, error)
+	GetAlertTypeByID(ctx context.Context, id uint) (*entity.AlertType, error)
 	GetAlertTypeByName(ctx context.Context, typeName string) (*entity.AlertType, error)
 	CreateAlertType(ctx context.Context, alertType *entity.AlertType) (*entity.AlertType, error)
+	UpdateAlertType(ctx context.Context, alertType *entity.AlertType) (*entity.AlertType, error)
+	ArchiveAlertType(ctx context.Context, id uint) (*entity.AlertType, error)
+	RestoreAlertType(ctx context.Context, id uint) (*entity.AlertType, error)
+	DeleteAlertType(ctx context.Context, id uint) error
+	MergeAlertTypes(ctx context.Context, sourceID, targetID uint) (*entity.AlertTypeMerge, error)
+	ResolveAlertType(ctx context.Context, typeName string) (*entity.AlertType, error)
+	GetAliases(ctx context.Context) ([]entity.AlertTypeAlias, error)
+	CreateAlias(ctx context.Context, alertTypeID uint, name string) (*entity.AlertTypeAlias, error)
+	DeleteAlias(ctx context.Context, id uint) error
+	GetAlertTypeMessages(ctx context.Context, id uint) (*entity.AlertType, error)
+	UpdateAlertTypeMessages(ctx context.Context, id uint, severity string, templates map[string]string) (*entity.AlertType, error)
 }
 
 // AlertService defines the interface for alert business logic
 type AlertService interface {
-	GetAllAlerts(ctx context.Context, page, limit int, isActive, alertTypeID, cameraID, from, to, search, severity, status string, includeRelations bool) ([]entity.Alert, int64, error)
-	GetActiveAlerts(ctx context.Context, page, limit int, alertTypeID, cameraID, from, to string, includeRelations bool) ([]entity.Alert, int64, int64, error)
+	GetAllAlerts(ctx context.Context, page, limit int, isActive, alertTypeID, cameraID, zoneID, from, to, search, severity, status, assignedTo, suppressed string, includeRelations bool) ([]entity.Alert, int64, error)
+	GetActiveAlerts(ctx context.Context, page, limit int, alertTypeID, cameraID, zoneID, from, to string, includeRelations bool) ([]entity.Alert, int64, int64, error)
 	CreateAlert(ctx context.Context, alert *entity.Alert) error
+	RecordDetection(ctx context.Context, alert *entity.Alert) (*entity.AlertCorrelation, error)
+	GetAlertOccurrences(ctx context.Context, id string) ([]entity.AlertOccurrence, error)
 	UpdateAlert(ctx context.Context, alert *entity.Alert) error
-	ResolveAlert(ctx context.Context, id, resolvedBy, resolutionNote string) error
+	ResolveAlert(ctx context.Context, id, resolvedBy, resolutionNote string) (*entity.AlertEvent, error)
+	TransitionAlert(ctx context.Context, id, status, actor, note string) (*entity.AlertEvent, error)
+	AssignAlert(ctx context.Context, id, assignee, actor, note string) (*entity.AlertEvent, error)
+	EscalateAlert(ctx context.Context, id, severity, actor, note string) (*entity.AlertEvent, error)
+	GetAlertEvents(ctx context.Context, id string) ([]entity.AlertEvent, error)
 	GetAlertByID(ctx context.Context, id string) (*entity.Alert, error)
+	LocalizeAlerts(ctx context.Context, alerts []entity.Alert, language string) error
+}
+
+// ZoneService defines the interface for the site, building, floor and zone hierarchy
+type ZoneService interface {
+	GetAllZones(ctx context.Context, kind string, tree bool) ([]entity.Zone, error)
+	GetZoneByID(ctx context.Context, id uint) (*entity.Zone, error)
+	GetZoneCameras(ctx context.Context, id uint) ([]entity.Camera, error)
+	CreateZone(ctx context.Context, zone *entity.Zone) error
+	UpdateZone(ctx context.Context, zone *entity.Zone) error
+	DeleteZone(ctx context.Context, id uint) error
+	SetZoneCameras(ctx context.Context, id uint, cameras []entity.ZoneCamera) (*entity.Zone, error)
+	AddZoneCamera(ctx context.Context, membership *entity.ZoneCamera) (*entity.Zone, error)
+	RemoveZoneCamera(ctx context.Context, id, cameraID uint) error
+	SetFloorPlan(ctx context.Context, id uint, data []byte) (*entity.Zone, error)
+	GetFloorPlan(ctx context.Context, id uint) (string, error)
+	DeleteFloorPlan(ctx context.Context, id uint) error
+}
+
+// CameraHealthService defines the interface for the camera health monitor
+type CameraHealthService interface {
+	CheckCameras(ctx context.Context) ([]entity.CameraHealth, error)
+	GetCameraHealth(ctx context.Context) ([]entity.CameraHealth, error)
+	GetStatusHistory(ctx context.Context, page, limit int, cameraID, source, from, to string) ([]entity.CameraStatusEvent, int64, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// CameraAvailabilityService defines the interface for camera availability reporting
+type CameraAvailabilityService interface {
+	GetAvailability(ctx context.Context, from, to, cameraID string) (*entity.CameraAvailabilityReport, error)
+	GetMonthlyReport(ctx context.Context, month string) (*entity.CameraAvailabilityReport, error)
+}
+
+// ErrInvalidConfigVersion rejects a record reporting a configuration version its camera never had
+var ErrInvalidConfigVersion = errors.New("invalid config version")
+
+// CameraConfigService defines the interface for the versioned configurations edge devices run
+type CameraConfigService interface {
+	GetConfig(ctx context.Context, cameraID uint) (*entity.CameraConfig, error)
+	GetVersions(ctx context.Context, cameraID uint) ([]entity.CameraConfig, error)
+	GetVersion(ctx context.Context, cameraID uint, version int) (*entity.CameraConfig, error)
+	SaveConfig(ctx context.Context, config *entity.CameraConfig) error
+	RestoreVersion(ctx context.Context, cameraID uint, version int, createdBy string) (*entity.CameraConfig, error)
+	DeleteConfig(ctx context.Context, cameraID uint) error
+	GetDeviceConfig(ctx context.Context, identity entity.DeviceIdentity) (*entity.CameraConfig, error)
+	ResolveVersion(ctx context.Context, cameraID uint, reported *int) (*int, error)
+}
+
+// CameraMapService defines the interface for the live camera map
+type CameraMapService interface {
+	GetCameraMap(ctx context.Context) (*entity.CameraMap, error)
+}
+
+// EvidenceService defines the interface for the evidence clips of alerts
+type EvidenceService interface {
+	Run(ctx context.Context)
+	CaptureClip(alert *entity.Alert)
+	GetClip(ctx context.Context, alertID string) (string, error)
+}
+
+// AlertStatsService defines the interface for alert statistics
+type AlertStatsService interface {
+	GetAlertStats(ctx context.Context, from, to, cameraID, zoneID, alertTypeID string, noisyLimit int) (*entity.AlertStats, error)
 }
 
 // AnalyticsService defines the interface for analytics business logic
 type AnalyticsService interface {
-	GetOccupancyRate(ctx context.Context, areaID uint) (float64, error)
-	GetPeakHours(ctx context.Context, areaID uint, date time.Time) ([]entity.TrendPoint, error)
-	GetHistoricalComparison(ctx context.Context, areaID uint, period string) ([]entity.TrendPoint, error)
-	GetDemographicInsights(ctx context.Context, areaID uint, timeWindow string) (interface{}, error)
+	GetOccupancyRate(ctx context.Context, cameraID uint) (*entity.OccupancyRate, error)
+	GetPeakHours(ctx context.Context, cameraID uint, date time.Time) ([]entity.TrendPoint, error)
+	GetHistoricalComparison(ctx context.Context, cameraID uint, period string) (*entity.HistoricalComparison, error)
+	GetDemographicInsights(ctx context.Context, cameraID uint, timeWindow string) (*entity.DemographicInsights, error)
 }
 
 // FaceRecognitionService defines the interface for analytics business logic
@@ -97,7 +180,96 @@ type FaceRecognitionService interface {
 // WebSocketService defines the interface for WebSocket business logic
 type WebSocketService interface {
 	NotifyAlert(alertType, cameraName, message string, data interface{})
+	NotifyOccupancy(data interface{})
+	NotifyAlertEvent(event *entity.AlertEvent)
+	NotifyCameraStatus(event *entity.CameraStatusEvent)
 	SendPersonalizedMessage(clientID, messageType string, data interface{}) bool
 	GetConnectionStats() map[string]interface{}
 	HandleClientMessage(clientID string, messageType string, data json.RawMessage) error
 }
+
+// IngestionLedgerService defines the interface for ingestion history business logic
+type IngestionLedgerService interface {
+	GetHistory(ctx context.Context, page, limit int, status, handler, search string) ([]entity.IngestionLedgerEntry, int64, error)
+}
+
+// DeadLetterService defines the interface for managing files that failed ingestion
+type DeadLetterService interface {
+	GetFolders(ctx context.Context) ([]entity.DeadLetterFolder, error)
+	GetDeadLetters(ctx context.Context, folder string, page, limit int, search string) ([]entity.DeadLetter, int64, error)
+	GetDeadLetter(ctx context.Context, folder, name string) (*entity.DeadLetter, error)
+	ReplacePayload(ctx context.Context, folder, name string, payload []byte) (*entity.DeadLetter, error)
+	Requeue(ctx context.Context, folder string, names []string) ([]entity.RequeueResult, error)
+	Purge(ctx context.Context, folder string, olderThan time.Duration) (int, error)
+}
+
+// WatcherService defines the interface for folder watcher monitoring
+type WatcherService interface {
+	GetStats(ctx context.Context) (*entity.WatcherStats, error)
+}
+
+// CameraDeviceService defines the interface for mapping device identifiers to cameras
+type CameraDeviceService interface {
+	ResolveCamera(ctx context.Context, identity entity.DeviceIdentity) (uint, error)
+	GetAllDevices(ctx context.Context, page, limit int, status, cameraID, search string) ([]entity.CameraDevice, int64, error)
+	GetDeviceByID(ctx context.Context, id uint) (*entity.CameraDevice, error)
+	CreateDevice(ctx context.Context, device *entity.CameraDevice) error
+	UpdateDevice(ctx context.Context, device *entity.CameraDevice) error
+	MapDevice(ctx context.Context, id, cameraID uint) (*entity.CameraDevice, error)
+	DeleteDevice(ctx context.Context, id uint) error
+	GetUnknownDevicePolicy() string
+}
+
+// AlertRuleService defines the interface for threshold rules that raise alerts from counts
+type AlertRuleService interface {
+	GetAllRules(ctx context.Context, enabled, cameraID, zoneID, zone string) ([]entity.AlertRule, error)
+	GetRuleByID(ctx context.Context, id uint) (*entity.AlertRule, error)
+	CreateRule(ctx context.Context, rule *entity.AlertRule) error
+	UpdateRule(ctx context.Context, rule *entity.AlertRule) error
+	DeleteRule(ctx context.Context, id uint) error
+	EvaluateRules(ctx context.Context) ([]entity.RuleEvaluation, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// NotificationService defines the interface for outbound alert notifications
+type NotificationService interface {
+	GetAllChannels(ctx context.Context) ([]entity.NotificationChannel, error)
+	GetChannelByID(ctx context.Context, id uint) (*entity.NotificationChannel, error)
+	CreateChannel(ctx context.Context, channel *entity.NotificationChannel) error
+	UpdateChannel(ctx context.Context, channel *entity.NotificationChannel) error
+	DeleteChannel(ctx context.Context, id uint) error
+	TestChannel(ctx context.Context, id uint) (*entity.NotificationDelivery, error)
+	GetAllRoutes(ctx context.Context, channelID string) ([]entity.NotificationRoute, error)
+	GetRouteByID(ctx context.Context, id uint) (*entity.NotificationRoute, error)
+	CreateRoute(ctx context.Context, route *entity.NotificationRoute) error
+	UpdateRoute(ctx context.Context, route *entity.NotificationRoute) error
+	DeleteRoute(ctx context.Context, id uint) error
+	GetDeliveries(ctx context.Context, page, limit int, status, channelID, alertID string) ([]entity.NotificationDelivery, int64, error)
+	RetryDelivery(ctx context.Context, id uint) (*entity.NotificationDelivery, error)
+	NotifyAlert(ctx context.Context, alert *entity.Alert, event string)
+	NotifyChannel(ctx context.Context, alert *entity.Alert, channelID uint, event, note string) error
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// EscalationService defines the interface for escalating unacknowledged alerts
+type EscalationService interface {
+	GetAllPolicies(ctx context.Context) ([]entity.EscalationPolicy, error)
+	GetPolicyByID(ctx context.Context, id uint) (*entity.EscalationPolicy, error)
+	CreatePolicy(ctx context.Context, policy *entity.EscalationPolicy) error
+	UpdatePolicy(ctx context.Context, policy *entity.EscalationPolicy) error
+	DeletePolicy(ctx context.Context, id uint) error
+	SetAlertTypePolicy(ctx context.Context, alertTypeID uint, policyID *uint) (*entity.AlertType, error)
+	GetAlertEscalation(ctx context.Context, alertID string) (*entity.AlertEscalation, error)
+	ProcessEscalations(ctx context.Context) ([]entity.AlertEvent, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+// MaintenanceService defines the interface for maintenance windows that suppress alerts
+type MaintenanceService interface {
+	GetAllWindows(ctx context.Context, enabled, cameraID, alertTypeID, active string) ([]entity.MaintenanceWindow, error)
+	GetWindowByID(ctx context.Context, id uint) (*entity.MaintenanceWindow, error)
+	CreateWindow(ctx context.Context, window *entity.MaintenanceWindow) error
+	UpdateWindow(ctx context.Context, window *entity.MaintenanceWindow) error
+	DeleteWindow(ctx context.Context, id uint) error
+	CheckSuppression(ctx context.Context, alert *entity.Alert, camera *entity.Camera) (string, error)
+}
@@ -3,12 +3,14 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"people-counting/internal/domain/entity"
 	"people-counting/internal/domain/service"
 	"people-counting/pkg/utils"
+	"people-counting/pkg/watcher"
 	"strconv"
 	"strings"
 	"time"
@@ -86,8 +88,10 @@ func (f FlexibleMissingItem) ToString() string {
 
 type AlertData struct {
 	UUID         string              `json:"uuid"`
-	CCTVID       uint                `json:"cctv_id"`
-	CameraID     uint                `json:"camera_id"` // Alternative field name
+	CCTVID       *uint               `json:"cctv_id"`
+	CameraID     *uint               `json:"camera_id"` // Alternative field name
+	DeviceSerial string              `json:"device_serial"`
+	Channel      int                 `json:"channel"`
 	ObjectID     FlexibleUint        `json:"objectID"`
 	ObjectName   string              `json:"object_name"` // Object type detected
 	TimeStamp    string              `json:"time_stamp"`
@@ -95,6 +99,8 @@ type AlertData struct {
 	ImagePath    string              `json:"image_path"`   // Standard field name
 	ImagePathAlt string              `json:"Image_path"`   // Alternative with capital I
 	MissingItem  FlexibleMissingItem `json:"missing_item"` // For PPE alerts - can be string or array
+	// Version of the camera configuration the device ran, the current version when not sent
+	ConfigVersion *int `json:"config_version"`
 }
 
 // AlertResponse represents the alert response with image URL
@@ -105,12 +111,22 @@ type AlertResponse struct {
 	Message        string            `json:"message"`
 	Severity       string            `json:"severity"`
 	IsActive       bool              `json:"is_active"`
+	Status         string            `json:"status"`
+	AssignedTo     string            `json:"assigned_to"`
+	AcknowledgedAt *time.Time        `json:"acknowledged_at"`
+	AcknowledgedBy string            `json:"acknowledged_by"`
+	ObjectID       string            `json:"object_id"`
+	Occurrences    int               `json:"occurrence_count"`
+	FirstSeenAt    *time.Time        `json:"first_seen_at"`
+	LastSeenAt     *time.Time        `json:"last_seen_at"`
 	DetectedAt     time.Time         `json:"detected_at"`
 	ResolvedAt     *time.Time        `json:"resolved_at"`
 	ResolvedBy     string            `json:"resolved_by"`
 	ResolutionNote string            `json:"resolution_note"`
+	ConfigVersion  *int              `json:"config_version"`
 	ImagePath      string            `json:"image_path"`
 	ImageURL       string            `json:"image_url"`
+	ClipURL        string            `json:"clip_url"`
 	CreatedAt      time.Time         `json:"created_at"`
 	UpdatedAt      time.Time         `json:"updated_at"`
 	AlertType      *entity.AlertType `json:"alert_type,omitempty"`
@@ -119,46 +135,46 @@ type AlertResponse struct {
 
 // AlertHandler handles HTTP requests related to alerts
 type AlertHandler struct {
-	alertService     service.AlertService
-	cameraService    service.CameraService
-	alertTypeService service.AlertTypeService
-	webSocketService service.WebSocketService
-	baseFolder       string // Base folder for alert data
-	alertType        string // Specific alert type for this handler
+	alertService        service.AlertService
+	cameraDeviceService service.CameraDeviceService
+	alertTypeService    service.AlertTypeService
+	webSocketService    service.WebSocketService
+	baseFolder          string // Base folder for alert data
+	alertType           string // Specific alert type for this handler
 }
 
 // NewAlertHandler creates a new alert handler
-func NewAlertHandler(alertTypeService service.AlertTypeService, alertService service.AlertService, cameraService service.CameraService, webSocketService service.WebSocketService) *AlertHandler {
+func NewAlertHandler(alertTypeService service.AlertTypeService, alertService service.AlertService, cameraDeviceService service.CameraDeviceService, webSocketService service.WebSocketService) *AlertHandler {
 	return &AlertHandler{
-		alertService:     alertService,
-		cameraService:    cameraService,
-		alertTypeService: alertTypeService,
-		webSocketService: webSocketService,
-		baseFolder:       "", // Will be set when used with specific folder
+		alertService:        alertService,
+		cameraDeviceService: cameraDeviceService,
+		alertTypeService:    alertTypeService,
+		webSocketService:    webSocketService,
+		baseFolder:          "", // Will be set when used with specific folder
 	}
 }
 
 // NewAlertHandlerWithFolder creates a new alert handler with specific base folder
-func NewAlertHandlerWithFolder(alertTypeService service.AlertTypeService, alertService service.AlertService, cameraService service.CameraService, webSocketService service.WebSocketService, baseFolder string) *AlertHandler {
+func NewAlertHandlerWithFolder(alertTypeService service.AlertTypeService, alertService service.AlertService, cameraDeviceService service.CameraDeviceService, webSocketService service.WebSocketService, baseFolder string) *AlertHandler {
 	return &AlertHandler{
-		alertService:     alertService,
-		cameraService:    cameraService,
-		alertTypeService: alertTypeService,
-		webSocketService: webSocketService,
-		baseFolder:       baseFolder,
-		alertType:        "", // Will be determined from folder structure or file content
+		alertService:        alertService,
+		cameraDeviceService: cameraDeviceService,
+		alertTypeService:    alertTypeService,
+		webSocketService:    webSocketService,
+		baseFolder:          baseFolder,
+		alertType:           "", // Will be determined from folder structure or file content
 	}
 }
 
 // NewAlertHandlerWithType creates a new alert handler with specific alert type
-func NewAlertHandlerWithType(alertTypeService service.AlertTypeService, alertService service.AlertService, cameraService service.CameraService, webSocketService service.WebSocketService, baseFolder string, alertType string) *AlertHandler {
+func NewAlertHandlerWithType(alertTypeService service.AlertTypeService, alertService service.AlertService, cameraDeviceService service.CameraDeviceService, webSocketService service.WebSocketService, baseFolder string, alertType string) *AlertHandler {
 	return &AlertHandler{
-		alertService:     alertService,
-		cameraService:    cameraService,
-		alertTypeService: alertTypeService,
-		webSocketService: webSocketService,
-		baseFolder:       baseFolder,
-		alertType:        alertType,
+		alertService:        alertService,
+		cameraDeviceService: cameraDeviceService,
+		alertTypeService:    alertTypeService,
+		webSocketService:    webSocketService,
+		baseFolder:          baseFolder,
+		alertType:           alertType,
 	}
 }
 
@@ -171,6 +187,13 @@ func (h *AlertHandler) RegisterRoutes(router fiber.Router) {
 	alerts.Get("/types", h.GetAlertTypes)
 	alerts.Post("/", h.CreateAlert)
 	alerts.Put("/:id/resolve", h.ResolveAlert)
+	alerts.Put("/:id/acknowledge", h.AcknowledgeAlert)
+	alerts.Put("/:id/status", h.TransitionAlert)
+	alerts.Put("/:id/assign", h.AssignAlert)
+	alerts.Put("/:id/escalate", h.EscalateAlert)
+	alerts.Put("/:id/reopen", h.ReopenAlert)
+	alerts.Get("/:id/events", h.GetAlertEvents)
+	alerts.Get("/:id/occurrences", h.GetAlertOccurrences)
 }
 
 // ListAlerts handles getting paginated alert records
@@ -185,11 +208,14 @@ func (h *AlertHandler) ListAlerts(c *fiber.Ctx) error {
 	isActive := c.Query("is_active", "")
 	alertTypeID := c.Query("alert_type_id", "")
 	cameraID := c.Query("camera_id", "")
+	zoneID := zoneIDQuery(c)
 	from := c.Query("from", "")
 	to := c.Query("to", "")
 	search := c.Query("search", "")
 	severity := c.Query("severity", "")
 	status := c.Query("status", "")
+	assignedTo := c.Query("assigned_to", "")
+	suppressed := c.Query("suppressed", "")
 	includeRelations := c.Query("include_relations") == "true"
 
 	// Set default date range to current month if no dates provided
@@ -204,18 +230,21 @@ func (h *AlertHandler) ListAlerts(c *fiber.Ctx) error {
 		to = lastDay.Format(time.RFC3339)
 	}
 
-	alerts, total, err := h.alertService.GetAllAlerts(ctx, page, limit, isActive, alertTypeID, cameraID, from, to, search, severity, status, includeRelations)
+	alerts, total, err := h.alertService.GetAllAlerts(ctx, page, limit, isActive, alertTypeID, cameraID, zoneID, from, to, search, severity, status, assignedTo, suppressed, includeRelations)
 	if err != nil {
 		status := fiber.StatusInternalServerError
 
 		// Check for specific errors
 		if err.Error() == "invalid alert type ID" ||
 			err.Error() == "invalid camera ID" ||
+			err.Error() == "invalid zone ID" ||
+			err.Error() == "invalid alert status" ||
 			err.Error() == "invalid 'from' date format. Use RFC3339 format (e.g. 2025-05-13T10:00:00Z)" ||
 			err.Error() == "invalid 'to' date format. Use RFC3339 format (e.g. 2025-05-13T10:00:00Z)" {
 			status = fiber.StatusBadRequest
 		} else if err.Error() == "alert type not found" ||
-			err.Error() == "camera not found" {
+			err.Error() == "camera not found" ||
+			err.Error() == "zone not found" {
 			status = fiber.StatusNotFound
 		}
 
@@ -225,6 +254,14 @@ func (h *AlertHandler) ListAlerts(c *fiber.Ctx) error {
 		})
 	}
 
+	// Render template messages in the language asked for
+	if err := h.alertService.LocalizeAlerts(ctx, alerts, requestLanguage(c)); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
 	// Get base URL from request
 	baseURL := c.Protocol() + "://" + c.Hostname()
 
@@ -238,10 +275,19 @@ func (h *AlertHandler) ListAlerts(c *fiber.Ctx) error {
 			Message:        alert.Message,
 			Severity:       alert.Severity,
 			IsActive:       alert.IsActive,
+			Status:         alert.Status,
+			AssignedTo:     alert.AssignedTo,
+			AcknowledgedAt: alert.AcknowledgedAt,
+			AcknowledgedBy: alert.AcknowledgedBy,
+			ObjectID:       alert.ObjectID,
+			Occurrences:    alert.Occurrences,
+			FirstSeenAt:    alert.FirstSeenAt,
+			LastSeenAt:     alert.LastSeenAt,
 			DetectedAt:     alert.DetectedAt,
 			ResolvedAt:     alert.ResolvedAt,
 			ResolvedBy:     alert.ResolvedBy,
 			ResolutionNote: alert.ResolutionNote,
+			ConfigVersion:  alert.ConfigVersion,
 			ImagePath:      alert.ImageURL, // Store original path
 			CreatedAt:      alert.CreatedAt,
 			UpdatedAt:      alert.UpdatedAt,
@@ -249,6 +295,11 @@ func (h *AlertHandler) ListAlerts(c *fiber.Ctx) error {
 			Camera:         alert.Camera,
 		}
 
+		// Link the evidence clip once it is written
+		if alert.ClipPath != "" {
+			resp.ClipURL = fmt.Sprintf("%s/api/alerts/%s/clip", baseURL, alert.ID)
+		}
+
 		// Generate image URL if image path exists
 		if alert.ImageURL != "" {
 			// Extract filename from the original path
@@ -290,6 +341,7 @@ func (h *AlertHandler) GetActiveAlerts(c *fiber.Ctx) error {
 	// Get filter parameters
 	alertTypeID := c.Query("alert_type_id", "")
 	cameraID := c.Query("camera_id", "")
+	zoneID := zoneIDQuery(c)
 	includeRelations := c.Query("include_relations") == "true"
 
 	// Parse date range using helper
@@ -301,13 +353,14 @@ func (h *AlertHandler) GetActiveAlerts(c *fiber.Ctx) error {
 	// Convert to strings for service layer
 	from, to := dateRange.ToRFC3339Strings()
 
-	alerts, total, totalAllActive, err := h.alertService.GetActiveAlerts(ctx, page, limit, alertTypeID, cameraID, from, to, includeRelations)
+	alerts, total, totalAllActive, err := h.alertService.GetActiveAlerts(ctx, page, limit, alertTypeID, cameraID, zoneID, from, to, includeRelations)
 	if err != nil {
 		status := fiber.StatusInternalServerError
 
 		// Check for specific errors
 		if strings.Contains(err.Error(), "invalid alert type ID") ||
-			strings.Contains(err.Error(), "invalid camera ID") {
+			strings.Contains(err.Error(), "invalid camera ID") ||
+			strings.Contains(err.Error(), "invalid zone ID") {
 			status = fiber.StatusBadRequest
 		} else if strings.Contains(err.Error(), "not found") {
 			status = fiber.StatusNotFound
@@ -319,6 +372,14 @@ func (h *AlertHandler) GetActiveAlerts(c *fiber.Ctx) error {
 		})
 	}
 
+	// Render template messages in the language asked for
+	if err := h.alertService.LocalizeAlerts(ctx, alerts, requestLanguage(c)); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
 	// Get base URL from request
 	baseURL := c.Protocol() + "://" + c.Hostname()
 
@@ -332,10 +393,19 @@ func (h *AlertHandler) GetActiveAlerts(c *fiber.Ctx) error {
 			Message:        alert.Message,
 			Severity:       alert.Severity,
 			IsActive:       alert.IsActive,
+			Status:         alert.Status,
+			AssignedTo:     alert.AssignedTo,
+			AcknowledgedAt: alert.AcknowledgedAt,
+			AcknowledgedBy: alert.AcknowledgedBy,
+			ObjectID:       alert.ObjectID,
+			Occurrences:    alert.Occurrences,
+			FirstSeenAt:    alert.FirstSeenAt,
+			LastSeenAt:     alert.LastSeenAt,
 			DetectedAt:     alert.DetectedAt,
 			ResolvedAt:     alert.ResolvedAt,
 			ResolvedBy:     alert.ResolvedBy,
 			ResolutionNote: alert.ResolutionNote,
+			ConfigVersion:  alert.ConfigVersion,
 			ImagePath:      alert.ImageURL,
 			CreatedAt:      alert.CreatedAt,
 			UpdatedAt:      alert.UpdatedAt,
@@ -343,6 +413,11 @@ func (h *AlertHandler) GetActiveAlerts(c *fiber.Ctx) error {
 			Camera:         alert.Camera,
 		}
 
+		// Link the evidence clip once it is written
+		if alert.ClipPath != "" {
+			resp.ClipURL = fmt.Sprintf("%s/api/alerts/%s/clip", baseURL, alert.ID)
+		}
+
 		// Generate image URL if image path exists
 		if alert.ImageURL != "" {
 			filename := filepath.Base(strings.ReplaceAll(alert.ImageURL, "\\", "/"))
@@ -395,7 +470,8 @@ func (h *AlertHandler) CreateAlert(c *fiber.Ctx) error {
 		// Check for validation errors
 		if err.Error() == "alert type ID is required" ||
 			err.Error() == "message is required" ||
-			err.Error() == "camera ID is required" {
+			err.Error() == "camera ID is required" ||
+			errors.Is(err, service.ErrInvalidConfigVersion) {
 			status = fiber.StatusBadRequest
 		} else if err.Error() == "alert type not found" ||
 			err.Error() == "camera not found" {
@@ -417,10 +493,19 @@ func (h *AlertHandler) CreateAlert(c *fiber.Ctx) error {
 		Message:        alert.Message,
 		Severity:       alert.Severity,
 		IsActive:       alert.IsActive,
+		Status:         alert.Status,
+		AssignedTo:     alert.AssignedTo,
+		AcknowledgedAt: alert.AcknowledgedAt,
+		AcknowledgedBy: alert.AcknowledgedBy,
+		ObjectID:       alert.ObjectID,
+		Occurrences:    alert.Occurrences,
+		FirstSeenAt:    alert.FirstSeenAt,
+		LastSeenAt:     alert.LastSeenAt,
 		DetectedAt:     alert.DetectedAt,
 		ResolvedAt:     alert.ResolvedAt,
 		ResolvedBy:     alert.ResolvedBy,
 		ResolutionNote: alert.ResolutionNote,
+		ConfigVersion:  alert.ConfigVersion,
 		ImagePath:      alert.ImageURL,
 		CreatedAt:      alert.CreatedAt,
 		UpdatedAt:      alert.UpdatedAt,
@@ -428,6 +513,11 @@ func (h *AlertHandler) CreateAlert(c *fiber.Ctx) error {
 		Camera:         alert.Camera,
 	}
 
+	// Link the evidence clip once it is written
+	if alert.ClipPath != "" {
+		resp.ClipURL = fmt.Sprintf("%s/api/alerts/%s/clip", baseURL, alert.ID)
+	}
+
 	// Generate image URL if image path exists
 	if alert.ImageURL != "" {
 		filename := filepath.Base(strings.ReplaceAll(alert.ImageURL, "\\", "/"))
@@ -444,8 +534,9 @@ func (h *AlertHandler) CreateAlert(c *fiber.Ctx) error {
 		}
 	}
 
-	// Send WebSocket notification to all connected clients
-	if h.webSocketService != nil {
+	// Send WebSocket notification to all connected clients, unless a maintenance window
+	// suppressed the alert
+	if h.webSocketService != nil && !alert.Suppressed {
 		cameraName := ""
 		if alert.Camera != nil {
 			cameraName = alert.Camera.Name
@@ -486,32 +577,165 @@ func (h *AlertHandler) ResolveAlert(c *fiber.Ctx) error {
 	}
 
 	// Resolve alert
-	err := h.alertService.ResolveAlert(ctx, id, resData.ResolvedBy, resData.ResolutionNote)
+	event, err := h.alertService.ResolveAlert(ctx, id, resData.ResolvedBy, resData.ResolutionNote)
 	if err != nil {
-		status := fiber.StatusInternalServerError
+		return h.writeLifecycleError(c, err)
+	}
 
-		// Check for specific errors
-		if err.Error() == "alert ID is required" ||
-			err.Error() == "resolved by is required" {
-			status = fiber.StatusBadRequest
-		} else if err.Error() == "alert not found" {
-			status = fiber.StatusNotFound
-		} else if err.Error() == "alert is already resolved" {
-			status = fiber.StatusBadRequest
-		}
+	h.notifyAlertEvent(event)
 
-		return c.Status(status).JSON(fiber.Map{
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Alert resolved successfully",
+		"data":  event,
+	})
+}
+
+// AlertActionData is the request body of alert lifecycle actions
+type AlertActionData struct {
+	Status     string `json:"status"`
+	AssignedTo string `json:"assigned_to"`
+	Severity   string `json:"severity"`
+	Actor      string `json:"actor"`
+	Note       string `json:"note"`
+}
+
+// AcknowledgeAlert handles acknowledging an alert
+func (h *AlertHandler) AcknowledgeAlert(c *fiber.Ctx) error {
+	return h.handleAlertAction(c, "Alert acknowledged successfully", func(ctx context.Context, id string, data *AlertActionData) (*entity.AlertEvent, error) {
+		return h.alertService.TransitionAlert(ctx, id, entity.AlertStatusAcknowledged, data.Actor, data.Note)
+	})
+}
+
+// TransitionAlert handles moving an alert to any allowed status
+func (h *AlertHandler) TransitionAlert(c *fiber.Ctx) error {
+	return h.handleAlertAction(c, "Alert status updated successfully", func(ctx context.Context, id string, data *AlertActionData) (*entity.AlertEvent, error) {
+		return h.alertService.TransitionAlert(ctx, id, data.Status, data.Actor, data.Note)
+	})
+}
+
+// AssignAlert handles assigning an alert to an operator
+func (h *AlertHandler) AssignAlert(c *fiber.Ctx) error {
+	return h.handleAlertAction(c, "Alert assigned successfully", func(ctx context.Context, id string, data *AlertActionData) (*entity.AlertEvent, error) {
+		return h.alertService.AssignAlert(ctx, id, data.AssignedTo, data.Actor, data.Note)
+	})
+}
+
+// EscalateAlert handles raising the severity of an alert
+func (h *AlertHandler) EscalateAlert(c *fiber.Ctx) error {
+	return h.handleAlertAction(c, "Alert escalated successfully", func(ctx context.Context, id string, data *AlertActionData) (*entity.AlertEvent, error) {
+		return h.alertService.EscalateAlert(ctx, id, data.Severity, data.Actor, data.Note)
+	})
+}
+
+// ReopenAlert handles reopening a resolved or false positive alert
+func (h *AlertHandler) ReopenAlert(c *fiber.Ctx) error {
+	return h.handleAlertAction(c, "Alert reopened successfully", func(ctx context.Context, id string, data *AlertActionData) (*entity.AlertEvent, error) {
+		return h.alertService.TransitionAlert(ctx, id, entity.AlertStatusReopened, data.Actor, data.Note)
+	})
+}
+
+// GetAlertEvents handles getting the lifecycle history of an alert
+func (h *AlertHandler) GetAlertEvents(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	events, err := h.alertService.GetAlertEvents(ctx, c.Params("id"))
+	if err != nil {
+		return h.writeLifecycleError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(events),
+		"data":  events,
+	})
+}
+
+// GetAlertOccurrences handles getting the detections folded into an alert
+func (h *AlertHandler) GetAlertOccurrences(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	occurrences, err := h.alertService.GetAlertOccurrences(ctx, c.Params("id"))
+	if err != nil {
+		return h.writeLifecycleError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(occurrences),
+		"data":  occurrences,
+	})
+}
+
+// handleAlertAction parses the action body, applies the action and broadcasts the resulting event
+func (h *AlertHandler) handleAlertAction(c *fiber.Ctx, msg string, action func(ctx context.Context, id string, data *AlertActionData) (*entity.AlertEvent, error)) error {
+	ctx := c.Context()
+
+	data := new(AlertActionData)
+	if err := c.BodyParser(data); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": true,
-			"msg":   err.Error(),
+			"msg":   "Invalid request body: " + err.Error(),
 		})
 	}
 
+	event, err := action(ctx, c.Params("id"), data)
+	if err != nil {
+		return h.writeLifecycleError(c, err)
+	}
+
+	h.notifyAlertEvent(event)
+
 	return c.JSON(fiber.Map{
 		"error": false,
-		"msg":   "Alert resolved successfully",
+		"msg":   msg,
+		"data":  event,
+	})
+}
+
+func (h *AlertHandler) writeLifecycleError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+
+	switch {
+	case err.Error() == "alert not found":
+		status = fiber.StatusNotFound
+	case err.Error() == "alert was changed by another request",
+		strings.HasPrefix(err.Error(), "alert is already"),
+		strings.HasPrefix(err.Error(), "cannot change alert status"),
+		strings.HasPrefix(err.Error(), "cannot assign"),
+		strings.HasPrefix(err.Error(), "cannot escalate"):
+		status = fiber.StatusConflict
+	case err.Error() == "alert ID is required",
+		err.Error() == "resolved by is required",
+		err.Error() == "actor is required",
+		err.Error() == "invalid alert status",
+		err.Error() == "invalid severity",
+		err.Error() == "alert is not assigned",
+		err.Error() == "severity must be higher than the current severity":
+		status = fiber.StatusBadRequest
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": true,
+		"msg":   err.Error(),
 	})
 }
 
+func (h *AlertHandler) notifyAlertEvent(event *entity.AlertEvent) {
+	if h.webSocketService != nil && event != nil {
+		h.webSocketService.NotifyAlertEvent(event)
+	}
+}
+
+// requestLanguage returns the alert message language asked for through Accept-Language, empty
+// when the header is missing or asks for no supported language
+func requestLanguage(c *fiber.Ctx) string {
+	if c.Get(fiber.HeaderAcceptLanguage) == "" {
+		return ""
+	}
+	return c.AcceptsLanguages(entity.AlertLanguages...)
+}
+
 func (h *AlertHandler) GetName() string {
 	return "alert_handler"
 }
@@ -521,15 +745,22 @@ func (h *AlertHandler) ProcessFile(filePath string) error {
 }
 
 func (h *AlertHandler) ProcessFileWithType(filePath string, alertType string) error {
-	// Create a background context
-	ctx := context.Background()
-
 	// Read file contents
 	fileData, err := os.ReadFile(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
+	return h.ProcessPayloadWithType(context.Background(), fileData, alertType)
+}
+
+// ProcessPayload processes a single JSON alert record using the handler's alert type
+func (h *AlertHandler) ProcessPayload(ctx context.Context, fileData []byte) error {
+	return h.ProcessPayloadWithType(ctx, fileData, h.alertType)
+}
+
+// ProcessPayloadWithType processes a single JSON alert record with the given alert type
+func (h *AlertHandler) ProcessPayloadWithType(ctx context.Context, fileData []byte, alertType string) error {
 	// Parse JSON into AlertData struct
 	var alertData AlertData
 	if err := json.Unmarshal(fileData, &alertData); err != nil {
@@ -538,15 +769,7 @@ func (h *AlertHandler) ProcessFileWithType(filePath string, alertType string) er
 
 	// Check if UUID is provided
 	if alertData.UUID == "" {
-		return fmt.Errorf("alert UUID is required")
-	}
-
-	// Check if alert with this UUID already exists
-	existingAlert, err := h.alertService.GetAlertByID(ctx, alertData.UUID)
-	isUpdate := false
-	if err == nil && existingAlert != nil {
-		// Alert already exists, we will update it
-		isUpdate = true
+		return watcher.Permanent(fmt.Errorf("alert UUID is required"))
 	}
 
 	// Use provided alert type if available, otherwise use alert type from JSON data
@@ -587,54 +810,31 @@ func (h *AlertHandler) ProcessFileWithType(filePath string, alertType string) er
 	// Convert AlertData to Alert entity
 	alert, err := alertData.ToAlert()
 	if err != nil {
-		return fmt.Errorf("failed to convert data to alert: %w", err)
+		return watcher.Permanent(fmt.Errorf("failed to convert data to alert: %w", err))
 	}
 
-	// Check if camera exists and create if it doesn't
-	if alert.CameraID != 0 {
-		camera, err := h.cameraService.GetCameraByID(ctx, alert.CameraID)
-
-		// Only create camera if it doesn't exist (when we get an error OR camera is nil)
-		if err != nil || camera == nil {
-			fmt.Printf("Camera %d not found, creating new camera\n", alert.CameraID)
-
-			newCamera := &entity.Camera{
-				ID:     alert.CameraID,
-				Name:   fmt.Sprintf("Camera %d (Auto-created)", alert.CameraID),
-				Status: "active",
-			}
-
-			err := h.cameraService.CreateCamera(ctx, newCamera)
-			if err != nil {
-				// Check if error is due to camera already existing (race condition)
-				if strings.Contains(strings.ToLower(err.Error()), "duplicate") ||
-					strings.Contains(strings.ToLower(err.Error()), "already exists") ||
-					strings.Contains(strings.ToLower(err.Error()), "unique constraint") {
-					fmt.Printf("Camera %d already exists (race condition), continuing...\n", alert.CameraID)
-				} else {
-					return fmt.Errorf("failed to create camera: %w", err)
-				}
-			} else {
-				fmt.Printf("Successfully created camera %d\n", alert.CameraID)
-			}
-		}
+	// Resolve the camera from the device identifiers
+	cameraID, err := resolveDeviceCamera(ctx, h.cameraDeviceService, alertData.Identity())
+	if err != nil {
+		return err
 	}
+	alert.CameraID = cameraID
+
+	// The version of the configuration the detection was made with, the current one when not
+	// sent
+	alert.ConfigVersion = alertData.ConfigVersion
 
 	alert.AlertTypeID = alertTypeID
 
-	// Create or update alert in database
-	if isUpdate {
-		if err := h.alertService.UpdateAlert(ctx, alert); err != nil {
-			return fmt.Errorf("failed to update alert: %w", err)
-		}
-	} else {
-		if err := h.alertService.CreateAlert(ctx, alert); err != nil {
-			return fmt.Errorf("failed to create alert: %w", err)
-		}
+	// Record the detection, repeated detections are folded into the open alert
+	correlation, err := h.alertService.RecordDetection(ctx, alert)
+	if err != nil {
+		return ingestionError("failed to record alert", err)
 	}
+	alert = correlation.Alert
 
-	// Send WebSocket notification after successful alert creation/update
-	if h.webSocketService != nil {
+	// Send WebSocket notification for new alerts and escalations only
+	if h.webSocketService != nil && correlation.ShouldNotify() {
 		// Get camera name for notification
 		cameraName := ""
 		if alert.Camera != nil {
@@ -659,10 +859,19 @@ func (h *AlertHandler) ProcessFileWithType(filePath string, alertType string) er
 			Message:        alert.Message,
 			Severity:       alert.Severity,
 			IsActive:       alert.IsActive,
+			Status:         alert.Status,
+			AssignedTo:     alert.AssignedTo,
+			AcknowledgedAt: alert.AcknowledgedAt,
+			AcknowledgedBy: alert.AcknowledgedBy,
+			ObjectID:       alert.ObjectID,
+			Occurrences:    alert.Occurrences,
+			FirstSeenAt:    alert.FirstSeenAt,
+			LastSeenAt:     alert.LastSeenAt,
 			DetectedAt:     alert.DetectedAt,
 			ResolvedAt:     alert.ResolvedAt,
 			ResolvedBy:     alert.ResolvedBy,
 			ResolutionNote: alert.ResolutionNote,
+			ConfigVersion:  alert.ConfigVersion,
 			ImagePath:      alert.ImageURL,
 			ImageURL:       alert.ImageURL,
 			CreatedAt:      alert.CreatedAt,
@@ -692,94 +901,61 @@ func (a AlertData) ToAlert() (*entity.Alert, error) {
 		return nil, fmt.Errorf("failed to parse timestamp '%s': %v", a.TimeStamp, err)
 	}
 
-	// Use camera helper to determine camera ID (ObjectID should not be used for camera resolution)
-	cameraResolver := utils.NewCameraIDResolver()
-	cameraID := cameraResolver.ResolveCameraID(a.CameraID, a.CCTVID) // Pass 0 for objectID to exclude it
-
 	// Determine which image path to use (handle both lowercase and uppercase variants)
 	imagePath := a.ImagePath
 	if imagePath == "" && a.ImagePathAlt != "" {
 		imagePath = a.ImagePathAlt
 	}
 
+	// Severity and message come from the alert type when the alert is recorded
 	alert := &entity.Alert{
-		ID:         a.UUID,
-		CameraID:   cameraID,
-		Message:    a.getAlertMessage(),
-		DetectedAt: parsedTime,
-		ImageURL:   imagePath,
-		Severity:   "high",
+		ID:          a.UUID,
+		DetectedAt:  parsedTime,
+		ImageURL:    imagePath,
+		ObjectName:  a.ObjectName,
+		MissingItem: a.MissingItem.ToString(),
+	}
+
+	// Object IDs are optional, trackers number objects from 1
+	if a.ObjectID != 0 {
+		alert.ObjectID = strconv.FormatUint(uint64(a.ObjectID), 10)
 	}
 
 	return alert, nil
 }
 
-func (h *AlertHandler) getAlertTypeIDFromString(ctx context.Context, typeName string) (uint, error) {
-	// Try to find existing alert type in database
-	alertType, err := h.alertTypeService.GetAlertTypeByName(ctx, typeName)
-	if err == nil && alertType != nil {
-		return alertType.ID, nil
-	}
-
-	// If not found, create new alert type
-	newAlertType := &entity.AlertType{
-		Name:        typeName,
-		Description: fmt.Sprintf("Auto-generated alert type for %s", typeName),
-		Icon:        "alert-triangle",
-		Color:       "#ff6b35",
+// Identity returns the identifiers of the device that raised the alert.
+// camera_id takes precedence over cctv_id, both are legacy identifiers.
+func (a AlertData) Identity() entity.DeviceIdentity {
+	legacyID := a.CameraID
+	if legacyID == nil {
+		legacyID = a.CCTVID
 	}
 
-	createdAlertType, err := h.alertTypeService.CreateAlertType(ctx, newAlertType)
-	if err != nil {
-		// If creation fails, try to get existing one again (in case of race condition)
-		existingAlertType, getErr := h.alertTypeService.GetAlertTypeByName(ctx, typeName)
-		if getErr == nil && existingAlertType != nil {
-			return existingAlertType.ID, nil
-		}
-		return 0, fmt.Errorf("failed to create alert type: %w", err)
+	return entity.DeviceIdentity{
+		Serial:   a.DeviceSerial,
+		Channel:  a.Channel,
+		LegacyID: legacyID,
 	}
-
-	return createdAlertType.ID, nil
 }
 
-func (a *AlertData) getAlertMessage() string {
-	alertType := strings.ToLower(strings.TrimSpace(a.AlertType))
-	missingItem := a.MissingItem.ToString()
-
-	// Handle Indonesian alert types
-	switch alertType {
-	case "tidak patuh":
-		if missingItem != "" {
-			return fmt.Sprintf("Personal protective equipment violation: Missing %s", missingItem)
-		}
-		return "Personal protective equipment violation detected"
-	case "restricted":
-		return "Person detected in restricted area"
-	case "fall-detection":
-		return "Person fall detected"
-	case "loitering":
-		return "Extended loitering detected"
-	case "hazardous-area":
-		return "Person detected in hazardous area"
-	case "personal-protective-equipment":
-		if missingItem != "" {
-			return fmt.Sprintf("Personal protective equipment violation: Missing %s", missingItem)
-		}
-		return "Personal protective equipment violation detected"
+// getAlertTypeIDFromString resolves the alert type of a name sent by a detector. Names are
+// matched to types and aliases ignoring case and whitespace, so "Loitering " does not spawn a
+// duplicate type.
+func (h *AlertHandler) getAlertTypeIDFromString(ctx context.Context, typeName string) (uint, error) {
+	alertType, err := h.alertTypeService.ResolveAlertType(ctx, typeName)
+	if err != nil {
+		return 0, err
 	}
 
-	// Default message for unknown alert types
-	if missingItem != "" {
-		return fmt.Sprintf("Alert detected: %s (Missing: %s)", a.AlertType, missingItem)
-	}
-	return fmt.Sprintf("Alert detected: %s", a.AlertType)
+	return alertType.ID, nil
 }
 
 // GetAlertTypes handles getting all alert types
 func (h *AlertHandler) GetAlertTypes(c *fiber.Ctx) error {
 	ctx := c.Context()
 
-	alertTypes, err := h.alertTypeService.GetAllAlertTypes(ctx)
+	alertTypes, err := h.alertTypeService.GetAllAlertTypes(ctx, false)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": true,
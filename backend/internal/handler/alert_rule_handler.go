@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AlertRuleHandler handles HTTP requests related to threshold alert rules
+type AlertRuleHandler struct {
+	alertRuleService service.AlertRuleService
+}
+
+// alertRuleRequest is the body of create and update requests
+type alertRuleRequest struct {
+	Name            string  `json:"name"`
+	Description     string  `json:"description"`
+	Enabled         *bool   `json:"enabled"`
+	CameraID        *uint   `json:"camera_id"`
+	ZoneID          *uint   `json:"zone_id"`
+	Zone            string  `json:"zone"`
+	Metric          string  `json:"metric"`
+	Condition       string  `json:"condition"`
+	Threshold       float64 `json:"threshold"`
+	WindowMinutes   int     `json:"window_minutes"`
+	ScheduleDays    string  `json:"schedule_days"`
+	ScheduleStart   string  `json:"schedule_start"`
+	ScheduleEnd     string  `json:"schedule_end"`
+	OutsideSchedule bool    `json:"outside_schedule"`
+	Severity        string  `json:"severity"`
+	CooldownMinutes int     `json:"cooldown_minutes"`
+}
+
+// NewAlertRuleHandler creates a new alert rule handler
+func NewAlertRuleHandler(alertRuleService service.AlertRuleService) *AlertRuleHandler {
+	return &AlertRuleHandler{
+		alertRuleService: alertRuleService,
+	}
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *AlertRuleHandler) RegisterRoutes(router fiber.Router) {
+	rules := router.Group("/alert-rules")
+
+	rules.Get("/", h.ListRules)
+	rules.Post("/evaluate", h.EvaluateRules)
+	rules.Get("/:id", h.GetRule)
+	rules.Post("/", h.CreateRule)
+	rules.Put("/:id", h.UpdateRule)
+	rules.Delete("/:id", h.DeleteRule)
+}
+
+// ListRules handles getting alert rules
+func (h *AlertRuleHandler) ListRules(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Get filter parameters
+	enabled := c.Query("enabled", "")
+	cameraID := c.Query("camera_id", "")
+	zoneID := c.Query("zone_id", "")
+	zone := c.Query("zone", "")
+
+	rules, err := h.alertRuleService.GetAllRules(ctx, enabled, cameraID, zoneID, zone)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(rules),
+		"data":  rules,
+	})
+}
+
+// GetRule handles getting an alert rule by ID
+func (h *AlertRuleHandler) GetRule(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid alert rule ID",
+		})
+	}
+
+	rule, err := h.alertRuleService.GetRuleByID(ctx, uint(id))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  rule,
+	})
+}
+
+// CreateRule handles creating an alert rule
+func (h *AlertRuleHandler) CreateRule(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse request body
+	request := new(alertRuleRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	rule := request.toRule()
+
+	if err := h.alertRuleService.CreateRule(ctx, rule); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"error": false,
+		"msg":   "Alert rule created successfully",
+		"data":  rule,
+	})
+}
+
+// UpdateRule handles updating an alert rule
+func (h *AlertRuleHandler) UpdateRule(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid alert rule ID",
+		})
+	}
+
+	// Parse request body
+	request := new(alertRuleRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	rule := request.toRule()
+	rule.ID = uint(id)
+
+	if err := h.alertRuleService.UpdateRule(ctx, rule); err != nil {
+		return h.writeError(c, err)
+	}
+
+	// Get updated rule
+	updated, err := h.alertRuleService.GetRuleByID(ctx, uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Error retrieving updated alert rule: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Alert rule updated successfully",
+		"data":  updated,
+	})
+}
+
+// DeleteRule handles deleting an alert rule
+func (h *AlertRuleHandler) DeleteRule(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid alert rule ID",
+		})
+	}
+
+	if err := h.alertRuleService.DeleteRule(ctx, uint(id)); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Alert rule deleted successfully",
+	})
+}
+
+// EvaluateRules handles evaluating the enabled rules now rather than on the next interval
+func (h *AlertRuleHandler) EvaluateRules(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	evaluations, err := h.alertRuleService.EvaluateRules(ctx)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(evaluations),
+		"data":  evaluations,
+	})
+}
+
+// writeError maps alert rule validation errors to response statuses
+func (h *AlertRuleHandler) writeError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+
+	switch err.Error() {
+	case "invalid camera ID",
+		"invalid zone ID",
+		"name is required",
+		"camera ID or zone is required",
+		"set either camera ID or zone, not both",
+		"invalid metric",
+		"invalid condition",
+		"surge is not supported for occupancy",
+		"threshold must not be negative",
+		"surge threshold must be greater than 1",
+		"window and cooldown must not be negative",
+		"invalid severity":
+		status = fiber.StatusBadRequest
+	case "an alert rule with the same name already exists":
+		status = fiber.StatusConflict
+	case "alert rule not found", "camera not found", "zone not found":
+		status = fiber.StatusNotFound
+	default:
+		if strings.HasPrefix(err.Error(), "invalid schedule") {
+			status = fiber.StatusBadRequest
+		}
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": true,
+		"msg":   err.Error(),
+	})
+}
+
+func (r *alertRuleRequest) toRule() *entity.AlertRule {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	return &entity.AlertRule{
+		Name:            r.Name,
+		Description:     r.Description,
+		Enabled:         enabled,
+		CameraID:        r.CameraID,
+		ZoneID:          r.ZoneID,
+		Zone:            r.Zone,
+		Metric:          r.Metric,
+		Condition:       r.Condition,
+		Threshold:       r.Threshold,
+		WindowMinutes:   r.WindowMinutes,
+		ScheduleDays:    r.ScheduleDays,
+		ScheduleStart:   r.ScheduleStart,
+		ScheduleEnd:     r.ScheduleEnd,
+		OutsideSchedule: r.OutsideSchedule,
+		Severity:        r.Severity,
+		CooldownMinutes: r.CooldownMinutes,
+	}
+}
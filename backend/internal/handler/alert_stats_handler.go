@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"strings"
+
+	"people-counting/internal/domain/service"
+	"people-counting/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AlertStatsHandler handles HTTP requests related to alert statistics
+type AlertStatsHandler struct {
+	alertStatsService service.AlertStatsService
+}
+
+// NewAlertStatsHandler creates a new alert statistics handler
+func NewAlertStatsHandler(alertStatsService service.AlertStatsService) *AlertStatsHandler {
+	return &AlertStatsHandler{
+		alertStatsService: alertStatsService,
+	}
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *AlertStatsHandler) RegisterRoutes(router fiber.Router) {
+	alerts := router.Group("/alerts")
+
+	alerts.Get("/stats", h.GetAlertStats)
+}
+
+// GetAlertStats handles getting alert counts by type, camera, zone, severity and hour of the day,
+// response times, the false positive rate and the noisiest cameras over a date range
+func (h *AlertStatsHandler) GetAlertStats(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Get filter parameters
+	cameraID := c.Query("camera_id", "")
+	zoneID := zoneIDQuery(c)
+	alertTypeID := c.Query("alert_type_id", "")
+	limit := c.QueryInt("limit", 5) // Noisy cameras
+
+	// Parse date range using helper
+	dateRange, err := utils.ParseDateRangeFromQuery(c)
+	if err != nil {
+		return utils.HandleDateFilterError(c, err)
+	}
+
+	// Convert to strings for service layer
+	from, to := dateRange.ToRFC3339Strings()
+
+	stats, err := h.alertStatsService.GetAlertStats(ctx, from, to, cameraID, zoneID, alertTypeID, limit)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			status = fiber.StatusBadRequest
+		} else if err.Error() == "zone not found" {
+			status = fiber.StatusNotFound
+		}
+
+		return c.Status(status).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  stats,
+	})
+}
@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"strconv"
+	"strings"
+
 	"people-counting/internal/domain/entity"
 	"people-counting/internal/domain/service"
 
@@ -12,6 +15,32 @@ type AlertTypeHandler struct {
 	alertTypeService service.AlertTypeService
 }
 
+// alertTypeMessagesRequest is the body of updating the severity and messages of an alert type
+type alertTypeMessagesRequest struct {
+	Severity string            `json:"severity"`
+	Messages map[string]string `json:"messages"` // Templates by language, such as "en" and "id"
+}
+
+// alertTypeRequest is the body of updating an alert type
+type alertTypeRequest struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Icon        string `json:"icon"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"` // Empty keeps the current severity
+}
+
+// alertTypeMergeRequest is the body of merging an alert type into another
+type alertTypeMergeRequest struct {
+	Into uint `json:"into"` // ID of the alert type to keep
+}
+
+// alertTypeAliasRequest is the body of adding an alias to an alert type
+type alertTypeAliasRequest struct {
+	Name string `json:"name"`
+}
+
 // NewAlertTypeHandler creates a new alert type handler
 func NewAlertTypeHandler(alertTypeService service.AlertTypeService) *AlertTypeHandler {
 	return &AlertTypeHandler{
@@ -25,13 +54,27 @@ func (h *AlertTypeHandler) RegisterRoutes(router fiber.Router) {
 
 	alertTypes.Get("/", h.ListAlertTypes)
 	alertTypes.Post("/", h.CreateAlertType)
+	alertTypes.Get("/aliases", h.ListAliases)
+	alertTypes.Delete("/aliases/:aliasId", h.DeleteAlias)
+	alertTypes.Get("/:id", h.GetAlertType)
+	alertTypes.Put("/:id", h.UpdateAlertType)
+	alertTypes.Delete("/:id", h.DeleteAlertType)
+	alertTypes.Put("/:id/archive", h.ArchiveAlertType)
+	alertTypes.Put("/:id/restore", h.RestoreAlertType)
+	alertTypes.Post("/:id/merge", h.MergeAlertType)
+	alertTypes.Post("/:id/aliases", h.CreateAlias)
+	alertTypes.Get("/:id/messages", h.GetAlertTypeMessages)
+	alertTypes.Put("/:id/messages", h.UpdateAlertTypeMessages)
 }
 
-// ListAlertTypes handles getting all alert types
+// ListAlertTypes handles getting all alert types. Archived types are left out unless
+// include_archived is true.
 func (h *AlertTypeHandler) ListAlertTypes(c *fiber.Ctx) error {
 	ctx := c.Context()
 
-	alertTypes, err := h.alertTypeService.GetAllAlertTypes(ctx)
+	includeArchived := c.Query("include_archived", "false") == "true"
+
+	alertTypes, err := h.alertTypeService.GetAllAlertTypes(ctx, includeArchived)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": true,
@@ -68,8 +111,11 @@ func (h *AlertTypeHandler) CreateAlertType(c *fiber.Ctx) error {
 		// Check for validation errors
 		if err.Error() == "name is required" ||
 			err.Error() == "icon is required" ||
-			err.Error() == "color is required" {
+			err.Error() == "color is required" ||
+			err.Error() == "invalid severity" {
 			status = fiber.StatusBadRequest
+		} else if err.Error() == "an alert type with the same name already exists" {
+			status = fiber.StatusConflict
 		}
 
 		return c.Status(status).JSON(fiber.Map{
@@ -84,3 +130,363 @@ func (h *AlertTypeHandler) CreateAlertType(c *fiber.Ctx) error {
 		"data":  alertType,
 	})
 }
+
+// GetAlertType handles getting an alert type by ID
+func (h *AlertTypeHandler) GetAlertType(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid alert type ID",
+		})
+	}
+
+	alertType, err := h.alertTypeService.GetAlertTypeByID(ctx, uint(id))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  alertType,
+	})
+}
+
+// UpdateAlertType handles updating an alert type. Renamed types keep their previous name as an
+// alias.
+func (h *AlertTypeHandler) UpdateAlertType(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid alert type ID",
+		})
+	}
+
+	// Parse request body
+	request := new(alertTypeRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	alertType, err := h.alertTypeService.UpdateAlertType(ctx, &entity.AlertType{
+		ID:          uint(id),
+		Name:        request.Name,
+		DisplayName: request.DisplayName,
+		Icon:        request.Icon,
+		Color:       request.Color,
+		Description: request.Description,
+		Severity:    request.Severity,
+	})
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Alert type updated successfully",
+		"data":  alertType,
+	})
+}
+
+// DeleteAlertType handles deleting an alert type that nothing refers to
+func (h *AlertTypeHandler) DeleteAlertType(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid alert type ID",
+		})
+	}
+
+	if err := h.alertTypeService.DeleteAlertType(ctx, uint(id)); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Alert type deleted successfully",
+	})
+}
+
+// ArchiveAlertType handles archiving an alert type
+func (h *AlertTypeHandler) ArchiveAlertType(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid alert type ID",
+		})
+	}
+
+	alertType, err := h.alertTypeService.ArchiveAlertType(ctx, uint(id))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Alert type archived successfully",
+		"data":  alertType,
+	})
+}
+
+// RestoreAlertType handles restoring an archived alert type
+func (h *AlertTypeHandler) RestoreAlertType(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid alert type ID",
+		})
+	}
+
+	alertType, err := h.alertTypeService.RestoreAlertType(ctx, uint(id))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Alert type restored successfully",
+		"data":  alertType,
+	})
+}
+
+// MergeAlertType handles merging an alert type into another. Its alerts are re-pointed to the
+// other type and its name becomes an alias of it.
+func (h *AlertTypeHandler) MergeAlertType(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid alert type ID",
+		})
+	}
+
+	// Parse request body
+	request := new(alertTypeMergeRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	if request.Into == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "into is required",
+		})
+	}
+
+	merge, err := h.alertTypeService.MergeAlertTypes(ctx, uint(id), request.Into)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Alert types merged successfully",
+		"data":  merge,
+	})
+}
+
+// ListAliases handles getting every alert type alias
+func (h *AlertTypeHandler) ListAliases(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	aliases, err := h.alertTypeService.GetAliases(ctx)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Error getting alert type aliases: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(aliases),
+		"data":  aliases,
+	})
+}
+
+// CreateAlias handles mapping another name to an alert type
+func (h *AlertTypeHandler) CreateAlias(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid alert type ID",
+		})
+	}
+
+	// Parse request body
+	request := new(alertTypeAliasRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	alias, err := h.alertTypeService.CreateAlias(ctx, uint(id), request.Name)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"error": false,
+		"msg":   "Alert type alias created successfully",
+		"data":  alias,
+	})
+}
+
+// DeleteAlias handles deleting an alert type alias
+func (h *AlertTypeHandler) DeleteAlias(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("aliasId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid alias ID",
+		})
+	}
+
+	if err := h.alertTypeService.DeleteAlias(ctx, uint(id)); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Alert type alias deleted successfully",
+	})
+}
+
+// GetAlertTypeMessages handles getting the severity and message templates of an alert type
+func (h *AlertTypeHandler) GetAlertTypeMessages(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid alert type ID",
+		})
+	}
+
+	alertType, err := h.alertTypeService.GetAlertTypeMessages(ctx, uint(id))
+	if err != nil {
+		return h.writeMessagesError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  alertType,
+	})
+}
+
+// UpdateAlertTypeMessages handles updating the severity and message templates of an
+// alert type
+func (h *AlertTypeHandler) UpdateAlertTypeMessages(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid alert type ID",
+		})
+	}
+
+	// Parse request body
+	request := new(alertTypeMessagesRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	alertType, err := h.alertTypeService.UpdateAlertTypeMessages(ctx, uint(id), request.Severity, request.Messages)
+	if err != nil {
+		return h.writeMessagesError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Alert type messages updated successfully",
+		"data":  alertType,
+	})
+}
+
+// writeMessagesError maps alert type message validation errors to response statuses
+func (h *AlertTypeHandler) writeMessagesError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+
+	switch {
+	case err.Error() == "alert type not found":
+		status = fiber.StatusNotFound
+	case strings.HasPrefix(err.Error(), "invalid "):
+		status = fiber.StatusBadRequest
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": true,
+		"msg":   err.Error(),
+	})
+}
+
+// writeError maps alert type management errors to response statuses
+func (h *AlertTypeHandler) writeError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+
+	switch {
+	case err.Error() == "alert type not found" ||
+		err.Error() == "target alert type not found" ||
+		err.Error() == "alias not found":
+		status = fiber.StatusNotFound
+	case err.Error() == "an alert type with the same name already exists" ||
+		err.Error() == "alert type is in use, archive or merge it instead":
+		status = fiber.StatusConflict
+	case err.Error() == "name is required" ||
+		err.Error() == "icon is required" ||
+		err.Error() == "color is required" ||
+		err.Error() == "cannot merge an alert type into itself" ||
+		strings.HasPrefix(err.Error(), "invalid "):
+		status = fiber.StatusBadRequest
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": true,
+		"msg":   err.Error(),
+	})
+}
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+	"people-counting/internal/domain/service"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AnalyticsHandler handles HTTP requests related to people count analytics
+type AnalyticsHandler struct {
+	analyticsService service.AnalyticsService
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(analyticsService service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *AnalyticsHandler) RegisterRoutes(router fiber.Router) {
+	analytics := router.Group("/analytics")
+
+	analytics.Get("/occupancy-rate", h.GetOccupancyRate)
+	analytics.Get("/peak-hours", h.GetPeakHours)
+	analytics.Get("/comparison", h.GetHistoricalComparison)
+	analytics.Get("/demographics", h.GetDemographicInsights)
+}
+
+// GetOccupancyRate handles getting live occupancy relative to camera capacity
+func (h *AnalyticsHandler) GetOccupancyRate(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	cameraID, err := parseAnalyticsCameraID(c)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	rate, err := h.analyticsService.GetOccupancyRate(ctx, cameraID)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  rate,
+	})
+}
+
+// GetPeakHours handles getting the hourly people counts of a day
+func (h *AnalyticsHandler) GetPeakHours(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	cameraID, err := parseAnalyticsCameraID(c)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	var date time.Time
+	if dateStr := c.Query("date", ""); dateStr != "" {
+		date, err = time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": true,
+				"msg":   "Invalid date format. Use YYYY-MM-DD",
+			})
+		}
+	}
+
+	hours, err := h.analyticsService.GetPeakHours(ctx, cameraID, date)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  hours,
+	})
+}
+
+// GetHistoricalComparison handles comparing today with last week, or this month with last month
+func (h *AnalyticsHandler) GetHistoricalComparison(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	cameraID, err := parseAnalyticsCameraID(c)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	comparison, err := h.analyticsService.GetHistoricalComparison(ctx, cameraID, c.Query("period", "day"))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  comparison,
+	})
+}
+
+// GetDemographicInsights handles getting visitor demographics over a time window
+func (h *AnalyticsHandler) GetDemographicInsights(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	cameraID, err := parseAnalyticsCameraID(c)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	insights, err := h.analyticsService.GetDemographicInsights(ctx, cameraID, c.Query("window", "24h"))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  insights,
+	})
+}
+
+func (h *AnalyticsHandler) writeError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+
+	switch err.Error() {
+	case "invalid camera ID",
+		"invalid period. Must be day or month",
+		"invalid window. Must be 24h, 7d, 30d, or 90d":
+		status = fiber.StatusBadRequest
+	case "camera not found":
+		status = fiber.StatusNotFound
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": true,
+		"msg":   err.Error(),
+	})
+}
+
+// parseAnalyticsCameraID reads the optional camera_id query, zero means all cameras
+func parseAnalyticsCameraID(c *fiber.Ctx) (uint, error) {
+	cameraIDStr := c.Query("camera_id", "")
+	if cameraIDStr == "" {
+		return 0, nil
+	}
+
+	cameraID, err := strconv.ParseUint(cameraIDStr, 10, 32)
+	if err != nil {
+		return 0, errors.New("invalid camera ID")
+	}
+
+	return uint(cameraID), nil
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/service"
+	"people-counting/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CameraAvailabilityHandler handles HTTP requests related to camera availability
+type CameraAvailabilityHandler struct {
+	cameraAvailabilityService service.CameraAvailabilityService
+}
+
+// NewCameraAvailabilityHandler creates a new camera availability handler
+func NewCameraAvailabilityHandler(cameraAvailabilityService service.CameraAvailabilityService) *CameraAvailabilityHandler {
+	return &CameraAvailabilityHandler{
+		cameraAvailabilityService: cameraAvailabilityService,
+	}
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *CameraAvailabilityHandler) RegisterRoutes(router fiber.Router) {
+	cameras := router.Group("/cameras")
+
+	cameras.Get("/availability", h.GetAvailability)
+	cameras.Get("/availability/sla.csv", h.ExportMonthlyReport)
+}
+
+// GetAvailability handles getting the availability, outages and MTTR of cameras over a date range
+func (h *CameraAvailabilityHandler) GetAvailability(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Get filter parameters
+	cameraID := c.Query("camera_id", "")
+
+	// Parse date range using helper
+	dateRange, err := utils.ParseDateRangeFromQuery(c)
+	if err != nil {
+		return utils.HandleDateFilterError(c, err)
+	}
+
+	// Convert to strings for service layer
+	from, to := dateRange.ToRFC3339Strings()
+
+	report, err := h.cameraAvailabilityService.GetAvailability(ctx, from, to, cameraID)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  report,
+	})
+}
+
+// ExportMonthlyReport handles downloading the availability of every camera over a month as CSV
+func (h *CameraAvailabilityHandler) ExportMonthlyReport(c *fiber.Ctx) error {
+	report, err := h.cameraAvailabilityService.GetMonthlyReport(c.Context(), c.Query("month", ""))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	data, err := availabilityCSV(report)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
+	month := report.From.Format("2006-01")
+	c.Set("Content-Type", "text/csv; charset=utf-8")
+	c.Attachment(fmt.Sprintf("camera-availability-%s.csv", month))
+
+	return c.Send(data)
+}
+
+// writeError writes a service error with the status it maps to
+func (h *CameraAvailabilityHandler) writeError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+
+	if err.Error() == "camera not found" {
+		status = fiber.StatusNotFound
+	} else if strings.HasPrefix(err.Error(), "invalid ") {
+		status = fiber.StatusBadRequest
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": true,
+		"msg":   err.Error(),
+	})
+}
+
+// availabilityCSV writes a row per camera and a last row for all cameras. Durations are in
+// minutes, empty when there is nothing to measure.
+func availabilityCSV(report *entity.CameraAvailabilityReport) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	month := report.From.Format("2006-01")
+	header := []string{
+		"month", "camera_id", "camera_name", "location", "monitored_hours", "downtime_minutes",
+		"availability_percent", "target_percent", "meets_target", "outages", "longest_outage_minutes", "mttr_minutes",
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	var monitored, downtime float64
+	var outages int
+	for _, camera := range report.Cameras {
+		monitored += camera.MonitoredSeconds
+		downtime += camera.DowntimeSeconds
+		outages += camera.Outages
+
+		meets := ""
+		if camera.MeetsTarget != nil {
+			meets = strconv.FormatBool(*camera.MeetsTarget)
+		}
+
+		mttr := ""
+		if camera.MeanTimeToRepair != nil {
+			mttr = formatFloat(*camera.MeanTimeToRepair / 60)
+		}
+
+		if err := writer.Write([]string{
+			month,
+			strconv.FormatUint(uint64(camera.CameraID), 10),
+			camera.CameraName,
+			camera.Location,
+			formatFloat(camera.MonitoredSeconds / 3600),
+			formatFloat(camera.DowntimeSeconds / 60),
+			formatPercentage(camera.Availability),
+			formatFloat(report.Target),
+			meets,
+			strconv.Itoa(camera.Outages),
+			formatFloat(camera.LongestOutage / 60),
+			mttr,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	meets := ""
+	if report.Availability != nil {
+		meets = strconv.FormatBool(*report.Availability >= report.Target)
+	}
+
+	if err := writer.Write([]string{
+		month, "", "All cameras", "",
+		formatFloat(monitored / 3600),
+		formatFloat(downtime / 60),
+		formatPercentage(report.Availability),
+		formatFloat(report.Target),
+		meets,
+		strconv.Itoa(outages),
+		"", "",
+	}); err != nil {
+		return nil, err
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// formatFloat formats a number with at most two decimals
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+// formatPercentage formats a percentage, empty when there is none
+func formatPercentage(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', 3, 64)
+}
@@ -0,0 +1,323 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CameraConfigHandler handles HTTP requests related to the configurations edge devices run for
+// cameras
+type CameraConfigHandler struct {
+	cameraConfigService service.CameraConfigService
+	auth                fiber.Handler
+}
+
+// cameraConfigRequest is the body of requests saving a configuration
+type cameraConfigRequest struct {
+	Polygons         []entity.ConfigPolygon  `json:"polygons"`
+	Tripwires        []entity.ConfigTripwire `json:"tripwires"`
+	Direction        string                  `json:"direction"`
+	Classes          []string                `json:"classes"`
+	LoiteringSeconds int                     `json:"loitering_seconds"`
+	Note             string                  `json:"note"`
+	CreatedBy        string                  `json:"created_by"`
+}
+
+// NewCameraConfigHandler creates a new camera configuration handler. The edge pull endpoint is
+// protected by the given auth middleware, the same as ingestion.
+func NewCameraConfigHandler(cameraConfigService service.CameraConfigService, auth fiber.Handler) *CameraConfigHandler {
+	return &CameraConfigHandler{
+		cameraConfigService: cameraConfigService,
+		auth:                auth,
+	}
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *CameraConfigHandler) RegisterRoutes(router fiber.Router) {
+	cameras := router.Group("/cameras")
+
+	cameras.Get("/:id/config", h.GetConfig)
+	cameras.Put("/:id/config", h.SaveConfig)
+	cameras.Delete("/:id/config", h.DeleteConfig)
+	cameras.Get("/:id/config/versions", h.ListVersions)
+	cameras.Get("/:id/config/versions/:version", h.GetVersion)
+	cameras.Post("/:id/config/versions/:version/restore", h.RestoreVersion)
+
+	// The auth middleware is attached per route, a group middleware would match every path
+	// starting with /edge
+	edge := router.Group("/edge")
+
+	edge.Get("/config", h.auth, h.PullConfig)
+}
+
+// GetConfig handles getting the current configuration of a camera
+func (h *CameraConfigHandler) GetConfig(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid camera ID",
+		})
+	}
+
+	config, err := h.cameraConfigService.GetConfig(c.Context(), uint(id))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  config,
+	})
+}
+
+// SaveConfig handles saving the configuration of a camera as its next version
+func (h *CameraConfigHandler) SaveConfig(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid camera ID",
+		})
+	}
+
+	// Parse request body
+	request := new(cameraConfigRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	config := &entity.CameraConfig{
+		CameraID:         uint(id),
+		Polygons:         request.Polygons,
+		Tripwires:        request.Tripwires,
+		Direction:        request.Direction,
+		Classes:          request.Classes,
+		LoiteringSeconds: request.LoiteringSeconds,
+		Note:             request.Note,
+		CreatedBy:        request.CreatedBy,
+	}
+
+	if err := h.cameraConfigService.SaveConfig(c.Context(), config); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"error": false,
+		"msg":   "Camera config saved successfully",
+		"data":  config,
+	})
+}
+
+// DeleteConfig handles archiving the configuration of a camera
+func (h *CameraConfigHandler) DeleteConfig(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid camera ID",
+		})
+	}
+
+	if err := h.cameraConfigService.DeleteConfig(c.Context(), uint(id)); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Camera config deleted successfully",
+	})
+}
+
+// ListVersions handles getting every version of the configuration of a camera
+func (h *CameraConfigHandler) ListVersions(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid camera ID",
+		})
+	}
+
+	configs, err := h.cameraConfigService.GetVersions(c.Context(), uint(id))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(configs),
+		"data":  configs,
+	})
+}
+
+// GetVersion handles getting a version of the configuration of a camera
+func (h *CameraConfigHandler) GetVersion(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid camera ID",
+		})
+	}
+
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid version",
+		})
+	}
+
+	config, err := h.cameraConfigService.GetVersion(c.Context(), uint(id), version)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  config,
+	})
+}
+
+// RestoreVersion handles saving an earlier version of the configuration of a camera as its next
+// version
+func (h *CameraConfigHandler) RestoreVersion(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid camera ID",
+		})
+	}
+
+	version, err := strconv.Atoi(c.Params("version"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid version",
+		})
+	}
+
+	// Parse request body, it is optional
+	var request struct {
+		CreatedBy string `json:"created_by"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": true,
+				"msg":   "Invalid request body: " + err.Error(),
+			})
+		}
+	}
+
+	config, err := h.cameraConfigService.RestoreVersion(c.Context(), uint(id), version, request.CreatedBy)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"error": false,
+		"msg":   "Camera config version restored successfully",
+		"data":  config,
+	})
+}
+
+// PullConfig handles edge devices polling the current configuration of their camera. Devices
+// identify themselves like in the records they send, by device_serial and channel or cctv_id,
+// and send back the ETag of the configuration they run in If-None-Match to get 304 Not Modified
+// until a new version is saved.
+func (h *CameraConfigHandler) PullConfig(c *fiber.Ctx) error {
+	identity := entity.DeviceIdentity{
+		Serial: strings.TrimSpace(c.Query("device_serial", "")),
+	}
+
+	if channel := c.Query("channel", ""); channel != "" {
+		parsed, err := strconv.Atoi(channel)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": true,
+				"msg":   "Invalid channel",
+			})
+		}
+		identity.Channel = parsed
+	}
+
+	if cctvID := c.Query("cctv_id", ""); cctvID != "" {
+		parsed, err := strconv.ParseUint(cctvID, 10, 32)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": true,
+				"msg":   "Invalid cctv_id",
+			})
+		}
+		legacyID := uint(parsed)
+		identity.LegacyID = &legacyID
+	}
+
+	config, err := h.cameraConfigService.GetDeviceConfig(c.Context(), identity)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	etag := config.ETag()
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+
+	if etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  config,
+	})
+}
+
+// writeError maps camera configuration errors to response statuses
+func (h *CameraConfigHandler) writeError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+
+	switch err.Error() {
+	case "device identifier is required", "channel must not be negative":
+		status = fiber.StatusBadRequest
+	case "camera not found", "camera config not found", "camera config version not found",
+		"unknown device", "device is quarantined", "device is not mapped to a camera":
+		status = fiber.StatusNotFound
+	default:
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			status = fiber.StatusBadRequest
+		}
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": true,
+		"msg":   err.Error(),
+	})
+}
+
+// etagMatches reports whether an If-None-Match header lists the entity tag, comparing weakly as
+// RFC 9110 asks for If-None-Match
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
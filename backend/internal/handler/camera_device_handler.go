@@ -0,0 +1,329 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/service"
+	"people-counting/pkg/watcher"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CameraDeviceHandler handles HTTP requests related to device to camera mappings
+type CameraDeviceHandler struct {
+	cameraDeviceService service.CameraDeviceService
+}
+
+// cameraDeviceRequest is the body of create and update requests
+type cameraDeviceRequest struct {
+	Serial   string `json:"serial"`
+	Channel  int    `json:"channel"`
+	LegacyID *uint  `json:"legacy_id"`
+	CameraID *uint  `json:"camera_id"`
+	Name     string `json:"name"`
+}
+
+// NewCameraDeviceHandler creates a new camera device handler
+func NewCameraDeviceHandler(cameraDeviceService service.CameraDeviceService) *CameraDeviceHandler {
+	return &CameraDeviceHandler{
+		cameraDeviceService: cameraDeviceService,
+	}
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *CameraDeviceHandler) RegisterRoutes(router fiber.Router) {
+	devices := router.Group("/camera-devices")
+
+	devices.Get("/", h.ListDevices)
+	devices.Get("/:id", h.GetDevice)
+	devices.Post("/", h.CreateDevice)
+	devices.Put("/:id", h.UpdateDevice)
+	devices.Post("/:id/map", h.MapDevice)
+	devices.Delete("/:id", h.DeleteDevice)
+}
+
+// ListDevices handles getting paginated device mappings
+func (h *CameraDeviceHandler) ListDevices(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Get pagination parameters
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 50)
+
+	// Get filter parameters
+	status := c.Query("status", "")
+	cameraID := c.Query("camera_id", "")
+	search := c.Query("search", "")
+
+	devices, total, err := h.cameraDeviceService.GetAllDevices(ctx, page, limit, status, cameraID, search)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+
+		if err.Error() == "invalid status. Must be mapped or quarantined" ||
+			err.Error() == "invalid camera ID" {
+			status = fiber.StatusBadRequest
+		}
+
+		return c.Status(status).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+
+	return c.JSON(fiber.Map{
+		"error":  false,
+		"count":  len(devices),
+		"total":  total,
+		"page":   page,
+		"pages":  (total + int64(limit) - 1) / int64(limit),
+		"policy": h.cameraDeviceService.GetUnknownDevicePolicy(),
+		"data":   devices,
+	})
+}
+
+// GetDevice handles getting a device mapping by ID
+func (h *CameraDeviceHandler) GetDevice(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid camera device ID",
+		})
+	}
+
+	device, err := h.cameraDeviceService.GetDeviceByID(ctx, uint(id))
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if err.Error() == "camera device not found" {
+			status = fiber.StatusNotFound
+		}
+
+		return c.Status(status).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  device,
+	})
+}
+
+// CreateDevice handles creating a device mapping
+func (h *CameraDeviceHandler) CreateDevice(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse request body
+	request := new(cameraDeviceRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	device := request.toDevice()
+
+	if err := h.cameraDeviceService.CreateDevice(ctx, device); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"error": false,
+		"msg":   "Camera device created successfully",
+		"data":  device,
+	})
+}
+
+// UpdateDevice handles updating a device mapping
+func (h *CameraDeviceHandler) UpdateDevice(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid camera device ID",
+		})
+	}
+
+	// Parse request body
+	request := new(cameraDeviceRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	device := request.toDevice()
+	device.ID = uint(id)
+
+	if err := h.cameraDeviceService.UpdateDevice(ctx, device); err != nil {
+		return h.writeError(c, err)
+	}
+
+	// Get updated device
+	updated, err := h.cameraDeviceService.GetDeviceByID(ctx, uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Error retrieving updated camera device: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Camera device updated successfully",
+		"data":  updated,
+	})
+}
+
+// MapDevice handles mapping a device, typically a quarantined one, to a camera.
+// Files rejected while the device was quarantined can then be requeued from the dead letters.
+func (h *CameraDeviceHandler) MapDevice(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid camera device ID",
+		})
+	}
+
+	var request struct {
+		CameraID uint `json:"camera_id"`
+	}
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	if request.CameraID == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "camera ID is required",
+		})
+	}
+
+	device, err := h.cameraDeviceService.MapDevice(ctx, uint(id), request.CameraID)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Camera device mapped successfully",
+		"data":  device,
+	})
+}
+
+// DeleteDevice handles deleting a device mapping
+func (h *CameraDeviceHandler) DeleteDevice(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid camera device ID",
+		})
+	}
+
+	if err := h.cameraDeviceService.DeleteDevice(ctx, uint(id)); err != nil {
+		status := fiber.StatusInternalServerError
+		if err.Error() == "camera device not found" {
+			status = fiber.StatusNotFound
+		}
+
+		return c.Status(status).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Camera device deleted successfully",
+	})
+}
+
+// writeError maps device validation errors to response statuses
+func (h *CameraDeviceHandler) writeError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+
+	switch err.Error() {
+	case "serial or legacy ID is required",
+		"channel must not be negative",
+		"camera device ID is required":
+		status = fiber.StatusBadRequest
+	case "a device with the same serial and channel already exists",
+		"a device with the same legacy ID already exists":
+		status = fiber.StatusConflict
+	case "camera device not found", "camera not found":
+		status = fiber.StatusNotFound
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": true,
+		"msg":   err.Error(),
+	})
+}
+
+func (r *cameraDeviceRequest) toDevice() *entity.CameraDevice {
+	return &entity.CameraDevice{
+		Serial:   r.Serial,
+		Channel:  r.Channel,
+		LegacyID: r.LegacyID,
+		CameraID: r.CameraID,
+		Name:     r.Name,
+	}
+}
+
+// resolveDeviceCamera resolves the camera of the device that produced a record. Records from
+// devices that are unidentified, rejected or quarantined fail permanently so they land in the
+// dead letters, where they can be requeued once the device is mapped.
+func resolveDeviceCamera(ctx context.Context, cameraDeviceService service.CameraDeviceService, identity entity.DeviceIdentity) (uint, error) {
+	cameraID, err := cameraDeviceService.ResolveCamera(ctx, identity)
+	if err != nil {
+		switch err.Error() {
+		case "device identifier is required",
+			"channel must not be negative",
+			"unknown device",
+			"device is quarantined",
+			"device is not mapped to a camera":
+			return 0, watcher.Permanent(fmt.Errorf("failed to resolve camera of %s: %w", identity, err))
+		}
+		return 0, fmt.Errorf("failed to resolve camera of %s: %w", identity, err)
+	}
+
+	return cameraID, nil
+}
+
+// ingestionError wraps an error of a service recording an ingested record. Records reporting a
+// configuration version their camera never had fail permanently, retrying cannot fix them.
+func ingestionError(message string, err error) error {
+	wrapped := fmt.Errorf("%s: %w", message, err)
+	if errors.Is(err, service.ErrInvalidConfigVersion) {
+		return watcher.Permanent(wrapped)
+	}
+	return wrapped
+}
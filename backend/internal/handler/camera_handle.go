@@ -2,6 +2,7 @@ package handler
 
 import (
 	"strconv"
+	"strings"
 
 	"people-counting/internal/domain/entity"
 	"people-counting/internal/domain/service"
@@ -118,7 +119,8 @@ func (h *CameraHandler) CreateCamera(c *fiber.Ctx) error {
 		if err.Error() == "camera name is required" ||
 			err.Error() == "camera location is required" ||
 			err.Error() == "area ID is required" ||
-			err.Error() == "invalid status. Must be active, inactive, maintenance, or issue" {
+			err.Error() == "capacity must not be negative" ||
+			strings.HasPrefix(err.Error(), "invalid ") {
 			status = fiber.StatusBadRequest
 		} else if err.Error() == "area not found" {
 			status = fiber.StatusNotFound
@@ -170,7 +172,7 @@ func (h *CameraHandler) UpdateCamera(c *fiber.Ctx) error {
 		// Check for specific errors
 		if err.Error() == "camera not found" || err.Error() == "area not found" {
 			status = fiber.StatusNotFound
-		} else if err.Error() == "invalid status. Must be active, inactive, maintenance, or issue" {
+		} else if err.Error() == "capacity must not be negative" || strings.HasPrefix(err.Error(), "invalid ") {
 			status = fiber.StatusBadRequest
 		}
 
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"strings"
+
+	"people-counting/internal/domain/service"
+	"people-counting/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CameraHealthHandler handles HTTP requests related to camera health and status history
+type CameraHealthHandler struct {
+	cameraHealthService service.CameraHealthService
+}
+
+// NewCameraHealthHandler creates a new camera health handler
+func NewCameraHealthHandler(cameraHealthService service.CameraHealthService) *CameraHealthHandler {
+	return &CameraHealthHandler{
+		cameraHealthService: cameraHealthService,
+	}
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *CameraHealthHandler) RegisterRoutes(router fiber.Router) {
+	cameras := router.Group("/cameras")
+
+	cameras.Get("/health", h.GetCameraHealth)
+	cameras.Post("/health/check", h.CheckCameras)
+	cameras.Get("/status-history", h.GetStatusHistory)
+}
+
+// GetCameraHealth handles getting the outcome of the last health check of every camera
+func (h *CameraHealthHandler) GetCameraHealth(c *fiber.Ctx) error {
+	health, err := h.cameraHealthService.GetCameraHealth(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(health),
+		"data":  health,
+	})
+}
+
+// CheckCameras handles checking every camera right away
+func (h *CameraHealthHandler) CheckCameras(c *fiber.Ctx) error {
+	health, err := h.cameraHealthService.CheckCameras(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(health),
+		"data":  health,
+	})
+}
+
+// GetStatusHistory handles getting camera status changes, newest first
+func (h *CameraHealthHandler) GetStatusHistory(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Get pagination parameters
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 50)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 50
+	}
+
+	// Get filter parameters
+	cameraID := c.Query("camera_id", "")
+	source := c.Query("source", "")
+
+	// Parse date range using helper
+	dateRange, err := utils.ParseDateRangeFromQuery(c)
+	if err != nil {
+		return utils.HandleDateFilterError(c, err)
+	}
+
+	// Convert to strings for service layer
+	from, to := dateRange.ToRFC3339Strings()
+
+	events, total, err := h.cameraHealthService.GetStatusHistory(ctx, page, limit, cameraID, source, from, to)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			status = fiber.StatusBadRequest
+		}
+
+		return c.Status(status).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(events),
+		"total": total,
+		"page":  page,
+		"pages": (total + int64(limit) - 1) / int64(limit),
+		"data":  events,
+	})
+}
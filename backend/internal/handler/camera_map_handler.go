@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"encoding/json"
+
+	"people-counting/internal/domain/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CameraMapHandler handles HTTP requests related to the live camera map
+type CameraMapHandler struct {
+	cameraMapService service.CameraMapService
+}
+
+// NewCameraMapHandler creates a new camera map handler
+func NewCameraMapHandler(cameraMapService service.CameraMapService) *CameraMapHandler {
+	return &CameraMapHandler{
+		cameraMapService: cameraMapService,
+	}
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *CameraMapHandler) RegisterRoutes(router fiber.Router) {
+	router.Get("/cameras.geojson", h.GetCameraMap)
+}
+
+// GetCameraMap handles getting the cameras as a GeoJSON feature collection. The collection is
+// the whole body so map libraries can load the URL as is.
+func (h *CameraMapHandler) GetCameraMap(c *fiber.Ctx) error {
+	cameraMap, err := h.cameraMapService.GetCameraMap(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
+	data, err := json.Marshal(cameraMap)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
+	c.Set("Content-Type", "application/geo+json")
+	c.Set("Cache-Control", "no-cache")
+
+	return c.Send(data)
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"time"
+
+	"people-counting/internal/domain/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeadLetterHandler handles HTTP requests for files that failed ingestion
+type DeadLetterHandler struct {
+	deadLetterService service.DeadLetterService
+}
+
+// NewDeadLetterHandler creates a new dead letter handler
+func NewDeadLetterHandler(deadLetterService service.DeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		deadLetterService: deadLetterService,
+	}
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *DeadLetterHandler) RegisterRoutes(router fiber.Router) {
+	deadLetters := router.Group("/ingestion/dead-letters")
+
+	deadLetters.Get("/", h.GetFolders)
+	deadLetters.Get("/:folder", h.GetDeadLetters)
+	deadLetters.Delete("/:folder", h.Purge)
+	deadLetters.Post("/:folder/requeue", h.RequeueBulk)
+	deadLetters.Get("/:folder/:name", h.GetDeadLetter)
+	deadLetters.Put("/:folder/:name", h.ReplacePayload)
+	deadLetters.Post("/:folder/:name/requeue", h.Requeue)
+}
+
+// GetFolders handles listing watched folders with their failed file counts
+func (h *DeadLetterHandler) GetFolders(c *fiber.Ctx) error {
+	folders, err := h.deadLetterService.GetFolders(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(folders),
+		"data":  folders,
+	})
+}
+
+// GetDeadLetters handles listing the failed files of a folder
+func (h *DeadLetterHandler) GetDeadLetters(c *fiber.Ctx) error {
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 50)
+	search := c.Query("search", "")
+
+	deadLetters, total, err := h.deadLetterService.GetDeadLetters(c.Context(), c.Params("folder"), page, limit, search)
+	if err != nil {
+		return deadLetterError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(deadLetters),
+		"total": total,
+		"page":  page,
+		"pages": (total + int64(limit) - 1) / int64(limit),
+		"data":  deadLetters,
+	})
+}
+
+// GetDeadLetter handles getting a single failed file with its full payload
+func (h *DeadLetterHandler) GetDeadLetter(c *fiber.Ctx) error {
+	deadLetter, err := h.deadLetterService.GetDeadLetter(c.Context(), c.Params("folder"), c.Params("name"))
+	if err != nil {
+		return deadLetterError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  deadLetter,
+	})
+}
+
+// ReplacePayload handles replacing the payload of a failed file with the request body
+func (h *DeadLetterHandler) ReplacePayload(c *fiber.Ctx) error {
+	deadLetter, err := h.deadLetterService.ReplacePayload(c.Context(), c.Params("folder"), c.Params("name"), c.Body())
+	if err != nil {
+		return deadLetterError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Payload replaced successfully",
+		"data":  deadLetter,
+	})
+}
+
+// Requeue handles moving a single failed file back into its watched folder
+func (h *DeadLetterHandler) Requeue(c *fiber.Ctx) error {
+	results, err := h.deadLetterService.Requeue(c.Context(), c.Params("folder"), []string{c.Params("name")})
+	if err != nil {
+		return deadLetterError(c, err)
+	}
+
+	if results[0].Status != "requeued" {
+		return c.Status(requeueErrorStatus(results[0].Error)).JSON(fiber.Map{
+			"error": true,
+			"msg":   results[0].Error,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "File requeued successfully",
+		"data":  results[0],
+	})
+}
+
+// RequeueBulk handles moving several, or all, failed files of a folder back into it
+func (h *DeadLetterHandler) RequeueBulk(c *fiber.Ctx) error {
+	var request struct {
+		Files []string `json:"files"`
+	}
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": true,
+				"msg":   "Invalid request body",
+			})
+		}
+	}
+
+	results, err := h.deadLetterService.Requeue(c.Context(), c.Params("folder"), request.Files)
+	if err != nil {
+		return deadLetterError(c, err)
+	}
+
+	requeued := 0
+	for _, result := range results {
+		if result.Status == "requeued" {
+			requeued++
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"error":    false,
+		"count":    len(results),
+		"requeued": requeued,
+		"failed":   len(results) - requeued,
+		"data":     results,
+	})
+}
+
+// Purge handles deleting failed files older than the older_than duration (default 7 days)
+func (h *DeadLetterHandler) Purge(c *fiber.Ctx) error {
+	olderThan, err := time.ParseDuration(c.Query("older_than", "168h"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid older_than duration",
+		})
+	}
+
+	purged, err := h.deadLetterService.Purge(c.Context(), c.Params("folder"), olderThan)
+	if err != nil {
+		return deadLetterError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error":  false,
+		"msg":    "Failed files purged successfully",
+		"purged": purged,
+	})
+}
+
+// deadLetterError maps dead letter service errors to HTTP responses
+func deadLetterError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+
+	switch err.Error() {
+	case "folder not found", "failed file not found":
+		status = fiber.StatusNotFound
+	case "invalid file name", "payload must be valid JSON", "older_than must not be negative":
+		status = fiber.StatusBadRequest
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": true,
+		"msg":   err.Error(),
+	})
+}
+
+// requeueErrorStatus maps a requeue failure message to an HTTP status
+func requeueErrorStatus(msg string) int {
+	switch msg {
+	case "failed file not found", "folder not found":
+		return fiber.StatusNotFound
+	case "invalid file name":
+		return fiber.StatusBadRequest
+	case "a file with the same name is already queued":
+		return fiber.StatusConflict
+	}
+	return fiber.StatusInternalServerError
+}
@@ -0,0 +1,310 @@
+package handler
+
+import (
+	"strconv"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// EscalationHandler handles HTTP requests related to escalation policies
+type EscalationHandler struct {
+	escalationService service.EscalationService
+}
+
+// escalationPolicyRequest is the body of create and update requests
+type escalationPolicyRequest struct {
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Enabled     *bool                   `json:"enabled"`
+	Steps       []escalationStepRequest `json:"steps"`
+}
+
+// escalationStepRequest is a step of an escalation policy request, in firing order
+type escalationStepRequest struct {
+	DelayMinutes int    `json:"delay_minutes"`
+	Target       string `json:"target"`
+	ChannelID    *uint  `json:"channel_id"`
+	Severity     string `json:"severity"`
+}
+
+// alertTypePolicyRequest is the body of attaching a policy to an alert type, null detaches it
+type alertTypePolicyRequest struct {
+	PolicyID *uint `json:"policy_id"`
+}
+
+// NewEscalationHandler creates a new escalation handler
+func NewEscalationHandler(escalationService service.EscalationService) *EscalationHandler {
+	return &EscalationHandler{
+		escalationService: escalationService,
+	}
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *EscalationHandler) RegisterRoutes(router fiber.Router) {
+	policies := router.Group("/escalation-policies")
+
+	policies.Get("/", h.ListPolicies)
+	policies.Post("/process", h.ProcessEscalations)
+	policies.Get("/:id", h.GetPolicy)
+	policies.Post("/", h.CreatePolicy)
+	policies.Put("/:id", h.UpdatePolicy)
+	policies.Delete("/:id", h.DeletePolicy)
+
+	router.Put("/alert-types/:id/escalation-policy", h.SetAlertTypePolicy)
+	router.Get("/alerts/:id/escalation", h.GetAlertEscalation)
+}
+
+// ListPolicies handles getting escalation policies
+func (h *EscalationHandler) ListPolicies(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	policies, err := h.escalationService.GetAllPolicies(ctx)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(policies),
+		"data":  policies,
+	})
+}
+
+// GetPolicy handles getting an escalation policy by ID
+func (h *EscalationHandler) GetPolicy(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid escalation policy ID",
+		})
+	}
+
+	policy, err := h.escalationService.GetPolicyByID(ctx, uint(id))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  policy,
+	})
+}
+
+// CreatePolicy handles creating an escalation policy
+func (h *EscalationHandler) CreatePolicy(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse request body
+	request := new(escalationPolicyRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	policy := request.toPolicy()
+
+	if err := h.escalationService.CreatePolicy(ctx, policy); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"error": false,
+		"msg":   "Escalation policy created successfully",
+		"data":  policy,
+	})
+}
+
+// UpdatePolicy handles updating an escalation policy and replacing its steps
+func (h *EscalationHandler) UpdatePolicy(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid escalation policy ID",
+		})
+	}
+
+	// Parse request body
+	request := new(escalationPolicyRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	policy := request.toPolicy()
+	policy.ID = uint(id)
+
+	if err := h.escalationService.UpdatePolicy(ctx, policy); err != nil {
+		return h.writeError(c, err)
+	}
+
+	// Get updated policy
+	updated, err := h.escalationService.GetPolicyByID(ctx, uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Error retrieving updated escalation policy: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Escalation policy updated successfully",
+		"data":  updated,
+	})
+}
+
+// DeletePolicy handles deleting an escalation policy
+func (h *EscalationHandler) DeletePolicy(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid escalation policy ID",
+		})
+	}
+
+	if err := h.escalationService.DeletePolicy(ctx, uint(id)); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Escalation policy deleted successfully",
+	})
+}
+
+// ProcessEscalations handles firing due escalation steps now rather than on the next interval
+func (h *EscalationHandler) ProcessEscalations(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	events, err := h.escalationService.ProcessEscalations(ctx)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(events),
+		"data":  events,
+	})
+}
+
+// SetAlertTypePolicy handles attaching an escalation policy to an alert type
+func (h *EscalationHandler) SetAlertTypePolicy(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid alert type ID",
+		})
+	}
+
+	// Parse request body
+	request := new(alertTypePolicyRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	alertType, err := h.escalationService.SetAlertTypePolicy(ctx, uint(id), request.PolicyID)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Alert type escalation policy updated successfully",
+		"data":  alertType,
+	})
+}
+
+// GetAlertEscalation handles getting how far an alert went through its escalation policy
+func (h *EscalationHandler) GetAlertEscalation(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	escalation, err := h.escalationService.GetAlertEscalation(ctx, c.Params("id"))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  escalation,
+	})
+}
+
+// writeError maps escalation validation errors to response statuses
+func (h *EscalationHandler) writeError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+
+	switch err.Error() {
+	case "name is required",
+		"alert ID is required",
+		"at least one step is required",
+		"step delay must not be negative",
+		"step delays must be in increasing order",
+		"each step needs a channel or a severity",
+		"invalid severity":
+		status = fiber.StatusBadRequest
+	case "an escalation policy with the same name already exists":
+		status = fiber.StatusConflict
+	case "escalation policy not found",
+		"alert type not found",
+		"alert not found",
+		"alert escalation not found",
+		"notification channel not found":
+		status = fiber.StatusNotFound
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": true,
+		"msg":   err.Error(),
+	})
+}
+
+func (r *escalationPolicyRequest) toPolicy() *entity.EscalationPolicy {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	steps := make([]entity.EscalationStep, 0, len(r.Steps))
+	for _, step := range r.Steps {
+		steps = append(steps, entity.EscalationStep{
+			DelayMinutes: step.DelayMinutes,
+			Target:       step.Target,
+			ChannelID:    step.ChannelID,
+			Severity:     step.Severity,
+		})
+	}
+
+	return &entity.EscalationPolicy{
+		Name:        r.Name,
+		Description: r.Description,
+		Enabled:     enabled,
+		Steps:       steps,
+	}
+}
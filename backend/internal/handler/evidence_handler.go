@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"people-counting/internal/domain/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// EvidenceHandler handles HTTP requests related to alert evidence clips
+type EvidenceHandler struct {
+	evidenceService service.EvidenceService
+}
+
+// NewEvidenceHandler creates a new evidence handler
+func NewEvidenceHandler(evidenceService service.EvidenceService) *EvidenceHandler {
+	return &EvidenceHandler{
+		evidenceService: evidenceService,
+	}
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *EvidenceHandler) RegisterRoutes(router fiber.Router) {
+	alerts := router.Group("/alerts")
+
+	alerts.Get("/:id/clip", h.GetClip)
+}
+
+// GetClip handles downloading the evidence clip of an alert
+func (h *EvidenceHandler) GetClip(c *fiber.Ctx) error {
+	ctx := c.Context()
+	id := c.Params("id")
+
+	path, err := h.evidenceService.GetClip(ctx, id)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+
+		switch err.Error() {
+		case "alert not found", "clip not found":
+			status = fiber.StatusNotFound
+		case "clip is not ready yet":
+			// The frames after the detection are still being buffered
+			status = fiber.StatusConflict
+		}
+
+		return c.Status(status).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
+	return c.Download(path, fmt.Sprintf("alert-%s%s", id, filepath.Ext(path)))
+}
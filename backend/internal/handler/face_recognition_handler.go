@@ -9,6 +9,7 @@ import (
 	"people-counting/internal/domain/entity"
 	"people-counting/internal/domain/service"
 	"people-counting/pkg/utils"
+	"people-counting/pkg/watcher"
 	"strings"
 	"time"
 
@@ -17,24 +18,26 @@ import (
 
 // RecognitionData holds the data structure for incoming face recognition data
 type RecognitionData struct {
-	UUID      string `json:"uuid"`
-	CameraID  *uint  `json:"camera_id"`
-	ObjectID  string `json:"objectID"`
-	TimeStamp string `json:"time_stamp"`
-	ImagePath string `json:"image_path"`
+	UUID         string `json:"uuid"`
+	CameraID     *uint  `json:"camera_id"`
+	DeviceSerial string `json:"device_serial"`
+	Channel      int    `json:"channel"`
+	ObjectID     string `json:"objectID"`
+	TimeStamp    string `json:"time_stamp"`
+	ImagePath    string `json:"image_path"`
 }
 
 // FaceRecognitionHandler handles HTTP requests related to face recognitions
 type FaceRecognitionHandler struct {
 	faceRecognitionService service.FaceRecognitionService
-	cameraService          service.CameraService
+	cameraDeviceService    service.CameraDeviceService
 }
 
 // NewFaceRecognitionHandler creates a new face recognition handler
-func NewFaceRecognitionHandler(faceRecognitionService service.FaceRecognitionService, cameraService service.CameraService) *FaceRecognitionHandler {
+func NewFaceRecognitionHandler(faceRecognitionService service.FaceRecognitionService, cameraDeviceService service.CameraDeviceService) *FaceRecognitionHandler {
 	return &FaceRecognitionHandler{
 		faceRecognitionService: faceRecognitionService,
-		cameraService:          cameraService,
+		cameraDeviceService:    cameraDeviceService,
 	}
 }
 
@@ -131,15 +134,17 @@ func (h *FaceRecognitionHandler) GetName() string {
 
 // ProcessFile processes a face recognition file from the given path
 func (h *FaceRecognitionHandler) ProcessFile(filePath string) error {
-	// Create a background context
-	ctx := context.Background()
-
 	// Read file contents
 	fileData, err := os.ReadFile(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
+	return h.ProcessPayload(context.Background(), fileData)
+}
+
+// ProcessPayload processes a single JSON face recognition record
+func (h *FaceRecognitionHandler) ProcessPayload(ctx context.Context, fileData []byte) error {
 	// Parse JSON into RecognitionData struct
 	var recognitionData RecognitionData
 	if err := json.Unmarshal(fileData, &recognitionData); err != nil {
@@ -148,7 +153,7 @@ func (h *FaceRecognitionHandler) ProcessFile(filePath string) error {
 
 	// Check if UUID is provided
 	if recognitionData.UUID == "" {
-		return fmt.Errorf("recognition UUID is required")
+		return watcher.Permanent(fmt.Errorf("recognition UUID is required"))
 	}
 
 	// Check if recognition with this UUID already exists
@@ -162,23 +167,15 @@ func (h *FaceRecognitionHandler) ProcessFile(filePath string) error {
 	// Convert RecognitionData to FaceRecognition entity
 	recognition, err := recognitionData.ToModel()
 	if err != nil {
-		return fmt.Errorf("failed to convert data to face recognition: %w", err)
+		return watcher.Permanent(fmt.Errorf("failed to convert data to face recognition: %w", err))
 	}
 
-	// Check if camera exists and create if it doesn't
-	camera, err := h.cameraService.GetCameraByID(ctx, recognition.CameraID)
-	if err != nil || camera == nil {
-		newCamera := &entity.Camera{
-			ID:     recognition.CameraID,
-			Name:   fmt.Sprintf("Camera %d (Auto-created)", recognition.CameraID),
-			Status: "active",
-		}
-
-		err := h.cameraService.CreateCamera(ctx, newCamera)
-		if err != nil {
-			return fmt.Errorf("failed to create camera: %w", err)
-		}
+	// Resolve the camera from the device identifiers
+	cameraID, err := resolveDeviceCamera(ctx, h.cameraDeviceService, recognitionData.Identity())
+	if err != nil {
+		return err
 	}
+	recognition.CameraID = cameraID
 
 	// Create or update face recognition in database
 	if isUpdate {
@@ -201,18 +198,8 @@ func (a RecognitionData) ToModel() (*entity.FaceRecognition, error) {
 		return nil, err
 	}
 
-	// Use camera helper to determine camera ID
-	cameraResolver := utils.NewCameraIDResolver()
-	var cameraID uint
-	if a.CameraID != nil {
-		cameraID = cameraResolver.ResolveCameraID(*a.CameraID, 0)
-	} else {
-		cameraID = cameraResolver.ResolveCameraID(0, 0) // Will default to 1
-	}
-
 	data := &entity.FaceRecognition{
 		ID:         a.UUID,
-		CameraID:   cameraID,
 		ObjectName: a.ObjectID,
 		DetectedAt: parsedTime,
 		ImagePath:  a.ImagePath,
@@ -220,3 +207,12 @@ func (a RecognitionData) ToModel() (*entity.FaceRecognition, error) {
 
 	return data, nil
 }
+
+// Identity returns the identifiers of the device that recognized the face
+func (a RecognitionData) Identity() entity.DeviceIdentity {
+	return entity.DeviceIdentity{
+		Serial:   a.DeviceSerial,
+		Channel:  a.Channel,
+		LegacyID: a.CameraID,
+	}
+}
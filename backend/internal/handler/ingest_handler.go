@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PayloadProcessor processes a single JSON record that would otherwise arrive as a file drop
+type PayloadProcessor interface {
+	ProcessPayload(ctx context.Context, payload []byte) error
+	GetName() string
+}
+
+// IngestResult describes the outcome of ingesting a single record
+type IngestResult struct {
+	Index  int    `json:"index"`
+	UUID   string `json:"uuid,omitempty"`
+	Status string `json:"status"` // ok, error
+	Error  string `json:"error,omitempty"`
+}
+
+// IngestHandler handles HTTP batch ingestion of detection records
+type IngestHandler struct {
+	processors map[string]PayloadProcessor
+	mu         sync.RWMutex
+	auth       fiber.Handler
+}
+
+// NewIngestHandler creates a new ingest handler protected by the given auth middleware
+func NewIngestHandler(auth fiber.Handler) *IngestHandler {
+	return &IngestHandler{
+		processors: make(map[string]PayloadProcessor),
+		auth:       auth,
+	}
+}
+
+// RegisterKind registers the processor used for /ingest/{kind}
+func (h *IngestHandler) RegisterKind(kind string, processor PayloadProcessor) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.processors[strings.ToLower(kind)] = processor
+}
+
+// UnregisterKind removes an ingestion kind
+func (h *IngestHandler) UnregisterKind(kind string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.processors, strings.ToLower(kind))
+}
+
+// Kinds returns the registered ingestion kinds
+func (h *IngestHandler) Kinds() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	kinds := make([]string, 0, len(h.processors))
+	for kind := range h.processors {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *IngestHandler) RegisterRoutes(router fiber.Router) {
+	// The auth middleware is attached per route, a group middleware would match every path
+	// starting with /ingest, such as /ingestion
+	ingest := router.Group("/ingest")
+
+	ingest.Get("/", h.auth, h.ListKinds)
+	ingest.Post("/:kind", h.auth, h.Ingest)
+}
+
+// ListKinds handles listing the supported ingestion kinds
+func (h *IngestHandler) ListKinds(c *fiber.Ctx) error {
+	kinds := h.Kinds()
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(kinds),
+		"data":  kinds,
+	})
+}
+
+// Ingest handles a single object, a JSON array or an NDJSON body for the given kind
+func (h *IngestHandler) Ingest(c *fiber.Ctx) error {
+	kind := strings.ToLower(c.Params("kind"))
+
+	h.mu.RLock()
+	processor, ok := h.processors[kind]
+	h.mu.RUnlock()
+
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": true,
+			"msg":   fmt.Sprintf("unknown ingestion kind '%s'", kind),
+		})
+	}
+
+	records, err := splitRecords(c.Body())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	if len(records) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Request body contains no records",
+		})
+	}
+
+	ctx := c.UserContext()
+	results := make([]IngestResult, 0, len(records))
+	succeeded := 0
+
+	for i, record := range records {
+		result := IngestResult{
+			Index: i,
+			UUID:  recordUUID(record),
+		}
+
+		if err := processor.ProcessPayload(ctx, record); err != nil {
+			result.Status = "error"
+			result.Error = err.Error()
+		} else {
+			result.Status = "ok"
+			succeeded++
+		}
+
+		results = append(results, result)
+	}
+
+	failed := len(records) - succeeded
+
+	status := fiber.StatusOK
+	msg := "All records ingested successfully"
+	if failed > 0 && succeeded > 0 {
+		status = fiber.StatusMultiStatus
+		msg = "Some records failed to ingest"
+	} else if failed > 0 {
+		status = fiber.StatusUnprocessableEntity
+		msg = "All records failed to ingest"
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error":     succeeded == 0,
+		"msg":       msg,
+		"kind":      kind,
+		"handler":   processor.GetName(),
+		"count":     len(records),
+		"succeeded": succeeded,
+		"failed":    failed,
+		"data":      results,
+	})
+}
+
+// splitRecords splits a request body into individual JSON records. It accepts a
+// single object, a JSON array of objects or newline-delimited JSON.
+func splitRecords(body []byte) ([]json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+
+	if trimmed[0] == '[' {
+		var records []json.RawMessage
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON array: %w", err)
+		}
+		return records, nil
+	}
+
+	// A single object is just NDJSON with one line
+	var records []json.RawMessage
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	for {
+		var record json.RawMessage
+		err := decoder.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse record %d: %w", len(records), err)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// recordUUID extracts the uuid field of a record for reporting, if present
+func recordUUID(record json.RawMessage) string {
+	var data struct {
+		UUID string `json:"uuid"`
+	}
+	if err := json.Unmarshal(record, &data); err != nil {
+		return ""
+	}
+	return data.UUID
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"people-counting/internal/domain/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// IngestionLedgerHandler handles HTTP requests related to the ingestion history
+type IngestionLedgerHandler struct {
+	ledgerService service.IngestionLedgerService
+}
+
+// NewIngestionLedgerHandler creates a new ingestion ledger handler
+func NewIngestionLedgerHandler(ledgerService service.IngestionLedgerService) *IngestionLedgerHandler {
+	return &IngestionLedgerHandler{
+		ledgerService: ledgerService,
+	}
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *IngestionLedgerHandler) RegisterRoutes(router fiber.Router) {
+	ingestion := router.Group("/ingestion")
+
+	ingestion.Get("/history", h.GetHistory)
+}
+
+// GetHistory handles getting paginated ingestion ledger entries
+func (h *IngestionLedgerHandler) GetHistory(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Get pagination parameters
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 50)
+
+	// Get filter parameters
+	status := c.Query("status", "")
+	handlerName := c.Query("handler", "")
+	search := c.Query("search", "")
+
+	entries, total, err := h.ledgerService.GetHistory(ctx, page, limit, status, handlerName, search)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+
+		if err.Error() == "invalid status. Must be processing, retrying, processed, or failed" {
+			status = fiber.StatusBadRequest
+		}
+
+		return c.Status(status).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(entries),
+		"total": total,
+		"page":  page,
+		"pages": (total + int64(limit) - 1) / int64(limit),
+		"data":  entries,
+	})
+}
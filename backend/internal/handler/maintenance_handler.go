@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MaintenanceHandler handles HTTP requests related to maintenance windows
+type MaintenanceHandler struct {
+	maintenanceService service.MaintenanceService
+}
+
+// maintenanceWindowRequest is the body of create and update requests
+type maintenanceWindowRequest struct {
+	Name          string     `json:"name"`
+	Description   string     `json:"description"`
+	Enabled       *bool      `json:"enabled"`
+	CameraID      *uint      `json:"camera_id"`
+	Zone          string     `json:"zone"`
+	AlertTypeID   *uint      `json:"alert_type_id"`
+	StartsAt      *time.Time `json:"starts_at"`
+	EndsAt        *time.Time `json:"ends_at"`
+	ScheduleDays  string     `json:"schedule_days"`
+	ScheduleStart string     `json:"schedule_start"`
+	ScheduleEnd   string     `json:"schedule_end"`
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(maintenanceService service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceService: maintenanceService,
+	}
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *MaintenanceHandler) RegisterRoutes(router fiber.Router) {
+	windows := router.Group("/maintenance-windows")
+
+	windows.Get("/", h.ListWindows)
+	windows.Get("/:id", h.GetWindow)
+	windows.Post("/", h.CreateWindow)
+	windows.Put("/:id", h.UpdateWindow)
+	windows.Delete("/:id", h.DeleteWindow)
+}
+
+// ListWindows handles getting maintenance windows
+func (h *MaintenanceHandler) ListWindows(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Get filter parameters
+	enabled := c.Query("enabled", "")
+	cameraID := c.Query("camera_id", "")
+	alertTypeID := c.Query("alert_type_id", "")
+	active := c.Query("active", "")
+
+	windows, err := h.maintenanceService.GetAllWindows(ctx, enabled, cameraID, alertTypeID, active)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(windows),
+		"data":  windows,
+	})
+}
+
+// GetWindow handles getting a maintenance window by ID
+func (h *MaintenanceHandler) GetWindow(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid maintenance window ID",
+		})
+	}
+
+	window, err := h.maintenanceService.GetWindowByID(ctx, uint(id))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  window,
+	})
+}
+
+// CreateWindow handles creating a maintenance window
+func (h *MaintenanceHandler) CreateWindow(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse request body
+	request := new(maintenanceWindowRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	window := request.toWindow()
+
+	if err := h.maintenanceService.CreateWindow(ctx, window); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"error": false,
+		"msg":   "Maintenance window created successfully",
+		"data":  window,
+	})
+}
+
+// UpdateWindow handles updating a maintenance window
+func (h *MaintenanceHandler) UpdateWindow(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid maintenance window ID",
+		})
+	}
+
+	// Parse request body
+	request := new(maintenanceWindowRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	window := request.toWindow()
+	window.ID = uint(id)
+
+	if err := h.maintenanceService.UpdateWindow(ctx, window); err != nil {
+		return h.writeError(c, err)
+	}
+
+	// Get updated window
+	updated, err := h.maintenanceService.GetWindowByID(ctx, uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Error retrieving updated maintenance window: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Maintenance window updated successfully",
+		"data":  updated,
+	})
+}
+
+// DeleteWindow handles deleting a maintenance window
+func (h *MaintenanceHandler) DeleteWindow(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid maintenance window ID",
+		})
+	}
+
+	if err := h.maintenanceService.DeleteWindow(ctx, uint(id)); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Maintenance window deleted successfully",
+	})
+}
+
+// writeError maps maintenance window validation errors to response statuses
+func (h *MaintenanceHandler) writeError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+
+	switch err.Error() {
+	case "invalid camera ID",
+		"invalid alert type ID",
+		"name is required",
+		"camera ID, zone or alert type ID is required":
+		status = fiber.StatusBadRequest
+	case "a maintenance window with the same name already exists":
+		status = fiber.StatusConflict
+	case "maintenance window not found", "camera not found", "alert type not found":
+		status = fiber.StatusNotFound
+	default:
+		if strings.HasPrefix(err.Error(), "invalid schedule") {
+			status = fiber.StatusBadRequest
+		}
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": true,
+		"msg":   err.Error(),
+	})
+}
+
+func (r *maintenanceWindowRequest) toWindow() *entity.MaintenanceWindow {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	return &entity.MaintenanceWindow{
+		Name:          r.Name,
+		Description:   r.Description,
+		Enabled:       enabled,
+		CameraID:      r.CameraID,
+		Zone:          r.Zone,
+		AlertTypeID:   r.AlertTypeID,
+		StartsAt:      r.StartsAt,
+		EndsAt:        r.EndsAt,
+		ScheduleDays:  r.ScheduleDays,
+		ScheduleStart: r.ScheduleStart,
+		ScheduleEnd:   r.ScheduleEnd,
+	}
+}
@@ -0,0 +1,502 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NotificationHandler handles HTTP requests related to outbound alert notifications
+type NotificationHandler struct {
+	notificationService service.NotificationService
+}
+
+// notificationChannelRequest is the body of channel create and update requests
+type notificationChannelRequest struct {
+	Name            string            `json:"name"`
+	Type            string            `json:"type"`
+	Enabled         *bool             `json:"enabled"`
+	URL             string            `json:"url"`
+	Method          string            `json:"method"`
+	Headers         map[string]string `json:"headers"`
+	Secret          string            `json:"secret"`
+	Recipients      string            `json:"recipients"`
+	SubjectTemplate string            `json:"subject_template"`
+	BodyTemplate    string            `json:"body_template"`
+}
+
+// notificationRouteRequest is the body of route create and update requests
+type notificationRouteRequest struct {
+	Name        string `json:"name"`
+	ChannelID   uint   `json:"channel_id"`
+	AlertTypeID *uint  `json:"alert_type_id"`
+	CameraID    *uint  `json:"camera_id"`
+	MinSeverity string `json:"min_severity"`
+	Events      string `json:"events"`
+	Enabled     *bool  `json:"enabled"`
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *NotificationHandler) RegisterRoutes(router fiber.Router) {
+	notifications := router.Group("/notifications")
+
+	channels := notifications.Group("/channels")
+	channels.Get("/", h.ListChannels)
+	channels.Get("/:id", h.GetChannel)
+	channels.Post("/", h.CreateChannel)
+	channels.Put("/:id", h.UpdateChannel)
+	channels.Delete("/:id", h.DeleteChannel)
+	channels.Post("/:id/test", h.TestChannel)
+
+	routes := notifications.Group("/routes")
+	routes.Get("/", h.ListRoutes)
+	routes.Get("/:id", h.GetRoute)
+	routes.Post("/", h.CreateRoute)
+	routes.Put("/:id", h.UpdateRoute)
+	routes.Delete("/:id", h.DeleteRoute)
+
+	deliveries := notifications.Group("/deliveries")
+	deliveries.Get("/", h.ListDeliveries)
+	deliveries.Post("/:id/retry", h.RetryDelivery)
+}
+
+// ListChannels handles getting all notification channels
+func (h *NotificationHandler) ListChannels(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	channels, err := h.notificationService.GetAllChannels(ctx)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(channels),
+		"data":  channels,
+	})
+}
+
+// GetChannel handles getting a notification channel by ID
+func (h *NotificationHandler) GetChannel(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid notification channel ID",
+		})
+	}
+
+	channel, err := h.notificationService.GetChannelByID(ctx, uint(id))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  channel,
+	})
+}
+
+// CreateChannel handles creating a notification channel
+func (h *NotificationHandler) CreateChannel(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse request body
+	request := new(notificationChannelRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	channel := request.toChannel()
+
+	if err := h.notificationService.CreateChannel(ctx, channel); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"error": false,
+		"msg":   "Notification channel created successfully",
+		"data":  channel,
+	})
+}
+
+// UpdateChannel handles updating a notification channel
+func (h *NotificationHandler) UpdateChannel(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid notification channel ID",
+		})
+	}
+
+	// Parse request body
+	request := new(notificationChannelRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	channel := request.toChannel()
+	channel.ID = uint(id)
+
+	if err := h.notificationService.UpdateChannel(ctx, channel); err != nil {
+		return h.writeError(c, err)
+	}
+
+	// Get updated channel
+	updated, err := h.notificationService.GetChannelByID(ctx, uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Error retrieving updated notification channel: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Notification channel updated successfully",
+		"data":  updated,
+	})
+}
+
+// DeleteChannel handles deleting a notification channel and its routes
+func (h *NotificationHandler) DeleteChannel(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid notification channel ID",
+		})
+	}
+
+	if err := h.notificationService.DeleteChannel(ctx, uint(id)); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Notification channel deleted successfully",
+	})
+}
+
+// TestChannel handles sending a sample notification through a channel, such as to a local
+// SMTP or HTTP stand-in while setting it up
+func (h *NotificationHandler) TestChannel(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid notification channel ID",
+		})
+	}
+
+	delivery, err := h.notificationService.TestChannel(ctx, uint(id))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	if delivery.Status != entity.NotificationStatusSent {
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Test notification failed: " + delivery.LastError,
+			"data":  delivery,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Test notification sent successfully",
+		"data":  delivery,
+	})
+}
+
+// ListRoutes handles getting notification routes
+func (h *NotificationHandler) ListRoutes(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	routes, err := h.notificationService.GetAllRoutes(ctx, c.Query("channel_id", ""))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(routes),
+		"data":  routes,
+	})
+}
+
+// GetRoute handles getting a notification route by ID
+func (h *NotificationHandler) GetRoute(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid notification route ID",
+		})
+	}
+
+	route, err := h.notificationService.GetRouteByID(ctx, uint(id))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  route,
+	})
+}
+
+// CreateRoute handles creating a notification route
+func (h *NotificationHandler) CreateRoute(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse request body
+	request := new(notificationRouteRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	route := request.toRoute()
+
+	if err := h.notificationService.CreateRoute(ctx, route); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"error": false,
+		"msg":   "Notification route created successfully",
+		"data":  route,
+	})
+}
+
+// UpdateRoute handles updating a notification route
+func (h *NotificationHandler) UpdateRoute(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid notification route ID",
+		})
+	}
+
+	// Parse request body
+	request := new(notificationRouteRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	route := request.toRoute()
+	route.ID = uint(id)
+
+	if err := h.notificationService.UpdateRoute(ctx, route); err != nil {
+		return h.writeError(c, err)
+	}
+
+	// Get updated route
+	updated, err := h.notificationService.GetRouteByID(ctx, uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Error retrieving updated notification route: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Notification route updated successfully",
+		"data":  updated,
+	})
+}
+
+// DeleteRoute handles deleting a notification route
+func (h *NotificationHandler) DeleteRoute(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid notification route ID",
+		})
+	}
+
+	if err := h.notificationService.DeleteRoute(ctx, uint(id)); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Notification route deleted successfully",
+	})
+}
+
+// ListDeliveries handles getting paginated notification deliveries
+func (h *NotificationHandler) ListDeliveries(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Get pagination parameters
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 50)
+
+	// Get filter parameters
+	status := c.Query("status", "")
+	channelID := c.Query("channel_id", "")
+	alertID := c.Query("alert_id", "")
+
+	deliveries, total, err := h.notificationService.GetDeliveries(ctx, page, limit, status, channelID, alertID)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(deliveries),
+		"total": total,
+		"page":  page,
+		"pages": (total + int64(limit) - 1) / int64(limit),
+		"data":  deliveries,
+	})
+}
+
+// RetryDelivery handles sending a pending or failed delivery again right away
+func (h *NotificationHandler) RetryDelivery(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid notification delivery ID",
+		})
+	}
+
+	delivery, err := h.notificationService.RetryDelivery(ctx, uint(id))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": delivery.Status != entity.NotificationStatusSent,
+		"msg":   "Notification retried, status " + delivery.Status,
+		"data":  delivery,
+	})
+}
+
+// writeError maps notification validation errors to response statuses
+func (h *NotificationHandler) writeError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+
+	switch err.Error() {
+	case "name is required",
+		"channel ID is required",
+		"secret is required for webhook channels",
+		"recipients are required for email channels",
+		"test notifications cannot be retried":
+		status = fiber.StatusBadRequest
+	case "a notification channel with the same name already exists",
+		"notification was already sent",
+		"notification is already being sent":
+		status = fiber.StatusConflict
+	case "notification channel not found",
+		"notification route not found",
+		"notification delivery not found",
+		"alert type not found",
+		"alert not found",
+		"camera not found":
+		status = fiber.StatusNotFound
+	default:
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			status = fiber.StatusBadRequest
+		}
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": true,
+		"msg":   err.Error(),
+	})
+}
+
+func (r *notificationChannelRequest) toChannel() *entity.NotificationChannel {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	return &entity.NotificationChannel{
+		Name:            r.Name,
+		Type:            r.Type,
+		Enabled:         enabled,
+		URL:             r.URL,
+		Method:          r.Method,
+		Headers:         r.Headers,
+		Secret:          r.Secret,
+		Recipients:      r.Recipients,
+		SubjectTemplate: r.SubjectTemplate,
+		BodyTemplate:    r.BodyTemplate,
+	}
+}
+
+func (r *notificationRouteRequest) toRoute() *entity.NotificationRoute {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	return &entity.NotificationRoute{
+		Name:        r.Name,
+		ChannelID:   r.ChannelID,
+		AlertTypeID: r.AlertTypeID,
+		CameraID:    r.CameraID,
+		MinSeverity: r.MinSeverity,
+		Events:      r.Events,
+		Enabled:     enabled,
+	}
+}
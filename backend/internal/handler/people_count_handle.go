@@ -3,11 +3,15 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"people-counting/internal/domain/entity"
 	"people-counting/internal/domain/service"
 	"people-counting/pkg/utils"
+	"people-counting/pkg/watcher"
+	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,7 +19,9 @@ import (
 
 type PeopleCountData struct {
 	UUID               string  `json:"uuid"`
-	CCTVID             uint    `json:"cctv_id"`
+	CCTVID             *uint   `json:"cctv_id"`
+	DeviceSerial       string  `json:"device_serial"`
+	Channel            int     `json:"channel"`
 	InCount            int     `json:"in_count"`
 	OutCount           int     `json:"out_count"`
 	FemaleCount        int     `json:"female_count"`
@@ -26,19 +32,30 @@ type PeopleCountData struct {
 	DeviceTimestamp    string  `json:"device_timestamp"`
 	DeviceTimestampUTC float64 `json:"device_timestamp_utc"`
 	SyncStatus         bool    `json:"sync_status"`
+	ConfigVersion      *int    `json:"config_version"` // Of the camera configuration the device ran
 }
 
 // PeopleCountHandler handles HTTP requests related to people counts
 type PeopleCountHandler struct {
-	peopleCountService service.PeopleCountService
-	cameraService      service.CameraService
+	peopleCountService  service.PeopleCountService
+	cameraDeviceService service.CameraDeviceService
+	webSocketService    service.WebSocketService
+
+	// occupancyPending is set while a coalesced occupancy broadcast is scheduled
+	occupancyPending bool
+	occupancyMu      sync.Mutex
 }
 
+// occupancyBroadcastDelay is how long counts recorded in a row are coalesced into a single
+// occupancy broadcast, so a large batch does not compute the occupancy once per record
+const occupancyBroadcastDelay = time.Second
+
 // NewPeopleCountHandler creates a new people count handler
-func NewPeopleCountHandler(peopleCountService service.PeopleCountService, cameraService service.CameraService) *PeopleCountHandler {
+func NewPeopleCountHandler(peopleCountService service.PeopleCountService, cameraDeviceService service.CameraDeviceService, webSocketService service.WebSocketService) *PeopleCountHandler {
 	return &PeopleCountHandler{
-		peopleCountService: peopleCountService,
-		cameraService:      cameraService,
+		peopleCountService:  peopleCountService,
+		cameraDeviceService: cameraDeviceService,
+		webSocketService:    webSocketService,
 	}
 }
 
@@ -52,6 +69,7 @@ func (h *PeopleCountHandler) RegisterRoutes(router fiber.Router) {
 	counts.Get("/trends", h.GetCountsTrend)
 	counts.Get("/distribution", h.GetCountsDistribution)
 	counts.Get("/peak-hours", h.GetPeakHoursAnalysis)
+	counts.Get("/occupancy", h.GetOccupancy)
 }
 
 // GetAllCounts handles getting paginated people count records
@@ -63,21 +81,21 @@ func (h *PeopleCountHandler) GetAllCounts(c *fiber.Ctx) error {
 	limit := c.QueryInt("limit", 50)
 
 	// Get filter parameters
-	areaID := c.Query("area_id", "")
+	zoneID := zoneIDQuery(c)
 	from := c.Query("from", "")
 	to := c.Query("to", "")
-	includeArea := c.Query("include_area") == "true"
+	includeZones := c.Query("include_zones") == "true" || c.Query("include_area") == "true"
 
-	counts, total, err := h.peopleCountService.GetAllCounts(ctx, page, limit, areaID, from, to, includeArea)
+	counts, total, err := h.peopleCountService.GetAllCounts(ctx, page, limit, zoneID, from, to, includeZones)
 	if err != nil {
 		status := fiber.StatusInternalServerError
 
 		// Check for specific errors
-		if err.Error() == "invalid area ID" ||
+		if err.Error() == "invalid zone ID" ||
 			err.Error() == "invalid 'from' date format. Use RFC3339 format (e.g. 2025-05-13T10:00:00Z)" ||
 			err.Error() == "invalid 'to' date format. Use RFC3339 format (e.g. 2025-05-13T10:00:00Z)" {
 			status = fiber.StatusBadRequest
-		} else if err.Error() == "area not found" {
+		} else if err.Error() == "zone not found" {
 			status = fiber.StatusNotFound
 		}
 
@@ -118,7 +136,8 @@ func (h *PeopleCountHandler) RecordCount(c *fiber.Ctx) error {
 
 		// Check for validation errors
 		if err.Error() == "area ID is required" ||
-			err.Error() == "demographic counts (child + adult + elderly) must equal gender counts (male + female)" {
+			err.Error() == "demographic counts (child + adult + elderly) must equal gender counts (male + female)" ||
+			errors.Is(err, service.ErrInvalidConfigVersion) {
 			status = fiber.StatusBadRequest
 		} else if err.Error() == "area not found" {
 			status = fiber.StatusNotFound
@@ -130,6 +149,8 @@ func (h *PeopleCountHandler) RecordCount(c *fiber.Ctx) error {
 		})
 	}
 
+	h.notifyOccupancy()
+
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"error": false,
 		"msg":   "Count recorded successfully",
@@ -149,9 +170,17 @@ func (h *PeopleCountHandler) GetCountsSummary(c *fiber.Ctx) error {
 	// Convert to strings for service layer
 	from, to := dateRange.ToRFC3339Strings()
 
-	summary, err := h.peopleCountService.GetCountsSummary(ctx, from, to)
+	summary, err := h.peopleCountService.GetCountsSummary(ctx, zoneIDQuery(c), from, to)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		status := fiber.StatusInternalServerError
+
+		if err.Error() == "invalid zone ID" {
+			status = fiber.StatusBadRequest
+		} else if err.Error() == "zone not found" {
+			status = fiber.StatusNotFound
+		}
+
+		return c.Status(status).JSON(fiber.Map{
 			"error": true,
 			"msg":   "Error getting counts summary: " + err.Error(),
 		})
@@ -169,7 +198,7 @@ func (h *PeopleCountHandler) GetCountsTrend(c *fiber.Ctx) error {
 
 	// Get parameters
 	interval := c.Query("interval", "hour") // hour, day, week, month
-	areaID := c.Query("area_id", "")        // optional area filter
+	zoneID := zoneIDQuery(c)                // optional zone filter
 
 	dateRange, err := utils.ParseDateRangeFromQuery(c)
 	if err != nil {
@@ -179,14 +208,14 @@ func (h *PeopleCountHandler) GetCountsTrend(c *fiber.Ctx) error {
 	// Convert to strings for service layer
 	from, to := dateRange.ToRFC3339Strings()
 
-	trends, err := h.peopleCountService.GetCountsTrend(ctx, interval, areaID, from, to)
+	trends, err := h.peopleCountService.GetCountsTrend(ctx, interval, zoneID, from, to)
 	if err != nil {
 		status := fiber.StatusInternalServerError
 
 		if err.Error() == "invalid interval. Must be hour, day, week, or month" ||
-			err.Error() == "invalid area ID" {
+			err.Error() == "invalid zone ID" {
 			status = fiber.StatusBadRequest
-		} else if err.Error() == "area not found" {
+		} else if err.Error() == "zone not found" {
 			status = fiber.StatusNotFound
 		}
 
@@ -238,15 +267,17 @@ func (h *PeopleCountHandler) GetName() string {
 }
 
 func (h *PeopleCountHandler) ProcessFile(filePath string) error {
-	// Create a background context
-	ctx := context.Background()
-
 	// Read file contents
 	fileData, err := os.ReadFile(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
+	return h.ProcessPayload(context.Background(), fileData)
+}
+
+// ProcessPayload processes a single JSON people count record
+func (h *PeopleCountHandler) ProcessPayload(ctx context.Context, fileData []byte) error {
 	// Parse JSON into AlertData struct
 	var countingData PeopleCountData
 	if err := json.Unmarshal(fileData, &countingData); err != nil {
@@ -264,34 +295,31 @@ func (h *PeopleCountHandler) ProcessFile(filePath string) error {
 	// Convert AlertData to Alert entity
 	counting, err := countingData.ToModel()
 	if err != nil {
-		return fmt.Errorf("failed to convert data to alert: %w", err)
+		return watcher.Permanent(fmt.Errorf("failed to convert data to alert: %w", err))
 	}
 
-	// Check if camera exists and create if it doesn't
-	camera, err := h.cameraService.GetCameraByID(ctx, counting.CameraID)
-	if err != nil || camera == nil {
-		newCamera := &entity.Camera{
-			ID:     counting.CameraID,
-			Name:   fmt.Sprintf("Camera %d (Auto-created)", counting.CameraID),
-			Status: "active",
-		}
-
-		err := h.cameraService.CreateCamera(ctx, newCamera)
-		if err != nil {
-			return fmt.Errorf("failed to create camera: %w", err)
-		}
+	// Resolve the camera from the device identifiers
+	cameraID, err := resolveDeviceCamera(ctx, h.cameraDeviceService, countingData.Identity())
+	if err != nil {
+		return err
 	}
+	counting.CameraID = cameraID
+
+	// The version of the configuration the count was made with, the current one when not sent
+	counting.ConfigVersion = countingData.ConfigVersion
 
 	// Create or update people count data in database
 	if isUpdate {
 		if err := h.peopleCountService.UpdatePeopleCount(ctx, counting); err != nil {
-			return fmt.Errorf("failed to update counting data: %w", err)
+			return ingestionError("failed to update counting data", err)
 		}
 	} else {
 		if err := h.peopleCountService.CreatePeopleCount(ctx, counting); err != nil {
-			return fmt.Errorf("failed to save counting data: %w", err)
+			return ingestionError("failed to save counting data", err)
 		}
 	}
+
+	h.notifyOccupancy()
 	return nil
 }
 
@@ -306,28 +334,31 @@ func (p PeopleCountData) ToModel() (*entity.PeopleCount, error) {
 		}
 	}
 
-	totalCount := p.InCount - p.OutCount
-
-	// Use camera helper to determine camera ID
-	cameraResolver := utils.NewCameraIDResolver()
-	cameraID := cameraResolver.ResolveCameraID(0, p.CCTVID) // Only CCTVID is available in this struct
-
 	// Buat entity PeopleCount baru
 	peopleCount := &entity.PeopleCount{
 		ID:           p.UUID,
-		CameraID:     cameraID,
 		Timestamp:    timestamp,
 		MaleCount:    p.MaleCount,
 		FemaleCount:  p.FemaleCount,
 		ChildCount:   p.ChildrenCount,
 		AdultCount:   p.AdultCount,
 		ElderlyCount: p.ElderCount,
-		TotalCount:   totalCount,
+		InCount:      p.InCount,
+		OutCount:     p.OutCount,
 	}
 
 	return peopleCount, nil
 }
 
+// Identity returns the identifiers of the device that produced the count
+func (p PeopleCountData) Identity() entity.DeviceIdentity {
+	return entity.DeviceIdentity{
+		Serial:   p.DeviceSerial,
+		Channel:  p.Channel,
+		LegacyID: p.CCTVID,
+	}
+}
+
 // GetPeakHoursAnalysis handles getting peak hours analysis
 func (h *PeopleCountHandler) GetPeakHoursAnalysis(c *fiber.Ctx) error {
 	ctx := c.Context()
@@ -361,3 +392,67 @@ func (h *PeopleCountHandler) GetPeakHoursAnalysis(c *fiber.Ctx) error {
 		"data":  analysis,
 	})
 }
+
+// GetOccupancy handles getting live occupancy per camera and zone since the last daily reset
+func (h *PeopleCountHandler) GetOccupancy(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Get filter parameters
+	cameraID := c.Query("camera_id", "")
+	zone := c.Query("zone", "") // Camera location
+	zoneID := zoneIDQuery(c)
+
+	occupancy, err := h.peopleCountService.GetOccupancy(ctx, cameraID, zone, zoneID)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+
+		if err.Error() == "invalid camera ID" || err.Error() == "invalid zone ID" {
+			status = fiber.StatusBadRequest
+		} else if err.Error() == "zone not found" {
+			status = fiber.StatusNotFound
+		}
+
+		return c.Status(status).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  occupancy,
+	})
+}
+
+// notifyOccupancy schedules a broadcast of the live occupancy after a count has been recorded.
+// Counts recorded before the broadcast runs share it.
+func (h *PeopleCountHandler) notifyOccupancy() {
+	if h.webSocketService == nil {
+		return
+	}
+
+	h.occupancyMu.Lock()
+	defer h.occupancyMu.Unlock()
+
+	if h.occupancyPending {
+		return
+	}
+	h.occupancyPending = true
+
+	time.AfterFunc(occupancyBroadcastDelay, h.broadcastOccupancy)
+}
+
+// broadcastOccupancy computes the live occupancy and broadcasts it
+func (h *PeopleCountHandler) broadcastOccupancy() {
+	h.occupancyMu.Lock()
+	h.occupancyPending = false
+	h.occupancyMu.Unlock()
+
+	occupancy, err := h.peopleCountService.GetOccupancy(context.Background(), "", "", "")
+	if err != nil {
+		log.Printf("WARNING: Failed to compute occupancy: %v", err)
+		return
+	}
+
+	h.webSocketService.NotifyOccupancy(occupancy)
+}
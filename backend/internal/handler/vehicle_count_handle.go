@@ -3,11 +3,13 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"people-counting/internal/domain/entity"
 	"people-counting/internal/domain/service"
 	"people-counting/pkg/utils"
+	"people-counting/pkg/watcher"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,26 +17,29 @@ import (
 
 type VehicleCountData struct {
 	UUID               string  `json:"uuid"`
-	CctvID             uint    `json:"cctv_id"`
+	CctvID             *uint   `json:"cctv_id"`
+	DeviceSerial       string  `json:"device_serial"`
+	Channel            int     `json:"channel"`
 	InCountCar         int     `json:"in_count_car"`
 	InCountTruck       int     `json:"in_count_truck"`
 	InCountPeople      int     `json:"in_count_people"`
 	OutCount           int     `json:"out_count"`
 	DeviceTimestamp    string  `json:"device_timestamp"`
 	DeviceTimestampUTC float64 `json:"device_timestamp_utc"`
+	ConfigVersion      *int    `json:"config_version"` // Of the camera configuration the device ran
 }
 
 // VehicleCountHandler handles HTTP requests related to vehicle counts
 type VehicleCountHandler struct {
 	vehicleCountService service.VehicleCountService
-	cameraService       service.CameraService
+	cameraDeviceService service.CameraDeviceService
 }
 
 // NewVehicleCountHandler creates a new vehicle count handler
-func NewVehicleCountHandler(vehicleCountService service.VehicleCountService, cameraService service.CameraService) *VehicleCountHandler {
+func NewVehicleCountHandler(vehicleCountService service.VehicleCountService, cameraDeviceService service.CameraDeviceService) *VehicleCountHandler {
 	return &VehicleCountHandler{
 		vehicleCountService: vehicleCountService,
-		cameraService:       cameraService,
+		cameraDeviceService: cameraDeviceService,
 	}
 }
 
@@ -114,7 +119,7 @@ func (h *VehicleCountHandler) RecordCount(c *fiber.Ctx) error {
 		status := fiber.StatusInternalServerError
 
 		// Check for validation errors
-		if err.Error() == "cctv ID is required" {
+		if err.Error() == "cctv ID is required" || errors.Is(err, service.ErrInvalidConfigVersion) {
 			status = fiber.StatusBadRequest
 		} else if err.Error() == "cctv not found" {
 			status = fiber.StatusNotFound
@@ -302,15 +307,17 @@ func (h *VehicleCountHandler) GetName() string {
 }
 
 func (h *VehicleCountHandler) ProcessFile(filePath string) error {
-	// Create a background context
-	ctx := context.Background()
-
 	// Read file contents
 	fileData, err := os.ReadFile(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
+	return h.ProcessPayload(context.Background(), fileData)
+}
+
+// ProcessPayload processes a single JSON vehicle count record
+func (h *VehicleCountHandler) ProcessPayload(ctx context.Context, fileData []byte) error {
 	// Parse JSON into VehicleCountData struct
 	var countingData VehicleCountData
 	if err := json.Unmarshal(fileData, &countingData); err != nil {
@@ -328,32 +335,27 @@ func (h *VehicleCountHandler) ProcessFile(filePath string) error {
 	// Convert VehicleCountData to VehicleCount entity
 	counting, err := countingData.ToModel()
 	if err != nil {
-		return fmt.Errorf("failed to convert data to vehicle count: %w", err)
+		return watcher.Permanent(fmt.Errorf("failed to convert data to vehicle count: %w", err))
 	}
 
-	// Check if camera exists and create if it doesn't
-	camera, err := h.cameraService.GetCameraByID(ctx, counting.CctvID)
-	if err != nil || camera == nil {
-		newCamera := &entity.Camera{
-			ID:     counting.CctvID,
-			Name:   fmt.Sprintf("CCTV %d (Auto-created)", counting.CctvID),
-			Status: "active",
-		}
-
-		err := h.cameraService.CreateCamera(ctx, newCamera)
-		if err != nil {
-			return fmt.Errorf("failed to create camera: %w", err)
-		}
+	// Resolve the camera from the device identifiers
+	cameraID, err := resolveDeviceCamera(ctx, h.cameraDeviceService, countingData.Identity())
+	if err != nil {
+		return err
 	}
+	counting.CctvID = cameraID
+
+	// The version of the configuration the count was made with, the current one when not sent
+	counting.ConfigVersion = countingData.ConfigVersion
 
 	// Create or update vehicle count data in database
 	if isUpdate {
 		if err := h.vehicleCountService.UpdateVehicleCount(ctx, counting); err != nil {
-			return fmt.Errorf("failed to update vehicle counting data: %w", err)
+			return ingestionError("failed to update vehicle counting data", err)
 		}
 	} else {
 		if err := h.vehicleCountService.CreateVehicleCount(ctx, counting); err != nil {
-			return fmt.Errorf("failed to save vehicle counting data: %w", err)
+			return ingestionError("failed to save vehicle counting data", err)
 		}
 	}
 	return nil
@@ -370,14 +372,9 @@ func (v VehicleCountData) ToModel() (*entity.VehicleCount, error) {
 		}
 	}
 
-	// Use camera helper to determine camera ID
-	cameraResolver := utils.NewCameraIDResolver()
-	cameraID := cameraResolver.ResolveCameraID(0, v.CctvID) // Only CctvID is available in this struct
-
 	// Create new VehicleCount entity
 	vehicleCount := &entity.VehicleCount{
 		ID:                 v.UUID,
-		CctvID:             cameraID,
 		Timestamp:          timestamp,
 		InCountCar:         v.InCountCar,
 		InCountTruck:       v.InCountTruck,
@@ -392,3 +389,12 @@ func (v VehicleCountData) ToModel() (*entity.VehicleCount, error) {
 
 	return vehicleCount, nil
 }
+
+// Identity returns the identifiers of the device that produced the count
+func (v VehicleCountData) Identity() entity.DeviceIdentity {
+	return entity.DeviceIdentity{
+		Serial:   v.DeviceSerial,
+		Channel:  v.Channel,
+		LegacyID: v.CctvID,
+	}
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"people-counting/internal/domain/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// WatcherHandler handles HTTP requests for folder watcher monitoring
+type WatcherHandler struct {
+	watcherService service.WatcherService
+}
+
+// NewWatcherHandler creates a new watcher handler
+func NewWatcherHandler(watcherService service.WatcherService) *WatcherHandler {
+	return &WatcherHandler{
+		watcherService: watcherService,
+	}
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *WatcherHandler) RegisterRoutes(router fiber.Router) {
+	ingestion := router.Group("/ingestion")
+
+	ingestion.Get("/watchers", h.GetStats)
+}
+
+// GetStats handles getting the folder watcher metrics
+func (h *WatcherHandler) GetStats(c *fiber.Ctx) error {
+	stats, err := h.watcherService.GetStats(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  stats,
+	})
+}
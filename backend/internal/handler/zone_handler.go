@@ -0,0 +1,466 @@
+package handler
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ZoneHandler handles HTTP requests related to sites, buildings, floors, zones and their cameras
+type ZoneHandler struct {
+	zoneService service.ZoneService
+}
+
+// zoneRequest is the body of create and update requests
+type zoneRequest struct {
+	ParentID    *uint  `json:"parent_id"`
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Capacity    int    `json:"capacity"`
+}
+
+// zoneCameraRequest places a camera in a zone
+type zoneCameraRequest struct {
+	CameraID  uint     `json:"camera_id"`
+	Direction string   `json:"direction"`
+	PlanX     *float64 `json:"plan_x"`
+	PlanY     *float64 `json:"plan_y"`
+}
+
+// zoneCamerasRequest is the body of requests replacing the cameras of a zone
+type zoneCamerasRequest struct {
+	Cameras []zoneCameraRequest `json:"cameras"`
+}
+
+// NewZoneHandler creates a new zone handler
+func NewZoneHandler(zoneService service.ZoneService) *ZoneHandler {
+	return &ZoneHandler{
+		zoneService: zoneService,
+	}
+}
+
+// RegisterRoutes registers routes for this handler
+func (h *ZoneHandler) RegisterRoutes(router fiber.Router) {
+	zones := router.Group("/zones")
+
+	zones.Get("/", h.ListZones)
+	zones.Get("/:id", h.GetZone)
+	zones.Post("/", h.CreateZone)
+	zones.Put("/:id", h.UpdateZone)
+	zones.Delete("/:id", h.DeleteZone)
+	zones.Get("/:id/cameras", h.GetZoneCameras)
+	zones.Put("/:id/cameras", h.SetZoneCameras)
+	zones.Put("/:id/cameras/:cameraId", h.AddZoneCamera)
+	zones.Delete("/:id/cameras/:cameraId", h.RemoveZoneCamera)
+	zones.Get("/:id/floor-plan", h.GetFloorPlan)
+	zones.Put("/:id/floor-plan", h.UploadFloorPlan)
+	zones.Delete("/:id/floor-plan", h.DeleteFloorPlan)
+}
+
+// ListZones handles getting zones, as a flat list or as a tree of sites
+func (h *ZoneHandler) ListZones(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Get filter parameters
+	kind := c.Query("kind", "")
+	tree := c.Query("tree") == "true"
+
+	zones, err := h.zoneService.GetAllZones(ctx, kind, tree)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(zones),
+		"data":  zones,
+	})
+}
+
+// GetZone handles getting a zone by ID with its cameras and the zones directly inside it
+func (h *ZoneHandler) GetZone(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid zone ID",
+		})
+	}
+
+	zone, err := h.zoneService.GetZoneByID(c.Context(), uint(id))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"data":  zone,
+	})
+}
+
+// CreateZone handles creating a zone
+func (h *ZoneHandler) CreateZone(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse request body
+	request := new(zoneRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	zone := request.toZone()
+
+	if err := h.zoneService.CreateZone(ctx, zone); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"error": false,
+		"msg":   "Zone created successfully",
+		"data":  zone,
+	})
+}
+
+// UpdateZone handles updating a zone
+func (h *ZoneHandler) UpdateZone(c *fiber.Ctx) error {
+	ctx := c.Context()
+
+	// Parse ID parameter
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid zone ID",
+		})
+	}
+
+	// Parse request body
+	request := new(zoneRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	zone := request.toZone()
+	zone.ID = uint(id)
+
+	if err := h.zoneService.UpdateZone(ctx, zone); err != nil {
+		return h.writeError(c, err)
+	}
+
+	// Get updated zone
+	updated, err := h.zoneService.GetZoneByID(ctx, uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Error retrieving updated zone: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Zone updated successfully",
+		"data":  updated,
+	})
+}
+
+// DeleteZone handles deleting a zone
+func (h *ZoneHandler) DeleteZone(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid zone ID",
+		})
+	}
+
+	if err := h.zoneService.DeleteZone(c.Context(), uint(id)); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Zone deleted successfully",
+	})
+}
+
+// GetZoneCameras handles getting the cameras of a zone and of the zones inside it
+func (h *ZoneHandler) GetZoneCameras(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid zone ID",
+		})
+	}
+
+	cameras, err := h.zoneService.GetZoneCameras(c.Context(), uint(id))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"count": len(cameras),
+		"data":  cameras,
+	})
+}
+
+// SetZoneCameras handles replacing the cameras of a zone and their directions
+func (h *ZoneHandler) SetZoneCameras(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid zone ID",
+		})
+	}
+
+	// Parse request body
+	request := new(zoneCamerasRequest)
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid request body: " + err.Error(),
+		})
+	}
+
+	cameras := make([]entity.ZoneCamera, 0, len(request.Cameras))
+	for _, camera := range request.Cameras {
+		cameras = append(cameras, camera.toZoneCamera(uint(id)))
+	}
+
+	zone, err := h.zoneService.SetZoneCameras(c.Context(), uint(id), cameras)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Zone cameras updated successfully",
+		"data":  zone,
+	})
+}
+
+// AddZoneCamera handles adding a camera to a zone or changing its direction and position
+func (h *ZoneHandler) AddZoneCamera(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid zone ID",
+		})
+	}
+
+	cameraID, err := strconv.ParseUint(c.Params("cameraId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid camera ID",
+		})
+	}
+
+	// Parse request body, the direction defaults to internal and the camera is not placed on the
+	// floor plan
+	request := new(zoneCameraRequest)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(request); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": true,
+				"msg":   "Invalid request body: " + err.Error(),
+			})
+		}
+	}
+
+	request.CameraID = uint(cameraID)
+
+	membership := request.toZoneCamera(uint(id))
+
+	zone, err := h.zoneService.AddZoneCamera(c.Context(), &membership)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Camera added to zone successfully",
+		"data":  zone,
+	})
+}
+
+// RemoveZoneCamera handles removing a camera from a zone
+func (h *ZoneHandler) RemoveZoneCamera(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid zone ID",
+		})
+	}
+
+	cameraID, err := strconv.ParseUint(c.Params("cameraId"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid camera ID",
+		})
+	}
+
+	if err := h.zoneService.RemoveZoneCamera(c.Context(), uint(id), uint(cameraID)); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Camera removed from zone successfully",
+	})
+}
+
+// UploadFloorPlan handles uploading the floor plan image of a zone, sent as the image field of a
+// multipart form
+func (h *ZoneHandler) UploadFloorPlan(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid zone ID",
+		})
+	}
+
+	file, err := c.FormFile("image")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Floor plan image is required in the image field",
+		})
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid floor plan: " + err.Error(),
+		})
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid floor plan: " + err.Error(),
+		})
+	}
+
+	zone, err := h.zoneService.SetFloorPlan(c.Context(), uint(id), data)
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Floor plan uploaded successfully",
+		"data":  zone,
+	})
+}
+
+// GetFloorPlan handles getting the floor plan image of a zone
+func (h *ZoneHandler) GetFloorPlan(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid zone ID",
+		})
+	}
+
+	path, err := h.zoneService.GetFloorPlan(c.Context(), uint(id))
+	if err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.SendFile(path)
+}
+
+// DeleteFloorPlan handles removing the floor plan image of a zone
+func (h *ZoneHandler) DeleteFloorPlan(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid zone ID",
+		})
+	}
+
+	if err := h.zoneService.DeleteFloorPlan(c.Context(), uint(id)); err != nil {
+		return h.writeError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"error": false,
+		"msg":   "Floor plan deleted successfully",
+	})
+}
+
+// writeError maps zone validation errors to response statuses
+func (h *ZoneHandler) writeError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusInternalServerError
+
+	switch err.Error() {
+	case "name is required":
+		status = fiber.StatusBadRequest
+	case "a zone with the same name already exists in the parent zone", "zone has zones inside it":
+		status = fiber.StatusConflict
+	case "zone not found", "parent zone not found", "camera not found", "camera is not in the zone", "floor plan not found":
+		status = fiber.StatusNotFound
+	default:
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			status = fiber.StatusBadRequest
+		}
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": true,
+		"msg":   err.Error(),
+	})
+}
+
+func (r *zoneRequest) toZone() *entity.Zone {
+	return &entity.Zone{
+		ParentID:    r.ParentID,
+		Kind:        r.Kind,
+		Name:        r.Name,
+		Description: r.Description,
+		Capacity:    r.Capacity,
+	}
+}
+
+func (r *zoneCameraRequest) toZoneCamera(zoneID uint) entity.ZoneCamera {
+	return entity.ZoneCamera{
+		ZoneID:    zoneID,
+		CameraID:  r.CameraID,
+		Direction: r.Direction,
+		PlanX:     r.PlanX,
+		PlanY:     r.PlanY,
+	}
+}
+
+// zoneIDQuery returns the zone filter of a request. area_id is still accepted from clients
+// written before zones existed.
+func zoneIDQuery(c *fiber.Ctx) string {
+	if zoneID := c.Query("zone_id", ""); zoneID != "" {
+		return zoneID
+	}
+	return c.Query("area_id", "")
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKeyAuth returns a middleware that only lets requests through when they carry
+// one of the given keys, either as "Authorization: Bearer <key>" or "X-API-Key: <key>".
+// When no keys are configured every request is rejected.
+func APIKeyAuth(keys []string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get("X-API-Key")
+		if key == "" {
+			auth := c.Get(fiber.HeaderAuthorization)
+			if strings.HasPrefix(auth, "Bearer ") {
+				key = strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+			}
+		}
+
+		if key == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": true,
+				"msg":   "API key is required",
+			})
+		}
+
+		for _, allowed := range keys {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(allowed)) == 1 {
+				return c.Next()
+			}
+		}
+
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": true,
+			"msg":   "Invalid API key",
+		})
+	}
+}
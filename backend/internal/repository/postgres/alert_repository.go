@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"people-counting/internal/domain/entity"
@@ -48,6 +49,11 @@ func (r *AlertRepositoryImpl) FindAll(ctx context.Context, page, limit int, filt
 			query = query.Where("camera_id = ?", cameraID)
 		}
 
+		// Cameras of a zone
+		if cameraIDs, ok := filters["camera_ids"].([]uint); ok {
+			query = query.Where("camera_id IN ?", cameraIDs)
+		}
+
 		if severity, ok := filters["severity"].(string); ok && severity != "" {
 			query = query.Where("severity = ?", severity)
 		}
@@ -58,6 +64,18 @@ func (r *AlertRepositoryImpl) FindAll(ctx context.Context, page, limit int, filt
 		}
 
 		// Status-based filters
+		if status, ok := filters["status"].(string); ok && status != "" {
+			query = query.Where("status = ?", status)
+		}
+
+		if assignedTo, ok := filters["assigned_to"].(string); ok && assignedTo != "" {
+			query = query.Where("assigned_to = ?", assignedTo)
+		}
+
+		if suppressed, ok := filters["suppressed"].(bool); ok {
+			query = query.Where("suppressed = ?", suppressed)
+		}
+
 		if _, ok := filters["resolved_at_null"]; ok {
 			query = query.Where("resolved_at IS NULL")
 		}
@@ -139,6 +157,11 @@ func (r *AlertRepositoryImpl) FindActive(ctx context.Context, page, limit int, f
 			query = query.Where("camera_id = ?", cameraID)
 		}
 
+		// Cameras of a zone
+		if cameraIDs, ok := filters["camera_ids"].([]uint); ok {
+			query = query.Where("camera_id IN ?", cameraIDs)
+		}
+
 		if from, ok := filters["from"].(time.Time); ok && !from.IsZero() {
 			query = query.Where("detected_at >= ?", from)
 		}
@@ -165,6 +188,31 @@ func (r *AlertRepositoryImpl) FindActive(ctx context.Context, page, limit int, f
 	return alerts, total, totalAllActive, nil
 }
 
+// CountActiveByCamera counts the open alerts of each camera that has any, leaving out alerts
+// suppressed by maintenance windows
+func (r *AlertRepositoryImpl) CountActiveByCamera(ctx context.Context) (map[uint]int64, error) {
+	var rows []struct {
+		CameraID uint
+		Count    int64
+	}
+
+	err := r.db.WithContext(ctx).Model(&entity.Alert{}).
+		Select("camera_id, COUNT(*) AS count").
+		Where("is_active = ? AND suppressed = ?", true, false).
+		Group("camera_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count active alerts: %w", err)
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CameraID] = row.Count
+	}
+
+	return counts, nil
+}
+
 // Create adds a new alert to the database
 func (r *AlertRepositoryImpl) Create(ctx context.Context, alert *entity.Alert) error {
 	// Set detected_at to current time if not provided
@@ -172,12 +220,38 @@ func (r *AlertRepositoryImpl) Create(ctx context.Context, alert *entity.Alert) e
 		alert.DetectedAt = time.Now()
 	}
 
-	// Set is_active to true by default
+	// New alerts start open, as the first occurrence of their detection
 	alert.IsActive = true
+	alert.Status = entity.AlertStatusNew
+	alert.Occurrences = 1
+	alert.FirstSeenAt = &alert.DetectedAt
+	alert.LastSeenAt = &alert.DetectedAt
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(alert).Error; err != nil {
+			return err
+		}
 
-	result := r.db.WithContext(ctx).Create(alert)
-	if result.Error != nil {
-		return result.Error
+		if err := tx.Create(&entity.AlertOccurrence{
+			AlertID:    alert.ID,
+			SourceID:   alert.ID,
+			ImageURL:   alert.ImageURL,
+			Severity:   alert.Severity,
+			DetectedAt: alert.DetectedAt,
+
+			ConfigVersion: alert.ConfigVersion,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&entity.AlertEvent{
+			AlertID:  alert.ID,
+			Action:   entity.AlertEventCreated,
+			ToStatus: alert.Status,
+		}).Error
+	})
+	if err != nil {
+		return err
 	}
 
 	// Load related entities if needed
@@ -217,33 +291,279 @@ func (r *AlertRepositoryImpl) Update(ctx context.Context, alert *entity.Alert) e
 	return nil
 }
 
-// Resolve marks an alert as resolved
-func (r *AlertRepositoryImpl) Resolve(ctx context.Context, id string, resolvedBy, note string) error {
-	// Check if alert exists
-	var alert entity.Alert
-	result := r.db.WithContext(ctx).Where("id = ?", id).First(&alert)
+// SetClipPath links the evidence clip of an alert
+func (r *AlertRepositoryImpl) SetClipPath(ctx context.Context, id, clipPath string) error {
+	result := r.db.WithContext(ctx).Model(&entity.Alert{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"clip_path":  clipPath,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("alert not found")
+	}
+
+	return nil
+}
+
+// ApplyEvent updates an alert and records the change in its history, in one transaction.
+// The update only applies while the alert is still in expectedStatus.
+func (r *AlertRepositoryImpl) ApplyEvent(ctx context.Context, id, expectedStatus string, updates map[string]interface{}, event *entity.AlertEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		updates["updated_at"] = time.Now()
+
+		result := tx.Model(&entity.Alert{}).
+			Where("id = ? AND status = ?", id, expectedStatus).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			var count int64
+			if err := tx.Model(&entity.Alert{}).Where("id = ?", id).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return errors.New("alert not found")
+			}
+			return errors.New("alert was changed by another request")
+		}
+
+		event.AlertID = id
+		return tx.Create(event).Error
+	})
+}
+
+// CreateEvent adds an event to the history of an alert
+func (r *AlertRepositoryImpl) CreateEvent(ctx context.Context, event *entity.AlertEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// FindEvents retrieves the history of an alert, oldest first
+func (r *AlertRepositoryImpl) FindEvents(ctx context.Context, alertID string) ([]entity.AlertEvent, error) {
+	var events []entity.AlertEvent
+
+	result := r.db.WithContext(ctx).
+		Where("alert_id = ?", alertID).
+		Order("created_at ASC, id ASC").
+		Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return events, nil
+}
+
+// FindCorrelated finds the open alert of a camera, alert type and object last seen within the
+// given range. It returns nil without error when there is none.
+func (r *AlertRepositoryImpl) FindCorrelated(ctx context.Context, cameraID, alertTypeID uint, objectID string, from, to time.Time) (*entity.Alert, error) {
+	var alerts []entity.Alert
+
+	result := r.db.WithContext(ctx).
+		Where("is_active = ?", true).
+		Where("camera_id = ? AND alert_type_id = ? AND COALESCE(object_id, '') = ?", cameraID, alertTypeID, objectID).
+		Where("last_seen_at >= ? AND first_seen_at <= ?", from, to).
+		Order("last_seen_at DESC").
+		Limit(1).
+		Find(&alerts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if len(alerts) == 0 {
+		return nil, nil
+	}
+
+	return &alerts[0], nil
+}
+
+// FindOccurrenceBySource finds the occurrence recorded for a detection
+func (r *AlertRepositoryImpl) FindOccurrenceBySource(ctx context.Context, sourceID string) (*entity.AlertOccurrence, error) {
+	var occurrence entity.AlertOccurrence
+
+	result := r.db.WithContext(ctx).Where("source_id = ?", sourceID).First(&occurrence)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("alert occurrence not found")
+		}
+		return nil, result.Error
+	}
+
+	return &occurrence, nil
+}
+
+// AddOccurrence folds a detection into an alert. When an escalation event is given, the alert
+// takes the severity of the occurrence and the event is recorded in its history.
+func (r *AlertRepositoryImpl) AddOccurrence(ctx context.Context, id string, occurrence *entity.AlertOccurrence, escalation *entity.AlertEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{
+			"occurrence_count": gorm.Expr("occurrence_count + 1"),
+			"first_seen_at":    gorm.Expr("LEAST(first_seen_at, ?)", occurrence.DetectedAt),
+			"last_seen_at":     gorm.Expr("GREATEST(last_seen_at, ?)", occurrence.DetectedAt),
+			"updated_at":       time.Now(),
+		}
+		if escalation != nil {
+			updates["severity"] = occurrence.Severity
+		}
+
+		result := tx.Model(&entity.Alert{}).Where("id = ?", id).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
 			return errors.New("alert not found")
 		}
-		return result.Error
+
+		occurrence.AlertID = id
+		if err := tx.Create(occurrence).Error; err != nil {
+			return err
+		}
+
+		if escalation != nil {
+			escalation.AlertID = id
+			return tx.Create(escalation).Error
+		}
+
+		return nil
+	})
+}
+
+// FindOccurrences retrieves the detections folded into an alert, oldest first
+func (r *AlertRepositoryImpl) FindOccurrences(ctx context.Context, alertID string) ([]entity.AlertOccurrence, error) {
+	var occurrences []entity.AlertOccurrence
+
+	result := r.db.WithContext(ctx).
+		Where("alert_id = ?", alertID).
+		Order("detected_at ASC, id ASC").
+		Find(&occurrences)
+	if result.Error != nil {
+		return nil, result.Error
 	}
 
-	// Check if already resolved
-	if !alert.IsActive {
-		return errors.New("alert is already resolved")
+	return occurrences, nil
+}
+
+// GetDailyCounts sums the alerts_daily aggregate by camera, alert type and severity over the days
+// whose bucket starts between from and to. Without the aggregate, as on databases set up without
+// TimescaleDB, the alerts are bucketed on the fly.
+func (r *AlertRepositoryImpl) GetDailyCounts(ctx context.Context, from, to time.Time, filters map[string]interface{}) ([]entity.AlertDailyCount, error) {
+	var counts []entity.AlertDailyCount
+
+	var query *gorm.DB
+	if r.hasDailyAggregate(ctx) {
+		query = r.db.WithContext(ctx).Table("alerts_daily")
+	} else {
+		daily := r.db.Model(&entity.Alert{}).
+			Select("date_trunc('day', detected_at) AS bucket, camera_id, alert_type_id, severity, COUNT(*) AS alert_count, SUM(CASE WHEN suppressed THEN 1 ELSE 0 END) AS suppressed_count").
+			Group("date_trunc('day', detected_at), camera_id, alert_type_id, severity")
+		query = r.db.WithContext(ctx).Table("(?) AS alerts_daily", daily)
 	}
 
-	// Update fields
-	now := time.Now()
+	query = query.
+		Select("camera_id, alert_type_id, severity, SUM(alert_count) AS alert_count, SUM(suppressed_count) AS suppressed_count").
+		Where("bucket >= ? AND bucket <= ?", from, to)
+	query = applyAlertStatsFilters(query, filters)
 
-	updateResult := r.db.WithContext(ctx).Model(&alert).Updates(map[string]interface{}{
-		"is_active":       false,
-		"resolved_at":     now,
-		"resolved_by":     resolvedBy,
-		"resolution_note": note,
-		"updated_at":      now,
-	})
+	err := query.Group("camera_id, alert_type_id, severity").Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch daily alert counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetHourOfDayCounts counts the alerts detected between from and to by hour of the day in the
+// given time zone, the session time zone when empty. Suppressed alerts are left out.
+func (r *AlertRepositoryImpl) GetHourOfDayCounts(ctx context.Context, from, to time.Time, timezone string, filters map[string]interface{}) ([]entity.AlertHourStat, error) {
+	var counts []entity.AlertHourStat
+
+	hour := "CAST(EXTRACT(HOUR FROM detected_at) AS integer)"
+	var args []interface{}
+	if timezone != "" {
+		hour = "CAST(EXTRACT(HOUR FROM detected_at AT TIME ZONE ?) AS integer)"
+		args = append(args, timezone)
+	}
+
+	query := r.db.WithContext(ctx).Model(&entity.Alert{}).
+		Select(hour+" AS hour, COUNT(*) AS count", args...).
+		Where("suppressed = ? AND detected_at >= ? AND detected_at <= ?", false, from, to)
+	query = applyAlertStatsFilters(query, filters)
+
+	err := query.Group("hour").Order("hour ASC").Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch alert counts by hour: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetCameraOutcomes sums how the alerts detected between from and to were acknowledged and
+// closed, by camera. Times are measured from when an alert was first seen. Suppressed alerts
+// are left out.
+func (r *AlertRepositoryImpl) GetCameraOutcomes(ctx context.Context, from, to time.Time, filters map[string]interface{}) ([]entity.AlertCameraOutcome, error) {
+	var outcomes []entity.AlertCameraOutcome
+
+	resolved := entity.AlertStatusResolved
+	falsePositive := entity.AlertStatusFalsePositive
+
+	query := r.db.WithContext(ctx).Model(&entity.Alert{}).
+		Select(`camera_id,
+			COUNT(*) AS alerts,
+			COUNT(acknowledged_at) AS acknowledged,
+			COALESCE(SUM(GREATEST(EXTRACT(EPOCH FROM acknowledged_at - COALESCE(first_seen_at, detected_at)), 0)), 0) AS acknowledge_seconds,
+			SUM(CASE WHEN status = ? AND resolved_at IS NOT NULL THEN 1 ELSE 0 END) AS resolved,
+			COALESCE(SUM(CASE WHEN status = ? AND resolved_at IS NOT NULL THEN GREATEST(EXTRACT(EPOCH FROM resolved_at - COALESCE(first_seen_at, detected_at)), 0) END), 0) AS resolve_seconds,
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS false_positives,
+			SUM(CASE WHEN status IN (?, ?) THEN 1 ELSE 0 END) AS closed`,
+			resolved, resolved, falsePositive, resolved, falsePositive).
+		Where("suppressed = ? AND detected_at >= ? AND detected_at <= ?", false, from, to)
+	query = applyAlertStatsFilters(query, filters)
+
+	err := query.Group("camera_id").Scan(&outcomes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch alert outcomes: %w", err)
+	}
+
+	return outcomes, nil
+}
+
+// hasDailyAggregate reports whether the alerts_daily aggregate exists
+func (r *AlertRepositoryImpl) hasDailyAggregate(ctx context.Context) bool {
+	return dailyAggregateExists(r.db.WithContext(ctx))
+}
+
+// dailyAggregateExists reports whether the alerts_daily aggregate exists
+func dailyAggregateExists(db *gorm.DB) bool {
+	var exists bool
+	db.Raw(`
+		SELECT EXISTS (
+			SELECT 1 FROM pg_catalog.pg_class c
+			JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+			WHERE c.relname = 'alerts_daily' AND n.nspname = current_schema()
+		)`).Scan(&exists)
+
+	return exists
+}
+
+// applyAlertStatsFilters narrows alert statistics to a camera, the cameras of a zone or an
+// alert type
+func applyAlertStatsFilters(query *gorm.DB, filters map[string]interface{}) *gorm.DB {
+	if cameraID, ok := filters["camera_id"].(uint); ok && cameraID > 0 {
+		query = query.Where("camera_id = ?", cameraID)
+	}
+
+	if cameraIDs, ok := filters["camera_ids"].([]uint); ok {
+		query = query.Where("camera_id IN ?", cameraIDs)
+	}
+
+	if alertTypeID, ok := filters["alert_type_id"].(uint); ok && alertTypeID > 0 {
+		query = query.Where("alert_type_id = ?", alertTypeID)
+	}
 
-	return updateResult.Error
+	return query
 }
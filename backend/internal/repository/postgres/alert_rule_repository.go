@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// AlertRuleRepositoryImpl implements repository.AlertRuleRepository
+type AlertRuleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAlertRuleRepository creates a new alert rule repository
+func NewAlertRuleRepository(db *gorm.DB) repository.AlertRuleRepository {
+	return &AlertRuleRepositoryImpl{
+		db: db,
+	}
+}
+
+// FindAll retrieves alert rules with filters
+func (r *AlertRuleRepositoryImpl) FindAll(ctx context.Context, filters map[string]interface{}) ([]entity.AlertRule, error) {
+	var rules []entity.AlertRule
+
+	query := r.db.WithContext(ctx).Order("id ASC")
+
+	if filters != nil {
+		if enabled, ok := filters["enabled"].(bool); ok {
+			query = query.Where("enabled = ?", enabled)
+		}
+
+		if cameraID, ok := filters["camera_id"].(uint); ok && cameraID > 0 {
+			query = query.Where("camera_id = ?", cameraID)
+		}
+
+		if zoneID, ok := filters["zone_id"].(uint); ok && zoneID > 0 {
+			query = query.Where("zone_id = ?", zoneID)
+		}
+
+		if zone, ok := filters["zone"].(string); ok && zone != "" {
+			query = query.Where("zone = ?", zone)
+		}
+	}
+
+	result := query.Find(&rules)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return rules, nil
+}
+
+// FindByID finds an alert rule by its ID
+func (r *AlertRuleRepositoryImpl) FindByID(ctx context.Context, id uint) (*entity.AlertRule, error) {
+	var rule entity.AlertRule
+
+	result := r.db.WithContext(ctx).First(&rule, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("alert rule not found")
+		}
+		return nil, result.Error
+	}
+
+	return &rule, nil
+}
+
+// FindByName finds an alert rule by its name
+func (r *AlertRuleRepositoryImpl) FindByName(ctx context.Context, name string) (*entity.AlertRule, error) {
+	var rule entity.AlertRule
+
+	result := r.db.WithContext(ctx).Where("name = ?", name).First(&rule)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("alert rule not found")
+		}
+		return nil, result.Error
+	}
+
+	return &rule, nil
+}
+
+// Create adds a new alert rule to the database
+func (r *AlertRuleRepositoryImpl) Create(ctx context.Context, rule *entity.AlertRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+// Update updates an existing alert rule in the database
+func (r *AlertRuleRepositoryImpl) Update(ctx context.Context, rule *entity.AlertRule) error {
+	result := r.db.WithContext(ctx).Model(rule).Updates(map[string]interface{}{
+		"name":             rule.Name,
+		"description":      rule.Description,
+		"enabled":          rule.Enabled,
+		"camera_id":        rule.CameraID,
+		"zone":             rule.Zone,
+		"metric":           rule.Metric,
+		"condition":        rule.Condition,
+		"threshold":        rule.Threshold,
+		"window_minutes":   rule.WindowMinutes,
+		"schedule_days":    rule.ScheduleDays,
+		"schedule_start":   rule.ScheduleStart,
+		"schedule_end":     rule.ScheduleEnd,
+		"outside_schedule": rule.OutsideSchedule,
+		"severity":         rule.Severity,
+		"cooldown_minutes": rule.CooldownMinutes,
+		"alert_type_id":    rule.AlertTypeID,
+		"updated_at":       time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("alert rule not found")
+	}
+
+	return nil
+}
+
+// MarkTriggered records when a rule last raised an alert
+func (r *AlertRuleRepositoryImpl) MarkTriggered(ctx context.Context, id uint, triggeredAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.AlertRule{}).
+		Where("id = ?", id).
+		Update("last_triggered_at", triggeredAt).Error
+}
+
+// Delete removes an alert rule from the database
+func (r *AlertRuleRepositoryImpl) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&entity.AlertRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("alert rule not found")
+	}
+
+	return nil
+}
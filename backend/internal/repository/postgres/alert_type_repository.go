@@ -3,7 +3,8 @@ package postgres
 import (
 	"context"
 	"errors"
-	"strings"
+	"fmt"
+	"time"
 
 	"people-counting/internal/domain/entity"
 	"people-counting/internal/domain/repository"
@@ -16,6 +17,14 @@ type AlertTypeRepositoryImpl struct {
 	db *gorm.DB
 }
 
+// alertTypeReferences are the models pointing at alert types through alert_type_id
+var alertTypeReferences = []interface{}{
+	&entity.Alert{},
+	&entity.AlertRule{},
+	&entity.NotificationRoute{},
+	&entity.MaintenanceWindow{},
+}
+
 // NewAlertTypeRepository creates a new alert type repository
 func NewAlertTypeRepository(db *gorm.DB) repository.AlertTypeRepository {
 	return &AlertTypeRepositoryImpl{
@@ -27,7 +36,7 @@ func NewAlertTypeRepository(db *gorm.DB) repository.AlertTypeRepository {
 func (r *AlertTypeRepositoryImpl) FindAll(ctx context.Context) ([]entity.AlertType, error) {
 	var alertTypes []entity.AlertType
 
-	result := r.db.WithContext(ctx).Order("id ASC").Find(&alertTypes)
+	result := r.db.WithContext(ctx).Preload("Messages").Preload("Aliases").Order("id ASC").Find(&alertTypes)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -58,16 +67,306 @@ func (r *AlertTypeRepositoryImpl) Create(ctx context.Context, alertType *entity.
 	return alertType, nil
 }
 
+// FindByName finds an alert type by its name, ignoring case and surrounding whitespace
 func (r *AlertTypeRepositoryImpl) FindByName(ctx context.Context, typeName string) (*entity.AlertType, error) {
 	var alertType *entity.AlertType
 
 	result := r.db.WithContext(ctx).
-		Where("LOWER(name) = LOWER(?)", strings.ToLower(typeName)).
+		Where("LOWER(TRIM(name)) = ?", entity.NormalizeAlertTypeName(typeName)).
+		Order("id ASC").
 		First(&alertType)
 
 	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("alert type not found")
+		}
 		return nil, result.Error
 	}
 
 	return alertType, nil
 }
+
+// Update updates the details of an alert type
+func (r *AlertTypeRepositoryImpl) Update(ctx context.Context, alertType *entity.AlertType) error {
+	result := r.db.WithContext(ctx).Model(&entity.AlertType{}).
+		Where("id = ?", alertType.ID).
+		Updates(map[string]interface{}{
+			"name":         alertType.Name,
+			"display_name": alertType.DisplayName,
+			"icon":         alertType.Icon,
+			"color":        alertType.Color,
+			"description":  alertType.Description,
+			"severity":     alertType.Severity,
+			"updated_at":   time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("alert type not found")
+	}
+
+	return nil
+}
+
+// SetArchived archives an alert type at the given time, or restores it when nil
+func (r *AlertTypeRepositoryImpl) SetArchived(ctx context.Context, id uint, archivedAt *time.Time) error {
+	result := r.db.WithContext(ctx).Model(&entity.AlertType{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"archived_at": archivedAt,
+			"updated_at":  time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("alert type not found")
+	}
+
+	return nil
+}
+
+// Delete deletes an alert type with its message templates and aliases
+func (r *AlertTypeRepositoryImpl) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("alert_type_id = ?", id).Delete(&entity.AlertTypeMessage{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("alert_type_id = ?", id).Delete(&entity.AlertTypeAlias{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&entity.AlertType{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("alert type not found")
+		}
+
+		return nil
+	})
+}
+
+// CountReferences counts the alerts, alert rules, notification routes and maintenance windows
+// of an alert type
+func (r *AlertTypeRepositoryImpl) CountReferences(ctx context.Context, id uint) (int64, error) {
+	var total int64
+
+	for _, model := range alertTypeReferences {
+		var count int64
+		if err := r.db.WithContext(ctx).Model(model).Where("alert_type_id = ?", id).Count(&count).Error; err != nil {
+			return 0, err
+		}
+		total += count
+	}
+
+	return total, nil
+}
+
+// Merge re-points everything of the source alert type to the target in a transaction, keeps the
+// source name and aliases as aliases of the target and deletes the source. Compressed chunks
+// holding alerts of the source are decompressed first. It returns the number of alerts moved.
+func (r *AlertTypeRepositoryImpl) Merge(ctx context.Context, sourceID, targetID uint) (int64, error) {
+	var moved int64
+
+	// Checked before the transaction, a failing query would abort it
+	hypertable := alertsIsHypertable(r.db.WithContext(ctx))
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var source entity.AlertType
+		if err := tx.First(&source, sourceID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("alert type not found")
+			}
+			return err
+		}
+
+		if hypertable {
+			if err := decompressAlertChunks(tx, sourceID); err != nil {
+				return err
+			}
+		}
+
+		for _, model := range alertTypeReferences {
+			result := tx.Model(model).Where("alert_type_id = ?", sourceID).Update("alert_type_id", targetID)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			if _, ok := model.(*entity.Alert); ok {
+				moved = result.RowsAffected
+			}
+		}
+
+		if err := tx.Model(&entity.AlertTypeAlias{}).Where("alert_type_id = ?", sourceID).Update("alert_type_id", targetID).Error; err != nil {
+			return err
+		}
+
+		alias := entity.AlertTypeAlias{Name: entity.NormalizeAlertTypeName(source.Name), AlertTypeID: targetID}
+		if err := tx.Where("name = ?", alias.Name).FirstOrCreate(&alias).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("alert_type_id = ?", sourceID).Delete(&entity.AlertTypeMessage{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&entity.AlertType{}, sourceID).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return moved, nil
+}
+
+// RefreshDailyCounts refreshes every bucket of the alerts_daily aggregate, which only refreshes
+// recent buckets on its own. It does nothing when the aggregate is missing.
+func (r *AlertTypeRepositoryImpl) RefreshDailyCounts(ctx context.Context) error {
+	if !dailyAggregateExists(r.db.WithContext(ctx)) {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Exec("CALL refresh_continuous_aggregate('alerts_daily', NULL, NULL)").Error
+}
+
+// alertsIsHypertable reports whether alerts is a TimescaleDB hypertable
+func alertsIsHypertable(db *gorm.DB) bool {
+	var hypertables int64
+	if err := db.Raw("SELECT COUNT(*) FROM timescaledb_information.hypertables WHERE hypertable_name = 'alerts'").Scan(&hypertables).Error; err != nil {
+		return false
+	}
+
+	return hypertables > 0
+}
+
+// decompressAlertChunks decompresses the compressed chunks of alerts that hold alerts of an
+// alert type, as TimescaleDB before 2.11 cannot update compressed chunks. The compression policy
+// compresses them again.
+func decompressAlertChunks(tx *gorm.DB, alertTypeID uint) error {
+	var decompressed int64
+	err := tx.Raw(`
+		SELECT COUNT(decompress_chunk(format('%I.%I', c.chunk_schema, c.chunk_name)::regclass, true))
+		FROM timescaledb_information.chunks c,
+			(SELECT MIN(detected_at) AS first_at, MAX(detected_at) AS last_at FROM alerts WHERE alert_type_id = ?) a
+		WHERE c.hypertable_name = 'alerts' AND c.is_compressed
+		AND c.range_start <= a.last_at AND c.range_end > a.first_at`, alertTypeID).Scan(&decompressed).Error
+	if err != nil {
+		return fmt.Errorf("failed to decompress alert chunks: %w", err)
+	}
+
+	return nil
+}
+
+// FindAliases retrieves every alias
+func (r *AlertTypeRepositoryImpl) FindAliases(ctx context.Context) ([]entity.AlertTypeAlias, error) {
+	var aliases []entity.AlertTypeAlias
+
+	result := r.db.WithContext(ctx).Order("name ASC").Find(&aliases)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return aliases, nil
+}
+
+// FindAliasByName finds an alias by its name, ignoring case and surrounding whitespace
+func (r *AlertTypeRepositoryImpl) FindAliasByName(ctx context.Context, name string) (*entity.AlertTypeAlias, error) {
+	var alias entity.AlertTypeAlias
+
+	result := r.db.WithContext(ctx).Where("name = ?", entity.NormalizeAlertTypeName(name)).First(&alias)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("alias not found")
+		}
+		return nil, result.Error
+	}
+
+	return &alias, nil
+}
+
+// CreateAlias adds an alias
+func (r *AlertTypeRepositoryImpl) CreateAlias(ctx context.Context, alias *entity.AlertTypeAlias) error {
+	alias.Name = entity.NormalizeAlertTypeName(alias.Name)
+	return r.db.WithContext(ctx).Create(alias).Error
+}
+
+// DeleteAlias deletes an alias
+func (r *AlertTypeRepositoryImpl) DeleteAlias(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&entity.AlertTypeAlias{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("alias not found")
+	}
+
+	return nil
+}
+
+// FindMessages retrieves the message templates of an alert type
+func (r *AlertTypeRepositoryImpl) FindMessages(ctx context.Context, alertTypeID uint) ([]entity.AlertTypeMessage, error) {
+	var messages []entity.AlertTypeMessage
+
+	result := r.db.WithContext(ctx).Where("alert_type_id = ?", alertTypeID).Order("language ASC").Find(&messages)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return messages, nil
+}
+
+// FindMessagesByLanguage retrieves the message templates of every alert type in a language
+func (r *AlertTypeRepositoryImpl) FindMessagesByLanguage(ctx context.Context, language string) ([]entity.AlertTypeMessage, error) {
+	var messages []entity.AlertTypeMessage
+
+	result := r.db.WithContext(ctx).Where("language = ?", language).Find(&messages)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return messages, nil
+}
+
+// UpdateMessages updates the severity of an alert type and replaces its message templates
+func (r *AlertTypeRepositoryImpl) UpdateMessages(ctx context.Context, alertTypeID uint, severity string, messages []entity.AlertTypeMessage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.AlertType{}).
+			Where("id = ?", alertTypeID).
+			Updates(map[string]interface{}{
+				"severity":   severity,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("alert type not found")
+		}
+
+		if err := tx.Where("alert_type_id = ?", alertTypeID).Delete(&entity.AlertTypeMessage{}).Error; err != nil {
+			return err
+		}
+
+		for i := range messages {
+			messages[i].ID = 0
+			messages[i].AlertTypeID = alertTypeID
+		}
+
+		if len(messages) > 0 {
+			if err := tx.Create(&messages).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CameraConfigRepositoryImpl implements repository.CameraConfigRepository
+type CameraConfigRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewCameraConfigRepository creates a new camera configuration repository
+func NewCameraConfigRepository(db *gorm.DB) repository.CameraConfigRepository {
+	return &CameraConfigRepositoryImpl{
+		db: db,
+	}
+}
+
+// FindCurrent finds the latest version of the configuration of a camera that is not archived
+func (r *CameraConfigRepositoryImpl) FindCurrent(ctx context.Context, cameraID uint) (*entity.CameraConfig, error) {
+	var config entity.CameraConfig
+
+	result := r.db.WithContext(ctx).
+		Where("camera_id = ? AND archived_at IS NULL", cameraID).
+		Order("version DESC").
+		First(&config)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("camera config not found")
+		}
+		return nil, result.Error
+	}
+
+	return &config, nil
+}
+
+// FindVersions retrieves every version of the configuration of a camera, newest first
+func (r *CameraConfigRepositoryImpl) FindVersions(ctx context.Context, cameraID uint) ([]entity.CameraConfig, error) {
+	var configs []entity.CameraConfig
+
+	result := r.db.WithContext(ctx).
+		Where("camera_id = ?", cameraID).
+		Order("version DESC").
+		Find(&configs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return configs, nil
+}
+
+// FindVersion finds a version of the configuration of a camera, archived or not
+func (r *CameraConfigRepositoryImpl) FindVersion(ctx context.Context, cameraID uint, version int) (*entity.CameraConfig, error) {
+	var config entity.CameraConfig
+
+	result := r.db.WithContext(ctx).
+		Where("camera_id = ? AND version = ?", cameraID, version).
+		First(&config)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("camera config version not found")
+		}
+		return nil, result.Error
+	}
+
+	return &config, nil
+}
+
+// Create adds the next version of the configuration of a camera. The camera row is locked so
+// concurrent saves get consecutive versions.
+func (r *CameraConfigRepositoryImpl) Create(ctx context.Context, config *entity.CameraConfig) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var camera entity.Camera
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&camera, config.CameraID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("camera not found")
+			}
+			return err
+		}
+
+		var latest int
+		if err := tx.Model(&entity.CameraConfig{}).
+			Where("camera_id = ?", config.CameraID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		config.ID = 0
+		config.Version = latest + 1
+		config.ArchivedAt = nil
+
+		return tx.Create(config).Error
+	})
+}
+
+// Archive archives the configuration of a camera, keeping its versions for the records made
+// with them
+func (r *CameraConfigRepositoryImpl) Archive(ctx context.Context, cameraID uint) error {
+	result := r.db.WithContext(ctx).Model(&entity.CameraConfig{}).
+		Where("camera_id = ? AND archived_at IS NULL", cameraID).
+		Update("archived_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("camera config not found")
+	}
+
+	return nil
+}
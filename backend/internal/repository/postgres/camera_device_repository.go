@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// CameraDeviceRepositoryImpl implements repository.CameraDeviceRepository
+type CameraDeviceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewCameraDeviceRepository creates a new camera device repository
+func NewCameraDeviceRepository(db *gorm.DB) repository.CameraDeviceRepository {
+	return &CameraDeviceRepositoryImpl{
+		db: db,
+	}
+}
+
+// FindAll retrieves paginated camera devices with filters
+func (r *CameraDeviceRepositoryImpl) FindAll(ctx context.Context, page, limit int, filters map[string]interface{}) ([]entity.CameraDevice, int64, error) {
+	var devices []entity.CameraDevice
+	var total int64
+
+	offset := (page - 1) * limit
+	query := r.db.WithContext(ctx).Model(&entity.CameraDevice{}).Order("id ASC")
+
+	if filters != nil {
+		if status, ok := filters["status"].(string); ok && status != "" {
+			query = query.Where("status = ?", status)
+		}
+
+		if cameraID, ok := filters["camera_id"].(uint); ok && cameraID > 0 {
+			query = query.Where("camera_id = ?", cameraID)
+		}
+
+		if search, ok := filters["search"].(string); ok && search != "" {
+			searchPattern := "%" + search + "%"
+			query = query.Where("serial ILIKE ? OR name ILIKE ?", searchPattern, searchPattern)
+		}
+	}
+
+	countQuery := query
+	countQuery.Count(&total)
+
+	result := query.Limit(limit).Offset(offset).Find(&devices)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return devices, total, nil
+}
+
+// FindByID finds a camera device by its ID
+func (r *CameraDeviceRepositoryImpl) FindByID(ctx context.Context, id uint) (*entity.CameraDevice, error) {
+	var device entity.CameraDevice
+
+	result := r.db.WithContext(ctx).First(&device, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("camera device not found")
+		}
+		return nil, result.Error
+	}
+
+	return &device, nil
+}
+
+// FindBySerial finds a camera device by its serial and channel
+func (r *CameraDeviceRepositoryImpl) FindBySerial(ctx context.Context, serial string, channel int) (*entity.CameraDevice, error) {
+	var device entity.CameraDevice
+
+	result := r.db.WithContext(ctx).Where("serial = ? AND channel = ?", serial, channel).First(&device)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("camera device not found")
+		}
+		return nil, result.Error
+	}
+
+	return &device, nil
+}
+
+// FindByLegacyID finds a camera device by its legacy cctv_id
+func (r *CameraDeviceRepositoryImpl) FindByLegacyID(ctx context.Context, legacyID uint) (*entity.CameraDevice, error) {
+	var device entity.CameraDevice
+
+	result := r.db.WithContext(ctx).Where("legacy_id = ?", legacyID).First(&device)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("camera device not found")
+		}
+		return nil, result.Error
+	}
+
+	return &device, nil
+}
+
+// Create adds a new camera device to the database
+func (r *CameraDeviceRepositoryImpl) Create(ctx context.Context, device *entity.CameraDevice) error {
+	return r.db.WithContext(ctx).Create(device).Error
+}
+
+// Register creates a device together with the camera it maps to. A camera without an ID gets
+// the next ID of the cameras sequence. A camera with an ID keeps it, as long as no other camera
+// has it, and the sequence is moved past it so cameras created later do not collide with it.
+func (r *CameraDeviceRepositoryImpl) Register(ctx context.Context, device *entity.CameraDevice, camera *entity.Camera) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if camera.ID == 0 {
+			if err := tx.Create(camera).Error; err != nil {
+				return err
+			}
+		} else {
+			// Keep cameras from being created with the sequence until it is moved past the ID
+			if err := tx.Exec("LOCK TABLE cameras IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+				return err
+			}
+
+			var count int64
+			if err := tx.Model(&entity.Camera{}).Where("id = ?", camera.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return fmt.Errorf("camera %d already exists", camera.ID)
+			}
+
+			if err := tx.Create(camera).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Exec("SELECT setval('cameras_id_seq', GREATEST((SELECT MAX(id) FROM cameras), (SELECT last_value FROM cameras_id_seq)))").Error; err != nil {
+				return err
+			}
+		}
+
+		device.CameraID = &camera.ID
+		return tx.Create(device).Error
+	})
+}
+
+// Update modifies an existing camera device in the database
+func (r *CameraDeviceRepositoryImpl) Update(ctx context.Context, device *entity.CameraDevice) error {
+	result := r.db.WithContext(ctx).Model(device).Updates(map[string]interface{}{
+		"serial":     device.Serial,
+		"channel":    device.Channel,
+		"legacy_id":  device.LegacyID,
+		"camera_id":  device.CameraID,
+		"status":     device.Status,
+		"name":       device.Name,
+		"updated_at": time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("camera device not found")
+	}
+
+	return nil
+}
+
+// Touch records that a device has been seen
+func (r *CameraDeviceRepositoryImpl) Touch(ctx context.Context, id uint, seenAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.CameraDevice{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"seen_count":    gorm.Expr("seen_count + 1"),
+			"first_seen_at": gorm.Expr("COALESCE(first_seen_at, ?)", seenAt),
+			"last_seen_at":  seenAt,
+		}).Error
+}
+
+// Delete removes a camera device from the database
+func (r *CameraDeviceRepositoryImpl) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&entity.CameraDevice{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("camera device not found")
+	}
+
+	return nil
+}
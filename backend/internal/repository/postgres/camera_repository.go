@@ -9,6 +9,7 @@ import (
 	"people-counting/internal/domain/repository"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CameraRepositoryImpl implements repository.CameraRepository
@@ -34,6 +35,10 @@ func (r *CameraRepositoryImpl) FindAll(ctx context.Context, filters map[string]i
 		if status, ok := filters["status"].(string); ok && status != "" {
 			query = query.Where("status = ?", status)
 		}
+
+		if location, ok := filters["location"].(string); ok && location != "" {
+			query = query.Where("location = ?", location)
+		}
 	}
 
 	result := query.Find(&cameras)
@@ -59,11 +64,14 @@ func (r *CameraRepositoryImpl) FindByID(ctx context.Context, id uint) (*entity.C
 	return &camera, nil
 }
 
-// FindByArea finds cameras by area ID
-func (r *CameraRepositoryImpl) FindByArea(ctx context.Context, areaID uint) ([]entity.Camera, error) {
+// FindByZone finds the cameras of a zone and of the zones inside it
+func (r *CameraRepositoryImpl) FindByZone(ctx context.Context, zoneID uint) ([]entity.Camera, error) {
 	var cameras []entity.Camera
 
-	result := r.db.WithContext(ctx).Where("area_id = ?", areaID).Find(&cameras)
+	result := r.db.WithContext(ctx).
+		Where("id IN ("+zoneCameraIDsQuery+")", zoneID).
+		Order("id ASC").
+		Find(&cameras)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -90,25 +98,162 @@ func (r *CameraRepositoryImpl) Update(ctx context.Context, camera *entity.Camera
 	return result.Error
 }
 
-// UpdateStatus updates just the status of a camera
-func (r *CameraRepositoryImpl) UpdateStatus(ctx context.Context, id uint, status string) error {
-	// Check if camera exists
-	var count int64
-	r.db.WithContext(ctx).Model(&entity.Camera{}).Where("id = ?", id).Count(&count)
-	if count == 0 {
-		return errors.New("camera not found")
+// ChangeStatus sets the status of the camera of event to event.ToStatus and records the event.
+// When event.FromStatus is set the status is only changed from that status, otherwise it is
+// filled in. It reports whether the status changed.
+func (r *CameraRepositoryImpl) ChangeStatus(ctx context.Context, event *entity.CameraStatusEvent) (bool, error) {
+	changed := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var camera entity.Camera
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&camera, event.CameraID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("camera not found")
+			}
+			return err
+		}
+
+		if camera.Status == event.ToStatus || (event.FromStatus != "" && camera.Status != event.FromStatus) {
+			return nil
+		}
+
+		if event.ChangedAt.IsZero() {
+			event.ChangedAt = time.Now()
+		}
+		event.FromStatus = camera.Status
+
+		if err := tx.Model(&entity.Camera{}).
+			Where("id = ?", event.CameraID).
+			Updates(map[string]interface{}{
+				"status":     event.ToStatus,
+				"updated_at": event.ChangedAt,
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+
+		changed = true
+		return nil
+	})
+
+	return changed, err
+}
+
+// FindStatusEvents retrieves paginated camera status changes with filters, newest first
+func (r *CameraRepositoryImpl) FindStatusEvents(ctx context.Context, page, limit int, filters map[string]interface{}) ([]entity.CameraStatusEvent, int64, error) {
+	var events []entity.CameraStatusEvent
+	var total int64
+
+	offset := (page - 1) * limit
+	query := r.db.WithContext(ctx).Model(&entity.CameraStatusEvent{}).Order("changed_at DESC, id DESC")
+
+	if filters != nil {
+		if cameraID, ok := filters["camera_id"].(uint); ok && cameraID > 0 {
+			query = query.Where("camera_id = ?", cameraID)
+		}
+
+		if source, ok := filters["source"].(string); ok && source != "" {
+			query = query.Where("source = ?", source)
+		}
+
+		if from, ok := filters["from"].(time.Time); ok {
+			query = query.Where("changed_at >= ?", from)
+		}
+
+		if to, ok := filters["to"].(time.Time); ok {
+			query = query.Where("changed_at <= ?", to)
+		}
 	}
 
-	// Update status and last online time
-	result := r.db.WithContext(ctx).Model(&entity.Camera{}).
-		Where("id = ?", id).
-		Updates(map[string]interface{}{
-			"status":      status,
-			"last_online": time.Now(),
-			"updated_at":  time.Now(),
-		})
+	countQuery := query
+	countQuery.Count(&total)
 
-	return result.Error
+	result := query.Limit(limit).Offset(offset).Find(&events)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return events, total, nil
+}
+
+// FindLatestStatusEvents retrieves the last status change of every camera that has one
+func (r *CameraRepositoryImpl) FindLatestStatusEvents(ctx context.Context) ([]entity.CameraStatusEvent, error) {
+	var events []entity.CameraStatusEvent
+
+	result := r.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT ON (camera_id) *
+		FROM camera_status_events
+		ORDER BY camera_id, changed_at DESC, id DESC
+	`).Scan(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return events, nil
+}
+
+// FindStatusTimeline retrieves the status changes of cameras between from and to, along with
+// the last change of each camera before from giving its status at from. Changes are ordered by
+// camera and time.
+func (r *CameraRepositoryImpl) FindStatusTimeline(ctx context.Context, from, to time.Time, filters map[string]interface{}) ([]entity.CameraStatusEvent, error) {
+	var events []entity.CameraStatusEvent
+
+	cameraFilter := ""
+	before := []interface{}{from}
+	between := []interface{}{from, to}
+	if filters != nil {
+		if cameraID, ok := filters["camera_id"].(uint); ok && cameraID > 0 {
+			cameraFilter = " AND camera_id = ?"
+			before = append(before, cameraID)
+			between = append(between, cameraID)
+		}
+	}
+
+	result := r.db.WithContext(ctx).Raw(`
+		SELECT * FROM (
+			(SELECT DISTINCT ON (camera_id) *
+			FROM camera_status_events
+			WHERE changed_at < ?`+cameraFilter+`
+			ORDER BY camera_id, changed_at DESC, id DESC)
+			UNION ALL
+			(SELECT *
+			FROM camera_status_events
+			WHERE changed_at >= ? AND changed_at <= ?`+cameraFilter+`)
+		) timeline
+		ORDER BY camera_id, changed_at, id
+	`, append(before, between...)...).Scan(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return events, nil
+}
+
+// FindLastActivity retrieves the last time a people count, vehicle count or alert of each camera
+// was recorded since the given time
+func (r *CameraRepositoryImpl) FindLastActivity(ctx context.Context, since time.Time) ([]entity.CameraActivity, error) {
+	var activity []entity.CameraActivity
+
+	result := r.db.WithContext(ctx).Raw(`
+		SELECT camera_id, MAX(last_active_at) AS last_active_at
+		FROM (
+			SELECT camera_id, MAX(timestamp) AS last_active_at FROM people_counts WHERE timestamp >= ? GROUP BY camera_id
+			UNION ALL
+			SELECT cctv_id, MAX(timestamp) AS last_active_at FROM vehicle_counts WHERE timestamp >= ? GROUP BY cctv_id
+			UNION ALL
+			SELECT camera_id, MAX(detected_at) AS last_active_at FROM alerts WHERE detected_at >= ? GROUP BY camera_id
+		) activity
+		WHERE camera_id IS NOT NULL
+		GROUP BY camera_id
+	`, since, since, since).Scan(&activity)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return activity, nil
 }
 
 // Delete removes a camera from the database
@@ -120,6 +265,11 @@ func (r *CameraRepositoryImpl) Delete(ctx context.Context, id uint) error {
 		return errors.New("camera not found")
 	}
 
-	result := r.db.WithContext(ctx).Delete(&entity.Camera{}, id)
-	return result.Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("camera_id = ?", id).Delete(&entity.ZoneCamera{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&entity.Camera{}, id).Error
+	})
 }
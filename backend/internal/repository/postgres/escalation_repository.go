@@ -0,0 +1,227 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// EscalationRepositoryImpl implements repository.EscalationRepository
+type EscalationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewEscalationRepository creates a new escalation repository
+func NewEscalationRepository(db *gorm.DB) repository.EscalationRepository {
+	return &EscalationRepositoryImpl{
+		db: db,
+	}
+}
+
+// preloadSteps loads the steps of policies in order
+func preloadSteps(db *gorm.DB) *gorm.DB {
+	return db.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	})
+}
+
+// FindPolicies retrieves all escalation policies with their steps
+func (r *EscalationRepositoryImpl) FindPolicies(ctx context.Context) ([]entity.EscalationPolicy, error) {
+	var policies []entity.EscalationPolicy
+
+	result := preloadSteps(r.db.WithContext(ctx)).Order("id ASC").Find(&policies)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return policies, nil
+}
+
+// FindPolicyByID finds an escalation policy with its steps by its ID
+func (r *EscalationRepositoryImpl) FindPolicyByID(ctx context.Context, id uint) (*entity.EscalationPolicy, error) {
+	var policy entity.EscalationPolicy
+
+	result := preloadSteps(r.db.WithContext(ctx)).First(&policy, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("escalation policy not found")
+		}
+		return nil, result.Error
+	}
+
+	return &policy, nil
+}
+
+// FindPolicyByName finds an escalation policy by its name
+func (r *EscalationRepositoryImpl) FindPolicyByName(ctx context.Context, name string) (*entity.EscalationPolicy, error) {
+	var policy entity.EscalationPolicy
+
+	result := r.db.WithContext(ctx).Where("name = ?", name).First(&policy)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("escalation policy not found")
+		}
+		return nil, result.Error
+	}
+
+	return &policy, nil
+}
+
+// CreatePolicy adds a new escalation policy with its steps to the database
+func (r *EscalationRepositoryImpl) CreatePolicy(ctx context.Context, policy *entity.EscalationPolicy) error {
+	return r.db.WithContext(ctx).Create(policy).Error
+}
+
+// UpdatePolicy updates an escalation policy and replaces its steps
+func (r *EscalationRepositoryImpl) UpdatePolicy(ctx context.Context, policy *entity.EscalationPolicy) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(policy).Updates(map[string]interface{}{
+			"name":        policy.Name,
+			"description": policy.Description,
+			"enabled":     policy.Enabled,
+			"updated_at":  time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("escalation policy not found")
+		}
+
+		if err := tx.Where("policy_id = ?", policy.ID).Delete(&entity.EscalationStep{}).Error; err != nil {
+			return err
+		}
+
+		for i := range policy.Steps {
+			policy.Steps[i].ID = 0
+			policy.Steps[i].PolicyID = policy.ID
+		}
+
+		if len(policy.Steps) > 0 {
+			if err := tx.Create(&policy.Steps).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeletePolicy removes an escalation policy, detaching it from its alert types. Escalations
+// in progress stop at their next check.
+func (r *EscalationRepositoryImpl) DeletePolicy(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.AlertType{}).
+			Where("escalation_policy_id = ?", id).
+			Updates(map[string]interface{}{
+				"escalation_policy_id":          nil,
+				"escalation_policy_attached_at": nil,
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("policy_id = ?", id).Delete(&entity.EscalationStep{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&entity.EscalationPolicy{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("escalation policy not found")
+		}
+
+		return nil
+	})
+}
+
+// SetAlertTypePolicy attaches an escalation policy to an alert type, or detaches it with nil.
+// The attach time is only moved when the policy changes.
+func (r *EscalationRepositoryImpl) SetAlertTypePolicy(ctx context.Context, alertTypeID uint, policyID *uint) error {
+	now := time.Now()
+
+	var attachedAt interface{}
+	if policyID != nil {
+		attachedAt = gorm.Expr("CASE WHEN escalation_policy_id = ? THEN escalation_policy_attached_at ELSE ? END", *policyID, now)
+	}
+
+	result := r.db.WithContext(ctx).Model(&entity.AlertType{}).
+		Where("id = ?", alertTypeID).
+		Updates(map[string]interface{}{
+			"escalation_policy_id":          policyID,
+			"escalation_policy_attached_at": attachedAt,
+			"updated_at":                    now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("alert type not found")
+	}
+
+	return nil
+}
+
+// FindUnacknowledgedAlerts retrieves the active alerts of the given types that nobody has
+// acknowledged yet, including alerts stored before statuses existed. Suppressed alerts are left
+// out.
+func (r *EscalationRepositoryImpl) FindUnacknowledgedAlerts(ctx context.Context, alertTypeIDs []uint) ([]entity.Alert, error) {
+	var alerts []entity.Alert
+
+	if len(alertTypeIDs) == 0 {
+		return alerts, nil
+	}
+
+	result := r.db.WithContext(ctx).
+		Where("is_active = ? AND suppressed = ? AND alert_type_id IN ?", true, false, alertTypeIDs).
+		Where("status IN ? OR status IS NULL OR status = ''", []string{entity.AlertStatusNew, entity.AlertStatusReopened}).
+		Order("created_at ASC").
+		Find(&alerts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return alerts, nil
+}
+
+// FindActiveEscalations retrieves escalations waiting for their next step
+func (r *EscalationRepositoryImpl) FindActiveEscalations(ctx context.Context) ([]entity.AlertEscalation, error) {
+	var escalations []entity.AlertEscalation
+
+	result := r.db.WithContext(ctx).Where("status = ?", entity.AlertEscalationActive).Find(&escalations)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return escalations, nil
+}
+
+// FindEscalation finds the escalation of an alert
+func (r *EscalationRepositoryImpl) FindEscalation(ctx context.Context, alertID string) (*entity.AlertEscalation, error) {
+	var escalation entity.AlertEscalation
+
+	result := r.db.WithContext(ctx).Where("alert_id = ?", alertID).First(&escalation)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("alert escalation not found")
+		}
+		return nil, result.Error
+	}
+
+	return &escalation, nil
+}
+
+// SaveEscalation creates or updates the escalation of an alert
+func (r *EscalationRepositoryImpl) SaveEscalation(ctx context.Context, escalation *entity.AlertEscalation) error {
+	escalation.UpdatedAt = time.Now()
+	return r.db.WithContext(ctx).Save(escalation).Error
+}
@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IngestionLedgerRepositoryImpl implements repository.IngestionLedgerRepository
+type IngestionLedgerRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewIngestionLedgerRepository creates a new ingestion ledger repository
+func NewIngestionLedgerRepository(db *gorm.DB) repository.IngestionLedgerRepository {
+	return &IngestionLedgerRepositoryImpl{
+		db: db,
+	}
+}
+
+// FindAll retrieves paginated ledger entries with filters
+func (r *IngestionLedgerRepositoryImpl) FindAll(ctx context.Context, page, limit int, filters map[string]interface{}) ([]entity.IngestionLedgerEntry, int64, error) {
+	var entries []entity.IngestionLedgerEntry
+	var total int64
+
+	offset := (page - 1) * limit
+	query := r.db.WithContext(ctx).Model(&entity.IngestionLedgerEntry{}).Order("updated_at DESC")
+
+	if filters != nil {
+		if status, ok := filters["status"].(string); ok && status != "" {
+			query = query.Where("status = ?", status)
+		}
+
+		if handler, ok := filters["handler"].(string); ok && handler != "" {
+			query = query.Where("handler = ?", handler)
+		}
+
+		if search, ok := filters["search"].(string); ok && search != "" {
+			searchPattern := "%" + search + "%"
+			query = query.Where("file_path ILIKE ? OR error ILIKE ?", searchPattern, searchPattern)
+		}
+	}
+
+	countQuery := query
+	countQuery.Count(&total)
+
+	result := query.Limit(limit).Offset(offset).Find(&entries)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return entries, total, nil
+}
+
+// FindByPathAndHash finds the ledger entry for a file path and content hash
+func (r *IngestionLedgerRepositoryImpl) FindByPathAndHash(ctx context.Context, filePath, contentHash string) (*entity.IngestionLedgerEntry, error) {
+	var entry entity.IngestionLedgerEntry
+
+	result := r.db.WithContext(ctx).
+		Where("file_path = ? AND content_hash = ?", filePath, contentHash).
+		First(&entry)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ledger entry not found")
+		}
+		return nil, result.Error
+	}
+
+	return &entry, nil
+}
+
+// Begin records a processing attempt, creating the entry or incrementing its attempt count
+func (r *IngestionLedgerRepositoryImpl) Begin(ctx context.Context, filePath, contentHash, handler string) (*entity.IngestionLedgerEntry, error) {
+	now := time.Now()
+
+	entry := &entity.IngestionLedgerEntry{
+		FilePath:    filePath,
+		ContentHash: contentHash,
+		Handler:     handler,
+		Status:      entity.IngestionStatusProcessing,
+		Attempts:    1,
+		StartedAt:   &now,
+	}
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "file_path"}, {Name: "content_hash"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"handler":     handler,
+			"status":      entity.IngestionStatusProcessing,
+			"attempts":    gorm.Expr("ingestion_ledger.attempts + 1"),
+			"error":       "",
+			"started_at":  now,
+			"finished_at": nil,
+			"updated_at":  now,
+		}),
+	}).Create(entry)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return r.FindByPathAndHash(ctx, filePath, contentHash)
+}
+
+// Finish records the final status of a processing attempt
+func (r *IngestionLedgerRepositoryImpl) Finish(ctx context.Context, filePath, contentHash, status, errText string) error {
+	now := time.Now()
+
+	result := r.db.WithContext(ctx).Model(&entity.IngestionLedgerEntry{}).
+		Where("file_path = ? AND content_hash = ?", filePath, contentHash).
+		Updates(map[string]interface{}{
+			"status":      status,
+			"error":       errText,
+			"finished_at": now,
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("ledger entry not found")
+	}
+
+	return nil
+}
+
+// Delete removes the ledger entry for a file path and content hash
+func (r *IngestionLedgerRepositoryImpl) Delete(ctx context.Context, filePath, contentHash string) error {
+	return r.db.WithContext(ctx).
+		Where("file_path = ? AND content_hash = ?", filePath, contentHash).
+		Delete(&entity.IngestionLedgerEntry{}).Error
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// MaintenanceWindowRepositoryImpl implements repository.MaintenanceWindowRepository
+type MaintenanceWindowRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewMaintenanceWindowRepository creates a new maintenance window repository
+func NewMaintenanceWindowRepository(db *gorm.DB) repository.MaintenanceWindowRepository {
+	return &MaintenanceWindowRepositoryImpl{
+		db: db,
+	}
+}
+
+// FindAll retrieves maintenance windows with filters
+func (r *MaintenanceWindowRepositoryImpl) FindAll(ctx context.Context, filters map[string]interface{}) ([]entity.MaintenanceWindow, error) {
+	var windows []entity.MaintenanceWindow
+
+	query := r.db.WithContext(ctx).Order("id ASC")
+
+	if filters != nil {
+		if enabled, ok := filters["enabled"].(bool); ok {
+			query = query.Where("enabled = ?", enabled)
+		}
+
+		if cameraID, ok := filters["camera_id"].(uint); ok && cameraID > 0 {
+			query = query.Where("camera_id = ?", cameraID)
+		}
+
+		if zone, ok := filters["zone"].(string); ok && zone != "" {
+			query = query.Where("zone = ?", zone)
+		}
+
+		if alertTypeID, ok := filters["alert_type_id"].(uint); ok && alertTypeID > 0 {
+			query = query.Where("alert_type_id = ?", alertTypeID)
+		}
+
+		// Windows that have not ended by the given time
+		if notEnded, ok := filters["not_ended"].(time.Time); ok {
+			query = query.Where("ends_at IS NULL OR ends_at > ?", notEnded)
+		}
+	}
+
+	result := query.Find(&windows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return windows, nil
+}
+
+// FindByID finds a maintenance window by its ID
+func (r *MaintenanceWindowRepositoryImpl) FindByID(ctx context.Context, id uint) (*entity.MaintenanceWindow, error) {
+	var window entity.MaintenanceWindow
+
+	result := r.db.WithContext(ctx).First(&window, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("maintenance window not found")
+		}
+		return nil, result.Error
+	}
+
+	return &window, nil
+}
+
+// FindByName finds a maintenance window by its name
+func (r *MaintenanceWindowRepositoryImpl) FindByName(ctx context.Context, name string) (*entity.MaintenanceWindow, error) {
+	var window entity.MaintenanceWindow
+
+	result := r.db.WithContext(ctx).Where("name = ?", name).First(&window)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("maintenance window not found")
+		}
+		return nil, result.Error
+	}
+
+	return &window, nil
+}
+
+// Create adds a new maintenance window to the database
+func (r *MaintenanceWindowRepositoryImpl) Create(ctx context.Context, window *entity.MaintenanceWindow) error {
+	return r.db.WithContext(ctx).Create(window).Error
+}
+
+// Update updates an existing maintenance window in the database
+func (r *MaintenanceWindowRepositoryImpl) Update(ctx context.Context, window *entity.MaintenanceWindow) error {
+	result := r.db.WithContext(ctx).Model(window).Updates(map[string]interface{}{
+		"name":           window.Name,
+		"description":    window.Description,
+		"enabled":        window.Enabled,
+		"camera_id":      window.CameraID,
+		"zone":           window.Zone,
+		"alert_type_id":  window.AlertTypeID,
+		"starts_at":      window.StartsAt,
+		"ends_at":        window.EndsAt,
+		"schedule_days":  window.ScheduleDays,
+		"schedule_start": window.ScheduleStart,
+		"schedule_end":   window.ScheduleEnd,
+		"updated_at":     time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("maintenance window not found")
+	}
+
+	return nil
+}
+
+// Delete removes a maintenance window from the database
+func (r *MaintenanceWindowRepositoryImpl) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&entity.MaintenanceWindow{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("maintenance window not found")
+	}
+
+	return nil
+}
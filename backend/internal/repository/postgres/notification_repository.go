@@ -0,0 +1,277 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+
+	"gorm.io/gorm"
+)
+
+// NotificationRepositoryImpl implements repository.NotificationRepository
+type NotificationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *gorm.DB) repository.NotificationRepository {
+	return &NotificationRepositoryImpl{
+		db: db,
+	}
+}
+
+// FindChannels retrieves all notification channels
+func (r *NotificationRepositoryImpl) FindChannels(ctx context.Context) ([]entity.NotificationChannel, error) {
+	var channels []entity.NotificationChannel
+
+	result := r.db.WithContext(ctx).Order("id ASC").Find(&channels)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return channels, nil
+}
+
+// FindChannelByID finds a notification channel by its ID
+func (r *NotificationRepositoryImpl) FindChannelByID(ctx context.Context, id uint) (*entity.NotificationChannel, error) {
+	var channel entity.NotificationChannel
+
+	result := r.db.WithContext(ctx).First(&channel, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("notification channel not found")
+		}
+		return nil, result.Error
+	}
+
+	return &channel, nil
+}
+
+// FindChannelByName finds a notification channel by its name
+func (r *NotificationRepositoryImpl) FindChannelByName(ctx context.Context, name string) (*entity.NotificationChannel, error) {
+	var channel entity.NotificationChannel
+
+	result := r.db.WithContext(ctx).Where("name = ?", name).First(&channel)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("notification channel not found")
+		}
+		return nil, result.Error
+	}
+
+	return &channel, nil
+}
+
+// CreateChannel adds a new notification channel to the database
+func (r *NotificationRepositoryImpl) CreateChannel(ctx context.Context, channel *entity.NotificationChannel) error {
+	return r.db.WithContext(ctx).Create(channel).Error
+}
+
+// UpdateChannel replaces every field of an existing notification channel
+func (r *NotificationRepositoryImpl) UpdateChannel(ctx context.Context, channel *entity.NotificationChannel) error {
+	result := r.db.WithContext(ctx).Model(channel).Select("*").Omit("id", "created_at").Updates(channel)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("notification channel not found")
+	}
+
+	return nil
+}
+
+// DeleteChannel removes a notification channel and its routes, deliveries are kept
+func (r *NotificationRepositoryImpl) DeleteChannel(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("channel_id = ?", id).Delete(&entity.NotificationRoute{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&entity.NotificationChannel{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("notification channel not found")
+		}
+
+		return nil
+	})
+}
+
+// FindRoutes retrieves notification routes with filters
+func (r *NotificationRepositoryImpl) FindRoutes(ctx context.Context, filters map[string]interface{}) ([]entity.NotificationRoute, error) {
+	var routes []entity.NotificationRoute
+
+	query := r.db.WithContext(ctx).Order("id ASC")
+
+	if filters != nil {
+		if channelID, ok := filters["channel_id"].(uint); ok && channelID > 0 {
+			query = query.Where("channel_id = ?", channelID)
+		}
+
+		if enabled, ok := filters["enabled"].(bool); ok {
+			query = query.Where("enabled = ?", enabled)
+		}
+	}
+
+	result := query.Find(&routes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return routes, nil
+}
+
+// FindRouteByID finds a notification route by its ID
+func (r *NotificationRepositoryImpl) FindRouteByID(ctx context.Context, id uint) (*entity.NotificationRoute, error) {
+	var route entity.NotificationRoute
+
+	result := r.db.WithContext(ctx).First(&route, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("notification route not found")
+		}
+		return nil, result.Error
+	}
+
+	return &route, nil
+}
+
+// CreateRoute adds a new notification route to the database
+func (r *NotificationRepositoryImpl) CreateRoute(ctx context.Context, route *entity.NotificationRoute) error {
+	return r.db.WithContext(ctx).Create(route).Error
+}
+
+// UpdateRoute replaces every field of an existing notification route
+func (r *NotificationRepositoryImpl) UpdateRoute(ctx context.Context, route *entity.NotificationRoute) error {
+	result := r.db.WithContext(ctx).Model(route).Select("*").Omit("id", "created_at").Updates(route)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("notification route not found")
+	}
+
+	return nil
+}
+
+// DeleteRoute removes a notification route from the database
+func (r *NotificationRepositoryImpl) DeleteRoute(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&entity.NotificationRoute{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("notification route not found")
+	}
+
+	return nil
+}
+
+// FindDeliveries retrieves paginated notification deliveries with filters, newest first
+func (r *NotificationRepositoryImpl) FindDeliveries(ctx context.Context, page, limit int, filters map[string]interface{}) ([]entity.NotificationDelivery, int64, error) {
+	var deliveries []entity.NotificationDelivery
+	var total int64
+
+	offset := (page - 1) * limit
+	query := r.db.WithContext(ctx).Model(&entity.NotificationDelivery{}).Order("created_at DESC, id DESC")
+
+	if filters != nil {
+		if status, ok := filters["status"].(string); ok && status != "" {
+			query = query.Where("status = ?", status)
+		}
+
+		if channelID, ok := filters["channel_id"].(uint); ok && channelID > 0 {
+			query = query.Where("channel_id = ?", channelID)
+		}
+
+		if alertID, ok := filters["alert_id"].(string); ok && alertID != "" {
+			query = query.Where("alert_id = ?", alertID)
+		}
+	}
+
+	countQuery := query
+	countQuery.Count(&total)
+
+	result := query.Limit(limit).Offset(offset).Find(&deliveries)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return deliveries, total, nil
+}
+
+// FindDeliveryByID finds a notification delivery by its ID
+func (r *NotificationRepositoryImpl) FindDeliveryByID(ctx context.Context, id uint) (*entity.NotificationDelivery, error) {
+	var delivery entity.NotificationDelivery
+
+	result := r.db.WithContext(ctx).First(&delivery, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("notification delivery not found")
+		}
+		return nil, result.Error
+	}
+
+	return &delivery, nil
+}
+
+// FindDueDeliveries retrieves pending deliveries whose next attempt is due, oldest first
+func (r *NotificationRepositoryImpl) FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.NotificationDelivery, error) {
+	var deliveries []entity.NotificationDelivery
+
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", entity.NotificationStatusPending, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&deliveries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return deliveries, nil
+}
+
+// CreateDelivery adds a new notification delivery to the database
+func (r *NotificationRepositoryImpl) CreateDelivery(ctx context.Context, delivery *entity.NotificationDelivery) error {
+	return r.db.WithContext(ctx).Create(delivery).Error
+}
+
+// ClaimDelivery claims the next attempt of a delivery that is not sent yet, counting it and
+// leasing the delivery until leaseUntil. Only one caller claims an attempt, whatever process it
+// runs in, the others get false.
+func (r *NotificationRepositoryImpl) ClaimDelivery(ctx context.Context, id uint, attempts int, leaseUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.NotificationDelivery{}).
+		Where("id = ? AND attempts = ? AND status <> ?", id, attempts, entity.NotificationStatusSent).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": leaseUntil,
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// UpdateDelivery records the outcome of a delivery attempt
+func (r *NotificationRepositoryImpl) UpdateDelivery(ctx context.Context, delivery *entity.NotificationDelivery) error {
+	return r.db.WithContext(ctx).Model(delivery).Updates(map[string]interface{}{
+		"status":          delivery.Status,
+		"attempts":        delivery.Attempts,
+		"last_error":      delivery.LastError,
+		"response_status": delivery.ResponseStatus,
+		"next_attempt_at": delivery.NextAttemptAt,
+		"delivered_at":    delivery.DeliveredAt,
+		"updated_at":      time.Now(),
+	}).Error
+}
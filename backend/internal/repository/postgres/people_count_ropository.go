@@ -35,8 +35,12 @@ func (r *PeopleCountRepositoryImpl) FindAll(ctx context.Context, page, limit int
 	query := r.db.WithContext(ctx).Model(&entity.PeopleCount{}).Order("timestamp DESC")
 
 	if filters != nil {
-		if areaID, ok := filters["camera_id"].(uint); ok && areaID != 0 {
-			query = query.Where("camera_id = ?", areaID)
+		if cameraID, ok := filters["camera_id"].(uint); ok && cameraID != 0 {
+			query = query.Where("camera_id = ?", cameraID)
+		}
+
+		if cameraIDs, ok := filters["camera_ids"].([]uint); ok {
+			query = query.Where("camera_id IN ?", cameraIDs)
 		}
 
 		if from, ok := filters["from"].(time.Time); ok && !from.IsZero() {
@@ -47,8 +51,8 @@ func (r *PeopleCountRepositoryImpl) FindAll(ctx context.Context, page, limit int
 			query = query.Where("timestamp <= ?", to)
 		}
 
-		if includeArea, ok := filters["include_area"].(bool); ok && includeArea {
-			query = query.Preload("Area")
+		if includeZones, ok := filters["include_zones"].(bool); ok && includeZones {
+			query = query.Preload("Camera").Preload("Camera.Zones.Zone")
 		}
 	}
 
@@ -82,11 +86,12 @@ func (r *PeopleCountRepositoryImpl) FindByID(ctx context.Context, id string) (*e
 	return &count, nil
 }
 
-func (r *PeopleCountRepositoryImpl) FindByArea(ctx context.Context, areaID uint, from, to time.Time, limit int) ([]entity.PeopleCount, error) {
+// FindByZone finds the people counts of the cameras of a zone and of the zones inside it
+func (r *PeopleCountRepositoryImpl) FindByZone(ctx context.Context, zoneID uint, from, to time.Time, limit int) ([]entity.PeopleCount, error) {
 	var counts []entity.PeopleCount
 
 	query := r.db.WithContext(ctx).
-		Where("camera_id = ?", areaID).
+		Where("camera_id IN ("+zoneCameraIDsQuery+")", zoneID).
 		Order("timestamp DESC").
 		Limit(limit)
 
@@ -185,6 +190,9 @@ func (r *PeopleCountRepositoryImpl) GetSummary(ctx context.Context, filters map[
 		if cameraIDStr, ok := filters["camera_id"].(string); ok && cameraIDStr != "" {
 			query = query.Where("pc.camera_id = ?", cameraIDStr)
 		}
+		if cameraIDs, ok := filters["camera_ids"].([]uint); ok {
+			query = query.Where("pc.camera_id IN ?", cameraIDs)
+		}
 	}
 
 	err := query.Scan(&rows).Error
@@ -272,6 +280,9 @@ func (r *PeopleCountRepositoryImpl) GetTrends(ctx context.Context, interval stri
 		if cameraIDStr, ok := filters["camera_id"].(string); ok && cameraIDStr != "" {
 			query = query.Where("camera_id = ?", cameraIDStr)
 		}
+		if cameraIDs, ok := filters["camera_ids"].([]uint); ok {
+			query = query.Where("camera_id IN ?", cameraIDs)
+		}
 	}
 
 	var trends []entity.TrendPoint
@@ -313,6 +324,102 @@ func (r *PeopleCountRepositoryImpl) GetDistributionByCamera(ctx context.Context,
 	return distribution, nil
 }
 
+// GetFlowSince sums the in and out counts of every camera since the given time
+func (r *PeopleCountRepositoryImpl) GetFlowSince(ctx context.Context, since time.Time, filters map[string]interface{}) ([]entity.CameraOccupancy, error) {
+	var flows []entity.CameraOccupancy
+
+	query := r.db.WithContext(ctx).Table("people_counts pc").
+		Select("pc.camera_id, a.name as camera_name, COALESCE(a.location, '') as zone, COALESCE(SUM(pc.in_count), 0) as in_count, COALESCE(SUM(pc.out_count), 0) as out_count, MAX(pc.timestamp) as last_updated").
+		Joins("JOIN cameras a ON pc.camera_id = a.id").
+		Where("pc.timestamp >= ?", since)
+
+	if filters != nil {
+		if cameraID, ok := filters["camera_id"].(uint); ok && cameraID > 0 {
+			query = query.Where("pc.camera_id = ?", cameraID)
+		}
+		if zone, ok := filters["zone"].(string); ok && zone != "" {
+			query = query.Where("a.location = ?", zone)
+		}
+		if cameraIDs, ok := filters["camera_ids"].([]uint); ok {
+			query = query.Where("pc.camera_id IN ?", cameraIDs)
+		}
+	}
+
+	err := query.Group("pc.camera_id, a.name, a.location").
+		Order("pc.camera_id ASC").
+		Find(&flows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch people flow: %w", err)
+	}
+
+	return flows, nil
+}
+
+// GetTotalsBetween sums people counts between from (inclusive) and to (exclusive), filtered by
+// camera, by zone (camera location) or by a set of cameras
+func (r *PeopleCountRepositoryImpl) GetTotalsBetween(ctx context.Context, from, to time.Time, filters map[string]interface{}) (*entity.TrendPoint, error) {
+	var totals entity.TrendPoint
+
+	query := r.db.WithContext(ctx).Table("people_counts pc").
+		Joins("JOIN cameras a ON pc.camera_id = a.id").
+		Where("pc.timestamp >= ? AND pc.timestamp < ?", from, to)
+
+	if filters != nil {
+		if cameraID, ok := filters["camera_id"].(uint); ok && cameraID > 0 {
+			query = query.Where("pc.camera_id = ?", cameraID)
+		}
+		if zone, ok := filters["zone"].(string); ok && zone != "" {
+			query = query.Where("a.location = ?", zone)
+		}
+		if cameraIDs, ok := filters["camera_ids"].([]uint); ok {
+			query = query.Where("pc.camera_id IN ?", cameraIDs)
+		}
+	}
+
+	err := query.Select("COALESCE(SUM(pc.male_count), 0) as male_count, COALESCE(SUM(pc.female_count), 0) as female_count, COALESCE(SUM(pc.male_count + pc.female_count), 0) as total_count, COALESCE(SUM(pc.child_count), 0) as child_count, COALESCE(SUM(pc.adult_count), 0) as adult_count, COALESCE(SUM(pc.elderly_count), 0) as elderly_count, COALESCE(SUM(pc.in_count), 0) as in_count, COALESCE(SUM(pc.out_count), 0) as out_count").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum people counts: %w", err)
+	}
+
+	return &totals, nil
+}
+
+// GetAggregates reads the hourly or daily people count aggregate between from (inclusive)
+// and to (exclusive), summed over cameras
+func (r *PeopleCountRepositoryImpl) GetAggregates(ctx context.Context, interval string, from, to time.Time, filters map[string]interface{}) ([]entity.TrendPoint, error) {
+	var table string
+	switch interval {
+	case "hour":
+		table = "people_counts_hourly"
+	case "day":
+		table = "people_counts_daily"
+	default:
+		return nil, errors.New("invalid interval. Must be hour or day")
+	}
+
+	var points []entity.TrendPoint
+
+	query := r.db.WithContext(ctx).Table(table).
+		Select("bucket as time_period, SUM(male_count) as male_count, SUM(female_count) as female_count, SUM(total_count) as total_count, SUM(child_count) as child_count, SUM(adult_count) as adult_count, SUM(elderly_count) as elderly_count, SUM(in_count) as in_count, SUM(out_count) as out_count").
+		Where("bucket >= ? AND bucket < ?", from, to)
+
+	if filters != nil {
+		if cameraID, ok := filters["camera_id"].(uint); ok && cameraID > 0 {
+			query = query.Where("camera_id = ?", cameraID)
+		}
+	}
+
+	err := query.Group("bucket").
+		Order("bucket ASC").
+		Scan(&points).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s aggregates: %w", table, err)
+	}
+
+	return points, nil
+}
+
 func (r *PeopleCountRepositoryImpl) GetDistributionByGender(ctx context.Context, timeWindow time.Time) (*entity.TotalCounts, error) {
 	var counts entity.TotalCounts
 
@@ -390,6 +497,9 @@ func (r *PeopleCountRepositoryImpl) GetPeakHoursAnalysis(ctx context.Context, fi
 		if cameraIDStr, ok := filters["camera_id"].(string); ok && cameraIDStr != "" {
 			query = query.Where("camera_id = ?", cameraIDStr)
 		}
+		if cameraIDs, ok := filters["camera_ids"].([]uint); ok {
+			query = query.Where("camera_id IN ?", cameraIDs)
+		}
 	}
 
 	err := query.Find(&results).Error
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"people-counting/internal/domain/entity"
@@ -358,6 +359,36 @@ func (r *VehicleCountRepositoryImpl) GetDistributionByVehicleType(ctx context.Co
 	return &counts, nil
 }
 
+// GetTotalsBetween sums vehicle counts between from (inclusive) and to (exclusive), filtered by
+// camera, by zone (camera location) or by a set of cameras
+func (r *VehicleCountRepositoryImpl) GetTotalsBetween(ctx context.Context, from, to time.Time, filters map[string]interface{}) (*entity.VehicleTotalCounts, error) {
+	var counts entity.VehicleTotalCounts
+
+	query := r.db.WithContext(ctx).Table("vehicle_counts vc").
+		Joins("JOIN cameras c ON vc.cctv_id = c.id").
+		Where("vc.timestamp >= ? AND vc.timestamp < ?", from, to)
+
+	if filters != nil {
+		if cameraID, ok := filters["camera_id"].(uint); ok && cameraID > 0 {
+			query = query.Where("vc.cctv_id = ?", cameraID)
+		}
+		if zone, ok := filters["zone"].(string); ok && zone != "" {
+			query = query.Where("c.location = ?", zone)
+		}
+		if cameraIDs, ok := filters["camera_ids"].([]uint); ok {
+			query = query.Where("vc.cctv_id IN ?", cameraIDs)
+		}
+	}
+
+	err := query.Select("COALESCE(SUM(vc.in_count_car), 0) as in_car, COALESCE(SUM(vc.in_count_truck), 0) as in_truck, COALESCE(SUM(vc.in_count_people), 0) as in_people, COALESCE(SUM(vc.out_count), 0) as out, COALESCE(SUM(vc.total_in_count), 0) as total_in, COALESCE(SUM(vc.total_vehicle_in_count), 0) as total_vehicle_in, COALESCE(SUM(vc.total_in_count) - SUM(vc.out_count), 0) as net_count").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum vehicle counts: %w", err)
+	}
+
+	return &counts, nil
+}
+
 // GetLatestByCctv retrieves the latest vehicle count record for a specific CCTV
 func (r *VehicleCountRepositoryImpl) GetLatestByCctv(ctx context.Context, cctvID uint) (*entity.VehicleCount, error) {
 	var count entity.VehicleCount
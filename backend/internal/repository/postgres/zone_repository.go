@@ -0,0 +1,226 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// zoneCameraIDsQuery selects the IDs of the cameras of a zone and of the zones inside it
+const zoneCameraIDsQuery = `
+	WITH RECURSIVE subtree AS (
+		SELECT id FROM zones WHERE id = ?
+		UNION ALL
+		SELECT z.id FROM zones z JOIN subtree s ON z.parent_id = s.id
+	)
+	SELECT DISTINCT zc.camera_id FROM zone_cameras zc JOIN subtree s ON zc.zone_id = s.id`
+
+// ZoneRepositoryImpl implements repository.ZoneRepository
+type ZoneRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewZoneRepository creates a new zone repository
+func NewZoneRepository(db *gorm.DB) repository.ZoneRepository {
+	return &ZoneRepositoryImpl{
+		db: db,
+	}
+}
+
+// FindAll retrieves zones with their cameras, with filters
+func (r *ZoneRepositoryImpl) FindAll(ctx context.Context, filters map[string]interface{}) ([]entity.Zone, error) {
+	var zones []entity.Zone
+
+	query := r.db.WithContext(ctx).Preload("Cameras").Order("id ASC")
+
+	if filters != nil {
+		if kind, ok := filters["kind"].(string); ok && kind != "" {
+			query = query.Where("kind = ?", kind)
+		}
+
+		if parentID, ok := filters["parent_id"].(uint); ok && parentID > 0 {
+			query = query.Where("parent_id = ?", parentID)
+		}
+	}
+
+	result := query.Find(&zones)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return zones, nil
+}
+
+// FindByID finds a zone by its ID with its cameras
+func (r *ZoneRepositoryImpl) FindByID(ctx context.Context, id uint) (*entity.Zone, error) {
+	var zone entity.Zone
+
+	result := r.db.WithContext(ctx).
+		Preload("Cameras", func(db *gorm.DB) *gorm.DB {
+			return db.Order("camera_id ASC")
+		}).
+		Preload("Cameras.Camera").
+		First(&zone, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("zone not found")
+		}
+		return nil, result.Error
+	}
+
+	return &zone, nil
+}
+
+// FindByName finds a zone by its name within its parent, a site when parentID is nil
+func (r *ZoneRepositoryImpl) FindByName(ctx context.Context, parentID *uint, name string) (*entity.Zone, error) {
+	var zone entity.Zone
+
+	query := r.db.WithContext(ctx).Where("LOWER(name) = LOWER(?)", name)
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+
+	result := query.First(&zone)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, errors.New("zone not found")
+		}
+		return nil, result.Error
+	}
+
+	return &zone, nil
+}
+
+// Create adds a new zone to the database
+func (r *ZoneRepositoryImpl) Create(ctx context.Context, zone *entity.Zone) error {
+	return r.db.WithContext(ctx).Omit("Cameras").Create(zone).Error
+}
+
+// Update updates an existing zone in the database
+func (r *ZoneRepositoryImpl) Update(ctx context.Context, zone *entity.Zone) error {
+	result := r.db.WithContext(ctx).Model(&entity.Zone{}).Where("id = ?", zone.ID).Updates(map[string]interface{}{
+		"parent_id":   zone.ParentID,
+		"kind":        zone.Kind,
+		"name":        zone.Name,
+		"description": zone.Description,
+		"capacity":    zone.Capacity,
+		"updated_at":  time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("zone not found")
+	}
+
+	return nil
+}
+
+// Delete removes a zone and its cameras from the database
+func (r *ZoneRepositoryImpl) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("zone_id = ?", id).Delete(&entity.ZoneCamera{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&entity.Zone{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("zone not found")
+		}
+
+		return nil
+	})
+}
+
+// SetFloorPlan sets the floor plan of a zone, or removes it when floorPlan is empty. Cameras
+// placed outside the new floor plan are unplaced.
+func (r *ZoneRepositoryImpl) SetFloorPlan(ctx context.Context, id uint, floorPlan string, width, height int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Zone{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"floor_plan":        floorPlan,
+			"floor_plan_width":  width,
+			"floor_plan_height": height,
+			"updated_at":        time.Now(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("zone not found")
+		}
+
+		return tx.Model(&entity.ZoneCamera{}).
+			Where("zone_id = ? AND plan_x IS NOT NULL", id).
+			Where("? = '' OR plan_x > ? OR plan_y > ?", floorPlan, width, height).
+			Updates(map[string]interface{}{"plan_x": nil, "plan_y": nil}).Error
+	})
+}
+
+// CountChildren counts the zones directly inside a zone
+func (r *ZoneRepositoryImpl) CountChildren(ctx context.Context, id uint) (int64, error) {
+	var count int64
+
+	result := r.db.WithContext(ctx).Model(&entity.Zone{}).Where("parent_id = ?", id).Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+// SetCameras replaces the cameras of a zone
+func (r *ZoneRepositoryImpl) SetCameras(ctx context.Context, zoneID uint, cameras []entity.ZoneCamera) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("zone_id = ?", zoneID).Delete(&entity.ZoneCamera{}).Error; err != nil {
+			return err
+		}
+
+		if len(cameras) == 0 {
+			return nil
+		}
+
+		for i := range cameras {
+			cameras[i].ID = 0
+			cameras[i].ZoneID = zoneID
+		}
+
+		return tx.Omit(clause.Associations).Create(&cameras).Error
+	})
+}
+
+// SaveCamera adds a camera to a zone or changes its direction and position when it is already
+// in the zone
+func (r *ZoneRepositoryImpl) SaveCamera(ctx context.Context, membership *entity.ZoneCamera) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "zone_id"}, {Name: "camera_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"direction", "plan_x", "plan_y"}),
+	}).Create(membership).Error
+}
+
+// RemoveCamera removes a camera from a zone
+func (r *ZoneRepositoryImpl) RemoveCamera(ctx context.Context, zoneID, cameraID uint) error {
+	result := r.db.WithContext(ctx).Where("zone_id = ? AND camera_id = ?", zoneID, cameraID).Delete(&entity.ZoneCamera{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("camera is not in the zone")
+	}
+
+	return nil
+}
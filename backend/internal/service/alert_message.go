@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"people-counting/internal/domain/entity"
+)
+
+// alertMessageTimeFormat formats the Time placeholder of alert messages
+const alertMessageTimeFormat = "2006-01-02 15:04:05"
+
+// defaultAlertMessages are the message templates of the alert types sent by the detectors, by
+// lowercase alert type name and language. Templates stored for an alert type take precedence.
+var defaultAlertMessages = map[string]map[string]string{
+	"tidak patuh": {
+		entity.AlertLanguageEnglish:    "Personal protective equipment violation{{if .MissingItem}}: Missing {{.MissingItem}}{{else}} detected{{end}}",
+		entity.AlertLanguageIndonesian: "Pelanggaran alat pelindung diri{{if .MissingItem}}: Tidak memakai {{.MissingItem}}{{else}} terdeteksi{{end}}",
+	},
+	"personal-protective-equipment": {
+		entity.AlertLanguageEnglish:    "Personal protective equipment violation{{if .MissingItem}}: Missing {{.MissingItem}}{{else}} detected{{end}}",
+		entity.AlertLanguageIndonesian: "Pelanggaran alat pelindung diri{{if .MissingItem}}: Tidak memakai {{.MissingItem}}{{else}} terdeteksi{{end}}",
+	},
+	"restricted": {
+		entity.AlertLanguageEnglish:    "Person detected in restricted area",
+		entity.AlertLanguageIndonesian: "Orang terdeteksi di area terlarang",
+	},
+	"fall-detection": {
+		entity.AlertLanguageEnglish:    "Person fall detected",
+		entity.AlertLanguageIndonesian: "Orang jatuh terdeteksi",
+	},
+	"loitering": {
+		entity.AlertLanguageEnglish:    "Extended loitering detected",
+		entity.AlertLanguageIndonesian: "Orang berdiam terlalu lama terdeteksi",
+	},
+	"hazardous-area": {
+		entity.AlertLanguageEnglish:    "Person detected in hazardous area",
+		entity.AlertLanguageIndonesian: "Orang terdeteksi di area berbahaya",
+	},
+}
+
+// genericAlertMessages are the message templates of alert types without their own, by language
+var genericAlertMessages = map[string]string{
+	entity.AlertLanguageEnglish:    "Alert detected: {{.AlertType}}{{if .MissingItem}} (Missing: {{.MissingItem}}){{end}}",
+	entity.AlertLanguageIndonesian: "Peringatan terdeteksi: {{.AlertType}}{{if .MissingItem}} (Tidak memakai: {{.MissingItem}}){{end}}",
+}
+
+// defaultAlertMessage returns the built-in message template of an alert type in a language
+func defaultAlertMessage(alertType *entity.AlertType, language string) string {
+	if messages, ok := defaultAlertMessages[strings.ToLower(strings.TrimSpace(alertType.Name))]; ok {
+		if message, ok := messages[language]; ok {
+			return message
+		}
+	}
+	return genericAlertMessages[language]
+}
+
+// alertMessageTemplate returns the message template of an alert type in a language, stored
+// templates taking precedence over the built-in ones
+func alertMessageTemplate(alertType *entity.AlertType, stored []entity.AlertTypeMessage, language string) string {
+	for _, message := range stored {
+		if message.AlertTypeID == alertType.ID && message.Language == language && message.Template != "" {
+			return message.Template
+		}
+	}
+	return defaultAlertMessage(alertType, language)
+}
+
+// newAlertMessageData returns the placeholders of the message of an alert
+func newAlertMessageData(alert *entity.Alert, alertType *entity.AlertType, cameraName string) *entity.AlertMessageData {
+	typeName := alertType.DisplayName
+	if typeName == "" {
+		typeName = alertType.Name
+	}
+
+	if cameraName == "" {
+		cameraName = fmt.Sprintf("Camera %d", alert.CameraID)
+	}
+
+	return &entity.AlertMessageData{
+		AlertType:   typeName,
+		Camera:      cameraName,
+		MissingItem: alert.MissingItem,
+		ObjectName:  alert.ObjectName,
+		Time:        alert.DetectedAt.Format(alertMessageTimeFormat),
+	}
+}
+
+// renderAlertMessage renders an alert message template
+func renderAlertMessage(template string, data *entity.AlertMessageData) (string, error) {
+	message, err := renderTemplate("message", template, data)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(message), nil
+}
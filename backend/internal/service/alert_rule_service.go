@@ -0,0 +1,534 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+	"people-counting/internal/domain/service"
+)
+
+// AlertRuleServiceImpl implements service.AlertRuleService
+type AlertRuleServiceImpl struct {
+	alertRuleRepository   repository.AlertRuleRepository
+	alertTypeRepository   repository.AlertTypeRepository
+	cameraRepository      repository.CameraRepository
+	zoneRepository        repository.ZoneRepository
+	peopleCountRepository repository.PeopleCountRepository
+	vehicleRepository     repository.VehicleCountRepository
+	alertService          service.AlertService
+	webSocketService      service.WebSocketService
+	occupancyReset        entity.OccupancyReset
+
+	// evaluateMu keeps scheduled and manual evaluations from raising the same alert twice
+	evaluateMu sync.Mutex
+}
+
+// NewAlertRuleService creates a new alert rule service. Schedules are read in the time zone of
+// occupancyReset, and occupancy is counted since its last reset.
+func NewAlertRuleService(
+	alertRuleRepository repository.AlertRuleRepository,
+	alertTypeRepository repository.AlertTypeRepository,
+	cameraRepository repository.CameraRepository,
+	zoneRepository repository.ZoneRepository,
+	peopleCountRepository repository.PeopleCountRepository,
+	vehicleRepository repository.VehicleCountRepository,
+	alertService service.AlertService,
+	webSocketService service.WebSocketService,
+	occupancyReset entity.OccupancyReset,
+) service.AlertRuleService {
+	return &AlertRuleServiceImpl{
+		alertRuleRepository:   alertRuleRepository,
+		alertTypeRepository:   alertTypeRepository,
+		cameraRepository:      cameraRepository,
+		zoneRepository:        zoneRepository,
+		peopleCountRepository: peopleCountRepository,
+		vehicleRepository:     vehicleRepository,
+		alertService:          alertService,
+		webSocketService:      webSocketService,
+		occupancyReset:        occupancyReset,
+	}
+}
+
+// GetAllRules retrieves alert rules with filters
+func (s *AlertRuleServiceImpl) GetAllRules(ctx context.Context, enabled, cameraID, zoneID, zone string) ([]entity.AlertRule, error) {
+	filters := make(map[string]interface{})
+
+	switch enabled {
+	case "true":
+		filters["enabled"] = true
+	case "false":
+		filters["enabled"] = false
+	}
+
+	if cameraID != "" {
+		id, err := strconv.ParseUint(cameraID, 10, 32)
+		if err != nil {
+			return nil, errors.New("invalid camera ID")
+		}
+		filters["camera_id"] = uint(id)
+	}
+
+	if zoneID != "" {
+		id, err := strconv.ParseUint(zoneID, 10, 32)
+		if err != nil {
+			return nil, errors.New("invalid zone ID")
+		}
+		filters["zone_id"] = uint(id)
+	}
+
+	if zone != "" {
+		filters["zone"] = zone
+	}
+
+	return s.alertRuleRepository.FindAll(ctx, filters)
+}
+
+// GetRuleByID retrieves an alert rule by its ID
+func (s *AlertRuleServiceImpl) GetRuleByID(ctx context.Context, id uint) (*entity.AlertRule, error) {
+	return s.alertRuleRepository.FindByID(ctx, id)
+}
+
+// CreateRule creates an alert rule together with the alert type of its alerts
+func (s *AlertRuleServiceImpl) CreateRule(ctx context.Context, rule *entity.AlertRule) error {
+	if err := s.validateRule(ctx, rule); err != nil {
+		return err
+	}
+
+	if _, err := s.alertRuleRepository.FindByName(ctx, rule.Name); err == nil {
+		return errors.New("an alert rule with the same name already exists")
+	}
+
+	alertTypeID, err := s.ruleAlertType(ctx, rule.Name)
+	if err != nil {
+		return err
+	}
+	rule.AlertTypeID = alertTypeID
+
+	return s.alertRuleRepository.Create(ctx, rule)
+}
+
+// UpdateRule updates an alert rule, its alerts keep their alert type
+func (s *AlertRuleServiceImpl) UpdateRule(ctx context.Context, rule *entity.AlertRule) error {
+	existing, err := s.alertRuleRepository.FindByID(ctx, rule.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validateRule(ctx, rule); err != nil {
+		return err
+	}
+
+	if other, err := s.alertRuleRepository.FindByName(ctx, rule.Name); err == nil && other.ID != rule.ID {
+		return errors.New("an alert rule with the same name already exists")
+	}
+
+	rule.AlertTypeID = existing.AlertTypeID
+	if rule.AlertTypeID == 0 {
+		if rule.AlertTypeID, err = s.ruleAlertType(ctx, rule.Name); err != nil {
+			return err
+		}
+	}
+
+	return s.alertRuleRepository.Update(ctx, rule)
+}
+
+// DeleteRule deletes an alert rule, its alerts are kept
+func (s *AlertRuleServiceImpl) DeleteRule(ctx context.Context, id uint) error {
+	return s.alertRuleRepository.Delete(ctx, id)
+}
+
+// EvaluateRules evaluates every enabled rule and raises an alert for each match
+func (s *AlertRuleServiceImpl) EvaluateRules(ctx context.Context) ([]entity.RuleEvaluation, error) {
+	s.evaluateMu.Lock()
+	defer s.evaluateMu.Unlock()
+
+	rules, err := s.alertRuleRepository.FindAll(ctx, map[string]interface{}{"enabled": true})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	evaluations := make([]entity.RuleEvaluation, 0, len(rules))
+
+	for i := range rules {
+		evaluation, err := s.evaluateRule(ctx, &rules[i], now)
+		if err != nil {
+			log.Printf("Failed to evaluate alert rule %q: %v", rules[i].Name, err)
+			evaluation.Skipped = err.Error()
+		}
+		evaluations = append(evaluations, evaluation)
+	}
+
+	return evaluations, nil
+}
+
+// Run evaluates the rules every interval until ctx is cancelled
+func (s *AlertRuleServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.EvaluateRules(ctx); err != nil {
+				log.Printf("Failed to evaluate alert rules: %v", err)
+			}
+		}
+	}
+}
+
+func (s *AlertRuleServiceImpl) evaluateRule(ctx context.Context, rule *entity.AlertRule, now time.Time) (entity.RuleEvaluation, error) {
+	evaluation := entity.RuleEvaluation{
+		RuleID:    rule.ID,
+		RuleName:  rule.Name,
+		Threshold: rule.Threshold,
+	}
+
+	// The whole window must fall inside the schedule, so a window that starts before opening
+	// time does not count as a quiet period
+	windowStart := now.Add(-rule.Window())
+	if !rule.Applies(now, s.location()) || !rule.Applies(windowStart, s.location()) {
+		evaluation.Skipped = "outside schedule"
+		return evaluation, nil
+	}
+
+	if rule.LastTriggeredAt != nil && now.Sub(*rule.LastTriggeredAt) < rule.Cooldown() {
+		evaluation.Skipped = "cooldown"
+		return evaluation, nil
+	}
+
+	cameraIDs, err := s.ruleCameraIDs(ctx, rule)
+	if err != nil {
+		return evaluation, err
+	}
+	if len(cameraIDs) == 0 {
+		evaluation.Skipped = "no cameras"
+		return evaluation, nil
+	}
+
+	value, err := s.metricValue(ctx, rule, cameraIDs, windowStart, now)
+	if err != nil {
+		return evaluation, err
+	}
+	evaluation.Value = value
+
+	var previous float64
+	switch rule.Condition {
+	case entity.RuleConditionAbove:
+		if rule.Metric == entity.RuleMetricOccupancy && rule.Threshold == 0 {
+			capacity, err := s.targetCapacity(ctx, rule, cameraIDs)
+			if err != nil {
+				return evaluation, err
+			}
+			if capacity == 0 {
+				evaluation.Skipped = "no capacity"
+				return evaluation, nil
+			}
+			evaluation.Threshold = float64(capacity)
+		}
+		evaluation.Matched = value > evaluation.Threshold
+	case entity.RuleConditionAtMost:
+		evaluation.Matched = value <= evaluation.Threshold
+	case entity.RuleConditionSurge:
+		previous, err = s.metricValue(ctx, rule, cameraIDs, windowStart.Add(-rule.Window()), windowStart)
+		if err != nil {
+			return evaluation, err
+		}
+		// A quiet previous window counts as one, so a handful of records is not a surge
+		evaluation.Threshold = rule.Threshold * max(previous, 1)
+		evaluation.Matched = value >= evaluation.Threshold
+	}
+
+	if !evaluation.Matched {
+		return evaluation, nil
+	}
+
+	alerts, err := s.raiseAlerts(ctx, rule, cameraIDs, ruleMessage(rule, value, evaluation.Threshold, previous), now)
+	for _, alert := range alerts {
+		evaluation.AlertIDs = append(evaluation.AlertIDs, alert.ID)
+	}
+	if len(alerts) > 0 {
+		evaluation.AlertID = alerts[0].ID
+	}
+
+	return evaluation, err
+}
+
+// metricValue reads the metric of the cameras targeted by a rule between from and to
+func (s *AlertRuleServiceImpl) metricValue(ctx context.Context, rule *entity.AlertRule, cameraIDs []uint, from, to time.Time) (float64, error) {
+	filters := map[string]interface{}{"camera_ids": cameraIDs}
+
+	if rule.Metric == entity.RuleMetricOccupancy {
+		flows, err := s.peopleCountRepository.GetFlowSince(ctx, s.occupancyReset.LastReset(to), filters)
+		if err != nil {
+			return 0, err
+		}
+
+		occupancy := 0
+		for _, flow := range flows {
+			occupancy += max(flow.InCount-flow.OutCount, 0)
+		}
+		return float64(occupancy), nil
+	}
+
+	if entity.IsVehicleRuleMetric(rule.Metric) {
+		totals, err := s.vehicleRepository.GetTotalsBetween(ctx, from, to, filters)
+		if err != nil {
+			return 0, err
+		}
+
+		switch rule.Metric {
+		case entity.RuleMetricCarIn:
+			return float64(totals.InCar), nil
+		case entity.RuleMetricTruckIn:
+			return float64(totals.InTruck), nil
+		case entity.RuleMetricVehicleOut:
+			return float64(totals.Out), nil
+		default:
+			return float64(totals.TotalVehicleIn), nil
+		}
+	}
+
+	totals, err := s.peopleCountRepository.GetTotalsBetween(ctx, from, to, filters)
+	if err != nil {
+		return 0, err
+	}
+
+	switch rule.Metric {
+	case entity.RuleMetricPeopleOut:
+		return float64(totals.OutCount), nil
+	case entity.RuleMetricPeopleTotal:
+		return float64(totals.TotalCount), nil
+	default:
+		return float64(totals.InCount), nil
+	}
+}
+
+// ruleCameraIDs returns the cameras targeted by a rule
+func (s *AlertRuleServiceImpl) ruleCameraIDs(ctx context.Context, rule *entity.AlertRule) ([]uint, error) {
+	if rule.CameraID != nil {
+		return []uint{*rule.CameraID}, nil
+	}
+
+	if rule.ZoneID != nil {
+		tree, err := loadZoneTree(ctx, s.zoneRepository)
+		if err != nil {
+			return nil, err
+		}
+		if tree.zones[*rule.ZoneID] == nil {
+			return nil, errors.New("zone not found")
+		}
+		return tree.cameraIDs(*rule.ZoneID), nil
+	}
+
+	cameras, err := s.cameraRepository.FindAll(ctx, map[string]interface{}{"location": rule.Zone})
+	if err != nil {
+		return nil, err
+	}
+
+	cameraIDs := make([]uint, 0, len(cameras))
+	for _, camera := range cameras {
+		cameraIDs = append(cameraIDs, camera.ID)
+	}
+	return cameraIDs, nil
+}
+
+// targetCapacity returns the capacity of the zone targeted by a rule, or sums the capacity of
+// its cameras when the zone has none
+func (s *AlertRuleServiceImpl) targetCapacity(ctx context.Context, rule *entity.AlertRule, cameraIDs []uint) (int, error) {
+	if rule.ZoneID != nil {
+		zone, err := s.zoneRepository.FindByID(ctx, *rule.ZoneID)
+		if err != nil {
+			return 0, err
+		}
+		if zone.Capacity > 0 {
+			return zone.Capacity, nil
+		}
+	}
+
+	capacity := 0
+	for _, cameraID := range cameraIDs {
+		camera, err := s.cameraRepository.FindByID(ctx, cameraID)
+		if err != nil {
+			return 0, err
+		}
+		capacity += camera.Capacity
+	}
+	return capacity, nil
+}
+
+// raiseAlerts creates an alert of a matched rule on each of its cameras and pushes them to
+// connected clients
+func (s *AlertRuleServiceImpl) raiseAlerts(ctx context.Context, rule *entity.AlertRule, cameraIDs []uint, message string, now time.Time) ([]*entity.Alert, error) {
+	alerts := make([]*entity.Alert, 0, len(cameraIDs))
+	var createErr error
+
+	for _, cameraID := range cameraIDs {
+		alert := &entity.Alert{
+			AlertTypeID: rule.AlertTypeID,
+			CameraID:    cameraID,
+			Message:     message,
+			Severity:    rule.Severity,
+			DetectedAt:  now,
+		}
+
+		if err := s.alertService.CreateAlert(ctx, alert); err != nil {
+			createErr = fmt.Errorf("failed to create alert for camera %d: %w", cameraID, err)
+			break
+		}
+		alerts = append(alerts, alert)
+
+		if s.webSocketService != nil && !alert.Suppressed {
+			cameraName := fmt.Sprintf("camera %d", cameraID)
+			if alert.Camera != nil {
+				cameraName = alert.Camera.Name
+			}
+			s.webSocketService.NotifyAlert(ruleAlertTypeName(rule.Name), cameraName, alert.Message, alert)
+		}
+	}
+
+	// The rule cools down once any alert is raised, so the cameras already alerted are not
+	// alerted again on the next evaluation
+	if len(alerts) > 0 {
+		if err := s.alertRuleRepository.MarkTriggered(ctx, rule.ID, now); err != nil {
+			log.Printf("Failed to record trigger of alert rule %q: %v", rule.Name, err)
+		}
+	}
+
+	return alerts, createErr
+}
+
+// ruleAlertType finds or creates the alert type of a rule
+func (s *AlertRuleServiceImpl) ruleAlertType(ctx context.Context, ruleName string) (uint, error) {
+	name := ruleAlertTypeName(ruleName)
+
+	if alertType, err := s.alertTypeRepository.FindByName(ctx, name); err == nil && alertType != nil {
+		return alertType.ID, nil
+	}
+
+	alertType, err := s.alertTypeRepository.Create(ctx, &entity.AlertType{
+		Name:        name,
+		Description: fmt.Sprintf("Auto-generated alert type for alert rule %s", ruleName),
+		Icon:        "activity",
+		Color:       "#f59e0b",
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create alert type: %w", err)
+	}
+
+	return alertType.ID, nil
+}
+
+// validateRule validates a rule and applies its defaults
+func (s *AlertRuleServiceImpl) validateRule(ctx context.Context, rule *entity.AlertRule) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" {
+		return errors.New("name is required")
+	}
+
+	targets := 0
+	for _, set := range []bool{rule.CameraID != nil, rule.ZoneID != nil, rule.Zone != ""} {
+		if set {
+			targets++
+		}
+	}
+	if targets == 0 {
+		return errors.New("camera ID or zone is required")
+	}
+	if targets > 1 {
+		return errors.New("set either camera ID or zone, not both")
+	}
+
+	if rule.CameraID != nil {
+		if _, err := s.cameraRepository.FindByID(ctx, *rule.CameraID); err != nil {
+			return errors.New("camera not found")
+		}
+	}
+
+	if rule.ZoneID != nil {
+		if _, err := s.zoneRepository.FindByID(ctx, *rule.ZoneID); err != nil {
+			return errors.New("zone not found")
+		}
+	}
+
+	if !entity.IsRuleMetric(rule.Metric) {
+		return errors.New("invalid metric")
+	}
+
+	if !entity.IsRuleCondition(rule.Condition) {
+		return errors.New("invalid condition")
+	}
+
+	if rule.Metric == entity.RuleMetricOccupancy && rule.Condition == entity.RuleConditionSurge {
+		return errors.New("surge is not supported for occupancy")
+	}
+
+	if rule.Threshold < 0 {
+		return errors.New("threshold must not be negative")
+	}
+
+	if rule.Condition == entity.RuleConditionSurge && rule.Threshold <= 1 {
+		return errors.New("surge threshold must be greater than 1")
+	}
+
+	if rule.WindowMinutes < 0 || rule.CooldownMinutes < 0 {
+		return errors.New("window and cooldown must not be negative")
+	}
+
+	if rule.WindowMinutes == 0 {
+		rule.WindowMinutes = 60
+	}
+
+	if rule.Severity == "" {
+		rule.Severity = "medium"
+	}
+
+	if entity.AlertSeverityRank(rule.Severity) < 0 {
+		return errors.New("invalid severity")
+	}
+
+	return rule.ValidateSchedule()
+}
+
+func (s *AlertRuleServiceImpl) location() *time.Location {
+	if s.occupancyReset.Location != nil {
+		return s.occupancyReset.Location
+	}
+	return time.Local
+}
+
+// ruleAlertTypeName is the name of the alert type of the alerts raised by a rule
+func ruleAlertTypeName(ruleName string) string {
+	return "Rule: " + ruleName
+}
+
+// ruleMessage describes why a rule matched
+func ruleMessage(rule *entity.AlertRule, value, threshold, previous float64) string {
+	metric := strings.ReplaceAll(rule.Metric, "_", " ")
+
+	switch rule.Condition {
+	case entity.RuleConditionAtMost:
+		return fmt.Sprintf("%s: %s at %s was %g in the last %d minutes, at most %g",
+			rule.Name, metric, rule.Target(), value, rule.WindowMinutes, threshold)
+	case entity.RuleConditionSurge:
+		return fmt.Sprintf("%s: %s at %s surged to %g in the last %d minutes, from %g in the previous %d minutes",
+			rule.Name, metric, rule.Target(), value, rule.WindowMinutes, previous, rule.WindowMinutes)
+	}
+
+	if rule.Metric == entity.RuleMetricOccupancy {
+		return fmt.Sprintf("%s: occupancy at %s reached %g, above %g", rule.Name, rule.Target(), value, threshold)
+	}
+
+	return fmt.Sprintf("%s: %s at %s reached %g in the last %d minutes, above %g",
+		rule.Name, metric, rule.Target(), value, rule.WindowMinutes, threshold)
+}
@@ -3,7 +3,10 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"people-counting/internal/domain/entity"
@@ -16,23 +19,73 @@ type AlertServiceImpl struct {
 	alertRepository     repository.AlertRepository
 	alertTypeRepository repository.AlertTypeRepository
 	cameraRepository    repository.CameraRepository
+	zoneRepository      repository.ZoneRepository
+	correlationWindow   time.Duration
+	language            string
+	maintenanceService  service.MaintenanceService
+	notificationService service.NotificationService
+	evidenceService     service.EvidenceService
+	cameraConfigService service.CameraConfigService
+
+	// Detections of the same camera, alert type and object are serialized so concurrent
+	// ingestion cannot open twin alerts, detections of other keys go on in parallel
+	correlationMu    sync.Mutex
+	correlationLocks map[correlationKey]*correlationLock
 }
 
-// NewAlertService creates a new alert service
+// correlationKey identifies the detections that may be folded into the same alert
+type correlationKey struct {
+	cameraID    uint
+	alertTypeID uint
+	objectID    string
+}
+
+// correlationLock serializes the detections of a correlation key, refs counts the detections
+// holding or waiting for it
+type correlationLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// NewAlertService creates a new alert service. Detections recorded within correlationWindow of
+// an open alert of the same camera, alert type and object are folded into it. Alerts created
+// without a message get the message template of their type in language. Alerts suppressed by
+// maintenanceService are stored but not notified. New, escalated and resolved alerts are sent
+// to notificationService. New alerts get an evidence clip from evidenceService. Alerts record the
+// configuration version of their camera from cameraConfigService. The services may be nil.
 func NewAlertService(
 	alertRepository repository.AlertRepository,
 	alertTypeRepository repository.AlertTypeRepository,
 	cameraRepository repository.CameraRepository,
+	zoneRepository repository.ZoneRepository,
+	correlationWindow time.Duration,
+	language string,
+	maintenanceService service.MaintenanceService,
+	notificationService service.NotificationService,
+	evidenceService service.EvidenceService,
+	cameraConfigService service.CameraConfigService,
 ) service.AlertService {
+	if !entity.IsAlertLanguage(language) {
+		language = entity.AlertLanguageEnglish
+	}
+
 	return &AlertServiceImpl{
 		alertRepository:     alertRepository,
 		alertTypeRepository: alertTypeRepository,
 		cameraRepository:    cameraRepository,
+		zoneRepository:      zoneRepository,
+		correlationWindow:   correlationWindow,
+		language:            language,
+		maintenanceService:  maintenanceService,
+		notificationService: notificationService,
+		evidenceService:     evidenceService,
+		cameraConfigService: cameraConfigService,
+		correlationLocks:    make(map[correlationKey]*correlationLock),
 	}
 }
 
 // GetAllAlerts retrieves paginated alert records with filters
-func (s *AlertServiceImpl) GetAllAlerts(ctx context.Context, page, limit int, isActive, alertTypeID, cameraID, from, to, search, severity, status string, includeRelations bool) ([]entity.Alert, int64, error) {
+func (s *AlertServiceImpl) GetAllAlerts(ctx context.Context, page, limit int, isActive, alertTypeID, cameraID, zoneID, from, to, search, severity, status, assignedTo, suppressed string, includeRelations bool) ([]entity.Alert, int64, error) {
 	// Use default pagination values if invalid
 	if page <= 0 {
 		page = 1
@@ -53,16 +106,28 @@ func (s *AlertServiceImpl) GetAllAlerts(ctx context.Context, page, limit int, is
 		filters["is_active"] = false
 	}
 
-	// Add status filter if provided (maps to is_active and resolved_at logic)
-	switch status {
-	case "active":
+	// Add status filter if provided, "active" matches every status that still needs attention
+	switch {
+	case status == "":
+	case status == "active":
 		filters["is_active"] = true
-		filters["resolved_at_null"] = true
-	case "acknowledged":
-		filters["is_active"] = false
-		filters["resolved_at_null"] = true
-	case "resolved":
-		filters["resolved_at_not_null"] = true
+	case entity.IsAlertStatus(status):
+		filters["status"] = status
+	default:
+		return nil, 0, errors.New("invalid alert status")
+	}
+
+	// Add assigned_to filter if provided
+	if assignedTo != "" {
+		filters["assigned_to"] = assignedTo
+	}
+
+	// Add suppressed filter if provided, suppressed alerts fell in a maintenance window
+	switch suppressed {
+	case "true":
+		filters["suppressed"] = true
+	case "false":
+		filters["suppressed"] = false
 	}
 
 	// Add severity filter if provided
@@ -107,6 +172,13 @@ func (s *AlertServiceImpl) GetAllAlerts(ctx context.Context, page, limit int, is
 		filters["camera_id"] = uint(id)
 	}
 
+	// Add zone filter if provided, alerts of the cameras of the zone and of the zones inside it
+	if zoneID != "" {
+		if _, _, err := zoneCameraFilter(ctx, s.zoneRepository, zoneID, filters); err != nil {
+			return nil, 0, err
+		}
+	}
+
 	// Add time range filters if provided
 	if from != "" {
 		fromTime, err := parseFlexibleDate(from)
@@ -133,7 +205,7 @@ func (s *AlertServiceImpl) GetAllAlerts(ctx context.Context, page, limit int, is
 }
 
 // GetActiveAlerts retrieves currently active alerts with pagination
-func (s *AlertServiceImpl) GetActiveAlerts(ctx context.Context, page, limit int, alertTypeID, cameraID, from, to string, includeRelations bool) ([]entity.Alert, int64, int64, error) {
+func (s *AlertServiceImpl) GetActiveAlerts(ctx context.Context, page, limit int, alertTypeID, cameraID, zoneID, from, to string, includeRelations bool) ([]entity.Alert, int64, int64, error) {
 	// Use default pagination values if invalid
 	if page <= 0 {
 		page = 1
@@ -178,6 +250,13 @@ func (s *AlertServiceImpl) GetActiveAlerts(ctx context.Context, page, limit int,
 		filters["camera_id"] = uint(id)
 	}
 
+	// Add zone filter if provided, alerts of the cameras of the zone and of the zones inside it
+	if zoneID != "" {
+		if _, _, err := zoneCameraFilter(ctx, s.zoneRepository, zoneID, filters); err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
 	// Add time range filters if provided
 	if from != "" {
 		fromTime, err := time.Parse(time.RFC3339, from)
@@ -205,31 +284,288 @@ func (s *AlertServiceImpl) GetActiveAlerts(ctx context.Context, page, limit int,
 
 // CreateAlert creates a new alert
 func (s *AlertServiceImpl) CreateAlert(ctx context.Context, alert *entity.Alert) error {
+	if err := s.validateNewAlert(ctx, alert); err != nil {
+		return err
+	}
+
+	// Create alert
+	if err := s.alertRepository.Create(ctx, alert); err != nil {
+		return err
+	}
+
+	s.captureEvidence(alert)
+	s.notify(ctx, alert, entity.NotificationEventCreated)
+
+	return nil
+}
+
+// RecordDetection records a detection, folding it into an open alert of the same camera, alert
+// type and object seen within the correlation window, or creating a new alert otherwise.
+// Detections already recorded under the same ID are not counted twice.
+func (s *AlertServiceImpl) RecordDetection(ctx context.Context, alert *entity.Alert) (*entity.AlertCorrelation, error) {
+	if err := s.validateNewAlert(ctx, alert); err != nil {
+		return nil, err
+	}
+
+	if alert.DetectedAt.IsZero() {
+		alert.DetectedAt = time.Now()
+	}
+
+	unlock := s.lockCorrelation(correlationKey{alert.CameraID, alert.AlertTypeID, alert.ObjectID})
+	defer unlock()
+
+	if alert.ID != "" {
+		if occurrence, err := s.alertRepository.FindOccurrenceBySource(ctx, alert.ID); err == nil {
+			existing, err := s.alertRepository.FindByID(ctx, occurrence.AlertID)
+			if err != nil {
+				return nil, err
+			}
+			return &entity.AlertCorrelation{Alert: existing, Duplicate: true}, nil
+		}
+
+		// Alerts stored before correlation have no occurrences, they are updated in place
+		if _, err := s.alertRepository.FindByID(ctx, alert.ID); err == nil {
+			if err := s.alertRepository.Update(ctx, alert); err != nil {
+				return nil, err
+			}
+			return &entity.AlertCorrelation{Alert: alert, Duplicate: true}, nil
+		}
+	}
+
+	if s.correlationWindow > 0 {
+		open, err := s.alertRepository.FindCorrelated(ctx, alert.CameraID, alert.AlertTypeID, alert.ObjectID,
+			alert.DetectedAt.Add(-s.correlationWindow), alert.DetectedAt.Add(s.correlationWindow))
+		if err != nil {
+			return nil, err
+		}
+
+		// Detections after a maintenance window open a new alert rather than joining a
+		// suppressed one, so that they are notified
+		if open != nil && (alert.Suppressed || !open.Suppressed) {
+			return s.foldDetection(ctx, open, alert)
+		}
+	}
+
+	if err := s.alertRepository.Create(ctx, alert); err != nil {
+		return nil, err
+	}
+
+	s.captureEvidence(alert)
+	s.notify(ctx, alert, entity.NotificationEventCreated)
+
+	return &entity.AlertCorrelation{Alert: alert, Created: true, Suppressed: alert.Suppressed}, nil
+}
+
+// lockCorrelation locks the detections of a correlation key and returns the function that
+// unlocks them. Locks are dropped once no detection holds them.
+func (s *AlertServiceImpl) lockCorrelation(key correlationKey) func() {
+	s.correlationMu.Lock()
+	lock, ok := s.correlationLocks[key]
+	if !ok {
+		lock = &correlationLock{}
+		s.correlationLocks[key] = lock
+	}
+	lock.refs++
+	s.correlationMu.Unlock()
+
+	lock.mu.Lock()
+
+	return func() {
+		lock.mu.Unlock()
+
+		s.correlationMu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(s.correlationLocks, key)
+		}
+		s.correlationMu.Unlock()
+	}
+}
+
+// foldDetection adds a detection to an open alert, escalating the alert when the detection
+// is more severe
+func (s *AlertServiceImpl) foldDetection(ctx context.Context, open, detection *entity.Alert) (*entity.AlertCorrelation, error) {
+	occurrence := &entity.AlertOccurrence{
+		SourceID:   detection.ID,
+		ImageURL:   detection.ImageURL,
+		Severity:   detection.Severity,
+		DetectedAt: detection.DetectedAt,
+
+		ConfigVersion: detection.ConfigVersion,
+	}
+
+	var escalation *entity.AlertEvent
+	if entity.AlertSeverityRank(detection.Severity) > entity.AlertSeverityRank(open.Severity) {
+		status := currentAlertStatus(open)
+		escalation = &entity.AlertEvent{
+			Action:       entity.AlertEventEscalated,
+			FromStatus:   status,
+			ToStatus:     status,
+			FromSeverity: open.Severity,
+			ToSeverity:   detection.Severity,
+			Actor:        "system",
+			Note:         fmt.Sprintf("Detection %s raised the severity", detection.ID),
+		}
+	}
+
+	if err := s.alertRepository.AddOccurrence(ctx, open.ID, occurrence, escalation); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.alertRepository.FindByID(ctx, open.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if escalation != nil && !detection.Suppressed {
+		s.notify(ctx, updated, entity.NotificationEventEscalated)
+	}
+
+	return &entity.AlertCorrelation{Alert: updated, Escalated: escalation != nil, Suppressed: detection.Suppressed}, nil
+}
+
+// GetAlertOccurrences retrieves the detections folded into an alert with their evidence images
+func (s *AlertServiceImpl) GetAlertOccurrences(ctx context.Context, id string) ([]entity.AlertOccurrence, error) {
+	if id == "" {
+		return nil, errors.New("alert ID is required")
+	}
+
+	if _, err := s.alertRepository.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.alertRepository.FindOccurrences(ctx, id)
+}
+
+// validateNewAlert validates an alert before it is created, filling in the severity and the
+// message template of its alert type when they are not given
+func (s *AlertServiceImpl) validateNewAlert(ctx context.Context, alert *entity.Alert) error {
 	// Validate alert
 	if alert.AlertTypeID == 0 {
 		return errors.New("alert type ID is required")
 	}
 
-	if alert.Message == "" {
-		return errors.New("message is required")
-	}
-
 	// Verify alert type exists
-	_, err := s.alertTypeRepository.FindByID(ctx, alert.AlertTypeID)
+	alertType, err := s.alertTypeRepository.FindByID(ctx, alert.AlertTypeID)
 	if err != nil {
 		return errors.New("alert type not found")
 	}
 
 	// Verify camera exists if provided
+	var camera *entity.Camera
+	cameraName := ""
 	if alert.CameraID != 0 {
-		_, err := s.cameraRepository.FindByID(ctx, alert.CameraID)
+		camera, err = s.cameraRepository.FindByID(ctx, alert.CameraID)
 		if err != nil {
 			return errors.New("camera not found")
 		}
+		cameraName = camera.Name
 	}
 
-	// Create alert
-	return s.alertRepository.Create(ctx, alert)
+	// Record the configuration version the detection was made with
+	alert.ConfigVersion, err = resolveConfigVersion(ctx, s.cameraConfigService, alert.CameraID, alert.ConfigVersion)
+	if err != nil {
+		return err
+	}
+
+	// Alerts of cameras in maintenance are stored flagged rather than dropped
+	alert.Suppressed = false
+	alert.SuppressedBy = ""
+	if s.maintenanceService != nil {
+		reason, err := s.maintenanceService.CheckSuppression(ctx, alert, camera)
+		if err != nil {
+			return err
+		}
+		alert.Suppressed = reason != ""
+		alert.SuppressedBy = reason
+	}
+
+	if alert.Severity == "" {
+		alert.Severity = alertType.Severity
+		if alert.Severity == "" {
+			alert.Severity = "high"
+		}
+	}
+
+	if alert.Message == "" {
+		messages, err := s.alertTypeRepository.FindMessages(ctx, alertType.ID)
+		if err != nil {
+			return err
+		}
+
+		template := alertMessageTemplate(alertType, messages, s.language)
+		if message, err := renderAlertMessage(template, newAlertMessageData(alert, alertType, cameraName)); err == nil {
+			alert.Message = message
+			alert.Language = s.language
+		}
+	}
+
+	if alert.Message == "" {
+		return errors.New("message is required")
+	}
+
+	return nil
+}
+
+// LocalizeAlerts renders the messages of alerts in a language. Messages given on creation
+// rather than rendered from a template are left as they are.
+func (s *AlertServiceImpl) LocalizeAlerts(ctx context.Context, alerts []entity.Alert, language string) error {
+	if !entity.IsAlertLanguage(language) {
+		return nil
+	}
+
+	var messages []entity.AlertTypeMessage
+	loaded := false
+	alertTypes := make(map[uint]*entity.AlertType)
+	cameraNames := make(map[uint]string)
+
+	for i := range alerts {
+		alert := &alerts[i]
+		if alert.Language == "" || alert.Language == language {
+			continue
+		}
+
+		if !loaded {
+			var err error
+			if messages, err = s.alertTypeRepository.FindMessagesByLanguage(ctx, language); err != nil {
+				return err
+			}
+			loaded = true
+		}
+
+		alertType := alertTypes[alert.AlertTypeID]
+		if alertType == nil {
+			if alert.AlertType.ID == alert.AlertTypeID {
+				alertType = &alert.AlertType
+			} else if found, err := s.alertTypeRepository.FindByID(ctx, alert.AlertTypeID); err == nil {
+				alertType = found
+			} else {
+				continue
+			}
+			alertTypes[alert.AlertTypeID] = alertType
+		}
+
+		cameraName, ok := cameraNames[alert.CameraID]
+		if !ok {
+			if alert.Camera != nil {
+				cameraName = alert.Camera.Name
+			} else if camera, err := s.cameraRepository.FindByID(ctx, alert.CameraID); err == nil {
+				cameraName = camera.Name
+			}
+			cameraNames[alert.CameraID] = cameraName
+		}
+
+		template := alertMessageTemplate(alertType, messages, language)
+		message, err := renderAlertMessage(template, newAlertMessageData(alert, alertType, cameraName))
+		if err != nil {
+			continue
+		}
+
+		alert.Message = message
+		alert.Language = language
+	}
+
+	return nil
 }
 
 // UpdateAlert updates an existing alert
@@ -265,16 +601,226 @@ func (s *AlertServiceImpl) UpdateAlert(ctx context.Context, alert *entity.Alert)
 }
 
 // ResolveAlert resolves (deactivates) an alert
-func (s *AlertServiceImpl) ResolveAlert(ctx context.Context, id, resolvedBy, resolutionNote string) error {
+func (s *AlertServiceImpl) ResolveAlert(ctx context.Context, id, resolvedBy, resolutionNote string) (*entity.AlertEvent, error) {
+	if resolvedBy == "" {
+		return nil, errors.New("resolved by is required")
+	}
+
+	return s.TransitionAlert(ctx, id, entity.AlertStatusResolved, resolvedBy, resolutionNote)
+}
+
+// TransitionAlert moves an alert to a new status and records the change in its history
+func (s *AlertServiceImpl) TransitionAlert(ctx context.Context, id, status, actor, note string) (*entity.AlertEvent, error) {
 	if id == "" {
-		return errors.New("alert ID is required")
+		return nil, errors.New("alert ID is required")
 	}
 
-	if resolvedBy == "" {
-		return errors.New("resolved by is required")
+	if actor == "" {
+		return nil, errors.New("actor is required")
+	}
+
+	if !entity.IsAlertStatus(status) {
+		return nil, errors.New("invalid alert status")
+	}
+
+	alert, err := s.alertRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	current := currentAlertStatus(alert)
+	if current == status {
+		return nil, fmt.Errorf("alert is already %s", strings.ReplaceAll(status, "_", " "))
+	}
+
+	if !entity.CanTransitionAlert(current, status) {
+		return nil, fmt.Errorf("cannot change alert status from %s to %s", current, status)
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":    status,
+		"is_active": entity.IsOpenAlertStatus(status),
+	}
+
+	switch status {
+	case entity.AlertStatusAcknowledged:
+		updates["acknowledged_at"] = now
+		updates["acknowledged_by"] = actor
+	case entity.AlertStatusInProgress:
+		// Starting work on an alert acknowledges it and, when unassigned, takes it
+		if alert.AcknowledgedAt == nil {
+			updates["acknowledged_at"] = now
+			updates["acknowledged_by"] = actor
+		}
+		if alert.AssignedTo == "" {
+			updates["assigned_to"] = actor
+		}
+	case entity.AlertStatusResolved, entity.AlertStatusFalsePositive:
+		updates["resolved_at"] = now
+		updates["resolved_by"] = actor
+		updates["resolution_note"] = note
+	case entity.AlertStatusReopened:
+		updates["resolved_at"] = nil
+		updates["resolved_by"] = ""
+		updates["resolution_note"] = ""
+	}
+
+	event := &entity.AlertEvent{
+		Action:     entity.AlertEventStatusChanged,
+		FromStatus: current,
+		ToStatus:   status,
+		Actor:      actor,
+		Note:       note,
+	}
+
+	return s.applyEvent(ctx, alert, updates, event)
+}
+
+// AssignAlert assigns an open alert to an operator, an empty assignee unassigns it
+func (s *AlertServiceImpl) AssignAlert(ctx context.Context, id, assignee, actor, note string) (*entity.AlertEvent, error) {
+	if id == "" {
+		return nil, errors.New("alert ID is required")
+	}
+
+	if actor == "" {
+		return nil, errors.New("actor is required")
+	}
+
+	alert, err := s.alertRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	current := currentAlertStatus(alert)
+	if !entity.IsOpenAlertStatus(current) {
+		return nil, errors.New("cannot assign a closed alert")
+	}
+
+	if alert.AssignedTo == assignee {
+		if assignee == "" {
+			return nil, errors.New("alert is not assigned")
+		}
+		return nil, fmt.Errorf("alert is already assigned to %s", assignee)
+	}
+
+	event := &entity.AlertEvent{
+		Action:     entity.AlertEventAssigned,
+		FromStatus: current,
+		ToStatus:   current,
+		AssignedTo: assignee,
+		Actor:      actor,
+		Note:       note,
+	}
+
+	return s.applyEvent(ctx, alert, map[string]interface{}{"assigned_to": assignee}, event)
+}
+
+// EscalateAlert raises the severity of an open alert, to the next severity when none is given
+func (s *AlertServiceImpl) EscalateAlert(ctx context.Context, id, severity, actor, note string) (*entity.AlertEvent, error) {
+	if id == "" {
+		return nil, errors.New("alert ID is required")
+	}
+
+	if actor == "" {
+		return nil, errors.New("actor is required")
+	}
+
+	alert, err := s.alertRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	current := currentAlertStatus(alert)
+	if !entity.IsOpenAlertStatus(current) {
+		return nil, errors.New("cannot escalate a closed alert")
+	}
+
+	rank := entity.AlertSeverityRank(alert.Severity)
+	if severity == "" {
+		if rank >= len(entity.AlertSeverities)-1 {
+			return nil, errors.New("alert is already at the highest severity")
+		}
+		severity = entity.AlertSeverities[rank+1]
+	} else {
+		newRank := entity.AlertSeverityRank(severity)
+		if newRank < 0 {
+			return nil, errors.New("invalid severity")
+		}
+		if newRank <= rank {
+			return nil, errors.New("severity must be higher than the current severity")
+		}
+	}
+
+	event := &entity.AlertEvent{
+		Action:       entity.AlertEventEscalated,
+		FromStatus:   current,
+		ToStatus:     current,
+		FromSeverity: alert.Severity,
+		ToSeverity:   severity,
+		Actor:        actor,
+		Note:         note,
+	}
+
+	return s.applyEvent(ctx, alert, map[string]interface{}{"severity": severity}, event)
+}
+
+// GetAlertEvents retrieves the lifecycle history of an alert
+func (s *AlertServiceImpl) GetAlertEvents(ctx context.Context, id string) ([]entity.AlertEvent, error) {
+	if id == "" {
+		return nil, errors.New("alert ID is required")
 	}
 
-	return s.alertRepository.Resolve(ctx, id, resolvedBy, resolutionNote)
+	if _, err := s.alertRepository.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.alertRepository.FindEvents(ctx, id)
+}
+
+// applyEvent stores a change and returns its event with the updated alert
+func (s *AlertServiceImpl) applyEvent(ctx context.Context, alert *entity.Alert, updates map[string]interface{}, event *entity.AlertEvent) (*entity.AlertEvent, error) {
+	if err := s.alertRepository.ApplyEvent(ctx, alert.ID, alert.Status, updates, event); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.alertRepository.FindByID(ctx, alert.ID)
+	if err != nil {
+		return nil, err
+	}
+	event.Alert = updated
+
+	switch {
+	case event.Action == entity.AlertEventEscalated:
+		s.notify(ctx, updated, entity.NotificationEventEscalated)
+	case event.Action == entity.AlertEventStatusChanged && event.ToStatus == entity.AlertStatusResolved:
+		s.notify(ctx, updated, entity.NotificationEventResolved)
+	}
+
+	return event, nil
+}
+
+// notify sends an alert event to the notification channels routed to it, unless the alert is
+// suppressed
+func (s *AlertServiceImpl) notify(ctx context.Context, alert *entity.Alert, event string) {
+	if s.notificationService != nil && !alert.Suppressed {
+		s.notificationService.NotifyAlert(ctx, alert, event)
+	}
+}
+
+// captureEvidence queues the evidence clip of a new alert, suppressed ones included
+func (s *AlertServiceImpl) captureEvidence(alert *entity.Alert) {
+	if s.evidenceService != nil {
+		s.evidenceService.CaptureClip(alert)
+	}
+}
+
+// currentAlertStatus returns the status of an alert, alerts stored before statuses existed are new
+func currentAlertStatus(alert *entity.Alert) string {
+	if alert.Status == "" {
+		return entity.AlertStatusNew
+	}
+	return alert.Status
 }
 
 func (s *AlertServiceImpl) GetAlertByID(ctx context.Context, id string) (*entity.Alert, error) {
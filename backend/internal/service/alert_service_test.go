@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+	"people-counting/internal/domain/service"
+)
+
+// fakeAlertRepository keeps alerts in memory, applying events like the database does. Methods
+// the tests do not use are left to the embedded nil repository.
+type fakeAlertRepository struct {
+	repository.AlertRepository
+
+	alerts map[string]*entity.Alert
+	events []entity.AlertEvent
+}
+
+func newFakeAlertRepository(alerts ...entity.Alert) *fakeAlertRepository {
+	repo := &fakeAlertRepository{alerts: make(map[string]*entity.Alert)}
+	for i := range alerts {
+		repo.alerts[alerts[i].ID] = &alerts[i]
+	}
+	return repo
+}
+
+func (r *fakeAlertRepository) FindByID(ctx context.Context, id string) (*entity.Alert, error) {
+	alert, ok := r.alerts[id]
+	if !ok {
+		return nil, errors.New("alert not found")
+	}
+	copied := *alert
+	return &copied, nil
+}
+
+func (r *fakeAlertRepository) ApplyEvent(ctx context.Context, id, expectedStatus string, updates map[string]interface{}, event *entity.AlertEvent) error {
+	alert, ok := r.alerts[id]
+	if !ok {
+		return errors.New("alert not found")
+	}
+	if alert.Status != expectedStatus {
+		return errors.New("alert was changed by another request")
+	}
+
+	for column, value := range updates {
+		switch column {
+		case "status":
+			alert.Status = value.(string)
+		case "is_active":
+			alert.IsActive = value.(bool)
+		case "assigned_to":
+			alert.AssignedTo = value.(string)
+		case "acknowledged_at":
+			at := value.(time.Time)
+			alert.AcknowledgedAt = &at
+		case "acknowledged_by":
+			alert.AcknowledgedBy = value.(string)
+		case "resolved_at":
+			if at, ok := value.(time.Time); ok {
+				alert.ResolvedAt = &at
+			} else {
+				alert.ResolvedAt = nil
+			}
+		case "resolved_by":
+			alert.ResolvedBy = value.(string)
+		case "resolution_note":
+			alert.ResolutionNote = value.(string)
+		}
+	}
+
+	event.AlertID = id
+	r.events = append(r.events, *event)
+	return nil
+}
+
+// recordingNotifier records the alert events sent to notification channels
+type recordingNotifier struct {
+	service.NotificationService
+
+	events []string
+}
+
+func (n *recordingNotifier) NotifyAlert(ctx context.Context, alert *entity.Alert, event string) {
+	n.events = append(n.events, event)
+}
+
+func newTestAlertService(repo *fakeAlertRepository, notifier *recordingNotifier) *AlertServiceImpl {
+	return &AlertServiceImpl{
+		alertRepository:     repo,
+		notificationService: notifier,
+		correlationLocks:    make(map[correlationKey]*correlationLock),
+	}
+}
+
+func TestTransitionAlertLifecycle(t *testing.T) {
+	repo := newFakeAlertRepository(entity.Alert{ID: "a1", Status: entity.AlertStatusNew, IsActive: true})
+	notifier := &recordingNotifier{}
+	svc := newTestAlertService(repo, notifier)
+	ctx := context.Background()
+
+	event, err := svc.TransitionAlert(ctx, "a1", entity.AlertStatusAcknowledged, "alice", "")
+	if err != nil {
+		t.Fatalf("acknowledge: %v", err)
+	}
+	if event.FromStatus != entity.AlertStatusNew || event.ToStatus != entity.AlertStatusAcknowledged || event.Actor != "alice" {
+		t.Errorf("unexpected event %+v", event)
+	}
+	if alert := repo.alerts["a1"]; alert.AcknowledgedAt == nil || alert.AcknowledgedBy != "alice" || !alert.IsActive {
+		t.Errorf("acknowledged alert %+v", alert)
+	}
+
+	// Starting work takes an unassigned alert but keeps the first acknowledgement
+	if _, err := svc.TransitionAlert(ctx, "a1", entity.AlertStatusInProgress, "bob", ""); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if alert := repo.alerts["a1"]; alert.AssignedTo != "bob" || alert.AcknowledgedBy != "alice" {
+		t.Errorf("in progress alert assigned to %q, acknowledged by %q", alert.AssignedTo, alert.AcknowledgedBy)
+	}
+
+	if _, err := svc.TransitionAlert(ctx, "a1", entity.AlertStatusResolved, "bob", "fixed the door"); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	alert := repo.alerts["a1"]
+	if alert.IsActive || alert.ResolvedAt == nil || alert.ResolvedBy != "bob" || alert.ResolutionNote != "fixed the door" {
+		t.Errorf("resolved alert %+v", alert)
+	}
+
+	if _, err := svc.TransitionAlert(ctx, "a1", entity.AlertStatusReopened, "carol", "happened again"); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	alert = repo.alerts["a1"]
+	if !alert.IsActive || alert.ResolvedAt != nil || alert.ResolvedBy != "" || alert.ResolutionNote != "" {
+		t.Errorf("reopened alert %+v", alert)
+	}
+
+	if len(repo.events) != 4 {
+		t.Errorf("%d events recorded, want 4", len(repo.events))
+	}
+	if strings.Join(notifier.events, ",") != entity.NotificationEventResolved {
+		t.Errorf("notified %v, want only the resolution", notifier.events)
+	}
+}
+
+func TestTransitionAlertRejectsInvalidChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		to      string
+		actor   string
+		wantErr string
+	}{
+		{"unknown status", entity.AlertStatusNew, "closed", "alice", "invalid alert status"},
+		{"no actor", entity.AlertStatusNew, entity.AlertStatusAcknowledged, "", "actor is required"},
+		{"same status", entity.AlertStatusAcknowledged, entity.AlertStatusAcknowledged, "alice", "alert is already acknowledged"},
+		{"back to acknowledged", entity.AlertStatusInProgress, entity.AlertStatusAcknowledged, "alice", "cannot change alert status from in_progress to acknowledged"},
+		{"reopen an open alert", entity.AlertStatusNew, entity.AlertStatusReopened, "alice", "cannot change alert status from new to reopened"},
+		{"acknowledge a resolved alert", entity.AlertStatusResolved, entity.AlertStatusAcknowledged, "alice", "cannot change alert status from resolved to acknowledged"},
+		{"false positive to resolved", entity.AlertStatusFalsePositive, entity.AlertStatusResolved, "alice", "cannot change alert status from false_positive to resolved"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeAlertRepository(entity.Alert{ID: "a1", Status: tt.status})
+			svc := newTestAlertService(repo, &recordingNotifier{})
+
+			_, err := svc.TransitionAlert(context.Background(), "a1", tt.to, tt.actor, "")
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+			if repo.alerts["a1"].Status != tt.status || len(repo.events) != 0 {
+				t.Errorf("a rejected change was applied")
+			}
+		})
+	}
+}
+
+func TestTransitionAlertTreatsLegacyAlertsAsNew(t *testing.T) {
+	repo := newFakeAlertRepository(entity.Alert{ID: "a1", IsActive: true})
+	svc := newTestAlertService(repo, &recordingNotifier{})
+
+	event, err := svc.TransitionAlert(context.Background(), "a1", entity.AlertStatusFalsePositive, "alice", "shadow")
+	if err != nil {
+		t.Fatalf("transition: %v", err)
+	}
+	if event.FromStatus != entity.AlertStatusNew {
+		t.Errorf("from status = %q, want new", event.FromStatus)
+	}
+	if alert := repo.alerts["a1"]; alert.Status != entity.AlertStatusFalsePositive || alert.IsActive {
+		t.Errorf("alert %+v, want a closed false positive", alert)
+	}
+}
+
+func TestTransitionAlertDetectsConcurrentChanges(t *testing.T) {
+	repo := newFakeAlertRepository(entity.Alert{ID: "a1", Status: entity.AlertStatusNew})
+	svc := newTestAlertService(repo, &recordingNotifier{})
+
+	// Another request acknowledges the alert between the read and the update
+	stale := &fakeStaleAlertRepository{fakeAlertRepository: repo}
+	svc.alertRepository = stale
+
+	_, err := svc.TransitionAlert(context.Background(), "a1", entity.AlertStatusResolved, "alice", "")
+	if err == nil || err.Error() != "alert was changed by another request" {
+		t.Fatalf("err = %v, want a conflict", err)
+	}
+	if repo.alerts["a1"].Status != entity.AlertStatusAcknowledged {
+		t.Errorf("status = %q, the concurrent change was overwritten", repo.alerts["a1"].Status)
+	}
+}
+
+// fakeStaleAlertRepository acknowledges the alert right after it is read
+type fakeStaleAlertRepository struct {
+	*fakeAlertRepository
+}
+
+func (r *fakeStaleAlertRepository) FindByID(ctx context.Context, id string) (*entity.Alert, error) {
+	alert, err := r.fakeAlertRepository.FindByID(ctx, id)
+	if err == nil {
+		r.alerts[id].Status = entity.AlertStatusAcknowledged
+	}
+	return alert, err
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+	"people-counting/internal/domain/service"
+)
+
+// defaultAlertStatsDays is the date range of alert statistics when none is given
+const defaultAlertStatsDays = 7
+
+// AlertStatsServiceImpl implements service.AlertStatsService. Counts by type, camera and
+// severity are read from the alerts_daily aggregate. Hours of the day and response times need
+// the alerts themselves.
+type AlertStatsServiceImpl struct {
+	alertRepository     repository.AlertRepository
+	alertTypeRepository repository.AlertTypeRepository
+	cameraRepository    repository.CameraRepository
+	zoneRepository      repository.ZoneRepository
+	location            *time.Location
+}
+
+// NewAlertStatsService creates a new alert statistics service. Days and hours are read in the
+// given time zone, the local time zone when nil.
+func NewAlertStatsService(
+	alertRepository repository.AlertRepository,
+	alertTypeRepository repository.AlertTypeRepository,
+	cameraRepository repository.CameraRepository,
+	zoneRepository repository.ZoneRepository,
+	location *time.Location,
+) service.AlertStatsService {
+	if location == nil {
+		location = time.Local
+	}
+
+	return &AlertStatsServiceImpl{
+		alertRepository:     alertRepository,
+		alertTypeRepository: alertTypeRepository,
+		cameraRepository:    cameraRepository,
+		zoneRepository:      zoneRepository,
+		location:            location,
+	}
+}
+
+// GetAlertStats summarizes the alerts detected between from and to, RFC3339 times that default
+// to the last seven days, of the cameras of a zone and of the zones inside it when zoneID is
+// set. noisyLimit caps the noisy cameras, which are ranked by alerts.
+func (s *AlertStatsServiceImpl) GetAlertStats(ctx context.Context, from, to, cameraID, zoneID, alertTypeID string, noisyLimit int) (*entity.AlertStats, error) {
+	start, end, err := s.statsRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := make(map[string]interface{})
+
+	if cameraID != "" {
+		id, err := strconv.ParseUint(cameraID, 10, 32)
+		if err != nil {
+			return nil, errors.New("invalid camera ID")
+		}
+		filters["camera_id"] = uint(id)
+	}
+
+	zone, zones, err := zoneCameraFilter(ctx, s.zoneRepository, zoneID, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	if alertTypeID != "" {
+		id, err := strconv.ParseUint(alertTypeID, 10, 32)
+		if err != nil {
+			return nil, errors.New("invalid alert type ID")
+		}
+		filters["alert_type_id"] = uint(id)
+	}
+
+	if noisyLimit <= 0 {
+		noisyLimit = 5
+	}
+
+	// Daily buckets start at midnight, the first day is counted whole
+	firstDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, s.location)
+
+	daily, err := s.alertRepository.GetDailyCounts(ctx, firstDay, end, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	hours, err := s.alertRepository.GetHourOfDayCounts(ctx, start, end, s.timezone(), filters)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes, err := s.alertRepository.GetCameraOutcomes(ctx, start, end, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	alertTypes, err := s.alertTypeRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cameras, err := s.cameraRepository.FindAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &entity.AlertStats{
+		From:       start,
+		To:         end,
+		ByType:     []entity.AlertTypeStat{},
+		ByCamera:   []entity.AlertCameraStat{},
+		ByZone:     []entity.AlertZoneStat{},
+		BySeverity: []entity.AlertSeverityStat{},
+		ByHour:     make([]entity.AlertHourStat, 24),
+	}
+
+	byType := make(map[uint]int64)
+	byCamera := make(map[uint]int64)
+	bySeverity := make(map[string]int64)
+	for _, row := range daily {
+		count := row.AlertCount - row.SuppressedCount
+		stats.Total += count
+		stats.Suppressed += row.SuppressedCount
+
+		if count > 0 {
+			byType[row.AlertTypeID] += count
+			byCamera[row.CameraID] += count
+			bySeverity[row.Severity] += count
+		}
+	}
+
+	for _, alertType := range alertTypes {
+		if count, ok := byType[alertType.ID]; ok {
+			stats.ByType = append(stats.ByType, entity.AlertTypeStat{
+				AlertTypeID: alertType.ID,
+				Name:        alertType.Name,
+				DisplayName: alertType.DisplayName,
+				Count:       count,
+			})
+			delete(byType, alertType.ID)
+		}
+	}
+
+	// Alerts of deleted types are still counted
+	for id, count := range byType {
+		stats.ByType = append(stats.ByType, entity.AlertTypeStat{AlertTypeID: id, Name: fmt.Sprintf("Alert type %d", id), Count: count})
+	}
+
+	sort.Slice(stats.ByType, func(i, j int) bool {
+		if stats.ByType[i].Count != stats.ByType[j].Count {
+			return stats.ByType[i].Count > stats.ByType[j].Count
+		}
+		return stats.ByType[i].AlertTypeID < stats.ByType[j].AlertTypeID
+	})
+
+	for _, id := range zones.breakdown(zone) {
+		zoneStat := entity.AlertZoneStat{ZoneID: id, Name: zones.zones[id].Name, Kind: zones.zones[id].Kind}
+		for _, cameraID := range zones.cameraIDs(id) {
+			zoneStat.Count += byCamera[cameraID]
+		}
+		stats.ByZone = append(stats.ByZone, zoneStat)
+	}
+
+	// Most severe first, unknown severities last
+	for i := len(entity.AlertSeverities) - 1; i >= 0; i-- {
+		severity := entity.AlertSeverities[i]
+		if count, ok := bySeverity[severity]; ok {
+			stats.BySeverity = append(stats.BySeverity, entity.AlertSeverityStat{Severity: severity, Count: count})
+			delete(bySeverity, severity)
+		}
+	}
+
+	for severity, count := range bySeverity {
+		stats.BySeverity = append(stats.BySeverity, entity.AlertSeverityStat{Severity: severity, Count: count})
+	}
+
+	for hour := range stats.ByHour {
+		stats.ByHour[hour].Hour = hour
+	}
+
+	for _, count := range hours {
+		if count.Hour >= 0 && count.Hour < 24 {
+			stats.ByHour[count.Hour].Count = count.Count
+		}
+	}
+
+	cameraNames := make(map[uint]string, len(cameras))
+	for _, camera := range cameras {
+		cameraNames[camera.ID] = camera.Name
+	}
+
+	cameraOutcomes := make(map[uint]entity.AlertCameraOutcome, len(outcomes))
+	var acknowledgeSeconds, resolveSeconds float64
+	var closed int64
+	for _, outcome := range outcomes {
+		cameraOutcomes[outcome.CameraID] = outcome
+
+		stats.Acknowledged += outcome.Acknowledged
+		stats.Resolved += outcome.Resolved
+		stats.FalsePositives += outcome.FalsePositives
+		acknowledgeSeconds += outcome.AcknowledgeSeconds
+		resolveSeconds += outcome.ResolveSeconds
+		closed += outcome.Closed
+	}
+
+	if stats.Acknowledged > 0 {
+		mean := round1(acknowledgeSeconds / float64(stats.Acknowledged))
+		stats.MeanTimeToAcknowledge = &mean
+	}
+
+	if stats.Resolved > 0 {
+		mean := round1(resolveSeconds / float64(stats.Resolved))
+		stats.MeanTimeToResolve = &mean
+	}
+
+	stats.FalsePositiveRate = percentage(int(stats.FalsePositives), int(closed))
+
+	for id, count := range byCamera {
+		name, ok := cameraNames[id]
+		if !ok {
+			name = fmt.Sprintf("Camera %d", id)
+		}
+
+		outcome := cameraOutcomes[id]
+		stats.ByCamera = append(stats.ByCamera, entity.AlertCameraStat{
+			CameraID:          id,
+			CameraName:        name,
+			Count:             count,
+			FalsePositives:    outcome.FalsePositives,
+			FalsePositiveRate: percentage(int(outcome.FalsePositives), int(outcome.Closed)),
+		})
+	}
+
+	sort.Slice(stats.ByCamera, func(i, j int) bool {
+		if stats.ByCamera[i].Count != stats.ByCamera[j].Count {
+			return stats.ByCamera[i].Count > stats.ByCamera[j].Count
+		}
+		return stats.ByCamera[i].CameraID < stats.ByCamera[j].CameraID
+	})
+
+	// Noisy cameras raise the most alerts, ties go to the most false positives
+	stats.NoisyCameras = make([]entity.AlertCameraStat, len(stats.ByCamera))
+	copy(stats.NoisyCameras, stats.ByCamera)
+	sort.SliceStable(stats.NoisyCameras, func(i, j int) bool {
+		if stats.NoisyCameras[i].Count != stats.NoisyCameras[j].Count {
+			return stats.NoisyCameras[i].Count > stats.NoisyCameras[j].Count
+		}
+		return stats.NoisyCameras[i].FalsePositives > stats.NoisyCameras[j].FalsePositives
+	})
+
+	if len(stats.NoisyCameras) > noisyLimit {
+		stats.NoisyCameras = stats.NoisyCameras[:noisyLimit]
+	}
+
+	return stats, nil
+}
+
+// statsRange parses the date range of alert statistics. An open end is now and an open start
+// is midnight seven days before the end.
+func (s *AlertStatsServiceImpl) statsRange(from, to string) (time.Time, time.Time, error) {
+	end := time.Now().In(s.location)
+	if to != "" {
+		parsed, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid to date")
+		}
+		end = parsed.In(s.location)
+	}
+
+	start := time.Date(end.Year(), end.Month(), end.Day()-defaultAlertStatsDays+1, 0, 0, 0, 0, s.location)
+	if from != "" {
+		parsed, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid from date")
+		}
+		start = parsed.In(s.location)
+	}
+
+	if start.After(end) {
+		return time.Time{}, time.Time{}, errors.New("invalid date range: 'from' date must be before or equal to 'to' date")
+	}
+
+	return start, end, nil
+}
+
+// timezone returns the name of the time zone for the database, empty for the local time zone
+// which the database session is expected to share
+func (s *AlertStatsServiceImpl) timezone() string {
+	if s.location == time.Local {
+		return ""
+	}
+	return s.location.String()
+}
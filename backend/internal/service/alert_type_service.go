@@ -3,6 +3,10 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
 
 	"people-counting/internal/domain/entity"
 	"people-counting/internal/domain/repository"
@@ -23,35 +27,389 @@ func NewAlertTypeService(
 	}
 }
 
-// GetAllAlertTypes retrieves all alert types
-func (s *AlertTypeServiceImpl) GetAllAlertTypes(ctx context.Context) ([]entity.AlertType, error) {
-	return s.alertTypeRepository.FindAll(ctx)
+// GetAllAlertTypes retrieves all alert types, archived ones only when asked
+func (s *AlertTypeServiceImpl) GetAllAlertTypes(ctx context.Context, includeArchived bool) ([]entity.AlertType, error) {
+	alertTypes, err := s.alertTypeRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if includeArchived {
+		return alertTypes, nil
+	}
+
+	current := make([]entity.AlertType, 0, len(alertTypes))
+	for _, alertType := range alertTypes {
+		if alertType.ArchivedAt == nil {
+			current = append(current, alertType)
+		}
+	}
+
+	return current, nil
+}
+
+// GetAlertTypeByID retrieves an alert type by its ID
+func (s *AlertTypeServiceImpl) GetAlertTypeByID(ctx context.Context, id uint) (*entity.AlertType, error) {
+	return s.alertTypeRepository.FindByID(ctx, id)
 }
 
 // CreateAlertType creates a new alert type
 func (s *AlertTypeServiceImpl) CreateAlertType(ctx context.Context, alertType *entity.AlertType) (*entity.AlertType, error) {
 	// Validate alert type
-	if alertType.Name == "" {
+	if err := validateAlertType(alertType); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNameAvailable(ctx, alertType.Name, 0); err != nil {
+		return nil, err
+	}
+
+	// A policy given on creation is attached now
+	alertType.EscalationPolicyAttachedAt = nil
+	if alertType.EscalationPolicyID != nil {
+		now := time.Now()
+		alertType.EscalationPolicyAttachedAt = &now
+	}
+
+	// Create alert type
+	return s.alertTypeRepository.Create(ctx, alertType)
+}
+
+// UpdateAlertType updates the details of an alert type. An empty severity keeps the current one.
+// The previous name of a renamed type becomes an alias, so that detectors still sending it keep
+// reaching the type.
+func (s *AlertTypeServiceImpl) UpdateAlertType(ctx context.Context, alertType *entity.AlertType) (*entity.AlertType, error) {
+	current, err := s.alertTypeRepository.FindByID(ctx, alertType.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if alertType.Severity == "" {
+		alertType.Severity = current.Severity
+	}
+
+	if err := validateAlertType(alertType); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNameAvailable(ctx, alertType.Name, alertType.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.alertTypeRepository.Update(ctx, alertType); err != nil {
+		return nil, err
+	}
+
+	oldName := entity.NormalizeAlertTypeName(current.Name)
+	newName := entity.NormalizeAlertTypeName(alertType.Name)
+
+	// An alias of the new name is now the name itself
+	if alias, err := s.alertTypeRepository.FindAliasByName(ctx, newName); err == nil {
+		if err := s.alertTypeRepository.DeleteAlias(ctx, alias.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if oldName != newName && oldName != "" {
+		if _, err := s.alertTypeRepository.FindAliasByName(ctx, oldName); err != nil {
+			alias := &entity.AlertTypeAlias{Name: oldName, AlertTypeID: alertType.ID}
+			if err := s.alertTypeRepository.CreateAlias(ctx, alias); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return s.alertTypeRepository.FindByID(ctx, alertType.ID)
+}
+
+// ArchiveAlertType hides an alert type from lists. Its alerts are kept and detections of the
+// type are still recorded.
+func (s *AlertTypeServiceImpl) ArchiveAlertType(ctx context.Context, id uint) (*entity.AlertType, error) {
+	now := time.Now()
+	if err := s.alertTypeRepository.SetArchived(ctx, id, &now); err != nil {
+		return nil, err
+	}
+
+	return s.alertTypeRepository.FindByID(ctx, id)
+}
+
+// RestoreAlertType brings an archived alert type back
+func (s *AlertTypeServiceImpl) RestoreAlertType(ctx context.Context, id uint) (*entity.AlertType, error) {
+	if err := s.alertTypeRepository.SetArchived(ctx, id, nil); err != nil {
+		return nil, err
+	}
+
+	return s.alertTypeRepository.FindByID(ctx, id)
+}
+
+// DeleteAlertType deletes an alert type nothing refers to. Types with alerts, alert rules,
+// notification routes or maintenance windows are archived or merged instead.
+func (s *AlertTypeServiceImpl) DeleteAlertType(ctx context.Context, id uint) error {
+	if _, err := s.alertTypeRepository.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	references, err := s.alertTypeRepository.CountReferences(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if references > 0 {
+		return errors.New("alert type is in use, archive or merge it instead")
+	}
+
+	return s.alertTypeRepository.Delete(ctx, id)
+}
+
+// MergeAlertTypes moves the alerts, rules, routes, maintenance windows and aliases of an alert
+// type to another and deletes it. The merged name becomes an alias of the target.
+func (s *AlertTypeServiceImpl) MergeAlertTypes(ctx context.Context, sourceID, targetID uint) (*entity.AlertTypeMerge, error) {
+	if sourceID == targetID {
+		return nil, errors.New("cannot merge an alert type into itself")
+	}
+
+	source, err := s.alertTypeRepository.FindByID(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.alertTypeRepository.FindByID(ctx, targetID); err != nil {
+		return nil, errors.New("target alert type not found")
+	}
+
+	moved, err := s.alertTypeRepository.Merge(ctx, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The merge stands either way, statistics of older days show the merged type until they
+	// are refreshed
+	if err := s.alertTypeRepository.RefreshDailyCounts(ctx); err != nil {
+		log.Printf("WARNING: Failed to refresh alert statistics after merging alert type %d into %d: %v", sourceID, targetID, err)
+	}
+
+	target, err := s.alertTypeRepository.FindByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.AlertTypeMerge{
+		Source:      source.Name,
+		Target:      target,
+		AlertsMoved: moved,
+	}, nil
+}
+
+// ResolveAlertType finds the alert type of a name sent by a detector, by name and then by alias,
+// ignoring case and surrounding whitespace. Unknown names get a new alert type.
+func (s *AlertTypeServiceImpl) ResolveAlertType(ctx context.Context, typeName string) (*entity.AlertType, error) {
+	name := entity.NormalizeAlertTypeName(typeName)
+	if name == "" {
 		return nil, errors.New("name is required")
 	}
 
+	if alertType, err := s.findByNameOrAlias(ctx, name); err == nil {
+		return alertType, nil
+	}
+
+	alertType, err := s.alertTypeRepository.Create(ctx, &entity.AlertType{
+		Name:        name,
+		Description: fmt.Sprintf("Auto-generated alert type for %s", name),
+		Icon:        "alert-triangle",
+		Color:       "#ff6b35",
+	})
+	if err != nil {
+		// Another detection may have created it in the meantime
+		if existing, findErr := s.findByNameOrAlias(ctx, name); findErr == nil {
+			return existing, nil
+		}
+		return nil, fmt.Errorf("failed to create alert type: %w", err)
+	}
+
+	return alertType, nil
+}
+
+// GetAliases retrieves every alert type alias
+func (s *AlertTypeServiceImpl) GetAliases(ctx context.Context) ([]entity.AlertTypeAlias, error) {
+	return s.alertTypeRepository.FindAliases(ctx)
+}
+
+// CreateAlias maps another name to an alert type
+func (s *AlertTypeServiceImpl) CreateAlias(ctx context.Context, alertTypeID uint, name string) (*entity.AlertTypeAlias, error) {
+	if _, err := s.alertTypeRepository.FindByID(ctx, alertTypeID); err != nil {
+		return nil, err
+	}
+
+	name = entity.NormalizeAlertTypeName(name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+
+	if len(name) > 50 {
+		return nil, errors.New("invalid name, at most 50 characters")
+	}
+
+	if err := s.checkNameAvailable(ctx, name, 0); err != nil {
+		return nil, err
+	}
+
+	alias := &entity.AlertTypeAlias{Name: name, AlertTypeID: alertTypeID}
+	if err := s.alertTypeRepository.CreateAlias(ctx, alias); err != nil {
+		return nil, err
+	}
+
+	return alias, nil
+}
+
+// DeleteAlias deletes an alert type alias
+func (s *AlertTypeServiceImpl) DeleteAlias(ctx context.Context, id uint) error {
+	return s.alertTypeRepository.DeleteAlias(ctx, id)
+}
+
+// findByNameOrAlias finds an alert type by its name, then by an alias
+func (s *AlertTypeServiceImpl) findByNameOrAlias(ctx context.Context, name string) (*entity.AlertType, error) {
+	if alertType, err := s.alertTypeRepository.FindByName(ctx, name); err == nil {
+		return alertType, nil
+	}
+
+	alias, err := s.alertTypeRepository.FindAliasByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.alertTypeRepository.FindByID(ctx, alias.AlertTypeID)
+}
+
+// checkNameAvailable checks that no other alert type is known by a name, neither as its name nor
+// as an alias. id is the alert type being renamed, 0 for new names.
+func (s *AlertTypeServiceImpl) checkNameAvailable(ctx context.Context, name string, id uint) error {
+	if other, err := s.alertTypeRepository.FindByName(ctx, name); err == nil && other.ID != id {
+		return errors.New("an alert type with the same name already exists")
+	}
+
+	if alias, err := s.alertTypeRepository.FindAliasByName(ctx, name); err == nil && alias.AlertTypeID != id {
+		return errors.New("an alert type with the same name already exists")
+	}
+
+	return nil
+}
+
+// validateAlertType validates the details of an alert type
+func validateAlertType(alertType *entity.AlertType) error {
+	alertType.Name = strings.TrimSpace(alertType.Name)
+	if alertType.Name == "" {
+		return errors.New("name is required")
+	}
+
 	if alertType.Icon == "" {
-		return nil, errors.New("icon is required")
+		return errors.New("icon is required")
 	}
 
 	if alertType.Color == "" {
-		return nil, errors.New("color is required")
+		return errors.New("color is required")
 	}
 
-	// Create alert type
-	return s.alertTypeRepository.Create(ctx, alertType)
+	if alertType.Severity != "" && entity.AlertSeverityRank(alertType.Severity) < 0 {
+		return errors.New("invalid severity")
+	}
+
+	return nil
 }
 
+// GetAlertTypeByName retrieves an alert type by its name
 func (s *AlertTypeServiceImpl) GetAlertTypeByName(ctx context.Context, typeName string) (*entity.AlertType, error) {
-	camera, err := s.alertTypeRepository.FindByName(ctx, typeName)
+	alertType, err := s.alertTypeRepository.FindByName(ctx, typeName)
 	if err != nil {
 		return nil, err
 	}
 
-	return camera, nil
+	return alertType, nil
+}
+
+// GetAlertTypeMessages retrieves an alert type with its message template in every language.
+// Languages without a stored template get the built-in one, which has no ID.
+func (s *AlertTypeServiceImpl) GetAlertTypeMessages(ctx context.Context, id uint) (*entity.AlertType, error) {
+	alertType, err := s.alertTypeRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.alertTypeRepository.FindMessages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	alertType.Messages = make([]entity.AlertTypeMessage, 0, len(entity.AlertLanguages))
+	for _, language := range entity.AlertLanguages {
+		message := entity.AlertTypeMessage{
+			AlertTypeID: id,
+			Language:    language,
+			Template:    defaultAlertMessage(alertType, language),
+		}
+
+		for _, m := range stored {
+			if m.Language == language {
+				message = m
+				break
+			}
+		}
+
+		alertType.Messages = append(alertType.Messages, message)
+	}
+
+	return alertType, nil
+}
+
+// UpdateAlertTypeMessages updates the severity of an alert type and replaces its message
+// templates by language. An empty severity keeps the current one and languages left out use the
+// built-in templates.
+func (s *AlertTypeServiceImpl) UpdateAlertTypeMessages(ctx context.Context, id uint, severity string, templates map[string]string) (*entity.AlertType, error) {
+	alertType, err := s.alertTypeRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if severity == "" {
+		severity = alertType.Severity
+	}
+
+	if entity.AlertSeverityRank(severity) < 0 {
+		return nil, errors.New("invalid severity")
+	}
+
+	for language := range templates {
+		if !entity.IsAlertLanguage(language) {
+			return nil, errors.New("invalid language")
+		}
+	}
+
+	// Templates are tried on sample data so that unknown placeholders are rejected here rather
+	// than when alerts arrive
+	sample := &entity.AlertMessageData{
+		AlertType:   alertType.Name,
+		Camera:      "Camera 1",
+		MissingItem: "helmet",
+		ObjectName:  "person",
+		Time:        time.Now().Format(alertMessageTimeFormat),
+	}
+
+	var messages []entity.AlertTypeMessage
+	for _, language := range entity.AlertLanguages {
+		template, ok := templates[language]
+		if !ok || template == "" {
+			continue
+		}
+
+		if _, err := renderAlertMessage(template, sample); err != nil {
+			return nil, fmt.Errorf("invalid %s message template: %w", language, err)
+		}
+
+		messages = append(messages, entity.AlertTypeMessage{Language: language, Template: template})
+	}
+
+	if err := s.alertTypeRepository.UpdateMessages(ctx, id, severity, messages); err != nil {
+		return nil, err
+	}
+
+	return s.GetAlertTypeMessages(ctx, id)
 }
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+	"people-counting/internal/domain/service"
+)
+
+// AnalyticsServiceImpl implements service.AnalyticsService on top of the people count
+// hourly and daily aggregates
+type AnalyticsServiceImpl struct {
+	peopleCountRepository repository.PeopleCountRepository
+	cameraRepository      repository.CameraRepository
+	occupancyReset        entity.OccupancyReset
+}
+
+// NewAnalyticsService creates a new analytics service. Days start at midnight in the time zone
+// of occupancyReset, and occupancy is counted since its last reset.
+func NewAnalyticsService(
+	peopleCountRepository repository.PeopleCountRepository,
+	cameraRepository repository.CameraRepository,
+	occupancyReset entity.OccupancyReset,
+) service.AnalyticsService {
+	return &AnalyticsServiceImpl{
+		peopleCountRepository: peopleCountRepository,
+		cameraRepository:      cameraRepository,
+		occupancyReset:        occupancyReset,
+	}
+}
+
+// GetOccupancyRate returns the live occupancy relative to the capacity of each camera
+func (s *AnalyticsServiceImpl) GetOccupancyRate(ctx context.Context, cameraID uint) (*entity.OccupancyRate, error) {
+	var cameras []entity.Camera
+	filters := make(map[string]interface{})
+
+	if cameraID > 0 {
+		camera, err := s.cameraRepository.FindByID(ctx, cameraID)
+		if err != nil {
+			return nil, err
+		}
+		cameras = []entity.Camera{*camera}
+		filters["camera_id"] = cameraID
+	} else {
+		var err error
+		cameras, err = s.cameraRepository.FindAll(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	since := s.occupancyReset.LastReset(time.Now())
+	flows, err := s.peopleCountRepository.GetFlowSince(ctx, since, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	occupancyByCamera := make(map[uint]int, len(flows))
+	for _, flow := range flows {
+		occupancyByCamera[flow.CameraID] = max(flow.InCount-flow.OutCount, 0)
+	}
+
+	rate := &entity.OccupancyRate{
+		Since:   since,
+		Cameras: []entity.CameraOccupancyRate{},
+	}
+
+	for _, camera := range cameras {
+		occupancy, counted := occupancyByCamera[camera.ID]
+		if !counted && camera.Capacity == 0 {
+			// Not a counting camera
+			continue
+		}
+
+		cameraRate := entity.CameraOccupancyRate{
+			CameraID:   camera.ID,
+			CameraName: camera.Name,
+			Occupancy:  occupancy,
+			Capacity:   camera.Capacity,
+			Rate:       percentage(occupancy, camera.Capacity),
+		}
+		rate.Cameras = append(rate.Cameras, cameraRate)
+
+		if camera.Capacity > 0 {
+			rate.Occupancy += occupancy
+			rate.Capacity += camera.Capacity
+		}
+	}
+
+	rate.Rate = percentage(rate.Occupancy, rate.Capacity)
+
+	return rate, nil
+}
+
+// GetPeakHours returns the 24 hourly points of a calendar day, today when date is zero.
+// Hours without data are zero.
+func (s *AnalyticsServiceImpl) GetPeakHours(ctx context.Context, cameraID uint, date time.Time) ([]entity.TrendPoint, error) {
+	if date.IsZero() {
+		date = time.Now().In(s.location())
+	}
+	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, s.location())
+	to := from.AddDate(0, 0, 1)
+
+	points, err := s.peopleCountRepository.GetAggregates(ctx, "hour", from, to, cameraFilter(cameraID))
+	if err != nil {
+		return nil, err
+	}
+
+	byHour := make(map[int]entity.TrendPoint, len(points))
+	for _, point := range points {
+		byHour[point.TimePeriod.In(s.location()).Hour()] = point
+	}
+
+	hours := make([]entity.TrendPoint, 0, 24)
+	for hour := from; hour.Before(to); hour = hour.Add(time.Hour) {
+		point, ok := byHour[hour.Hour()]
+		if !ok {
+			point = entity.TrendPoint{}
+		}
+		point.TimePeriod = hour
+		hours = append(hours, point)
+	}
+
+	return hours, nil
+}
+
+// GetHistoricalComparison compares today with the same weekday last week (period "day"), or
+// this month with last month (period "month"), both up to the same elapsed time
+func (s *AnalyticsServiceImpl) GetHistoricalComparison(ctx context.Context, cameraID uint, period string) (*entity.HistoricalComparison, error) {
+	now := time.Now().In(s.location())
+
+	var interval string
+	var currentFrom, previousFrom, previousTo time.Time
+
+	switch period {
+	case "day", "":
+		period = "day"
+		interval = "hour"
+		currentFrom = s.startOfDay(now)
+		previousFrom = currentFrom.AddDate(0, 0, -7)
+		previousTo = now.AddDate(0, 0, -7)
+	case "month":
+		interval = "day"
+		currentFrom = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, s.location())
+		previousFrom = currentFrom.AddDate(0, -1, 0)
+		// Months differ in length, never compare past the end of last month
+		previousTo = previousFrom.Add(now.Sub(currentFrom))
+		if previousTo.After(currentFrom) {
+			previousTo = currentFrom
+		}
+	default:
+		return nil, errors.New("invalid period. Must be day or month")
+	}
+
+	filters := cameraFilter(cameraID)
+
+	currentSeries, err := s.peopleCountRepository.GetAggregates(ctx, interval, currentFrom, now, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	previousSeries, err := s.peopleCountRepository.GetAggregates(ctx, interval, previousFrom, previousTo, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	current := sumPoints(currentSeries)
+	previous := sumPoints(previousSeries)
+
+	return &entity.HistoricalComparison{
+		Period: period,
+		Current: entity.ComparisonWindow{
+			From:   currentFrom,
+			To:     now,
+			Series: nonNilPoints(currentSeries),
+		},
+		Previous: entity.ComparisonWindow{
+			From:   previousFrom,
+			To:     previousTo,
+			Series: nonNilPoints(previousSeries),
+		},
+		Metrics: entity.ComparisonMetrics{
+			Visitors: compareMetric(current.TotalCount, previous.TotalCount),
+			In:       compareMetric(current.InCount, previous.InCount),
+			Out:      compareMetric(current.OutCount, previous.OutCount),
+			Male:     compareMetric(current.MaleCount, previous.MaleCount),
+			Female:   compareMetric(current.FemaleCount, previous.FemaleCount),
+			Child:    compareMetric(current.ChildCount, previous.ChildCount),
+			Adult:    compareMetric(current.AdultCount, previous.AdultCount),
+			Elderly:  compareMetric(current.ElderlyCount, previous.ElderlyCount),
+		},
+	}, nil
+}
+
+// GetDemographicInsights summarizes gender and age groups over the last 24h, 7d, 30d, or 90d
+func (s *AnalyticsServiceImpl) GetDemographicInsights(ctx context.Context, cameraID uint, timeWindow string) (*entity.DemographicInsights, error) {
+	now := time.Now().In(s.location())
+
+	var from time.Time
+	switch timeWindow {
+	case "24h", "":
+		timeWindow = "24h"
+		from = now.Add(-24 * time.Hour)
+	case "7d":
+		from = s.startOfDay(now).AddDate(0, 0, -6)
+	case "30d":
+		from = s.startOfDay(now).AddDate(0, 0, -29)
+	case "90d":
+		from = s.startOfDay(now).AddDate(0, 0, -89)
+	default:
+		return nil, errors.New("invalid window. Must be 24h, 7d, 30d, or 90d")
+	}
+
+	// The last 24 hours do not align with daily buckets
+	interval := "day"
+	if timeWindow == "24h" {
+		interval = "hour"
+	}
+
+	points, err := s.peopleCountRepository.GetAggregates(ctx, interval, from, now, cameraFilter(cameraID))
+	if err != nil {
+		return nil, err
+	}
+
+	total := sumPoints(points)
+	insights := &entity.DemographicInsights{
+		Window: timeWindow,
+		From:   from,
+		To:     now,
+		Totals: entity.TotalCounts{
+			Male:    total.MaleCount,
+			Female:  total.FemaleCount,
+			Child:   total.ChildCount,
+			Adult:   total.AdultCount,
+			Elderly: total.ElderlyCount,
+			Total:   total.TotalCount,
+		},
+		Daily: nonNilPoints(points),
+	}
+
+	genderTotal := total.MaleCount + total.FemaleCount
+	ageTotal := total.ChildCount + total.AdultCount + total.ElderlyCount
+
+	insights.Share = entity.DemographicShare{
+		Male:    share(total.MaleCount, genderTotal),
+		Female:  share(total.FemaleCount, genderTotal),
+		Child:   share(total.ChildCount, ageTotal),
+		Adult:   share(total.AdultCount, ageTotal),
+		Elderly: share(total.ElderlyCount, ageTotal),
+	}
+
+	insights.DominantGender = dominant([]string{"male", "female"}, []int{total.MaleCount, total.FemaleCount})
+	insights.DominantAgeGroup = dominant([]string{"child", "adult", "elderly"}, []int{total.ChildCount, total.AdultCount, total.ElderlyCount})
+
+	return insights, nil
+}
+
+func (s *AnalyticsServiceImpl) location() *time.Location {
+	if s.occupancyReset.Location != nil {
+		return s.occupancyReset.Location
+	}
+	return time.Local
+}
+
+func (s *AnalyticsServiceImpl) startOfDay(t time.Time) time.Time {
+	t = t.In(s.location())
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, s.location())
+}
+
+func cameraFilter(cameraID uint) map[string]interface{} {
+	filters := make(map[string]interface{})
+	if cameraID > 0 {
+		filters["camera_id"] = cameraID
+	}
+	return filters
+}
+
+func sumPoints(points []entity.TrendPoint) entity.TrendPoint {
+	var total entity.TrendPoint
+	for _, point := range points {
+		total.MaleCount += point.MaleCount
+		total.FemaleCount += point.FemaleCount
+		total.TotalCount += point.TotalCount
+		total.ChildCount += point.ChildCount
+		total.AdultCount += point.AdultCount
+		total.ElderlyCount += point.ElderlyCount
+		total.InCount += point.InCount
+		total.OutCount += point.OutCount
+	}
+	return total
+}
+
+func nonNilPoints(points []entity.TrendPoint) []entity.TrendPoint {
+	if points == nil {
+		return []entity.TrendPoint{}
+	}
+	return points
+}
+
+// compareMetric computes the delta and the percentage change from previous to current
+func compareMetric(current, previous int) entity.MetricComparison {
+	comparison := entity.MetricComparison{
+		Current:  current,
+		Previous: previous,
+		Delta:    current - previous,
+	}
+
+	if previous != 0 {
+		change := round1(float64(current-previous) / float64(previous) * 100)
+		comparison.Percentage = &change
+	}
+
+	return comparison
+}
+
+// percentage returns value as a percentage of total, nil when total is zero
+func percentage(value, total int) *float64 {
+	if total <= 0 {
+		return nil
+	}
+	rate := round1(float64(value) / float64(total) * 100)
+	return &rate
+}
+
+// share returns value as a percentage of total, zero when total is zero
+func share(value, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return round1(float64(value) / float64(total) * 100)
+}
+
+// dominant returns the name of the largest value, or an empty string without data
+func dominant(names []string, values []int) string {
+	best := ""
+	bestValue := 0
+	for i, value := range values {
+		if value > bestValue {
+			best = names[i]
+			bestValue = value
+		}
+	}
+	return best
+}
+
+func round1(value float64) float64 {
+	return math.Round(value*10) / 10
+}
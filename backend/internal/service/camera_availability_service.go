@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+	"people-counting/internal/domain/service"
+)
+
+// defaultAvailabilityDays is the date range of the availability report when none is given
+const defaultAvailabilityDays = 30
+
+// CameraAvailabilityServiceImpl implements service.CameraAvailabilityService. Availability is
+// replayed from the camera_status_events hypertable: time in issue is an outage, time in
+// maintenance or inactive is planned and not monitored.
+type CameraAvailabilityServiceImpl struct {
+	cameraRepository repository.CameraRepository
+	location         *time.Location
+	target           float64
+}
+
+// NewCameraAvailabilityService creates a new camera availability service. Months are read in
+// the given time zone, the local time zone when nil. target is the availability percentage
+// agreed on.
+func NewCameraAvailabilityService(
+	cameraRepository repository.CameraRepository,
+	location *time.Location,
+	target float64,
+) service.CameraAvailabilityService {
+	if location == nil {
+		location = time.Local
+	}
+
+	return &CameraAvailabilityServiceImpl{
+		cameraRepository: cameraRepository,
+		location:         location,
+		target:           target,
+	}
+}
+
+// GetAvailability reports the availability of cameras between from and to, RFC3339 times that
+// default to the last thirty days
+func (s *CameraAvailabilityServiceImpl) GetAvailability(ctx context.Context, from, to, cameraID string) (*entity.CameraAvailabilityReport, error) {
+	end := time.Now().In(s.location)
+	if to != "" {
+		parsed, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return nil, errors.New("invalid to date")
+		}
+		end = parsed.In(s.location)
+	}
+
+	start := time.Date(end.Year(), end.Month(), end.Day()-defaultAvailabilityDays+1, 0, 0, 0, 0, s.location)
+	if from != "" {
+		parsed, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return nil, errors.New("invalid from date")
+		}
+		start = parsed.In(s.location)
+	}
+
+	if !start.Before(end) {
+		return nil, errors.New("invalid date range: 'from' date must be before 'to' date")
+	}
+
+	return s.report(ctx, start, end, cameraID)
+}
+
+// GetMonthlyReport reports the availability of every camera over a calendar month, given as
+// YYYY-MM and defaulting to the previous month
+func (s *CameraAvailabilityServiceImpl) GetMonthlyReport(ctx context.Context, month string) (*entity.CameraAvailabilityReport, error) {
+	now := time.Now().In(s.location)
+	start := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, s.location)
+
+	if month != "" {
+		parsed, err := time.ParseInLocation("2006-01", month, s.location)
+		if err != nil {
+			return nil, errors.New("invalid month. Use YYYY-MM")
+		}
+		start = parsed
+	}
+
+	if start.After(now) {
+		return nil, errors.New("invalid month: it has not started yet")
+	}
+
+	return s.report(ctx, start, start.AddDate(0, 1, 0), "")
+}
+
+// report replays the status changes of cameras between start and end
+func (s *CameraAvailabilityServiceImpl) report(ctx context.Context, start, end time.Time, cameraID string) (*entity.CameraAvailabilityReport, error) {
+	filters := make(map[string]interface{})
+
+	var cameras []entity.Camera
+	if cameraID != "" {
+		id, err := strconv.ParseUint(cameraID, 10, 32)
+		if err != nil {
+			return nil, errors.New("invalid camera ID")
+		}
+
+		camera, err := s.cameraRepository.FindByID(ctx, uint(id))
+		if err != nil {
+			return nil, err
+		}
+
+		cameras = []entity.Camera{*camera}
+		filters["camera_id"] = camera.ID
+	} else {
+		var err error
+		cameras, err = s.cameraRepository.FindAll(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	timeline, err := s.cameraRepository.FindStatusTimeline(ctx, start, end, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(map[uint][]entity.CameraStatusEvent)
+	for _, event := range timeline {
+		events[event.CameraID] = append(events[event.CameraID], event)
+	}
+
+	// The future is not monitored yet
+	until := end
+	if now := time.Now(); until.After(now) {
+		until = now
+	}
+
+	report := &entity.CameraAvailabilityReport{
+		From:    start,
+		To:      end,
+		Target:  s.target,
+		Cameras: make([]entity.CameraAvailability, 0, len(cameras)),
+	}
+
+	var monitored, downtime float64
+	for _, camera := range cameras {
+		availability := s.cameraAvailability(camera, events[camera.ID], start, until)
+		monitored += availability.MonitoredSeconds
+		downtime += availability.DowntimeSeconds
+
+		report.Cameras = append(report.Cameras, availability)
+	}
+
+	report.Availability = availabilityPercentage(monitored, downtime)
+
+	return report, nil
+}
+
+// cameraAvailability replays the status changes of a camera, ordered by time, between start
+// and until. The first change may precede start and gives the status at start.
+func (s *CameraAvailabilityServiceImpl) cameraAvailability(camera entity.Camera, events []entity.CameraStatusEvent, start, until time.Time) entity.CameraAvailability {
+	availability := entity.CameraAvailability{
+		CameraID:   camera.ID,
+		CameraName: camera.Name,
+		Location:   camera.Location,
+		Status:     camera.Status,
+	}
+
+	// Cameras added during the range are monitored from when they were added
+	cursor := start
+	if camera.CreatedAt.After(cursor) {
+		cursor = camera.CreatedAt
+	}
+
+	// Status at the cursor and since when the camera had it
+	status, since := camera.Status, cursor
+	if len(events) > 0 {
+		if events[0].ChangedAt.Before(start) {
+			status, since = events[0].ToStatus, events[0].ChangedAt
+			events = events[1:]
+		} else if events[0].FromStatus != "" {
+			status = events[0].FromStatus
+		}
+	}
+
+	// An outage lasts from a change to issue until a change to any other status, a change from
+	// issue to issue does not split it. outageSeconds is the time of the current outage inside
+	// the range.
+	var outageSeconds float64
+	var repairs int
+	var repairSeconds float64
+
+	segment := func(to time.Time) {
+		if !to.After(cursor) || status == entity.CameraStatusMaintenance || status == entity.CameraStatusInactive {
+			return
+		}
+
+		seconds := to.Sub(cursor).Seconds()
+		availability.MonitoredSeconds += seconds
+
+		if status == entity.CameraStatusIssue {
+			availability.DowntimeSeconds += seconds
+			outageSeconds += seconds
+		}
+	}
+
+	endOutage := func() {
+		if outageSeconds > 0 {
+			availability.Outages++
+			availability.LongestOutage = math.Max(availability.LongestOutage, outageSeconds)
+		}
+		outageSeconds = 0
+	}
+
+	for _, event := range events {
+		if event.ChangedAt.After(until) {
+			break
+		}
+
+		segment(event.ChangedAt)
+
+		switch {
+		case status == entity.CameraStatusIssue && event.ToStatus != entity.CameraStatusIssue:
+			endOutage()
+			if event.ChangedAt.After(cursor) {
+				repairs++
+				repairSeconds += event.ChangedAt.Sub(since).Seconds()
+			}
+			since = event.ChangedAt
+		case status != entity.CameraStatusIssue:
+			since = event.ChangedAt
+		}
+
+		status = event.ToStatus
+		if event.ChangedAt.After(cursor) {
+			cursor = event.ChangedAt
+		}
+	}
+
+	segment(until)
+	endOutage()
+
+	availability.MonitoredSeconds = math.Round(availability.MonitoredSeconds)
+	availability.DowntimeSeconds = math.Round(availability.DowntimeSeconds)
+	availability.LongestOutage = math.Round(availability.LongestOutage)
+	availability.Availability = availabilityPercentage(availability.MonitoredSeconds, availability.DowntimeSeconds)
+
+	if repairs > 0 {
+		mean := math.Round(repairSeconds / float64(repairs))
+		availability.MeanTimeToRepair = &mean
+	}
+
+	if availability.Availability != nil {
+		meets := *availability.Availability >= s.target
+		availability.MeetsTarget = &meets
+	}
+
+	return availability
+}
+
+// availabilityPercentage returns the share of the monitored time a camera was up to three
+// decimals, as agreements go to 99.9%. It is nil when nothing was monitored.
+func availabilityPercentage(monitored, downtime float64) *float64 {
+	if monitored <= 0 {
+		return nil
+	}
+
+	percentage := math.Round((monitored-downtime)/monitored*100*1000) / 1000
+	return &percentage
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"people-counting/internal/domain/entity"
+)
+
+var availabilityStart = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+// at returns the time the given number of hours after the start of the test range
+func at(hours float64) time.Time {
+	return availabilityStart.Add(time.Duration(hours * float64(time.Hour)))
+}
+
+func statusChange(from, to string, hours float64) entity.CameraStatusEvent {
+	return entity.CameraStatusEvent{CameraID: 1, FromStatus: from, ToStatus: to, ChangedAt: at(hours)}
+}
+
+func replayAvailability(status string, events []entity.CameraStatusEvent, until time.Time) entity.CameraAvailability {
+	s := &CameraAvailabilityServiceImpl{location: time.UTC, target: 99}
+	camera := entity.Camera{ID: 1, Name: "Gate", Status: status, CreatedAt: availabilityStart.AddDate(-1, 0, 0)}
+	return s.cameraAvailability(camera, events, availabilityStart, until)
+}
+
+func TestCameraAvailabilityCountsOutages(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		events     []entity.CameraStatusEvent
+		until      float64
+		outages    int
+		longest    float64
+		downtime   float64
+		monitored  float64
+		meanRepair *float64
+	}{
+		{
+			name:      "no changes",
+			status:    entity.CameraStatusActive,
+			until:     10,
+			monitored: 10 * 3600,
+		},
+		{
+			name:   "one outage",
+			status: entity.CameraStatusActive,
+			events: []entity.CameraStatusEvent{
+				statusChange(entity.CameraStatusActive, entity.CameraStatusIssue, 2),
+				statusChange(entity.CameraStatusIssue, entity.CameraStatusActive, 3),
+			},
+			until:      10,
+			outages:    1,
+			longest:    3600,
+			downtime:   3600,
+			monitored:  10 * 3600,
+			meanRepair: floatPtr(3600),
+		},
+		{
+			name:   "issue to issue is one outage",
+			status: entity.CameraStatusActive,
+			events: []entity.CameraStatusEvent{
+				statusChange(entity.CameraStatusActive, entity.CameraStatusIssue, 1),
+				statusChange(entity.CameraStatusIssue, entity.CameraStatusIssue, 2),
+				statusChange(entity.CameraStatusIssue, entity.CameraStatusIssue, 3),
+				statusChange(entity.CameraStatusIssue, entity.CameraStatusActive, 4),
+			},
+			until:      10,
+			outages:    1,
+			longest:    3 * 3600,
+			downtime:   3 * 3600,
+			monitored:  10 * 3600,
+			meanRepair: floatPtr(3 * 3600),
+		},
+		{
+			name:   "outage started before the range",
+			status: entity.CameraStatusActive,
+			events: []entity.CameraStatusEvent{
+				statusChange(entity.CameraStatusActive, entity.CameraStatusIssue, -2),
+				statusChange(entity.CameraStatusIssue, entity.CameraStatusIssue, 1),
+				statusChange(entity.CameraStatusIssue, entity.CameraStatusActive, 2),
+			},
+			until:     10,
+			outages:   1,
+			longest:   2 * 3600,
+			downtime:  2 * 3600,
+			monitored: 10 * 3600,
+			// Repair time runs from when the outage began, before the range
+			meanRepair: floatPtr(4 * 3600),
+		},
+		{
+			name:   "outage still going at the end of the range",
+			status: entity.CameraStatusActive,
+			events: []entity.CameraStatusEvent{
+				statusChange(entity.CameraStatusActive, entity.CameraStatusIssue, 8),
+				statusChange(entity.CameraStatusIssue, entity.CameraStatusIssue, 9),
+			},
+			until:     10,
+			outages:   1,
+			longest:   2 * 3600,
+			downtime:  2 * 3600,
+			monitored: 10 * 3600,
+		},
+		{
+			name:   "two outages",
+			status: entity.CameraStatusActive,
+			events: []entity.CameraStatusEvent{
+				statusChange(entity.CameraStatusActive, entity.CameraStatusIssue, 1),
+				statusChange(entity.CameraStatusIssue, entity.CameraStatusActive, 2),
+				statusChange(entity.CameraStatusActive, entity.CameraStatusIssue, 5),
+				statusChange(entity.CameraStatusIssue, entity.CameraStatusActive, 8),
+			},
+			until:      10,
+			outages:    2,
+			longest:    3 * 3600,
+			downtime:   4 * 3600,
+			monitored:  10 * 3600,
+			meanRepair: floatPtr(2 * 3600),
+		},
+		{
+			name:   "maintenance is not monitored",
+			status: entity.CameraStatusActive,
+			events: []entity.CameraStatusEvent{
+				statusChange(entity.CameraStatusActive, entity.CameraStatusMaintenance, 2),
+				statusChange(entity.CameraStatusMaintenance, entity.CameraStatusActive, 6),
+			},
+			until:     10,
+			monitored: 6 * 3600,
+		},
+		{
+			name:   "inactive the whole range",
+			status: entity.CameraStatusInactive,
+			until:  10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := replayAvailability(tt.status, tt.events, at(tt.until))
+
+			if got.Outages != tt.outages {
+				t.Errorf("outages = %d, want %d", got.Outages, tt.outages)
+			}
+			if got.LongestOutage != tt.longest {
+				t.Errorf("longest outage = %v, want %v", got.LongestOutage, tt.longest)
+			}
+			if got.DowntimeSeconds != tt.downtime {
+				t.Errorf("downtime = %v, want %v", got.DowntimeSeconds, tt.downtime)
+			}
+			if got.MonitoredSeconds != tt.monitored {
+				t.Errorf("monitored = %v, want %v", got.MonitoredSeconds, tt.monitored)
+			}
+
+			switch {
+			case tt.meanRepair == nil && got.MeanTimeToRepair != nil:
+				t.Errorf("mean time to repair = %v, want none", *got.MeanTimeToRepair)
+			case tt.meanRepair != nil && got.MeanTimeToRepair == nil:
+				t.Errorf("mean time to repair missing, want %v", *tt.meanRepair)
+			case tt.meanRepair != nil && *got.MeanTimeToRepair != *tt.meanRepair:
+				t.Errorf("mean time to repair = %v, want %v", *got.MeanTimeToRepair, *tt.meanRepair)
+			}
+
+			if tt.monitored == 0 && got.Availability != nil {
+				t.Errorf("availability = %v, want none when nothing was monitored", *got.Availability)
+			}
+		})
+	}
+}
+
+func TestCameraAvailabilityPercentage(t *testing.T) {
+	events := []entity.CameraStatusEvent{
+		statusChange(entity.CameraStatusActive, entity.CameraStatusIssue, 0),
+		statusChange(entity.CameraStatusIssue, entity.CameraStatusActive, 0.1),
+	}
+
+	got := replayAvailability(entity.CameraStatusActive, events, at(100))
+	if got.Availability == nil || *got.Availability != 99.9 {
+		t.Fatalf("availability = %v, want 99.9", got.Availability)
+	}
+	if got.MeetsTarget == nil || !*got.MeetsTarget {
+		t.Errorf("meets target = %v, want true", got.MeetsTarget)
+	}
+}
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
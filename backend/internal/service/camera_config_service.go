@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+	"people-counting/internal/domain/service"
+)
+
+// CameraConfigServiceImpl implements service.CameraConfigService
+type CameraConfigServiceImpl struct {
+	configRepository    repository.CameraConfigRepository
+	cameraRepository    repository.CameraRepository
+	cameraDeviceService service.CameraDeviceService
+}
+
+// NewCameraConfigService creates a new camera configuration service. Edge devices are resolved
+// to their camera the same way as the records they ingest.
+func NewCameraConfigService(
+	configRepository repository.CameraConfigRepository,
+	cameraRepository repository.CameraRepository,
+	cameraDeviceService service.CameraDeviceService,
+) service.CameraConfigService {
+	return &CameraConfigServiceImpl{
+		configRepository:    configRepository,
+		cameraRepository:    cameraRepository,
+		cameraDeviceService: cameraDeviceService,
+	}
+}
+
+// GetConfig retrieves the current configuration of a camera
+func (s *CameraConfigServiceImpl) GetConfig(ctx context.Context, cameraID uint) (*entity.CameraConfig, error) {
+	if _, err := s.cameraRepository.FindByID(ctx, cameraID); err != nil {
+		return nil, err
+	}
+
+	return s.configRepository.FindCurrent(ctx, cameraID)
+}
+
+// GetVersions retrieves every version of the configuration of a camera, newest first
+func (s *CameraConfigServiceImpl) GetVersions(ctx context.Context, cameraID uint) ([]entity.CameraConfig, error) {
+	if _, err := s.cameraRepository.FindByID(ctx, cameraID); err != nil {
+		return nil, err
+	}
+
+	return s.configRepository.FindVersions(ctx, cameraID)
+}
+
+// GetVersion retrieves a version of the configuration of a camera
+func (s *CameraConfigServiceImpl) GetVersion(ctx context.Context, cameraID uint, version int) (*entity.CameraConfig, error) {
+	if version < 1 {
+		return nil, errors.New("invalid version")
+	}
+
+	return s.configRepository.FindVersion(ctx, cameraID, version)
+}
+
+// SaveConfig validates a configuration and saves it as the next version of the configuration
+// of its camera
+func (s *CameraConfigServiceImpl) SaveConfig(ctx context.Context, config *entity.CameraConfig) error {
+	if _, err := s.cameraRepository.FindByID(ctx, config.CameraID); err != nil {
+		return err
+	}
+
+	if err := validateCameraConfig(config); err != nil {
+		return err
+	}
+
+	return s.configRepository.Create(ctx, config)
+}
+
+// RestoreVersion saves a copy of an earlier version as the next version, so edge devices
+// switch back to it
+func (s *CameraConfigServiceImpl) RestoreVersion(ctx context.Context, cameraID uint, version int, createdBy string) (*entity.CameraConfig, error) {
+	previous, err := s.GetVersion(ctx, cameraID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &entity.CameraConfig{
+		CameraID:         cameraID,
+		Polygons:         previous.Polygons,
+		Tripwires:        previous.Tripwires,
+		Direction:        previous.Direction,
+		Classes:          previous.Classes,
+		LoiteringSeconds: previous.LoiteringSeconds,
+		Note:             fmt.Sprintf("Restored version %d", version),
+		CreatedBy:        createdBy,
+	}
+
+	if err := s.configRepository.Create(ctx, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// DeleteConfig archives the configuration of a camera. Edge devices no longer receive it and
+// the next saved configuration continues the version numbers.
+func (s *CameraConfigServiceImpl) DeleteConfig(ctx context.Context, cameraID uint) error {
+	if _, err := s.cameraRepository.FindByID(ctx, cameraID); err != nil {
+		return err
+	}
+
+	return s.configRepository.Archive(ctx, cameraID)
+}
+
+// GetDeviceConfig retrieves the current configuration of the camera of an edge device
+func (s *CameraConfigServiceImpl) GetDeviceConfig(ctx context.Context, identity entity.DeviceIdentity) (*entity.CameraConfig, error) {
+	cameraID, err := s.cameraDeviceService.ResolveCamera(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.configRepository.FindCurrent(ctx, cameraID)
+}
+
+// ResolveVersion returns the configuration version to record on a record ingested from a
+// camera: the version the device reported, otherwise the current version of the camera, and
+// nil when the camera has no configuration. A reported version the camera never had is invalid.
+func (s *CameraConfigServiceImpl) ResolveVersion(ctx context.Context, cameraID uint, reported *int) (*int, error) {
+	if reported != nil && *reported != 0 {
+		if _, err := s.configRepository.FindVersion(ctx, cameraID, *reported); err != nil {
+			if err.Error() == "camera config version not found" {
+				return nil, fmt.Errorf("%w %d, camera %d never had it", service.ErrInvalidConfigVersion, *reported, cameraID)
+			}
+			return nil, err
+		}
+
+		version := *reported
+		return &version, nil
+	}
+
+	config, err := s.configRepository.FindCurrent(ctx, cameraID)
+	if err != nil {
+		if err.Error() == "camera config not found" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &config.Version, nil
+}
+
+// resolveConfigVersion returns the configuration version to record on a record of a camera,
+// see CameraConfigService.ResolveVersion. Reported versions are kept unchecked without
+// cameraConfigService, and records without a camera have no version.
+func resolveConfigVersion(ctx context.Context, cameraConfigService service.CameraConfigService, cameraID uint, reported *int) (*int, error) {
+	if cameraID == 0 {
+		return nil, nil
+	}
+
+	if cameraConfigService == nil {
+		return reported, nil
+	}
+
+	return cameraConfigService.ResolveVersion(ctx, cameraID, reported)
+}
+
+// validateCameraConfig validates a configuration and normalizes its direction and classes
+func validateCameraConfig(config *entity.CameraConfig) error {
+	if config.Polygons == nil {
+		config.Polygons = []entity.ConfigPolygon{}
+	}
+
+	hasLoitering := false
+	for i := range config.Polygons {
+		polygon := &config.Polygons[i]
+		polygon.Name = strings.TrimSpace(polygon.Name)
+		polygon.Kind = strings.ToLower(strings.TrimSpace(polygon.Kind))
+
+		if !entity.IsConfigPolygonKind(polygon.Kind) {
+			return fmt.Errorf("invalid polygon %d kind. Must be roi, restricted or loitering", i+1)
+		}
+
+		if len(polygon.Points) < 3 {
+			return fmt.Errorf("invalid polygon %d, it needs at least 3 points", i+1)
+		}
+
+		for _, point := range polygon.Points {
+			if !isFramePoint(point) {
+				return fmt.Errorf("invalid polygon %d, points must be fractions of the frame between 0 and 1", i+1)
+			}
+		}
+
+		if polygon.Kind == entity.ConfigPolygonKindLoitering {
+			hasLoitering = true
+		}
+	}
+
+	if config.Tripwires == nil {
+		config.Tripwires = []entity.ConfigTripwire{}
+	}
+
+	for i := range config.Tripwires {
+		tripwire := &config.Tripwires[i]
+		tripwire.Name = strings.TrimSpace(tripwire.Name)
+
+		if !isFramePoint(tripwire.Start) || !isFramePoint(tripwire.End) {
+			return fmt.Errorf("invalid tripwire %d, points must be fractions of the frame between 0 and 1", i+1)
+		}
+
+		if tripwire.Start == tripwire.End {
+			return fmt.Errorf("invalid tripwire %d, its start and end must differ", i+1)
+		}
+	}
+
+	config.Direction = strings.ToLower(strings.TrimSpace(config.Direction))
+	if config.Direction == "" {
+		config.Direction = entity.ConfigDirectionLeftToRight
+	}
+
+	if !entity.IsConfigDirection(config.Direction) {
+		return errors.New("invalid direction. Must be left_to_right or right_to_left")
+	}
+
+	classes := make([]string, 0, len(config.Classes))
+	seen := make(map[string]bool, len(config.Classes))
+	for _, class := range config.Classes {
+		class = strings.ToLower(strings.TrimSpace(class))
+		if class == "" {
+			return errors.New("invalid classes, a class cannot be empty")
+		}
+		if !seen[class] {
+			seen[class] = true
+			classes = append(classes, class)
+		}
+	}
+	config.Classes = classes
+
+	if config.LoiteringSeconds < 0 {
+		return errors.New("invalid loitering seconds, it cannot be negative")
+	}
+
+	if hasLoitering && config.LoiteringSeconds == 0 {
+		return errors.New("invalid loitering seconds, loitering polygons need a duration")
+	}
+
+	return nil
+}
+
+// isFramePoint reports whether a point lies within the camera frame
+func isFramePoint(point entity.ConfigPoint) bool {
+	return point.X >= 0 && point.X <= 1 && point.Y >= 0 && point.Y <= 1
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+	"people-counting/internal/domain/service"
+)
+
+// CameraDeviceServiceImpl implements service.CameraDeviceService
+type CameraDeviceServiceImpl struct {
+	deviceRepository repository.CameraDeviceRepository
+	cameraRepository repository.CameraRepository
+	policy           string
+}
+
+// NewCameraDeviceService creates a new camera device service. Records from devices without a
+// mapping are handled according to policy: reject, quarantine, or auto-register.
+func NewCameraDeviceService(deviceRepository repository.CameraDeviceRepository, cameraRepository repository.CameraRepository, policy string) service.CameraDeviceService {
+	return &CameraDeviceServiceImpl{
+		deviceRepository: deviceRepository,
+		cameraRepository: cameraRepository,
+		policy:           policy,
+	}
+}
+
+// ResolveCamera returns the camera a device is mapped to, applying the unknown device policy
+// when the device has no mapping yet
+func (s *CameraDeviceServiceImpl) ResolveCamera(ctx context.Context, identity entity.DeviceIdentity) (uint, error) {
+	if identity.IsZero() {
+		return 0, errors.New("device identifier is required")
+	}
+
+	if identity.Channel < 0 {
+		return 0, errors.New("channel must not be negative")
+	}
+
+	device, err := s.findDevice(ctx, identity)
+	if err != nil {
+		return 0, err
+	}
+
+	if device == nil {
+		return s.handleUnknownDevice(ctx, identity)
+	}
+
+	if err := s.deviceRepository.Touch(ctx, device.ID, time.Now()); err != nil {
+		log.Printf("WARNING: Failed to record activity of device %s: %v", identity, err)
+	}
+
+	if device.Status == entity.CameraDeviceStatusQuarantined {
+		return 0, errors.New("device is quarantined")
+	}
+
+	if device.CameraID == nil {
+		return 0, errors.New("device is not mapped to a camera")
+	}
+
+	return *device.CameraID, nil
+}
+
+// findDevice looks a device up by serial and channel, then by legacy ID. A legacy device that
+// starts sending its serial keeps its mapping and learns the serial.
+func (s *CameraDeviceServiceImpl) findDevice(ctx context.Context, identity entity.DeviceIdentity) (*entity.CameraDevice, error) {
+	if identity.Serial != "" {
+		device, err := s.deviceRepository.FindBySerial(ctx, identity.Serial, identity.Channel)
+		if err == nil {
+			return device, nil
+		}
+		if err.Error() != "camera device not found" {
+			return nil, err
+		}
+	}
+
+	if identity.LegacyID == nil {
+		return nil, nil
+	}
+
+	device, err := s.deviceRepository.FindByLegacyID(ctx, *identity.LegacyID)
+	if err != nil {
+		if err.Error() == "camera device not found" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if identity.Serial != "" && device.Serial == "" {
+		device.Serial = identity.Serial
+		device.Channel = identity.Channel
+		if err := s.deviceRepository.Update(ctx, device); err != nil {
+			log.Printf("WARNING: Failed to record serial of device %s: %v", identity, err)
+		}
+	}
+
+	return device, nil
+}
+
+// handleUnknownDevice applies the unknown device policy
+func (s *CameraDeviceServiceImpl) handleUnknownDevice(ctx context.Context, identity entity.DeviceIdentity) (uint, error) {
+	now := time.Now()
+	device := &entity.CameraDevice{
+		Serial:      identity.Serial,
+		Channel:     identity.Channel,
+		LegacyID:    identity.LegacyID,
+		SeenCount:   1,
+		FirstSeenAt: &now,
+		LastSeenAt:  &now,
+	}
+
+	switch s.policy {
+	case entity.UnknownDevicePolicyQuarantine:
+		device.Status = entity.CameraDeviceStatusQuarantined
+		if err := s.deviceRepository.Create(ctx, device); err != nil {
+			return 0, fmt.Errorf("failed to quarantine device: %w", err)
+		}
+
+		log.Printf("Quarantined unknown device %s", identity)
+		return 0, errors.New("device is quarantined")
+
+	case entity.UnknownDevicePolicyAutoRegister:
+		camera := &entity.Camera{
+			Name:   fmt.Sprintf("Camera %s (Auto-created)", identity),
+			Status: "active",
+		}
+
+		// Legacy devices keep the camera numbering used before the mapping table existed
+		if identity.LegacyID != nil {
+			camera.ID = *identity.LegacyID + 1
+			camera.Name = fmt.Sprintf("Camera %d (Auto-created)", camera.ID)
+		}
+
+		device.Status = entity.CameraDeviceStatusMapped
+		if err := s.deviceRepository.Register(ctx, device, camera); err != nil {
+			return 0, fmt.Errorf("failed to register device: %w", err)
+		}
+
+		log.Printf("Registered unknown device %s as camera %d", identity, camera.ID)
+		return camera.ID, nil
+
+	default:
+		return 0, errors.New("unknown device")
+	}
+}
+
+// GetAllDevices retrieves paginated camera devices with filters
+func (s *CameraDeviceServiceImpl) GetAllDevices(ctx context.Context, page, limit int, status, cameraID, search string) ([]entity.CameraDevice, int64, error) {
+	// Use default pagination values if invalid
+	if page <= 0 {
+		page = 1
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+
+	filters := make(map[string]interface{})
+
+	if status != "" {
+		if status != entity.CameraDeviceStatusMapped && status != entity.CameraDeviceStatusQuarantined {
+			return nil, 0, errors.New("invalid status. Must be mapped or quarantined")
+		}
+		filters["status"] = status
+	}
+
+	if cameraID != "" {
+		id, err := strconv.ParseUint(cameraID, 10, 32)
+		if err != nil {
+			return nil, 0, errors.New("invalid camera ID")
+		}
+		filters["camera_id"] = uint(id)
+	}
+
+	if search != "" {
+		filters["search"] = search
+	}
+
+	return s.deviceRepository.FindAll(ctx, page, limit, filters)
+}
+
+// GetDeviceByID retrieves a camera device by its ID
+func (s *CameraDeviceServiceImpl) GetDeviceByID(ctx context.Context, id uint) (*entity.CameraDevice, error) {
+	return s.deviceRepository.FindByID(ctx, id)
+}
+
+// CreateDevice creates a device mapping. A device without a camera is created quarantined.
+func (s *CameraDeviceServiceImpl) CreateDevice(ctx context.Context, device *entity.CameraDevice) error {
+	if err := s.validateDevice(ctx, device); err != nil {
+		return err
+	}
+
+	return s.deviceRepository.Create(ctx, device)
+}
+
+// UpdateDevice updates the identifiers and camera of a device mapping
+func (s *CameraDeviceServiceImpl) UpdateDevice(ctx context.Context, device *entity.CameraDevice) error {
+	if device.ID == 0 {
+		return errors.New("camera device ID is required")
+	}
+
+	if _, err := s.deviceRepository.FindByID(ctx, device.ID); err != nil {
+		return err
+	}
+
+	if err := s.validateDevice(ctx, device); err != nil {
+		return err
+	}
+
+	return s.deviceRepository.Update(ctx, device)
+}
+
+// MapDevice maps a device, typically a quarantined one, to a camera
+func (s *CameraDeviceServiceImpl) MapDevice(ctx context.Context, id, cameraID uint) (*entity.CameraDevice, error) {
+	device, err := s.deviceRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	device.CameraID = &cameraID
+	if err := s.validateDevice(ctx, device); err != nil {
+		return nil, err
+	}
+
+	if err := s.deviceRepository.Update(ctx, device); err != nil {
+		return nil, err
+	}
+
+	return device, nil
+}
+
+// DeleteDevice deletes a device mapping
+func (s *CameraDeviceServiceImpl) DeleteDevice(ctx context.Context, id uint) error {
+	return s.deviceRepository.Delete(ctx, id)
+}
+
+// GetUnknownDevicePolicy returns the policy applied to devices without a mapping
+func (s *CameraDeviceServiceImpl) GetUnknownDevicePolicy() string {
+	return s.policy
+}
+
+// validateDevice checks identifiers and camera, rejects identifiers already mapped by
+// another device, and derives the status from the camera
+func (s *CameraDeviceServiceImpl) validateDevice(ctx context.Context, device *entity.CameraDevice) error {
+	if device.Serial == "" && device.LegacyID == nil {
+		return errors.New("serial or legacy ID is required")
+	}
+
+	if device.Channel < 0 {
+		return errors.New("channel must not be negative")
+	}
+
+	if device.Serial != "" {
+		existing, err := s.deviceRepository.FindBySerial(ctx, device.Serial, device.Channel)
+		if err == nil && existing.ID != device.ID {
+			return errors.New("a device with the same serial and channel already exists")
+		}
+	}
+
+	if device.LegacyID != nil {
+		existing, err := s.deviceRepository.FindByLegacyID(ctx, *device.LegacyID)
+		if err == nil && existing.ID != device.ID {
+			return errors.New("a device with the same legacy ID already exists")
+		}
+	}
+
+	if device.CameraID != nil && *device.CameraID == 0 {
+		device.CameraID = nil
+	}
+
+	if device.CameraID == nil {
+		device.Status = entity.CameraDeviceStatusQuarantined
+		return nil
+	}
+
+	if _, err := s.cameraRepository.FindByID(ctx, *device.CameraID); err != nil {
+		return err
+	}
+
+	device.Status = entity.CameraDeviceStatusMapped
+	return nil
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BufferedFrame is a stream image kept in the frame buffer of a camera
+type BufferedFrame struct {
+	Path string
+	Time time.Time
+}
+
+// CameraFrameBuffer keeps the recent stream images of every camera on disk, so that evidence
+// clips can show what happened before an alert. The stream directory only holds the latest
+// image_<camera ID>.jpg of each camera. Its versions are copied to a directory per camera,
+// named after the time they were written in Unix milliseconds.
+type CameraFrameBuffer struct {
+	streamDirectory string
+	bufferDirectory string
+	interval        time.Duration
+	retention       time.Duration
+
+	mu           sync.Mutex
+	lastModified map[uint]time.Time
+}
+
+// NewCameraFrameBuffer creates a frame buffer that looks for new stream images every interval
+// and keeps them for retention. A zero interval disables the buffer.
+func NewCameraFrameBuffer(streamDirectory, bufferDirectory string, interval, retention time.Duration) *CameraFrameBuffer {
+	return &CameraFrameBuffer{
+		streamDirectory: streamDirectory,
+		bufferDirectory: bufferDirectory,
+		interval:        interval,
+		retention:       retention,
+		lastModified:    make(map[uint]time.Time),
+	}
+}
+
+// Enabled reports whether frames are buffered
+func (b *CameraFrameBuffer) Enabled() bool {
+	return b.interval > 0
+}
+
+// Retention returns how long frames are kept
+func (b *CameraFrameBuffer) Retention() time.Duration {
+	return b.retention
+}
+
+// Run buffers the stream images until ctx is done
+func (b *CameraFrameBuffer) Run(ctx context.Context) {
+	if !b.Enabled() {
+		return
+	}
+
+	if err := os.MkdirAll(b.bufferDirectory, 0755); err != nil {
+		log.Printf("Failed to create frame buffer directory: %v", err)
+		return
+	}
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	pruneTicker := time.NewTicker(30 * time.Second)
+	defer pruneTicker.Stop()
+
+	log.Printf("Buffering camera frames every %v for %v in %s", b.interval, b.retention, b.bufferDirectory)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.captureAll()
+		case now := <-pruneTicker.C:
+			b.prune(now.Add(-b.retention))
+		}
+	}
+}
+
+// Frames returns the buffered frames of a camera taken between from and to, oldest first
+func (b *CameraFrameBuffer) Frames(cameraID uint, from, to time.Time) ([]BufferedFrame, error) {
+	entries, err := os.ReadDir(b.cameraDirectory(cameraID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var frames []BufferedFrame
+	for _, entry := range entries {
+		taken, ok := frameTime(entry.Name())
+		if !ok || taken.Before(from) || taken.After(to) {
+			continue
+		}
+
+		frames = append(frames, BufferedFrame{
+			Path: filepath.Join(b.cameraDirectory(cameraID), entry.Name()),
+			Time: taken,
+		})
+	}
+
+	sort.Slice(frames, func(i, j int) bool {
+		return frames[i].Time.Before(frames[j].Time)
+	})
+
+	return frames, nil
+}
+
+// captureAll copies the stream images written since the last capture
+func (b *CameraFrameBuffer) captureAll() {
+	images, err := filepath.Glob(filepath.Join(b.streamDirectory, "image_*.jpg"))
+	if err != nil {
+		return
+	}
+
+	for _, image := range images {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(image), "image_"), ".jpg")
+		cameraID, err := strconv.ParseUint(name, 10, 32)
+		if err != nil {
+			continue
+		}
+
+		if err := b.capture(uint(cameraID), image); err != nil {
+			log.Printf("Failed to buffer frame of camera %d: %v", cameraID, err)
+		}
+	}
+}
+
+// capture copies the stream image of a camera when it changed
+func (b *CameraFrameBuffer) capture(cameraID uint, image string) error {
+	info, err := os.Stat(image)
+	if err != nil {
+		return nil
+	}
+
+	modified := info.ModTime()
+
+	b.mu.Lock()
+	unchanged := modified.Equal(b.lastModified[cameraID])
+	b.mu.Unlock()
+	if unchanged {
+		return nil
+	}
+
+	data, err := os.ReadFile(image)
+	if err != nil {
+		return err
+	}
+
+	// The stream image is overwritten in place, a half written one is picked up next time
+	if !completeJPEG(data) {
+		return nil
+	}
+
+	directory := b.cameraDirectory(cameraID)
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return err
+	}
+
+	path := filepath.Join(directory, fmt.Sprintf("%d.jpg", modified.UnixMilli()))
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+
+	if err := os.Rename(path+".tmp", path); err != nil {
+		os.Remove(path + ".tmp")
+		return err
+	}
+
+	b.mu.Lock()
+	b.lastModified[cameraID] = modified
+	b.mu.Unlock()
+
+	return nil
+}
+
+// prune removes the frames taken before the given time
+func (b *CameraFrameBuffer) prune(before time.Time) {
+	directories, err := os.ReadDir(b.bufferDirectory)
+	if err != nil {
+		return
+	}
+
+	for _, directory := range directories {
+		if !directory.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(b.bufferDirectory, directory.Name())
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if taken, ok := frameTime(entry.Name()); ok && taken.Before(before) {
+				os.Remove(filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+}
+
+// cameraDirectory returns the buffer directory of a camera
+func (b *CameraFrameBuffer) cameraDirectory(cameraID uint) string {
+	return filepath.Join(b.bufferDirectory, strconv.FormatUint(uint64(cameraID), 10))
+}
+
+// frameTime reads the time a buffered frame was taken from its file name
+func frameTime(name string) (time.Time, bool) {
+	if !strings.HasSuffix(name, ".jpg") {
+		return time.Time{}, false
+	}
+
+	millis, err := strconv.ParseInt(strings.TrimSuffix(name, ".jpg"), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.UnixMilli(millis), true
+}
+
+// completeJPEG reports whether data starts and ends with the JPEG start and end markers
+func completeJPEG(data []byte) bool {
+	return len(data) > 4 &&
+		bytes.HasPrefix(data, []byte{0xFF, 0xD8}) &&
+		bytes.HasSuffix(bytes.TrimRight(data, "\x00"), []byte{0xFF, 0xD9})
+}
@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+	"people-counting/internal/domain/service"
+)
+
+// cameraActivityLookback is how far back the last data of cameras is looked up. Cameras
+// without stream images and without data in this period give no signal to judge by.
+const cameraActivityLookback = 7 * 24 * time.Hour
+
+// CameraHealthOptions configures the camera health monitor, see config.CameraHealthConfig
+type CameraHealthOptions struct {
+	FrameStaleAfter time.Duration
+	DataStaleAfter  time.Duration
+	DecodeFailures  int
+	FailChecks      int
+	RecoverChecks   int
+}
+
+// cameraHealthState is the outcome of the checks of a camera so far
+type cameraHealthState struct {
+	failed  int
+	healthy int
+	last    entity.CameraHealth
+}
+
+// CameraHealthServiceImpl implements service.CameraHealthService. Each check judges a camera
+// by the age of its stream image, the last people count, vehicle count or alert it sent, and
+// the stream images that failed to decode. Active cameras unhealthy for FailChecks checks in a
+// row are flagged as issue, and cameras the monitor flagged are made active again after
+// RecoverChecks healthy checks in a row. Inactive and maintenance cameras are left alone.
+type CameraHealthServiceImpl struct {
+	cameraRepository repository.CameraRepository
+	streamService    *CameraStreamService
+	webSocketService service.WebSocketService
+	streamDirectory  string
+	options          CameraHealthOptions
+
+	mu     sync.Mutex
+	states map[uint]*cameraHealthState
+}
+
+// NewCameraHealthService creates a new camera health monitor reading the stream images in
+// streamDirectory. streamService may be nil, decode failures are then not checked.
+func NewCameraHealthService(
+	cameraRepository repository.CameraRepository,
+	streamService *CameraStreamService,
+	webSocketService service.WebSocketService,
+	streamDirectory string,
+	options CameraHealthOptions,
+) service.CameraHealthService {
+	if options.FailChecks < 1 {
+		options.FailChecks = 1
+	}
+	if options.RecoverChecks < 1 {
+		options.RecoverChecks = 1
+	}
+
+	return &CameraHealthServiceImpl{
+		cameraRepository: cameraRepository,
+		streamService:    streamService,
+		webSocketService: webSocketService,
+		streamDirectory:  streamDirectory,
+		options:          options,
+		states:           make(map[uint]*cameraHealthState),
+	}
+}
+
+// CheckCameras checks every camera once, changing the status of those whose health has been
+// failing or recovering for long enough
+func (s *CameraHealthServiceImpl) CheckCameras(ctx context.Context) ([]entity.CameraHealth, error) {
+	cameras, err := s.cameraRepository.FindAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	activity, err := s.cameraRepository.FindLastActivity(ctx, now.Add(-cameraActivityLookback))
+	if err != nil {
+		return nil, err
+	}
+
+	lastData := make(map[uint]time.Time, len(activity))
+	for _, row := range activity {
+		lastData[row.CameraID] = row.LastActiveAt
+	}
+
+	latest, err := s.cameraRepository.FindLatestStatusEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	latestSource := make(map[uint]string, len(latest))
+	for _, event := range latest {
+		latestSource[event.CameraID] = event.Source
+	}
+
+	results := make([]entity.CameraHealth, 0, len(cameras))
+	for _, camera := range cameras {
+		health := s.checkCamera(camera, lastData, now)
+
+		s.mu.Lock()
+		state, ok := s.states[camera.ID]
+		if !ok {
+			state = &cameraHealthState{}
+			s.states[camera.ID] = state
+		}
+
+		if health.Healthy != nil {
+			if *health.Healthy {
+				state.healthy++
+				state.failed = 0
+			} else {
+				state.failed++
+				state.healthy = 0
+			}
+		}
+
+		var change *entity.CameraStatusEvent
+		switch {
+		case camera.Status == entity.CameraStatusActive && state.failed >= s.options.FailChecks:
+			change = &entity.CameraStatusEvent{
+				CameraID:   camera.ID,
+				FromStatus: entity.CameraStatusActive,
+				ToStatus:   entity.CameraStatusIssue,
+				Source:     entity.CameraStatusSourceMonitor,
+				Reason:     health.Reason,
+				ChangedAt:  now,
+			}
+		case camera.Status == entity.CameraStatusIssue && state.healthy >= s.options.RecoverChecks &&
+			latestSource[camera.ID] == entity.CameraStatusSourceMonitor:
+			// Cameras flagged by hand stay flagged until they are cleared by hand
+			change = &entity.CameraStatusEvent{
+				CameraID:   camera.ID,
+				FromStatus: entity.CameraStatusIssue,
+				ToStatus:   entity.CameraStatusActive,
+				Source:     entity.CameraStatusSourceMonitor,
+				Reason:     "stream and data are fresh again",
+				ChangedAt:  now,
+			}
+		}
+
+		health.FailedChecks = state.failed
+		health.HealthyChecks = state.healthy
+		state.last = health
+		s.mu.Unlock()
+
+		if change != nil {
+			changed, err := s.cameraRepository.ChangeStatus(ctx, change)
+			if err != nil {
+				log.Printf("Failed to change status of camera %d: %v", camera.ID, err)
+			} else if changed {
+				log.Printf("Camera %d status changed from %s to %s: %s", camera.ID, change.FromStatus, change.ToStatus, change.Reason)
+				health.Status = change.ToStatus
+
+				s.mu.Lock()
+				state.last.Status = change.ToStatus
+				s.mu.Unlock()
+
+				if s.webSocketService != nil {
+					s.webSocketService.NotifyCameraStatus(change)
+				}
+			}
+		}
+
+		results = append(results, health)
+	}
+
+	s.forgetDeleted(cameras)
+
+	return results, nil
+}
+
+// GetCameraHealth returns the outcome of the last check of every camera, checking them first
+// when the monitor has not run yet
+func (s *CameraHealthServiceImpl) GetCameraHealth(ctx context.Context) ([]entity.CameraHealth, error) {
+	s.mu.Lock()
+	results := make([]entity.CameraHealth, 0, len(s.states))
+	for _, state := range s.states {
+		results = append(results, state.last)
+	}
+	s.mu.Unlock()
+
+	if len(results) == 0 {
+		return s.CheckCameras(ctx)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].CameraID < results[j].CameraID
+	})
+
+	return results, nil
+}
+
+// GetStatusHistory retrieves paginated camera status changes, newest first. from and to are
+// RFC3339 times.
+func (s *CameraHealthServiceImpl) GetStatusHistory(ctx context.Context, page, limit int, cameraID, source, from, to string) ([]entity.CameraStatusEvent, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	filters := make(map[string]interface{})
+
+	if cameraID != "" {
+		id, err := strconv.ParseUint(cameraID, 10, 32)
+		if err != nil {
+			return nil, 0, errors.New("invalid camera ID")
+		}
+		filters["camera_id"] = uint(id)
+	}
+
+	if source != "" {
+		if source != entity.CameraStatusSourceManual && source != entity.CameraStatusSourceMonitor {
+			return nil, 0, errors.New("invalid source. Must be manual or monitor")
+		}
+		filters["source"] = source
+	}
+
+	if from != "" {
+		parsed, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return nil, 0, errors.New("invalid from date")
+		}
+		filters["from"] = parsed
+	}
+
+	if to != "" {
+		parsed, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return nil, 0, errors.New("invalid to date")
+		}
+		filters["to"] = parsed
+	}
+
+	return s.cameraRepository.FindStatusEvents(ctx, page, limit, filters)
+}
+
+// Run checks the cameras every interval until ctx is done
+func (s *CameraHealthServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.CheckCameras(ctx); err != nil {
+				log.Printf("Failed to check camera health: %v", err)
+			}
+		}
+	}
+}
+
+// checkCamera judges a camera by its signals. Healthy is left nil for cameras that are not
+// watched or gave no signal.
+func (s *CameraHealthServiceImpl) checkCamera(camera entity.Camera, lastData map[uint]time.Time, now time.Time) entity.CameraHealth {
+	health := entity.CameraHealth{
+		CameraID:   camera.ID,
+		CameraName: camera.Name,
+		Status:     camera.Status,
+		CheckedAt:  now,
+	}
+
+	imagePath := filepath.Join(s.streamDirectory, fmt.Sprintf("image_%d.jpg", camera.ID))
+	if info, err := os.Stat(imagePath); err == nil {
+		modified := info.ModTime()
+		health.LastFrame = &modified
+	}
+
+	if last, ok := lastData[camera.ID]; ok {
+		health.LastData = &last
+	}
+
+	if s.streamService != nil {
+		health.DecodeFailures = s.streamService.DecodeFailures(camera.ID)
+	}
+
+	if camera.Status != entity.CameraStatusActive && camera.Status != entity.CameraStatusIssue {
+		return health
+	}
+
+	var problems []string
+	signal := false
+
+	if s.options.DecodeFailures > 0 && health.DecodeFailures >= s.options.DecodeFailures {
+		problems = append(problems, fmt.Sprintf("%d stream images in a row failed to decode", health.DecodeFailures))
+	}
+
+	if health.LastFrame != nil {
+		// Cameras streaming images are judged by them, data may pause when nobody passes by
+		signal = true
+		if age := now.Sub(*health.LastFrame); s.options.FrameStaleAfter > 0 && age > s.options.FrameStaleAfter {
+			problems = append(problems, fmt.Sprintf("no stream image for %s", age.Round(time.Second)))
+		}
+	} else if health.LastData != nil && s.options.DataStaleAfter > 0 {
+		signal = true
+		if age := now.Sub(*health.LastData); age > s.options.DataStaleAfter {
+			problems = append(problems, fmt.Sprintf("no data for %s", age.Round(time.Second)))
+		}
+	}
+
+	if !signal && len(problems) == 0 {
+		return health
+	}
+
+	healthy := len(problems) == 0
+	health.Healthy = &healthy
+	health.Reason = strings.Join(problems, ", ")
+
+	return health
+}
+
+// forgetDeleted drops the state of cameras that no longer exist
+func (s *CameraHealthServiceImpl) forgetDeleted(cameras []entity.Camera) {
+	existing := make(map[uint]bool, len(cameras))
+	for _, camera := range cameras {
+		existing[camera.ID] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := range s.states {
+		if !existing[id] {
+			delete(s.states, id)
+		}
+	}
+}
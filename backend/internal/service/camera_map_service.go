@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+	"people-counting/internal/domain/service"
+)
+
+// CameraMapServiceImpl implements service.CameraMapService
+type CameraMapServiceImpl struct {
+	cameraRepository   repository.CameraRepository
+	zoneRepository     repository.ZoneRepository
+	alertRepository    repository.AlertRepository
+	peopleCountService service.PeopleCountService
+}
+
+// NewCameraMapService creates a new camera map service
+func NewCameraMapService(
+	cameraRepository repository.CameraRepository,
+	zoneRepository repository.ZoneRepository,
+	alertRepository repository.AlertRepository,
+	peopleCountService service.PeopleCountService,
+) service.CameraMapService {
+	return &CameraMapServiceImpl{
+		cameraRepository:   cameraRepository,
+		zoneRepository:     zoneRepository,
+		alertRepository:    alertRepository,
+		peopleCountService: peopleCountService,
+	}
+}
+
+// GetCameraMap returns every camera as a GeoJSON feature with its status, open alerts, live
+// occupancy and position on the floor plans of its zones
+func (s *CameraMapServiceImpl) GetCameraMap(ctx context.Context) (*entity.CameraMap, error) {
+	cameras, err := s.cameraRepository.FindAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	zones, err := s.zoneRepository.FindAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	activeAlerts, err := s.alertRepository.CountActiveByCamera(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	occupancy, err := s.peopleCountService.GetOccupancy(ctx, "", "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	cameraOccupancy := make(map[uint]*entity.CameraOccupancy, len(occupancy.Cameras))
+	for i := range occupancy.Cameras {
+		cameraOccupancy[occupancy.Cameras[i].CameraID] = &occupancy.Cameras[i]
+	}
+
+	cameraZones := make(map[uint][]entity.CameraFeatureZone)
+	for _, zone := range zones {
+		for _, membership := range zone.Cameras {
+			cameraZones[membership.CameraID] = append(cameraZones[membership.CameraID], entity.CameraFeatureZone{
+				ZoneID:    zone.ID,
+				Name:      zone.Name,
+				Kind:      zone.Kind,
+				Direction: membership.Direction,
+				FloorPlan: zone.FloorPlan != "",
+				PlanX:     membership.PlanX,
+				PlanY:     membership.PlanY,
+			})
+		}
+	}
+
+	features := make([]entity.CameraFeature, 0, len(cameras))
+	for _, camera := range cameras {
+		feature := entity.CameraFeature{
+			Type: "Feature",
+			ID:   camera.ID,
+			Properties: entity.CameraFeatureProperties{
+				Name:         camera.Name,
+				Location:     camera.Location,
+				Status:       camera.Status,
+				Floor:        camera.Floor,
+				Bearing:      camera.Bearing,
+				FieldOfView:  camera.FieldOfView,
+				Capacity:     camera.Capacity,
+				ActiveAlerts: activeAlerts[camera.ID],
+				Occupancy:    cameraOccupancy[camera.ID],
+				Zones:        cameraZones[camera.ID],
+			},
+		}
+
+		if camera.Latitude != nil && camera.Longitude != nil {
+			feature.Geometry = &entity.PointGeometry{
+				Type:        "Point",
+				Coordinates: [2]float64{*camera.Longitude, *camera.Latitude},
+			}
+		}
+
+		if feature.Properties.Zones == nil {
+			feature.Properties.Zones = []entity.CameraFeatureZone{}
+		}
+
+		features = append(features, feature)
+	}
+
+	return &entity.CameraMap{
+		Type:     "FeatureCollection",
+		Features: features,
+	}, nil
+}
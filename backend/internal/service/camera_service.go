@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"time"
 
 	"people-counting/internal/domain/entity"
@@ -17,6 +18,7 @@ import (
 type CameraServiceImpl struct {
 	cameraRepository repository.CameraRepository
 	streamService    *CameraStreamService
+	webSocketService service.WebSocketService
 	dataDir          string
 }
 
@@ -24,9 +26,11 @@ type CameraServiceImpl struct {
 func NewCameraService(
 	cameraRepository repository.CameraRepository,
 	dataDir string,
+	webSocketService service.WebSocketService,
 ) service.CameraService {
 	service := &CameraServiceImpl{
 		cameraRepository: cameraRepository,
+		webSocketService: webSocketService,
 		dataDir:          dataDir,
 	}
 
@@ -101,6 +105,14 @@ func (s *CameraServiceImpl) CreateCamera(ctx context.Context, camera *entity.Cam
 		return errors.New("camera name is required")
 	}
 
+	if camera.Capacity < 0 {
+		return errors.New("capacity must not be negative")
+	}
+
+	if err := validateCameraPlacement(camera); err != nil {
+		return err
+	}
+
 	// Set default status if not provided
 	if camera.Status == "" {
 		camera.Status = "active"
@@ -144,6 +156,40 @@ func (s *CameraServiceImpl) UpdateCamera(ctx context.Context, camera *entity.Cam
 		existingCamera.Location = camera.Location
 	}
 
+	if camera.IPAddress != "" {
+		existingCamera.IPAddress = camera.IPAddress
+	}
+
+	if err := validateCameraPlacement(camera); err != nil {
+		return err
+	}
+
+	if camera.Latitude != nil {
+		existingCamera.Latitude = camera.Latitude
+	}
+
+	if camera.Longitude != nil {
+		existingCamera.Longitude = camera.Longitude
+	}
+
+	if camera.Floor != nil {
+		existingCamera.Floor = camera.Floor
+	}
+
+	if camera.Bearing != nil {
+		existingCamera.Bearing = camera.Bearing
+	}
+
+	if camera.FieldOfView != nil {
+		existingCamera.FieldOfView = camera.FieldOfView
+	}
+
+	if camera.Capacity < 0 {
+		return errors.New("capacity must not be negative")
+	} else if camera.Capacity > 0 {
+		existingCamera.Capacity = camera.Capacity
+	}
+
 	if camera.Status != "" {
 		// Validate status
 		validStatuses := []string{"active", "inactive", "maintenance", "issue"}
@@ -159,7 +205,12 @@ func (s *CameraServiceImpl) UpdateCamera(ctx context.Context, camera *entity.Cam
 			return errors.New("invalid status. Must be active, inactive, maintenance, or issue")
 		}
 
-		existingCamera.Status = camera.Status
+		if camera.Status != existingCamera.Status {
+			if err := s.changeStatus(ctx, camera.ID, camera.Status); err != nil {
+				return err
+			}
+			existingCamera.Status = camera.Status
+		}
 	}
 
 	// Update last online time
@@ -168,6 +219,32 @@ func (s *CameraServiceImpl) UpdateCamera(ctx context.Context, camera *entity.Cam
 	return s.cameraRepository.Update(ctx, existingCamera)
 }
 
+// validateCameraPlacement validates the address and the map position of a camera, the fields
+// that are not set are skipped
+func validateCameraPlacement(camera *entity.Camera) error {
+	if camera.IPAddress != "" && net.ParseIP(camera.IPAddress) == nil {
+		return errors.New("invalid IP address")
+	}
+
+	if camera.Latitude != nil && (*camera.Latitude < -90 || *camera.Latitude > 90) {
+		return errors.New("invalid latitude, it must be between -90 and 90")
+	}
+
+	if camera.Longitude != nil && (*camera.Longitude < -180 || *camera.Longitude > 180) {
+		return errors.New("invalid longitude, it must be between -180 and 180")
+	}
+
+	if camera.Bearing != nil && (*camera.Bearing < 0 || *camera.Bearing >= 360) {
+		return errors.New("invalid bearing, it must be at least 0 and less than 360 degrees")
+	}
+
+	if camera.FieldOfView != nil && (*camera.FieldOfView <= 0 || *camera.FieldOfView > 360) {
+		return errors.New("invalid field of view, it must be more than 0 and at most 360 degrees")
+	}
+
+	return nil
+}
+
 // UpdateCameraStatus updates just the status of a camera
 func (s *CameraServiceImpl) UpdateCameraStatus(ctx context.Context, id uint, status string) error {
 	if id == 0 {
@@ -188,7 +265,27 @@ func (s *CameraServiceImpl) UpdateCameraStatus(ctx context.Context, id uint, sta
 		return errors.New("invalid status. Must be active, inactive, maintenance, or issue")
 	}
 
-	return s.cameraRepository.UpdateStatus(ctx, id, status)
+	return s.changeStatus(ctx, id, status)
+}
+
+// changeStatus records a status change made by hand and broadcasts it
+func (s *CameraServiceImpl) changeStatus(ctx context.Context, id uint, status string) error {
+	event := &entity.CameraStatusEvent{
+		CameraID: id,
+		ToStatus: status,
+		Source:   entity.CameraStatusSourceManual,
+	}
+
+	changed, err := s.cameraRepository.ChangeStatus(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	if changed && s.webSocketService != nil {
+		s.webSocketService.NotifyCameraStatus(event)
+	}
+
+	return nil
 }
 
 // DeleteCamera deletes a camera
@@ -44,6 +44,9 @@ type CameraStream struct {
 	isRunning    bool
 	ctx          context.Context
 	cancelFn     context.CancelFunc
+
+	// decodeFailures counts the images in a row that failed to decode, guarded by clientsMu
+	decodeFailures int
 }
 
 // StreamConfig defines configuration for a stream
@@ -126,6 +129,23 @@ func (s *CameraStreamService) StreamExists(cameraID uint) bool {
 	return exists && stream.isRunning
 }
 
+// DecodeFailures returns how many images of a camera in a row failed to decode, zero when the
+// camera is not streaming
+func (s *CameraStreamService) DecodeFailures(cameraID uint) int {
+	s.mu.Lock()
+	stream, exists := s.streams[cameraID]
+	s.mu.Unlock()
+
+	if !exists {
+		return 0
+	}
+
+	stream.clientsMu.Lock()
+	defer stream.clientsMu.Unlock()
+
+	return stream.decodeFailures
+}
+
 // GetCameraStreamURL returns the URL for a camera's stream
 func (s *CameraStreamService) GetCameraStreamURL(c *fiber.Ctx, cameraID uint) string {
 	baseURL := c.Protocol() + "://" + c.Hostname()
@@ -481,6 +501,18 @@ func (s *CameraStream) broadcastFrame(frameData []byte) {
 	}
 }
 
+// recordDecode counts the images in a row that failed to decode, for the health monitor
+func (s *CameraStream) recordDecode(err error) {
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+
+	if err != nil {
+		s.decodeFailures++
+	} else {
+		s.decodeFailures = 0
+	}
+}
+
 // frameGenerator reads image files and broadcasts frames
 func (s *CameraStream) frameGenerator() {
 	interval := time.Second / time.Duration(s.frameRate)
@@ -518,6 +550,7 @@ func (s *CameraStream) frameGenerator() {
 
 			img, _, err := image.Decode(file)
 			file.Close()
+			s.recordDecode(err)
 			if err != nil {
 				time.Sleep(interval)
 				continue
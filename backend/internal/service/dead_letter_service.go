@@ -0,0 +1,348 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/service"
+	"people-counting/pkg/watcher"
+)
+
+// deadLetterPreviewSize is the number of payload bytes included in listings
+const deadLetterPreviewSize = 512
+
+// DeadLetterServiceImpl implements service.DeadLetterService on top of the watcher manager's failed folders
+type DeadLetterServiceImpl struct {
+	watcherManager *watcher.Manager
+}
+
+// NewDeadLetterService creates a new dead letter service
+func NewDeadLetterService(watcherManager *watcher.Manager) service.DeadLetterService {
+	return &DeadLetterServiceImpl{
+		watcherManager: watcherManager,
+	}
+}
+
+// GetFolders retrieves every watched folder with the number of failed files it holds
+func (s *DeadLetterServiceImpl) GetFolders(ctx context.Context) ([]entity.DeadLetterFolder, error) {
+	folders := make([]entity.DeadLetterFolder, 0)
+
+	for _, config := range s.watcherManager.Folders() {
+		folder := entity.DeadLetterFolder{
+			Name:         filepath.Base(config.Path),
+			Path:         config.Path,
+			FailedFolder: config.FailedFolder,
+			Handler:      config.Handler.GetName(),
+		}
+
+		files, err := s.failedFiles(config)
+		if err != nil {
+			return nil, err
+		}
+
+		folder.Count = len(files)
+		if len(files) > 0 {
+			oldest := files[len(files)-1].modTime
+			folder.OldestFailedAt = &oldest
+		}
+
+		folders = append(folders, folder)
+	}
+
+	return folders, nil
+}
+
+// GetDeadLetters retrieves paginated failed files of a folder, newest first
+func (s *DeadLetterServiceImpl) GetDeadLetters(ctx context.Context, folder string, page, limit int, search string) ([]entity.DeadLetter, int64, error) {
+	// Use default pagination values if invalid
+	if page <= 0 {
+		page = 1
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+
+	config, err := s.findFolder(folder)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	files, err := s.failedFiles(config)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var deadLetters []entity.DeadLetter
+	for _, file := range files {
+		deadLetter, err := s.loadDeadLetter(config, file.name, false)
+		if err != nil {
+			continue
+		}
+
+		if search != "" && !matchesSearch(deadLetter, search) {
+			continue
+		}
+
+		deadLetters = append(deadLetters, *deadLetter)
+	}
+
+	total := int64(len(deadLetters))
+	offset := (page - 1) * limit
+	if offset >= len(deadLetters) {
+		return []entity.DeadLetter{}, total, nil
+	}
+
+	end := offset + limit
+	if end > len(deadLetters) {
+		end = len(deadLetters)
+	}
+
+	return deadLetters[offset:end], total, nil
+}
+
+// GetDeadLetter retrieves a single failed file including its full payload
+func (s *DeadLetterServiceImpl) GetDeadLetter(ctx context.Context, folder, name string) (*entity.DeadLetter, error) {
+	config, err := s.findFolder(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateDeadLetterName(name); err != nil {
+		return nil, err
+	}
+
+	return s.loadDeadLetter(config, name, true)
+}
+
+// ReplacePayload overwrites the payload of a failed file, e.g. to fix a malformed record before requeuing it
+func (s *DeadLetterServiceImpl) ReplacePayload(ctx context.Context, folder, name string, payload []byte) (*entity.DeadLetter, error) {
+	config, err := s.findFolder(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateDeadLetterName(name); err != nil {
+		return nil, err
+	}
+
+	if !json.Valid(payload) {
+		return nil, errors.New("payload must be valid JSON")
+	}
+
+	path := filepath.Join(config.FailedFolder, name)
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New("failed file not found")
+		}
+		return nil, err
+	}
+
+	// Write to a temporary file first so a partial write never replaces the original
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, payload, 0644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return nil, err
+	}
+
+	return s.loadDeadLetter(config, name, true)
+}
+
+// Requeue moves failed files back into the watched folder. When names is empty every failed file is requeued.
+func (s *DeadLetterServiceImpl) Requeue(ctx context.Context, folder string, names []string) ([]entity.RequeueResult, error) {
+	config, err := s.findFolder(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		files, err := s.failedFiles(config)
+		if err != nil {
+			return nil, err
+		}
+
+		// Requeue oldest first so they are processed in their original order
+		for i := len(files) - 1; i >= 0; i-- {
+			names = append(names, files[i].name)
+		}
+	}
+
+	results := make([]entity.RequeueResult, 0, len(names))
+	for _, name := range names {
+		result := entity.RequeueResult{Name: name, Status: "requeued"}
+		if err := s.watcherManager.Requeue(config.Path, name); err != nil {
+			result.Status = "error"
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// Purge deletes failed files and their sidecars older than the given age
+func (s *DeadLetterServiceImpl) Purge(ctx context.Context, folder string, olderThan time.Duration) (int, error) {
+	if olderThan < 0 {
+		return 0, errors.New("older_than must not be negative")
+	}
+
+	config, err := s.findFolder(folder)
+	if err != nil {
+		return 0, err
+	}
+
+	files, err := s.failedFiles(config)
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	purged := 0
+
+	for _, file := range files {
+		if file.modTime.After(cutoff) {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(config.FailedFolder, file.name)); err != nil && !os.IsNotExist(err) {
+			return purged, err
+		}
+		os.Remove(watcher.SidecarPath(config.FailedFolder, file.name))
+		purged++
+	}
+
+	return purged, nil
+}
+
+// failedFile is a payload file in a failed folder
+type failedFile struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+// findFolder resolves a watched folder by its directory name
+func (s *DeadLetterServiceImpl) findFolder(name string) (watcher.FolderConfig, error) {
+	for _, config := range s.watcherManager.Folders() {
+		if filepath.Base(config.Path) == name {
+			return config, nil
+		}
+	}
+
+	return watcher.FolderConfig{}, errors.New("folder not found")
+}
+
+// failedFiles lists the payload files of a folder's failed folder, newest first
+func (s *DeadLetterServiceImpl) failedFiles(config watcher.FolderConfig) ([]failedFile, error) {
+	entries, err := os.ReadDir(config.FailedFolder)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var files []failedFile
+	for _, entry := range entries {
+		if entry.IsDir() || watcher.IsSidecar(entry.Name()) || strings.HasSuffix(entry.Name(), ".tmp") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		files = append(files, failedFile{
+			name:    entry.Name(),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+
+	return files, nil
+}
+
+// loadDeadLetter reads a failed file and its sidecar
+func (s *DeadLetterServiceImpl) loadDeadLetter(config watcher.FolderConfig, name string, includePayload bool) (*entity.DeadLetter, error) {
+	path := filepath.Join(config.FailedFolder, name)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New("failed file not found")
+		}
+		return nil, err
+	}
+
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetter := &entity.DeadLetter{
+		Folder:   filepath.Base(config.Path),
+		Name:     name,
+		Size:     info.Size(),
+		FailedAt: info.ModTime(),
+		Preview:  string(payload),
+	}
+
+	if len(payload) > deadLetterPreviewSize {
+		deadLetter.Preview = string(payload[:deadLetterPreviewSize])
+	}
+
+	if includePayload {
+		deadLetter.Payload = string(payload)
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		deadLetter.ParseError = err.Error()
+	}
+
+	// Files that failed before sidecars were introduced have none
+	if data, err := os.ReadFile(watcher.SidecarPath(config.FailedFolder, name)); err == nil {
+		var sidecar watcher.ErrorSidecar
+		if err := json.Unmarshal(data, &sidecar); err == nil {
+			deadLetter.FailedAt = sidecar.FailedAt
+			deadLetter.Handler = sidecar.Handler
+			deadLetter.Error = sidecar.Error
+			deadLetter.ErrorChain = sidecar.ErrorChain
+			deadLetter.Classification = sidecar.Classification
+			deadLetter.Attempts = sidecar.Attempts
+		}
+	}
+
+	return deadLetter, nil
+}
+
+// validateDeadLetterName rejects names that would escape the failed folder
+func validateDeadLetterName(name string) error {
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." || watcher.IsSidecar(name) {
+		return errors.New("invalid file name")
+	}
+	return nil
+}
+
+// matchesSearch reports whether a dead letter's name or error contains the search term
+func matchesSearch(deadLetter *entity.DeadLetter, search string) bool {
+	search = strings.ToLower(search)
+	return strings.Contains(strings.ToLower(deadLetter.Name), search) ||
+		strings.Contains(strings.ToLower(deadLetter.Error), search) ||
+		strings.Contains(strings.ToLower(deadLetter.ParseError), search)
+}
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+	"people-counting/internal/domain/service"
+)
+
+// EscalationServiceImpl implements service.EscalationService
+type EscalationServiceImpl struct {
+	escalationRepository repository.EscalationRepository
+	alertRepository      repository.AlertRepository
+	alertTypeRepository  repository.AlertTypeRepository
+	alertService         service.AlertService
+	notificationService  service.NotificationService
+	webSocketService     service.WebSocketService
+
+	// processMu keeps scheduled and manual runs from firing the same step twice
+	processMu sync.Mutex
+}
+
+// NewEscalationService creates a new escalation service
+func NewEscalationService(
+	escalationRepository repository.EscalationRepository,
+	alertRepository repository.AlertRepository,
+	alertTypeRepository repository.AlertTypeRepository,
+	alertService service.AlertService,
+	notificationService service.NotificationService,
+	webSocketService service.WebSocketService,
+) service.EscalationService {
+	return &EscalationServiceImpl{
+		escalationRepository: escalationRepository,
+		alertRepository:      alertRepository,
+		alertTypeRepository:  alertTypeRepository,
+		alertService:         alertService,
+		notificationService:  notificationService,
+		webSocketService:     webSocketService,
+	}
+}
+
+// GetAllPolicies retrieves all escalation policies with their steps
+func (s *EscalationServiceImpl) GetAllPolicies(ctx context.Context) ([]entity.EscalationPolicy, error) {
+	return s.escalationRepository.FindPolicies(ctx)
+}
+
+// GetPolicyByID retrieves an escalation policy with its steps by its ID
+func (s *EscalationServiceImpl) GetPolicyByID(ctx context.Context, id uint) (*entity.EscalationPolicy, error) {
+	return s.escalationRepository.FindPolicyByID(ctx, id)
+}
+
+// CreatePolicy creates an escalation policy with its steps
+func (s *EscalationServiceImpl) CreatePolicy(ctx context.Context, policy *entity.EscalationPolicy) error {
+	if err := s.validatePolicy(ctx, policy); err != nil {
+		return err
+	}
+
+	if _, err := s.escalationRepository.FindPolicyByName(ctx, policy.Name); err == nil {
+		return errors.New("an escalation policy with the same name already exists")
+	}
+
+	return s.escalationRepository.CreatePolicy(ctx, policy)
+}
+
+// UpdatePolicy updates an escalation policy and replaces its steps. Alerts already escalating
+// continue from the step they reached.
+func (s *EscalationServiceImpl) UpdatePolicy(ctx context.Context, policy *entity.EscalationPolicy) error {
+	if _, err := s.escalationRepository.FindPolicyByID(ctx, policy.ID); err != nil {
+		return err
+	}
+
+	if err := s.validatePolicy(ctx, policy); err != nil {
+		return err
+	}
+
+	if other, err := s.escalationRepository.FindPolicyByName(ctx, policy.Name); err == nil && other.ID != policy.ID {
+		return errors.New("an escalation policy with the same name already exists")
+	}
+
+	return s.escalationRepository.UpdatePolicy(ctx, policy)
+}
+
+// DeletePolicy deletes an escalation policy and detaches it from its alert types
+func (s *EscalationServiceImpl) DeletePolicy(ctx context.Context, id uint) error {
+	return s.escalationRepository.DeletePolicy(ctx, id)
+}
+
+// SetAlertTypePolicy attaches an escalation policy to an alert type, or detaches it with nil
+func (s *EscalationServiceImpl) SetAlertTypePolicy(ctx context.Context, alertTypeID uint, policyID *uint) (*entity.AlertType, error) {
+	if _, err := s.alertTypeRepository.FindByID(ctx, alertTypeID); err != nil {
+		return nil, err
+	}
+
+	if policyID != nil {
+		if _, err := s.escalationRepository.FindPolicyByID(ctx, *policyID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.escalationRepository.SetAlertTypePolicy(ctx, alertTypeID, policyID); err != nil {
+		return nil, err
+	}
+
+	return s.alertTypeRepository.FindByID(ctx, alertTypeID)
+}
+
+// GetAlertEscalation retrieves how far an alert went through its escalation policy
+func (s *EscalationServiceImpl) GetAlertEscalation(ctx context.Context, alertID string) (*entity.AlertEscalation, error) {
+	if alertID == "" {
+		return nil, errors.New("alert ID is required")
+	}
+
+	if _, err := s.alertRepository.FindByID(ctx, alertID); err != nil {
+		return nil, err
+	}
+
+	return s.escalationRepository.FindEscalation(ctx, alertID)
+}
+
+// ProcessEscalations fires the due steps of unacknowledged alerts and stops the escalation of
+// alerts that were acknowledged or closed. It returns the escalation events recorded.
+func (s *EscalationServiceImpl) ProcessEscalations(ctx context.Context) ([]entity.AlertEvent, error) {
+	s.processMu.Lock()
+	defer s.processMu.Unlock()
+
+	now := time.Now()
+
+	policies, err := s.escalationRepository.FindPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make(map[uint]*entity.EscalationPolicy)
+	for i := range policies {
+		if policies[i].Enabled && len(policies[i].Steps) > 0 {
+			enabled[policies[i].ID] = &policies[i]
+		}
+	}
+
+	alertTypes, err := s.alertTypeRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	typePolicies := make(map[uint]*entity.EscalationPolicy)
+	typeAttachedAt := make(map[uint]*time.Time)
+	var alertTypeIDs []uint
+	for _, alertType := range alertTypes {
+		if alertType.EscalationPolicyID == nil || enabled[*alertType.EscalationPolicyID] == nil {
+			continue
+		}
+		typePolicies[alertType.ID] = enabled[*alertType.EscalationPolicyID]
+		typeAttachedAt[alertType.ID] = alertType.EscalationPolicyAttachedAt
+		alertTypeIDs = append(alertTypeIDs, alertType.ID)
+	}
+
+	alerts, err := s.escalationRepository.FindUnacknowledgedAlerts(ctx, alertTypeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	unacknowledged := make(map[string]bool, len(alerts))
+	for _, alert := range alerts {
+		unacknowledged[alert.ID] = true
+	}
+
+	active, err := s.escalationRepository.FindActiveEscalations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Escalations end once the alert is acknowledged, closed, or its type loses the policy
+	activeByAlert := make(map[string]*entity.AlertEscalation, len(active))
+	for i := range active {
+		escalation := &active[i]
+		if unacknowledged[escalation.AlertID] {
+			activeByAlert[escalation.AlertID] = escalation
+			continue
+		}
+
+		escalation.Status = entity.AlertEscalationStopped
+		if err := s.escalationRepository.SaveEscalation(ctx, escalation); err != nil {
+			log.Printf("Failed to stop escalation of alert %s: %v", escalation.AlertID, err)
+		}
+	}
+
+	var events []entity.AlertEvent
+	for i := range alerts {
+		alert := &alerts[i]
+		policy := typePolicies[alert.AlertTypeID]
+
+		escalation := activeByAlert[alert.ID]
+		if escalation == nil {
+			if escalation, err = s.startEscalation(ctx, alert, typeAttachedAt[alert.AlertTypeID], now); err != nil {
+				log.Printf("Failed to start escalation of alert %s: %v", alert.ID, err)
+				continue
+			}
+			if escalation == nil {
+				continue
+			}
+		}
+		escalation.PolicyID = policy.ID
+
+		event, err := s.fireDueStep(ctx, alert, policy, escalation, now)
+		if err != nil {
+			log.Printf("Failed to escalate alert %s: %v", alert.ID, err)
+			continue
+		}
+		if event != nil {
+			events = append(events, *event)
+		}
+	}
+
+	return events, nil
+}
+
+// Run processes escalations every interval until ctx is cancelled
+func (s *EscalationServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.ProcessEscalations(ctx); err != nil {
+				log.Printf("Failed to process escalations: %v", err)
+			}
+		}
+	}
+}
+
+// startEscalation starts tracking an unacknowledged alert. Alerts are escalated from the time
+// they were raised, alerts raised before the policy was attached from that time, and reopened
+// alerts from the time they are found reopened. It returns nil when the alert already went
+// through every step.
+func (s *EscalationServiceImpl) startEscalation(ctx context.Context, alert *entity.Alert, policyAttachedAt *time.Time, now time.Time) (*entity.AlertEscalation, error) {
+	existing, err := s.escalationRepository.FindEscalation(ctx, alert.ID)
+	if err != nil && err.Error() != "alert escalation not found" {
+		return nil, err
+	}
+
+	if existing != nil {
+		if existing.Status == entity.AlertEscalationCompleted {
+			return nil, nil
+		}
+
+		// Stopped escalations restart when the alert is reopened or its type gets a policy again
+		existing.Status = entity.AlertEscalationActive
+		existing.Level = 0
+		existing.StartedAt = now
+		existing.LastStepAt = nil
+		return existing, nil
+	}
+
+	startedAt := alert.CreatedAt
+	if startedAt.IsZero() || currentAlertStatus(alert) == entity.AlertStatusReopened {
+		startedAt = now
+	}
+
+	// Attaching a policy does not make every older alert of the type overdue at once
+	if policyAttachedAt != nil && policyAttachedAt.After(startedAt) {
+		startedAt = *policyAttachedAt
+	}
+
+	return &entity.AlertEscalation{
+		AlertID:   alert.ID,
+		Status:    entity.AlertEscalationActive,
+		StartedAt: startedAt,
+	}, nil
+}
+
+// fireDueStep fires the latest due step of an escalation and saves its progress. Steps that
+// became due together, such as after downtime, are folded into the latest one so recipients
+// are not flooded.
+func (s *EscalationServiceImpl) fireDueStep(ctx context.Context, alert *entity.Alert, policy *entity.EscalationPolicy, escalation *entity.AlertEscalation, now time.Time) (*entity.AlertEvent, error) {
+	due := -1
+	for i := escalation.Level; i < len(policy.Steps); i++ {
+		if escalation.StartedAt.Add(policy.Steps[i].Delay()).After(now) {
+			break
+		}
+		due = i
+	}
+
+	if due < 0 {
+		return nil, s.escalationRepository.SaveEscalation(ctx, escalation)
+	}
+
+	step := &policy.Steps[due]
+	status := currentAlertStatus(alert)
+
+	note := fmt.Sprintf("Not acknowledged after %d minutes, step %d of escalation policy %s", step.DelayMinutes, step.Position, policy.Name)
+	if step.Target != "" {
+		note += ", escalated to " + step.Target
+	}
+
+	event := &entity.AlertEvent{
+		AlertID:    alert.ID,
+		Action:     entity.AlertEventEscalationStep,
+		FromStatus: status,
+		ToStatus:   status,
+		AssignedTo: alert.AssignedTo,
+		Actor:      "system",
+	}
+
+	var problems []string
+
+	if step.Severity != "" && entity.AlertSeverityRank(step.Severity) > entity.AlertSeverityRank(alert.Severity) {
+		escalated, err := s.alertService.EscalateAlert(ctx, alert.ID, step.Severity, "system", note)
+		if err != nil {
+			problems = append(problems, "severity not raised: "+err.Error())
+		} else {
+			event.FromSeverity = alert.Severity
+			event.ToSeverity = step.Severity
+			alert = escalated.Alert
+		}
+	}
+
+	if step.ChannelID != nil {
+		if err := s.notificationService.NotifyChannel(ctx, alert, *step.ChannelID, entity.NotificationEventEscalation, note); err != nil {
+			problems = append(problems, "notification not sent: "+err.Error())
+		}
+	}
+
+	event.Note = note
+	if len(problems) > 0 {
+		event.Note += " (" + strings.Join(problems, "; ") + ")"
+	}
+
+	if err := s.alertRepository.CreateEvent(ctx, event); err != nil {
+		return nil, err
+	}
+
+	escalation.Level = due + 1
+	escalation.LastStepAt = &now
+	if escalation.Level >= len(policy.Steps) {
+		escalation.Status = entity.AlertEscalationCompleted
+	}
+
+	if err := s.escalationRepository.SaveEscalation(ctx, escalation); err != nil {
+		return nil, err
+	}
+
+	event.Alert = alert
+	if s.webSocketService != nil {
+		s.webSocketService.NotifyAlertEvent(event)
+	}
+
+	return event, nil
+}
+
+// validatePolicy validates a policy and numbers its steps
+func (s *EscalationServiceImpl) validatePolicy(ctx context.Context, policy *entity.EscalationPolicy) error {
+	policy.Name = strings.TrimSpace(policy.Name)
+	if policy.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if len(policy.Steps) == 0 {
+		return errors.New("at least one step is required")
+	}
+
+	for i := range policy.Steps {
+		step := &policy.Steps[i]
+		step.Position = i + 1
+
+		if step.DelayMinutes < 0 {
+			return errors.New("step delay must not be negative")
+		}
+
+		if i > 0 && step.DelayMinutes < policy.Steps[i-1].DelayMinutes {
+			return errors.New("step delays must be in increasing order")
+		}
+
+		if step.ChannelID == nil && step.Severity == "" {
+			return errors.New("each step needs a channel or a severity")
+		}
+
+		if step.ChannelID != nil {
+			if _, err := s.notificationService.GetChannelByID(ctx, *step.ChannelID); err != nil {
+				return err
+			}
+		}
+
+		if step.Severity != "" && entity.AlertSeverityRank(step.Severity) < 0 {
+			return errors.New("invalid severity")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+	"people-counting/internal/domain/service"
+)
+
+// Evidence clip formats
+const (
+	EvidenceClipGIF   = "gif"
+	EvidenceClipMJPEG = "mjpeg"
+)
+
+// evidenceClipMaxWidth keeps GIF clips small, frames are scaled down to this width
+const evidenceClipMaxWidth = 640
+
+// EvidenceOptions configures evidence clips
+type EvidenceOptions struct {
+	Before time.Duration // Of the buffer before the detection time
+	After  time.Duration // Of the buffer after the detection time
+	Format string        // gif or mjpeg
+}
+
+// evidenceJob is an alert waiting for the frames after its detection
+type evidenceJob struct {
+	alertID  string
+	cameraID uint
+	from     time.Time
+	to       time.Time
+}
+
+// EvidenceServiceImpl implements service.EvidenceService
+type EvidenceServiceImpl struct {
+	alertRepository repository.AlertRepository
+	frameBuffer     *CameraFrameBuffer
+	clipDirectory   string
+	options         EvidenceOptions
+
+	mu      sync.Mutex
+	pending []evidenceJob
+}
+
+// NewEvidenceService creates a new evidence service writing clips to clipDirectory
+func NewEvidenceService(
+	alertRepository repository.AlertRepository,
+	frameBuffer *CameraFrameBuffer,
+	clipDirectory string,
+	options EvidenceOptions,
+) service.EvidenceService {
+	if options.Format != EvidenceClipMJPEG {
+		options.Format = EvidenceClipGIF
+	}
+
+	return &EvidenceServiceImpl{
+		alertRepository: alertRepository,
+		frameBuffer:     frameBuffer,
+		clipDirectory:   clipDirectory,
+		options:         options,
+	}
+}
+
+// Run writes the clips of alerts once their frames are buffered, until ctx is done
+func (s *EvidenceServiceImpl) Run(ctx context.Context) {
+	if !s.frameBuffer.Enabled() {
+		return
+	}
+
+	if err := os.MkdirAll(s.clipDirectory, 0755); err != nil {
+		log.Printf("Failed to create evidence clip directory: %v", err)
+		return
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, job := range s.dueJobs(now) {
+				if err := s.writeClip(ctx, job); err != nil {
+					log.Printf("Failed to write evidence clip of alert %s: %v", job.alertID, err)
+				}
+			}
+		}
+	}
+}
+
+// CaptureClip queues the clip of a new alert. It is written once the frames after the detection
+// time are buffered. Alerts detected longer ago than the buffer keeps have no clip.
+func (s *EvidenceServiceImpl) CaptureClip(alert *entity.Alert) {
+	if !s.frameBuffer.Enabled() || alert.ID == "" || alert.CameraID == 0 {
+		return
+	}
+
+	detectedAt := alert.DetectedAt
+	if detectedAt.IsZero() {
+		detectedAt = time.Now()
+	}
+
+	if time.Since(detectedAt) > s.frameBuffer.Retention() {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending = append(s.pending, evidenceJob{
+		alertID:  alert.ID,
+		cameraID: alert.CameraID,
+		from:     detectedAt.Add(-s.options.Before),
+		to:       detectedAt.Add(s.options.After),
+	})
+}
+
+// GetClip returns the path of the evidence clip of an alert
+func (s *EvidenceServiceImpl) GetClip(ctx context.Context, alertID string) (string, error) {
+	alert, err := s.alertRepository.FindByID(ctx, alertID)
+	if err != nil {
+		return "", err
+	}
+
+	if alert.ClipPath == "" {
+		if s.isPending(alertID) {
+			return "", errors.New("clip is not ready yet")
+		}
+		return "", errors.New("clip not found")
+	}
+
+	if _, err := os.Stat(alert.ClipPath); err != nil {
+		return "", errors.New("clip not found")
+	}
+
+	return alert.ClipPath, nil
+}
+
+// dueJobs takes the queued clips whose frames are all buffered
+func (s *EvidenceServiceImpl) dueJobs(now time.Time) []evidenceJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []evidenceJob
+	waiting := s.pending[:0]
+	for _, job := range s.pending {
+		// One more frame interval so the last frame is written
+		if now.After(job.to.Add(s.frameBuffer.interval)) {
+			due = append(due, job)
+		} else {
+			waiting = append(waiting, job)
+		}
+	}
+	s.pending = waiting
+
+	return due
+}
+
+// isPending reports whether the clip of an alert is queued
+func (s *EvidenceServiceImpl) isPending(alertID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, job := range s.pending {
+		if job.alertID == alertID {
+			return true
+		}
+	}
+	return false
+}
+
+// writeClip freezes the buffered frames of a job into a clip and links it to the alert
+func (s *EvidenceServiceImpl) writeClip(ctx context.Context, job evidenceJob) error {
+	frames, err := s.frameBuffer.Frames(job.cameraID, job.from, job.to)
+	if err != nil {
+		return err
+	}
+
+	if len(frames) == 0 {
+		log.Printf("No buffered frames of camera %d for the evidence clip of alert %s", job.cameraID, job.alertID)
+		return nil
+	}
+
+	path := filepath.Join(s.clipDirectory, fmt.Sprintf("%s.%s", job.alertID, s.options.Format))
+	file, err := os.Create(path + ".tmp")
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	if s.options.Format == EvidenceClipMJPEG {
+		err = writeMJPEGClip(writer, frames)
+	} else {
+		err = writeGIFClip(writer, frames, s.frameBuffer.interval)
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(path+".tmp", path)
+	}
+	if err != nil {
+		os.Remove(path + ".tmp")
+		return err
+	}
+
+	return s.alertRepository.SetClipPath(ctx, job.alertID, path)
+}
+
+// writeMJPEGClip writes the frames one after the other, as played by common video players
+func writeMJPEGClip(w io.Writer, frames []BufferedFrame) error {
+	for _, frame := range frames {
+		data, err := os.ReadFile(frame.Path)
+		if err != nil {
+			// Pruned meanwhile
+			continue
+		}
+
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeGIFClip writes the frames as an animated GIF played at the pace they were taken.
+// lastDelay is how long the last frame is shown.
+func writeGIFClip(w io.Writer, frames []BufferedFrame, lastDelay time.Duration) error {
+	animation := &gif.GIF{}
+
+	for i, frame := range frames {
+		file, err := os.Open(frame.Path)
+		if err != nil {
+			continue
+		}
+
+		img, err := jpeg.Decode(file)
+		file.Close()
+		if err != nil {
+			continue
+		}
+
+		img = scaleDown(img, evidenceClipMaxWidth)
+
+		// The clip takes the size of its first frame, frames after a resolution change are left out
+		if len(animation.Image) > 0 && img.Bounds() != animation.Image[0].Bounds() {
+			continue
+		}
+
+		paletted := image.NewPaletted(img.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(paletted, img.Bounds(), img, img.Bounds().Min)
+
+		delay := lastDelay
+		if i+1 < len(frames) {
+			delay = frames[i+1].Time.Sub(frame.Time)
+		}
+
+		animation.Image = append(animation.Image, paletted)
+		animation.Delay = append(animation.Delay, gifDelay(delay))
+	}
+
+	if len(animation.Image) == 0 {
+		return errors.New("no readable frames")
+	}
+
+	return gif.EncodeAll(w, animation)
+}
+
+// gifDelay converts a frame duration to GIF delay units of 10ms. Browsers show shorter delays
+// than 20ms as 100ms, and long gaps are shortened to 5s.
+func gifDelay(d time.Duration) int {
+	delay := int(d / (10 * time.Millisecond))
+	if delay < 2 {
+		return 2
+	}
+	if delay > 500 {
+		return 500
+	}
+	return delay
+}
+
+// scaleDown shrinks an image to maxWidth by nearest neighbour, keeping its aspect ratio.
+// Images already narrow enough are returned as is.
+func scaleDown(img image.Image, maxWidth int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() <= maxWidth {
+		return img
+	}
+
+	height := bounds.Dy() * maxWidth / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	scaled := image.NewRGBA(image.Rect(0, 0, maxWidth, height))
+	for y := 0; y < height; y++ {
+		sourceY := bounds.Min.Y + y*bounds.Dy()/height
+		for x := 0; x < maxWidth; x++ {
+			sourceX := bounds.Min.X + x*bounds.Dx()/maxWidth
+			scaled.Set(x, y, img.At(sourceX, sourceY))
+		}
+	}
+
+	return scaled
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+	"people-counting/internal/domain/service"
+	"people-counting/pkg/watcher"
+)
+
+// Ensure IngestionLedgerServiceImpl can back both the API and the folder watcher
+var (
+	_ service.IngestionLedgerService = (*IngestionLedgerServiceImpl)(nil)
+	_ watcher.Ledger                 = (*IngestionLedgerServiceImpl)(nil)
+)
+
+// IngestionLedgerServiceImpl implements service.IngestionLedgerService and watcher.Ledger
+type IngestionLedgerServiceImpl struct {
+	ledgerRepository repository.IngestionLedgerRepository
+}
+
+// NewIngestionLedgerService creates a new ingestion ledger service
+func NewIngestionLedgerService(ledgerRepository repository.IngestionLedgerRepository) *IngestionLedgerServiceImpl {
+	return &IngestionLedgerServiceImpl{
+		ledgerRepository: ledgerRepository,
+	}
+}
+
+// GetHistory retrieves paginated ingestion history with filters
+func (s *IngestionLedgerServiceImpl) GetHistory(ctx context.Context, page, limit int, status, handler, search string) ([]entity.IngestionLedgerEntry, int64, error) {
+	// Use default pagination values if invalid
+	if page <= 0 {
+		page = 1
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+
+	filters := make(map[string]interface{})
+
+	if status != "" {
+		switch status {
+		case entity.IngestionStatusProcessing, entity.IngestionStatusRetrying, entity.IngestionStatusProcessed, entity.IngestionStatusFailed:
+			filters["status"] = status
+		default:
+			return nil, 0, errors.New("invalid status. Must be processing, retrying, processed, or failed")
+		}
+	}
+
+	if handler != "" {
+		filters["handler"] = handler
+	}
+
+	if search != "" {
+		filters["search"] = search
+	}
+
+	return s.ledgerRepository.FindAll(ctx, page, limit, filters)
+}
+
+// Lookup returns the ledger entry for a file, or nil when it has never been seen
+func (s *IngestionLedgerServiceImpl) Lookup(path, hash string) (*watcher.LedgerEntry, error) {
+	entry, err := s.ledgerRepository.FindByPathAndHash(context.Background(), path, hash)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &watcher.LedgerEntry{
+		Status:   entry.Status,
+		Attempts: entry.Attempts,
+	}, nil
+}
+
+// Begin records the start of a processing attempt
+func (s *IngestionLedgerServiceImpl) Begin(path, hash, handler string) error {
+	_, err := s.ledgerRepository.Begin(context.Background(), path, hash, handler)
+	return err
+}
+
+// Finish records the outcome of a processing attempt
+func (s *IngestionLedgerServiceImpl) Finish(path, hash, status, errText string) error {
+	return s.ledgerRepository.Finish(context.Background(), path, hash, status, errText)
+}
+
+// Forget removes the ledger entry so that a requeued file is processed again
+func (s *IngestionLedgerServiceImpl) Forget(path, hash string) error {
+	return s.ledgerRepository.Delete(context.Background(), path, hash)
+}
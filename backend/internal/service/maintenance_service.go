@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+	"people-counting/internal/domain/service"
+)
+
+// MaintenanceServiceImpl implements service.MaintenanceService
+type MaintenanceServiceImpl struct {
+	maintenanceWindowRepository repository.MaintenanceWindowRepository
+	cameraRepository            repository.CameraRepository
+	alertTypeRepository         repository.AlertTypeRepository
+	location                    *time.Location
+}
+
+// NewMaintenanceService creates a new maintenance service. Weekly schedules are read in the
+// given time zone, the local time zone when nil.
+func NewMaintenanceService(
+	maintenanceWindowRepository repository.MaintenanceWindowRepository,
+	cameraRepository repository.CameraRepository,
+	alertTypeRepository repository.AlertTypeRepository,
+	location *time.Location,
+) service.MaintenanceService {
+	if location == nil {
+		location = time.Local
+	}
+
+	return &MaintenanceServiceImpl{
+		maintenanceWindowRepository: maintenanceWindowRepository,
+		cameraRepository:            cameraRepository,
+		alertTypeRepository:         alertTypeRepository,
+		location:                    location,
+	}
+}
+
+// GetAllWindows retrieves maintenance windows with filters. With active set to true only the
+// windows in force now are returned.
+func (s *MaintenanceServiceImpl) GetAllWindows(ctx context.Context, enabled, cameraID, alertTypeID, active string) ([]entity.MaintenanceWindow, error) {
+	filters := make(map[string]interface{})
+
+	switch enabled {
+	case "true":
+		filters["enabled"] = true
+	case "false":
+		filters["enabled"] = false
+	}
+
+	if cameraID != "" {
+		id, err := strconv.ParseUint(cameraID, 10, 32)
+		if err != nil {
+			return nil, errors.New("invalid camera ID")
+		}
+		filters["camera_id"] = uint(id)
+	}
+
+	if alertTypeID != "" {
+		id, err := strconv.ParseUint(alertTypeID, 10, 32)
+		if err != nil {
+			return nil, errors.New("invalid alert type ID")
+		}
+		filters["alert_type_id"] = uint(id)
+	}
+
+	windows, err := s.maintenanceWindowRepository.FindAll(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	if active != "true" {
+		return windows, nil
+	}
+
+	now := time.Now()
+	activeWindows := make([]entity.MaintenanceWindow, 0, len(windows))
+	for _, window := range windows {
+		if window.Active(now, s.location) {
+			activeWindows = append(activeWindows, window)
+		}
+	}
+
+	return activeWindows, nil
+}
+
+// GetWindowByID retrieves a maintenance window by its ID
+func (s *MaintenanceServiceImpl) GetWindowByID(ctx context.Context, id uint) (*entity.MaintenanceWindow, error) {
+	return s.maintenanceWindowRepository.FindByID(ctx, id)
+}
+
+// CreateWindow creates a maintenance window
+func (s *MaintenanceServiceImpl) CreateWindow(ctx context.Context, window *entity.MaintenanceWindow) error {
+	if err := s.validateWindow(ctx, window); err != nil {
+		return err
+	}
+
+	if _, err := s.maintenanceWindowRepository.FindByName(ctx, window.Name); err == nil {
+		return errors.New("a maintenance window with the same name already exists")
+	}
+
+	return s.maintenanceWindowRepository.Create(ctx, window)
+}
+
+// UpdateWindow updates a maintenance window
+func (s *MaintenanceServiceImpl) UpdateWindow(ctx context.Context, window *entity.MaintenanceWindow) error {
+	if _, err := s.maintenanceWindowRepository.FindByID(ctx, window.ID); err != nil {
+		return err
+	}
+
+	if err := s.validateWindow(ctx, window); err != nil {
+		return err
+	}
+
+	if other, err := s.maintenanceWindowRepository.FindByName(ctx, window.Name); err == nil && other.ID != window.ID {
+		return errors.New("a maintenance window with the same name already exists")
+	}
+
+	return s.maintenanceWindowRepository.Update(ctx, window)
+}
+
+// DeleteWindow deletes a maintenance window
+func (s *MaintenanceServiceImpl) DeleteWindow(ctx context.Context, id uint) error {
+	return s.maintenanceWindowRepository.Delete(ctx, id)
+}
+
+// CheckSuppression returns why an alert of a camera is suppressed, empty when it is not. Alerts
+// are suppressed while their camera is in maintenance or a maintenance window covering them is
+// in force at their detection time.
+func (s *MaintenanceServiceImpl) CheckSuppression(ctx context.Context, alert *entity.Alert, camera *entity.Camera) (string, error) {
+	if camera != nil && camera.Status == "maintenance" {
+		return entity.AlertSuppressedByCameraMaintenance, nil
+	}
+
+	at := alert.DetectedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	windows, err := s.maintenanceWindowRepository.FindAll(ctx, map[string]interface{}{
+		"enabled":   true,
+		"not_ended": at,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for i := range windows {
+		window := &windows[i]
+		if window.Covers(alert, camera) && window.Active(at, s.location) {
+			return "maintenance window " + window.Name, nil
+		}
+	}
+
+	return "", nil
+}
+
+// validateWindow validates a maintenance window
+func (s *MaintenanceServiceImpl) validateWindow(ctx context.Context, window *entity.MaintenanceWindow) error {
+	window.Name = strings.TrimSpace(window.Name)
+	if window.Name == "" {
+		return errors.New("name is required")
+	}
+
+	window.Zone = strings.TrimSpace(window.Zone)
+	if window.CameraID == nil && window.Zone == "" && window.AlertTypeID == nil {
+		return errors.New("camera ID, zone or alert type ID is required")
+	}
+
+	if window.CameraID != nil {
+		if _, err := s.cameraRepository.FindByID(ctx, *window.CameraID); err != nil {
+			return errors.New("camera not found")
+		}
+	}
+
+	if window.AlertTypeID != nil {
+		if _, err := s.alertTypeRepository.FindByID(ctx, *window.AlertTypeID); err != nil {
+			return errors.New("alert type not found")
+		}
+	}
+
+	return window.ValidateSchedule()
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"people-counting/internal/domain/entity"
+)
+
+// Headers sent with every webhook. The signature is the hex HMAC-SHA256 of the timestamp, a
+// dot and the body, keyed with the channel secret, so receivers can reject replayed requests.
+const (
+	webhookEventHeader     = "X-Notification-Event"
+	webhookDeliveryHeader  = "X-Notification-Delivery"
+	webhookTimestampHeader = "X-Notification-Timestamp"
+	webhookSignatureHeader = "X-Notification-Signature"
+)
+
+const (
+	defaultSubjectTemplate = "[{{.Severity}}] {{.AlertType}} at {{.Camera}}"
+	defaultEmailTemplate   = `{{.Message}}
+{{if .Note}}
+{{.Note}}
+{{end}}
+Event:       {{.Event}}
+Alert type:  {{.AlertType}}
+Camera:      {{.Camera}}
+Severity:    {{.Severity}}
+Status:      {{.Status}}
+Occurrences: {{.Occurrences}}
+Detected at: {{.DetectedAt.Format "2006-01-02 15:04:05 MST"}}
+Alert ID:    {{.AlertID}}
+{{if .ImageURL}}Image:       {{.ImageURL}}
+{{end}}`
+)
+
+// SMTPOptions is the SMTP server used by email channels
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// permanentError marks a delivery failure that retrying cannot fix, such as a broken template
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+func isPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// notificationSender sends notifications through channels
+type notificationSender struct {
+	client  *http.Client
+	smtp    SMTPOptions
+	timeout time.Duration
+}
+
+func newNotificationSender(smtpOptions SMTPOptions, timeout time.Duration) *notificationSender {
+	return &notificationSender{
+		client:  &http.Client{Timeout: timeout},
+		smtp:    smtpOptions,
+		timeout: timeout,
+	}
+}
+
+// send delivers a message through a channel and returns the HTTP response status, if any
+func (s *notificationSender) send(ctx context.Context, channel *entity.NotificationChannel, delivery *entity.NotificationDelivery, message *entity.NotificationMessage) (int, error) {
+	switch channel.Type {
+	case entity.NotificationChannelWebhook:
+		return s.sendWebhook(ctx, channel, delivery, message)
+	case entity.NotificationChannelHTTP:
+		return s.sendHTTP(ctx, channel, message)
+	case entity.NotificationChannelEmail:
+		return 0, s.sendEmail(channel, message)
+	}
+
+	return 0, permanent(fmt.Errorf("unsupported channel type %q", channel.Type))
+}
+
+// sendWebhook posts the signed JSON payload of a message
+func (s *notificationSender) sendWebhook(ctx context.Context, channel *entity.NotificationChannel, delivery *entity.NotificationDelivery, message *entity.NotificationMessage) (int, error) {
+	now := time.Now()
+	body, err := json.Marshal(map[string]interface{}{
+		"delivery_id": delivery.ID,
+		"event":       message.Event,
+		"sent_at":     now,
+		"alert":       message,
+	})
+	if err != nil {
+		return 0, permanent(fmt.Errorf("failed to encode payload: %w", err))
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	headers := map[string]string{
+		"Content-Type":         "application/json",
+		webhookEventHeader:     message.Event,
+		webhookDeliveryHeader:  strconv.FormatUint(uint64(delivery.ID), 10),
+		webhookTimestampHeader: timestamp,
+		webhookSignatureHeader: "sha256=" + signWebhook(channel.Secret, timestamp, body),
+	}
+
+	return s.do(ctx, channel.Method, channel.URL, body, channel.Headers, headers)
+}
+
+// sendHTTP sends the rendered body template of a channel, or the JSON message without one
+func (s *notificationSender) sendHTTP(ctx context.Context, channel *entity.NotificationChannel, message *entity.NotificationMessage) (int, error) {
+	url, err := renderTemplate("url", channel.URL, message)
+	if err != nil {
+		return 0, permanent(err)
+	}
+
+	var body []byte
+	if channel.BodyTemplate != "" {
+		rendered, err := renderTemplate("body", channel.BodyTemplate, message)
+		if err != nil {
+			return 0, permanent(err)
+		}
+		body = []byte(rendered)
+	} else {
+		if body, err = json.Marshal(message); err != nil {
+			return 0, permanent(fmt.Errorf("failed to encode payload: %w", err))
+		}
+	}
+
+	return s.do(ctx, channel.Method, url, body, map[string]string{"Content-Type": "application/json"}, channel.Headers)
+}
+
+// do sends a request, later header sets override earlier ones. Responses other than 2xx fail.
+func (s *notificationSender) do(ctx context.Context, method, url string, body []byte, headerSets ...map[string]string) (int, error) {
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, permanent(fmt.Errorf("failed to create request: %w", err))
+	}
+
+	for _, set := range headerSets {
+		for key, value := range set {
+			req.Header.Set(key, value)
+		}
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+
+	return resp.StatusCode, nil
+}
+
+// sendEmail sends a plain text email to the recipients of a channel
+func (s *notificationSender) sendEmail(channel *entity.NotificationChannel, message *entity.NotificationMessage) error {
+	if s.smtp.Host == "" || s.smtp.From == "" {
+		return permanent(errors.New("SMTP is not configured, set SMTP_HOST and SMTP_FROM"))
+	}
+
+	recipients := channel.RecipientList()
+	if len(recipients) == 0 {
+		return permanent(errors.New("channel has no recipients"))
+	}
+
+	subjectTemplate := channel.SubjectTemplate
+	if subjectTemplate == "" {
+		subjectTemplate = defaultSubjectTemplate
+	}
+	subject, err := renderTemplate("subject", subjectTemplate, message)
+	if err != nil {
+		return permanent(err)
+	}
+
+	bodyTemplate := channel.BodyTemplate
+	if bodyTemplate == "" {
+		bodyTemplate = defaultEmailTemplate
+	}
+	body, err := renderTemplate("body", bodyTemplate, message)
+	if err != nil {
+		return permanent(err)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.smtp.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", strings.ReplaceAll(subject, "\n", " ")))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return s.sendMail(recipients, msg.Bytes())
+}
+
+// sendMail sends a message through the SMTP server, upgrading to TLS when the server offers it
+func (s *notificationSender) sendMail(recipients []string, msg []byte) error {
+	addr := net.JoinHostPort(s.smtp.Host, strconv.Itoa(s.smtp.Port))
+
+	conn, err := net.DialTimeout("tcp", addr, s.timeout)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, s.smtp.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.smtp.Host}); err != nil {
+			return err
+		}
+	}
+
+	if s.smtp.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.smtp.Username, s.smtp.Password, s.smtp.Host)); err != nil {
+			return permanent(fmt.Errorf("SMTP authentication failed: %w", err))
+		}
+	}
+
+	if err := client.Mail(s.smtp.From); err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(msg); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// signWebhook signs a webhook body sent at timestamp
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// templateFuncs are available in channel templates. json quotes a value for JSON bodies, such
+// as {"text": {{json .Message}}}.
+var templateFuncs = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		encoded, err := json.Marshal(value)
+		return string(encoded), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// parseTemplate parses a channel template
+func parseTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// renderTemplate renders a template with its data, such as a channel template with a message
+func renderTemplate(name, text string, data interface{}) (string, error) {
+	tmpl, err := parseTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", name, err)
+	}
+
+	return out.String(), nil
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"people-counting/internal/domain/entity"
+)
+
+func testMessage() *entity.NotificationMessage {
+	return &entity.NotificationMessage{
+		Event:       entity.NotificationEventCreated,
+		AlertID:     "alert-1",
+		AlertType:   "Intrusion",
+		CameraID:    7,
+		Camera:      "Gate",
+		Severity:    "high",
+		Status:      entity.AlertStatusNew,
+		Message:     `Person at "gate"`,
+		Occurrences: 1,
+		DetectedAt:  time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+// capturedRequest is a request received by a test HTTP server
+type capturedRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   []byte
+}
+
+func newCaptureServer(t *testing.T, status int) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+
+	requests := make(chan capturedRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- capturedRequest{method: r.Method, path: r.URL.Path, header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
+
+func TestSendWebhookSignsTimestampAndBody(t *testing.T) {
+	server, requests := newCaptureServer(t, http.StatusOK)
+	sender := newNotificationSender(SMTPOptions{}, 5*time.Second)
+
+	channel := &entity.NotificationChannel{
+		Type:    entity.NotificationChannelWebhook,
+		URL:     server.URL + "/hook",
+		Secret:  "s3cret",
+		Headers: map[string]string{"X-Custom": "yes"},
+	}
+	delivery := &entity.NotificationDelivery{ID: 42}
+
+	status, err := sender.send(context.Background(), channel, delivery, testMessage())
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+
+	req := <-requests
+	if req.method != http.MethodPost {
+		t.Errorf("method = %s, want POST", req.method)
+	}
+	if got := req.header.Get("X-Custom"); got != "yes" {
+		t.Errorf("custom header = %q, want yes", got)
+	}
+	if got := req.header.Get(webhookEventHeader); got != entity.NotificationEventCreated {
+		t.Errorf("event header = %q", got)
+	}
+	if got := req.header.Get(webhookDeliveryHeader); got != "42" {
+		t.Errorf("delivery header = %q, want 42", got)
+	}
+
+	timestamp := req.header.Get(webhookTimestampHeader)
+	if _, err := strconv.ParseInt(timestamp, 10, 64); err != nil {
+		t.Fatalf("timestamp header %q is not a unix time: %v", timestamp, err)
+	}
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(timestamp + "." + string(req.body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := req.header.Get(webhookSignatureHeader); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+
+	var payload struct {
+		DeliveryID uint                       `json:"delivery_id"`
+		Event      string                     `json:"event"`
+		Alert      entity.NotificationMessage `json:"alert"`
+	}
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if payload.DeliveryID != 42 || payload.Event != entity.NotificationEventCreated || payload.Alert.AlertID != "alert-1" {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+}
+
+func TestSendHTTPRendersTemplates(t *testing.T) {
+	server, requests := newCaptureServer(t, http.StatusAccepted)
+	sender := newNotificationSender(SMTPOptions{}, 5*time.Second)
+
+	channel := &entity.NotificationChannel{
+		Type:         entity.NotificationChannelHTTP,
+		URL:          server.URL + "/cameras/{{.CameraID}}",
+		Method:       http.MethodPut,
+		BodyTemplate: `{"text": {{json .Message}}, "severity": "{{upper .Severity}}"}`,
+	}
+
+	status, err := sender.send(context.Background(), channel, &entity.NotificationDelivery{ID: 1}, testMessage())
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if status != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202", status)
+	}
+
+	req := <-requests
+	if req.method != http.MethodPut {
+		t.Errorf("method = %s, want PUT", req.method)
+	}
+	if req.path != "/cameras/7" {
+		t.Errorf("path = %s, want /cameras/7", req.path)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(req.body, &body); err != nil {
+		t.Fatalf("body %s is not JSON: %v", req.body, err)
+	}
+	if body["text"] != `Person at "gate"` || body["severity"] != "HIGH" {
+		t.Errorf("unexpected body %v", body)
+	}
+}
+
+func TestSendHTTPFailures(t *testing.T) {
+	server, _ := newCaptureServer(t, http.StatusServiceUnavailable)
+	sender := newNotificationSender(SMTPOptions{}, 5*time.Second)
+
+	channel := &entity.NotificationChannel{Type: entity.NotificationChannelHTTP, URL: server.URL}
+	status, err := sender.send(context.Background(), channel, &entity.NotificationDelivery{}, testMessage())
+	if err == nil || isPermanent(err) {
+		t.Fatalf("err = %v, want a transient error", err)
+	}
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", status)
+	}
+
+	channel.BodyTemplate = "{{.Missing}}"
+	if _, err := sender.send(context.Background(), channel, &entity.NotificationDelivery{}, testMessage()); !isPermanent(err) {
+		t.Errorf("err = %v, want a permanent error for a broken template", err)
+	}
+}
+
+// smtpStandIn is a minimal SMTP server recording the messages it receives
+type smtpStandIn struct {
+	listener net.Listener
+	messages chan smtpMessage
+}
+
+type smtpMessage struct {
+	from       string
+	recipients []string
+	data       string
+}
+
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	server := &smtpStandIn{listener: listener, messages: make(chan smtpMessage, 10)}
+	go server.serve()
+	return server
+}
+
+func (s *smtpStandIn) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStandIn) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpStandIn) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) {
+		conn.Write([]byte(line + "\r\n"))
+	}
+
+	var message smtpMessage
+	reply("220 localhost ESMTP")
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			message.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			message.recipients = append(message.recipients, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			message.data = data.String()
+			s.messages <- message
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSendEmailThroughSMTP(t *testing.T) {
+	server := newSMTPStandIn(t)
+	sender := newNotificationSender(SMTPOptions{Host: "127.0.0.1", Port: server.port(), From: "alerts@example.com"}, 5*time.Second)
+
+	channel := &entity.NotificationChannel{
+		Type:       entity.NotificationChannelEmail,
+		Recipients: "ops@example.com, guard@example.com",
+	}
+
+	if _, err := sender.send(context.Background(), channel, &entity.NotificationDelivery{}, testMessage()); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	var message smtpMessage
+	select {
+	case message = <-server.messages:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+
+	if message.from != "alerts@example.com" {
+		t.Errorf("from = %q", message.from)
+	}
+	if strings.Join(message.recipients, ",") != "ops@example.com,guard@example.com" {
+		t.Errorf("recipients = %v", message.recipients)
+	}
+	if !strings.Contains(message.data, "Subject: [high] Intrusion at Gate\r\n") {
+		t.Errorf("subject missing from message:\n%s", message.data)
+	}
+	if !strings.Contains(message.data, "To: ops@example.com, guard@example.com\r\n") {
+		t.Errorf("recipients missing from message:\n%s", message.data)
+	}
+	if !strings.Contains(message.data, "Camera:      Gate\r\n") || !strings.Contains(message.data, "Alert ID:    alert-1\r\n") {
+		t.Errorf("default body not rendered:\n%s", message.data)
+	}
+}
+
+func TestSendEmailWithoutSMTPIsPermanent(t *testing.T) {
+	sender := newNotificationSender(SMTPOptions{}, time.Second)
+	channel := &entity.NotificationChannel{Type: entity.NotificationChannelEmail, Recipients: "ops@example.com"}
+
+	if _, err := sender.send(context.Background(), channel, &entity.NotificationDelivery{}, testMessage()); !isPermanent(err) {
+		t.Fatalf("err = %v, want a permanent error", err)
+	}
+}
@@ -0,0 +1,631 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+	"people-counting/internal/domain/service"
+)
+
+// NotificationOptions configures how notifications are delivered
+type NotificationOptions struct {
+	// Failed deliveries are retried with exponential backoff until MaxAttempts
+	MaxAttempts         int
+	RetryInitialBackoff time.Duration
+	RetryMaxBackoff     time.Duration
+	Timeout             time.Duration
+	// PublicURL is the address of this API as seen by recipients, used for image links
+	PublicURL string
+	SMTP      SMTPOptions
+}
+
+// NotificationServiceImpl implements service.NotificationService
+type NotificationServiceImpl struct {
+	notificationRepository repository.NotificationRepository
+	alertRepository        repository.AlertRepository
+	alertTypeRepository    repository.AlertTypeRepository
+	cameraRepository       repository.CameraRepository
+	options                NotificationOptions
+	sender                 *notificationSender
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(
+	notificationRepository repository.NotificationRepository,
+	alertRepository repository.AlertRepository,
+	alertTypeRepository repository.AlertTypeRepository,
+	cameraRepository repository.CameraRepository,
+	options NotificationOptions,
+) service.NotificationService {
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = 1
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 10 * time.Second
+	}
+
+	return &NotificationServiceImpl{
+		notificationRepository: notificationRepository,
+		alertRepository:        alertRepository,
+		alertTypeRepository:    alertTypeRepository,
+		cameraRepository:       cameraRepository,
+		options:                options,
+		sender:                 newNotificationSender(options.SMTP, options.Timeout),
+	}
+}
+
+// GetAllChannels retrieves all notification channels
+func (s *NotificationServiceImpl) GetAllChannels(ctx context.Context) ([]entity.NotificationChannel, error) {
+	return s.notificationRepository.FindChannels(ctx)
+}
+
+// GetChannelByID retrieves a notification channel by its ID
+func (s *NotificationServiceImpl) GetChannelByID(ctx context.Context, id uint) (*entity.NotificationChannel, error) {
+	return s.notificationRepository.FindChannelByID(ctx, id)
+}
+
+// CreateChannel creates a notification channel
+func (s *NotificationServiceImpl) CreateChannel(ctx context.Context, channel *entity.NotificationChannel) error {
+	if err := validateChannel(channel); err != nil {
+		return err
+	}
+
+	if _, err := s.notificationRepository.FindChannelByName(ctx, channel.Name); err == nil {
+		return errors.New("a notification channel with the same name already exists")
+	}
+
+	return s.notificationRepository.CreateChannel(ctx, channel)
+}
+
+// UpdateChannel updates a notification channel, an empty secret keeps the current one
+func (s *NotificationServiceImpl) UpdateChannel(ctx context.Context, channel *entity.NotificationChannel) error {
+	existing, err := s.notificationRepository.FindChannelByID(ctx, channel.ID)
+	if err != nil {
+		return err
+	}
+
+	if channel.Secret == "" {
+		channel.Secret = existing.Secret
+	}
+
+	if err := validateChannel(channel); err != nil {
+		return err
+	}
+
+	if other, err := s.notificationRepository.FindChannelByName(ctx, channel.Name); err == nil && other.ID != channel.ID {
+		return errors.New("a notification channel with the same name already exists")
+	}
+
+	channel.CreatedAt = existing.CreatedAt
+
+	return s.notificationRepository.UpdateChannel(ctx, channel)
+}
+
+// DeleteChannel deletes a notification channel and its routes
+func (s *NotificationServiceImpl) DeleteChannel(ctx context.Context, id uint) error {
+	return s.notificationRepository.DeleteChannel(ctx, id)
+}
+
+// TestChannel sends a sample notification through a channel, even a disabled one, and returns
+// the recorded delivery
+func (s *NotificationServiceImpl) TestChannel(ctx context.Context, id uint) (*entity.NotificationDelivery, error) {
+	channel, err := s.notificationRepository.FindChannelByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &entity.NotificationDelivery{
+		ChannelID: channel.ID,
+		Event:     entity.NotificationEventTest,
+		Status:    entity.NotificationStatusPending,
+	}
+	if err := s.notificationRepository.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	message := &entity.NotificationMessage{
+		Event:       entity.NotificationEventTest,
+		AlertType:   "Test",
+		Camera:      "Test camera",
+		Severity:    "low",
+		Status:      entity.AlertStatusNew,
+		Message:     fmt.Sprintf("Test notification from channel %s", channel.Name),
+		Occurrences: 1,
+		DetectedAt:  time.Now(),
+	}
+
+	// A test is a single attempt, it is never retried
+	s.attempt(ctx, channel, delivery, message, 1)
+
+	return delivery, nil
+}
+
+// GetAllRoutes retrieves notification routes, optionally of a channel
+func (s *NotificationServiceImpl) GetAllRoutes(ctx context.Context, channelID string) ([]entity.NotificationRoute, error) {
+	filters := make(map[string]interface{})
+
+	if channelID != "" {
+		id, err := strconv.ParseUint(channelID, 10, 32)
+		if err != nil {
+			return nil, errors.New("invalid channel ID")
+		}
+		filters["channel_id"] = uint(id)
+	}
+
+	return s.notificationRepository.FindRoutes(ctx, filters)
+}
+
+// GetRouteByID retrieves a notification route by its ID
+func (s *NotificationServiceImpl) GetRouteByID(ctx context.Context, id uint) (*entity.NotificationRoute, error) {
+	return s.notificationRepository.FindRouteByID(ctx, id)
+}
+
+// CreateRoute creates a notification route
+func (s *NotificationServiceImpl) CreateRoute(ctx context.Context, route *entity.NotificationRoute) error {
+	if err := s.validateRoute(ctx, route); err != nil {
+		return err
+	}
+
+	return s.notificationRepository.CreateRoute(ctx, route)
+}
+
+// UpdateRoute updates a notification route
+func (s *NotificationServiceImpl) UpdateRoute(ctx context.Context, route *entity.NotificationRoute) error {
+	existing, err := s.notificationRepository.FindRouteByID(ctx, route.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.validateRoute(ctx, route); err != nil {
+		return err
+	}
+
+	route.CreatedAt = existing.CreatedAt
+
+	return s.notificationRepository.UpdateRoute(ctx, route)
+}
+
+// DeleteRoute deletes a notification route
+func (s *NotificationServiceImpl) DeleteRoute(ctx context.Context, id uint) error {
+	return s.notificationRepository.DeleteRoute(ctx, id)
+}
+
+// GetDeliveries retrieves paginated notification deliveries with filters
+func (s *NotificationServiceImpl) GetDeliveries(ctx context.Context, page, limit int, status, channelID, alertID string) ([]entity.NotificationDelivery, int64, error) {
+	// Use default pagination values if invalid
+	if page <= 0 {
+		page = 1
+	}
+
+	if limit <= 0 {
+		limit = 50
+	}
+
+	filters := make(map[string]interface{})
+
+	if status != "" {
+		if !entity.IsNotificationStatus(status) {
+			return nil, 0, errors.New("invalid status. Must be pending, sent, or failed")
+		}
+		filters["status"] = status
+	}
+
+	if channelID != "" {
+		id, err := strconv.ParseUint(channelID, 10, 32)
+		if err != nil {
+			return nil, 0, errors.New("invalid channel ID")
+		}
+		filters["channel_id"] = uint(id)
+	}
+
+	if alertID != "" {
+		filters["alert_id"] = alertID
+	}
+
+	return s.notificationRepository.FindDeliveries(ctx, page, limit, filters)
+}
+
+// RetryDelivery sends a pending or failed delivery again right away
+func (s *NotificationServiceImpl) RetryDelivery(ctx context.Context, id uint) (*entity.NotificationDelivery, error) {
+	delivery, err := s.notificationRepository.FindDeliveryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if delivery.Status == entity.NotificationStatusSent {
+		return nil, errors.New("notification was already sent")
+	}
+
+	if delivery.Event == entity.NotificationEventTest {
+		return nil, errors.New("test notifications cannot be retried")
+	}
+
+	channel, message, err := s.prepare(ctx, delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	// A manual retry always gets one more attempt, even after the delivery gave up
+	if err := s.attempt(ctx, channel, delivery, message, max(delivery.Attempts+1, s.options.MaxAttempts)); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// NotifyAlert records a delivery for every channel routed the event of an alert, then sends
+// them in the background. Deliveries that fail are retried by Run.
+func (s *NotificationServiceImpl) NotifyAlert(ctx context.Context, alert *entity.Alert, event string) {
+	routes, err := s.notificationRepository.FindRoutes(ctx, map[string]interface{}{"enabled": true})
+	if err != nil {
+		log.Printf("Failed to load notification routes for alert %s: %v", alert.ID, err)
+		return
+	}
+
+	if len(routes) == 0 {
+		return
+	}
+
+	channels, err := s.notificationRepository.FindChannels(ctx)
+	if err != nil {
+		log.Printf("Failed to load notification channels for alert %s: %v", alert.ID, err)
+		return
+	}
+
+	enabled := make(map[uint]*entity.NotificationChannel)
+	for i := range channels {
+		if channels[i].Enabled {
+			enabled[channels[i].ID] = &channels[i]
+		}
+	}
+
+	// An alert is sent once per channel, whichever routes match it
+	sent := make(map[uint]bool)
+	var deliveries []*entity.NotificationDelivery
+
+	for i := range routes {
+		route := &routes[i]
+		if enabled[route.ChannelID] == nil || sent[route.ChannelID] || !route.Matches(alert, event) {
+			continue
+		}
+		sent[route.ChannelID] = true
+
+		// The first attempt is made right away, the retry loop only picks the delivery up if
+		// that attempt never records its outcome
+		next := time.Now().Add(s.options.RetryInitialBackoff + s.options.Timeout)
+		delivery := &entity.NotificationDelivery{
+			ChannelID:     route.ChannelID,
+			RouteID:       &route.ID,
+			AlertID:       alert.ID,
+			Event:         event,
+			Status:        entity.NotificationStatusPending,
+			NextAttemptAt: &next,
+		}
+		if err := s.notificationRepository.CreateDelivery(ctx, delivery); err != nil {
+			log.Printf("Failed to record notification of alert %s for channel %d: %v", alert.ID, route.ChannelID, err)
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if len(deliveries) == 0 {
+		return
+	}
+
+	message := s.buildMessage(ctx, alert, event)
+
+	// Every channel is sent on its own, a slow channel does not hold the others back
+	for _, delivery := range deliveries {
+		go s.attempt(context.Background(), enabled[delivery.ChannelID], delivery, message, s.options.MaxAttempts)
+	}
+}
+
+// NotifyChannel sends an event of an alert straight to a channel, whatever its routes, then
+// retries it like routed deliveries
+func (s *NotificationServiceImpl) NotifyChannel(ctx context.Context, alert *entity.Alert, channelID uint, event, note string) error {
+	channel, err := s.notificationRepository.FindChannelByID(ctx, channelID)
+	if err != nil {
+		return err
+	}
+
+	if !channel.Enabled {
+		return fmt.Errorf("notification channel %s is disabled", channel.Name)
+	}
+
+	next := time.Now().Add(s.options.RetryInitialBackoff + s.options.Timeout)
+	delivery := &entity.NotificationDelivery{
+		ChannelID:     channel.ID,
+		AlertID:       alert.ID,
+		Event:         event,
+		Note:          note,
+		Status:        entity.NotificationStatusPending,
+		NextAttemptAt: &next,
+	}
+	if err := s.notificationRepository.CreateDelivery(ctx, delivery); err != nil {
+		return err
+	}
+
+	message := s.buildMessage(ctx, alert, event)
+	message.Note = note
+
+	go s.attempt(context.Background(), channel, delivery, message, s.options.MaxAttempts)
+
+	return nil
+}
+
+// Run retries due deliveries every interval until ctx is cancelled
+func (s *NotificationServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.retryDue(ctx)
+		}
+	}
+}
+
+func (s *NotificationServiceImpl) retryDue(ctx context.Context) {
+	deliveries, err := s.notificationRepository.FindDueDeliveries(ctx, time.Now(), 100)
+	if err != nil {
+		log.Printf("Failed to load due notifications: %v", err)
+		return
+	}
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+
+		channel, message, err := s.prepare(ctx, delivery)
+		if err != nil {
+			// The channel or alert is gone, there is nothing left to send
+			s.finish(ctx, delivery, 0, permanent(err), s.options.MaxAttempts)
+			continue
+		}
+
+		s.attempt(ctx, channel, delivery, message, s.options.MaxAttempts)
+	}
+}
+
+// prepare loads the channel and message of a stored delivery
+func (s *NotificationServiceImpl) prepare(ctx context.Context, delivery *entity.NotificationDelivery) (*entity.NotificationChannel, *entity.NotificationMessage, error) {
+	channel, err := s.notificationRepository.FindChannelByID(ctx, delivery.ChannelID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	alert, err := s.alertRepository.FindByID(ctx, delivery.AlertID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	message := s.buildMessage(ctx, alert, delivery.Event)
+	message.Note = delivery.Note
+
+	return channel, message, nil
+}
+
+// attempt claims the next attempt of a delivery, sends it and records the outcome. The claim
+// keeps new alerts, the retry loop and manual retries from sending a delivery twice, it fails
+// when the delivery is already being sent or was sent.
+func (s *NotificationServiceImpl) attempt(ctx context.Context, channel *entity.NotificationChannel, delivery *entity.NotificationDelivery, message *entity.NotificationMessage, maxAttempts int) error {
+	// The retry loop picks the delivery up again if the attempt never records its outcome
+	leaseUntil := time.Now().Add(s.options.RetryInitialBackoff + s.options.Timeout)
+	claimed, err := s.notificationRepository.ClaimDelivery(ctx, delivery.ID, delivery.Attempts, leaseUntil)
+	if err != nil {
+		log.Printf("Failed to claim notification %d: %v", delivery.ID, err)
+		return err
+	}
+	if !claimed {
+		return errors.New("notification is already being sent")
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, s.options.Timeout)
+	defer cancel()
+
+	delivery.Attempts++
+	status, err := s.sender.send(sendCtx, channel, delivery, message)
+	s.finish(ctx, delivery, status, err, maxAttempts)
+
+	return nil
+}
+
+// finish records the outcome of an attempt, scheduling the next one after a failure
+func (s *NotificationServiceImpl) finish(ctx context.Context, delivery *entity.NotificationDelivery, status int, sendErr error, maxAttempts int) {
+	now := time.Now()
+	delivery.ResponseStatus = status
+	delivery.NextAttemptAt = nil
+
+	switch {
+	case sendErr == nil:
+		delivery.Status = entity.NotificationStatusSent
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	case isPermanent(sendErr) || delivery.Attempts >= maxAttempts:
+		delivery.Status = entity.NotificationStatusFailed
+		delivery.LastError = sendErr.Error()
+	default:
+		next := now.Add(s.backoff(delivery.Attempts))
+		delivery.Status = entity.NotificationStatusPending
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = &next
+	}
+
+	if sendErr != nil {
+		log.Printf("Notification %d of alert %s through channel %d failed (attempt %d): %v",
+			delivery.ID, delivery.AlertID, delivery.ChannelID, delivery.Attempts, sendErr)
+	}
+
+	if err := s.notificationRepository.UpdateDelivery(ctx, delivery); err != nil {
+		log.Printf("Failed to record notification %d: %v", delivery.ID, err)
+	}
+}
+
+// backoff returns the delay before the attempt after the given number of attempts
+func (s *NotificationServiceImpl) backoff(attempts int) time.Duration {
+	delay := s.options.RetryInitialBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if s.options.RetryMaxBackoff > 0 && delay >= s.options.RetryMaxBackoff {
+			return s.options.RetryMaxBackoff
+		}
+	}
+	return delay
+}
+
+// buildMessage describes an alert event, resolving the names of its alert type and camera
+func (s *NotificationServiceImpl) buildMessage(ctx context.Context, alert *entity.Alert, event string) *entity.NotificationMessage {
+	message := &entity.NotificationMessage{
+		Event:       event,
+		AlertID:     alert.ID,
+		AlertType:   alert.AlertType.Name,
+		CameraID:    alert.CameraID,
+		Severity:    alert.Severity,
+		Status:      currentAlertStatus(alert),
+		Message:     alert.Message,
+		Occurrences: max(alert.Occurrences, 1),
+		DetectedAt:  alert.DetectedAt,
+	}
+
+	if message.AlertType == "" {
+		if alertType, err := s.alertTypeRepository.FindByID(ctx, alert.AlertTypeID); err == nil {
+			message.AlertType = alertType.Name
+		}
+	}
+
+	if alert.Camera != nil {
+		message.Camera = alert.Camera.Name
+	} else if alert.CameraID != 0 {
+		message.Camera = fmt.Sprintf("Camera %d", alert.CameraID)
+		if camera, err := s.cameraRepository.FindByID(ctx, alert.CameraID); err == nil {
+			message.Camera = camera.Name
+		}
+	}
+
+	if alert.ImageURL != "" && s.options.PublicURL != "" {
+		filename := filepath.Base(strings.ReplaceAll(alert.ImageURL, "\\", "/"))
+		alertTypeName := strings.ToLower(strings.ReplaceAll(message.AlertType, " ", "-"))
+		message.ImageURL = fmt.Sprintf("%s/api/images/alerts/%s/%s", strings.TrimRight(s.options.PublicURL, "/"), alertTypeName, filename)
+	}
+
+	return message
+}
+
+// validateChannel validates a channel and applies its defaults
+func validateChannel(channel *entity.NotificationChannel) error {
+	channel.Name = strings.TrimSpace(channel.Name)
+	if channel.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if !entity.IsNotificationChannelType(channel.Type) {
+		return errors.New("invalid channel type. Must be webhook, email, or http")
+	}
+
+	switch channel.Type {
+	case entity.NotificationChannelWebhook, entity.NotificationChannelHTTP:
+		// Templated URLs are checked once rendered
+		if !strings.Contains(channel.URL, "{{") {
+			parsed, err := url.Parse(channel.URL)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return errors.New("invalid url. Must be an http or https URL")
+			}
+		}
+
+		channel.Method = strings.ToUpper(channel.Method)
+		switch channel.Method {
+		case "":
+			channel.Method = http.MethodPost
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodGet:
+		default:
+			return errors.New("invalid method. Must be POST, PUT, PATCH, or GET")
+		}
+
+		if channel.Type == entity.NotificationChannelWebhook && channel.Secret == "" {
+			return errors.New("secret is required for webhook channels")
+		}
+
+		if channel.Type == entity.NotificationChannelHTTP {
+			if _, err := parseTemplate("url", channel.URL); err != nil {
+				return err
+			}
+		}
+	case entity.NotificationChannelEmail:
+		recipients := channel.RecipientList()
+		if len(recipients) == 0 {
+			return errors.New("recipients are required for email channels")
+		}
+		for _, recipient := range recipients {
+			if _, err := mail.ParseAddress(recipient); err != nil {
+				return fmt.Errorf("invalid recipient %q", recipient)
+			}
+		}
+
+		if _, err := parseTemplate("subject", channel.SubjectTemplate); err != nil {
+			return err
+		}
+	}
+
+	if _, err := parseTemplate("body", channel.BodyTemplate); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateRoute validates a route and normalizes its events
+func (s *NotificationServiceImpl) validateRoute(ctx context.Context, route *entity.NotificationRoute) error {
+	route.Name = strings.TrimSpace(route.Name)
+	if route.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if route.ChannelID == 0 {
+		return errors.New("channel ID is required")
+	}
+
+	if _, err := s.notificationRepository.FindChannelByID(ctx, route.ChannelID); err != nil {
+		return err
+	}
+
+	if route.AlertTypeID != nil {
+		if _, err := s.alertTypeRepository.FindByID(ctx, *route.AlertTypeID); err != nil {
+			return errors.New("alert type not found")
+		}
+	}
+
+	if route.CameraID != nil {
+		if _, err := s.cameraRepository.FindByID(ctx, *route.CameraID); err != nil {
+			return errors.New("camera not found")
+		}
+	}
+
+	if route.MinSeverity != "" && entity.AlertSeverityRank(route.MinSeverity) < 0 {
+		return errors.New("invalid severity")
+	}
+
+	if route.Events != "" {
+		events := route.EventList()
+		for _, event := range events {
+			if !entity.IsNotificationEvent(event) {
+				return fmt.Errorf("invalid event %q, use created, escalated, or resolved", event)
+			}
+		}
+		route.Events = strings.Join(events, ",")
+	}
+
+	return nil
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"people-counting/internal/domain/entity"
+)
+
+// fakeNotificationRepository keeps deliveries in memory, claiming them like the database does
+type fakeNotificationRepository struct {
+	mu         sync.Mutex
+	deliveries map[uint]entity.NotificationDelivery
+	nextID     uint
+}
+
+func newFakeNotificationRepository() *fakeNotificationRepository {
+	return &fakeNotificationRepository{deliveries: make(map[uint]entity.NotificationDelivery)}
+}
+
+func (r *fakeNotificationRepository) delivery(id uint) entity.NotificationDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.deliveries[id]
+}
+
+func (r *fakeNotificationRepository) FindChannels(ctx context.Context) ([]entity.NotificationChannel, error) {
+	return nil, nil
+}
+
+func (r *fakeNotificationRepository) FindChannelByID(ctx context.Context, id uint) (*entity.NotificationChannel, error) {
+	return nil, errors.New("notification channel not found")
+}
+
+func (r *fakeNotificationRepository) FindChannelByName(ctx context.Context, name string) (*entity.NotificationChannel, error) {
+	return nil, errors.New("notification channel not found")
+}
+
+func (r *fakeNotificationRepository) CreateChannel(ctx context.Context, channel *entity.NotificationChannel) error {
+	return nil
+}
+
+func (r *fakeNotificationRepository) UpdateChannel(ctx context.Context, channel *entity.NotificationChannel) error {
+	return nil
+}
+
+func (r *fakeNotificationRepository) DeleteChannel(ctx context.Context, id uint) error {
+	return nil
+}
+
+func (r *fakeNotificationRepository) FindRoutes(ctx context.Context, filters map[string]interface{}) ([]entity.NotificationRoute, error) {
+	return nil, nil
+}
+
+func (r *fakeNotificationRepository) FindRouteByID(ctx context.Context, id uint) (*entity.NotificationRoute, error) {
+	return nil, errors.New("notification route not found")
+}
+
+func (r *fakeNotificationRepository) CreateRoute(ctx context.Context, route *entity.NotificationRoute) error {
+	return nil
+}
+
+func (r *fakeNotificationRepository) UpdateRoute(ctx context.Context, route *entity.NotificationRoute) error {
+	return nil
+}
+
+func (r *fakeNotificationRepository) DeleteRoute(ctx context.Context, id uint) error {
+	return nil
+}
+
+func (r *fakeNotificationRepository) FindDeliveries(ctx context.Context, page, limit int, filters map[string]interface{}) ([]entity.NotificationDelivery, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *fakeNotificationRepository) FindDeliveryByID(ctx context.Context, id uint) (*entity.NotificationDelivery, error) {
+	delivery := r.delivery(id)
+	return &delivery, nil
+}
+
+func (r *fakeNotificationRepository) FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.NotificationDelivery, error) {
+	return nil, nil
+}
+
+func (r *fakeNotificationRepository) CreateDelivery(ctx context.Context, delivery *entity.NotificationDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	delivery.ID = r.nextID
+	r.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (r *fakeNotificationRepository) ClaimDelivery(ctx context.Context, id uint, attempts int, leaseUntil time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delivery, ok := r.deliveries[id]
+	if !ok || delivery.Attempts != attempts || delivery.Status == entity.NotificationStatusSent {
+		return false, nil
+	}
+
+	delivery.Attempts++
+	delivery.NextAttemptAt = &leaseUntil
+	r.deliveries[id] = delivery
+	return true, nil
+}
+
+func (r *fakeNotificationRepository) UpdateDelivery(ctx context.Context, delivery *entity.NotificationDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func newTestNotificationService(repo *fakeNotificationRepository, options NotificationOptions) *NotificationServiceImpl {
+	return &NotificationServiceImpl{
+		notificationRepository: repo,
+		options:                options,
+		sender:                 newNotificationSender(options.SMTP, options.Timeout),
+	}
+}
+
+func TestAttemptRetriesWithBackoffUntilMaxAttempts(t *testing.T) {
+	server, _ := newCaptureServer(t, http.StatusInternalServerError)
+	repo := newFakeNotificationRepository()
+	svc := newTestNotificationService(repo, NotificationOptions{
+		MaxAttempts:         3,
+		RetryInitialBackoff: time.Minute,
+		RetryMaxBackoff:     90 * time.Second,
+		Timeout:             5 * time.Second,
+	})
+
+	channel := &entity.NotificationChannel{Type: entity.NotificationChannelHTTP, URL: server.URL}
+	delivery := &entity.NotificationDelivery{Status: entity.NotificationStatusPending}
+	repo.CreateDelivery(context.Background(), delivery)
+
+	wantBackoffs := []time.Duration{time.Minute, 90 * time.Second}
+	for i, want := range wantBackoffs {
+		before := time.Now()
+		if err := svc.attempt(context.Background(), channel, delivery, testMessage(), 3); err != nil {
+			t.Fatalf("attempt %d: %v", i+1, err)
+		}
+
+		stored := repo.delivery(delivery.ID)
+		if stored.Status != entity.NotificationStatusPending || stored.Attempts != i+1 {
+			t.Fatalf("after attempt %d: status %s, attempts %d", i+1, stored.Status, stored.Attempts)
+		}
+		if stored.ResponseStatus != http.StatusInternalServerError || stored.LastError == "" {
+			t.Errorf("after attempt %d: response %d, error %q", i+1, stored.ResponseStatus, stored.LastError)
+		}
+		if stored.NextAttemptAt == nil {
+			t.Fatalf("after attempt %d: no next attempt", i+1)
+		}
+		if delay := stored.NextAttemptAt.Sub(before); delay < want || delay > want+time.Second {
+			t.Errorf("after attempt %d: next attempt in %v, want %v", i+1, delay, want)
+		}
+	}
+
+	if err := svc.attempt(context.Background(), channel, delivery, testMessage(), 3); err != nil {
+		t.Fatalf("last attempt: %v", err)
+	}
+	stored := repo.delivery(delivery.ID)
+	if stored.Status != entity.NotificationStatusFailed || stored.Attempts != 3 || stored.NextAttemptAt != nil {
+		t.Errorf("after the last attempt: status %s, attempts %d, next %v", stored.Status, stored.Attempts, stored.NextAttemptAt)
+	}
+}
+
+func TestAttemptRecordsSentDelivery(t *testing.T) {
+	server, _ := newCaptureServer(t, http.StatusOK)
+	repo := newFakeNotificationRepository()
+	svc := newTestNotificationService(repo, NotificationOptions{MaxAttempts: 3, RetryInitialBackoff: time.Minute, Timeout: 5 * time.Second})
+
+	channel := &entity.NotificationChannel{Type: entity.NotificationChannelHTTP, URL: server.URL}
+	delivery := &entity.NotificationDelivery{Status: entity.NotificationStatusPending}
+	repo.CreateDelivery(context.Background(), delivery)
+
+	if err := svc.attempt(context.Background(), channel, delivery, testMessage(), 3); err != nil {
+		t.Fatalf("attempt: %v", err)
+	}
+
+	stored := repo.delivery(delivery.ID)
+	if stored.Status != entity.NotificationStatusSent || stored.DeliveredAt == nil || stored.NextAttemptAt != nil {
+		t.Errorf("status %s, delivered %v, next %v", stored.Status, stored.DeliveredAt, stored.NextAttemptAt)
+	}
+
+	// A sent delivery is never claimed again
+	if err := svc.attempt(context.Background(), channel, delivery, testMessage(), 3); err == nil {
+		t.Error("a sent delivery was sent again")
+	}
+}
+
+func TestAttemptPermanentFailureIsNotRetried(t *testing.T) {
+	repo := newFakeNotificationRepository()
+	svc := newTestNotificationService(repo, NotificationOptions{MaxAttempts: 5, RetryInitialBackoff: time.Minute, Timeout: time.Second})
+
+	channel := &entity.NotificationChannel{Type: entity.NotificationChannelHTTP, URL: "http://127.0.0.1", BodyTemplate: "{{.Missing}}"}
+	delivery := &entity.NotificationDelivery{Status: entity.NotificationStatusPending}
+	repo.CreateDelivery(context.Background(), delivery)
+
+	if err := svc.attempt(context.Background(), channel, delivery, testMessage(), 5); err != nil {
+		t.Fatalf("attempt: %v", err)
+	}
+
+	stored := repo.delivery(delivery.ID)
+	if stored.Status != entity.NotificationStatusFailed || stored.Attempts != 1 || stored.NextAttemptAt != nil {
+		t.Errorf("status %s, attempts %d, next %v", stored.Status, stored.Attempts, stored.NextAttemptAt)
+	}
+}
+
+func TestAttemptClaimSendsOnce(t *testing.T) {
+	server, requests := newCaptureServer(t, http.StatusOK)
+	repo := newFakeNotificationRepository()
+	svc := newTestNotificationService(repo, NotificationOptions{MaxAttempts: 3, RetryInitialBackoff: time.Minute, Timeout: 5 * time.Second})
+
+	channel := &entity.NotificationChannel{Type: entity.NotificationChannelHTTP, URL: server.URL}
+	created := &entity.NotificationDelivery{Status: entity.NotificationStatusPending}
+	repo.CreateDelivery(context.Background(), created)
+
+	// The first attempt and the retry loop race for the same stored delivery
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		delivery := repo.delivery(created.ID)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- svc.attempt(context.Background(), channel, &delivery, testMessage(), 3)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d attempts claimed the delivery, want 1", succeeded)
+	}
+	if len(requests) != 1 {
+		t.Errorf("%d requests sent, want 1", len(requests))
+	}
+}
+
+func TestBackoffDoublesUpToMax(t *testing.T) {
+	svc := &NotificationServiceImpl{options: NotificationOptions{RetryInitialBackoff: time.Second, RetryMaxBackoff: 10 * time.Second}}
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, delay := range want {
+		if got := svc.backoff(i + 1); got != delay {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, delay)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"sort"
 	"strconv"
 	"time"
 
@@ -32,14 +33,25 @@ func parseFlexibleDate(dateStr string) (time.Time, error) {
 // PeopleCountServiceImpl implements service.PeopleCountService
 type PeopleCountServiceImpl struct {
 	peopleCountRepository repository.PeopleCountRepository
+	zoneRepository        repository.ZoneRepository
+	cameraConfigService   service.CameraConfigService
+	occupancyReset        entity.OccupancyReset
 }
 
-// NewPeopleCountService creates a new people count service
+// NewPeopleCountService creates a new people count service, live occupancy restarts
+// from zero every day at occupancyReset. Counts record the configuration version of their
+// camera from cameraConfigService.
 func NewPeopleCountService(
 	peopleCountRepository repository.PeopleCountRepository,
+	zoneRepository repository.ZoneRepository,
+	cameraConfigService service.CameraConfigService,
+	occupancyReset entity.OccupancyReset,
 ) service.PeopleCountService {
 	return &PeopleCountServiceImpl{
 		peopleCountRepository: peopleCountRepository,
+		zoneRepository:        zoneRepository,
+		cameraConfigService:   cameraConfigService,
+		occupancyReset:        occupancyReset,
 	}
 }
 
@@ -51,8 +63,9 @@ func (s *PeopleCountServiceImpl) GetAlertByID(ctx context.Context, id string) (*
 	return s.peopleCountRepository.FindByID(ctx, id)
 }
 
-// GetAllCounts retrieves paginated people count records
-func (s *PeopleCountServiceImpl) GetAllCounts(ctx context.Context, page, limit int, areaID, from, to string, includeArea bool) ([]entity.PeopleCount, int64, error) {
+// GetAllCounts retrieves paginated people count records, of the cameras of a zone and of the
+// zones inside it when zoneID is set
+func (s *PeopleCountServiceImpl) GetAllCounts(ctx context.Context, page, limit int, zoneID, from, to string, includeZones bool) ([]entity.PeopleCount, int64, error) {
 	// Use default pagination values if invalid
 	if page <= 0 {
 		page = 1
@@ -65,14 +78,11 @@ func (s *PeopleCountServiceImpl) GetAllCounts(ctx context.Context, page, limit i
 	// Prepare filters
 	filters := make(map[string]interface{})
 
-	// Add area filter if provided
-	if areaID != "" {
-		id, err := strconv.ParseUint(areaID, 10, 64)
-		if err != nil {
-			return nil, 0, errors.New("invalid area ID")
+	// Add zone filter if provided
+	if zoneID != "" {
+		if _, _, err := zoneCameraFilter(ctx, s.zoneRepository, zoneID, filters); err != nil {
+			return nil, 0, err
 		}
-
-		filters["area_id"] = uint(id)
 	}
 
 	// Add time range filters if provided
@@ -92,15 +102,19 @@ func (s *PeopleCountServiceImpl) GetAllCounts(ctx context.Context, page, limit i
 		filters["to"] = toTime
 	}
 
-	// Add include area flag if requested
-	if includeArea {
-		filters["include_area"] = true
+	// Add include zones flag if requested, the camera of each count comes with its zones
+	if includeZones {
+		filters["include_zones"] = true
 	}
 
 	return s.peopleCountRepository.FindAll(ctx, page, limit, filters)
 }
 
 func (s *PeopleCountServiceImpl) CreatePeopleCount(ctx context.Context, counting *entity.PeopleCount) error {
+	if err := s.resolveConfigVersion(ctx, counting); err != nil {
+		return err
+	}
+
 	return s.peopleCountRepository.Create(ctx, counting)
 }
 
@@ -110,9 +124,25 @@ func (s *PeopleCountServiceImpl) UpdatePeopleCount(ctx context.Context, counting
 		return errors.New("people count ID is required")
 	}
 
+	if err := s.resolveConfigVersion(ctx, counting); err != nil {
+		return err
+	}
+
 	return s.peopleCountRepository.Update(ctx, counting)
 }
 
+// resolveConfigVersion sets the configuration version a count was made with, the version
+// reported by the device when it sent one
+func (s *PeopleCountServiceImpl) resolveConfigVersion(ctx context.Context, counting *entity.PeopleCount) error {
+	version, err := resolveConfigVersion(ctx, s.cameraConfigService, counting.CameraID, counting.ConfigVersion)
+	if err != nil {
+		return err
+	}
+
+	counting.ConfigVersion = version
+	return nil
+}
+
 func (s *PeopleCountServiceImpl) GetByID(ctx context.Context, id string) (*entity.PeopleCount, error) {
 	if id == "" {
 		return nil, errors.New("people count ID is required")
@@ -157,12 +187,24 @@ func (s *PeopleCountServiceImpl) RecordCount(ctx context.Context, count *entity.
 		count.Timestamp = time.Now()
 	}
 
+	if err := s.resolveConfigVersion(ctx, count); err != nil {
+		return err
+	}
+
 	return s.peopleCountRepository.Create(ctx, count)
 }
 
-// GetCountsSummary retrieves a summary of current people counts
-func (s *PeopleCountServiceImpl) GetCountsSummary(ctx context.Context, from, to string) (*entity.CountSummary, error) {
+// GetCountsSummary retrieves a summary of current people counts, of the cameras of a zone and
+// of the zones inside it when zoneID is set. The summary is broken down by the zones directly
+// inside that zone, or by site.
+func (s *PeopleCountServiceImpl) GetCountsSummary(ctx context.Context, zoneID, from, to string) (*entity.CountSummary, error) {
 	filters := make(map[string]interface{})
+
+	id, tree, err := zoneCameraFilter(ctx, s.zoneRepository, zoneID, filters)
+	if err != nil {
+		return nil, err
+	}
+
 	if from != "" {
 		fromTime, err := parseFlexibleDate(from)
 		if err != nil {
@@ -179,11 +221,45 @@ func (s *PeopleCountServiceImpl) GetCountsSummary(ctx context.Context, from, to
 		filters["to"] = toTime
 	}
 
-	return s.peopleCountRepository.GetSummary(ctx, filters)
+	summary, err := s.peopleCountRepository.GetSummary(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	cameras := make(map[uint]entity.CameraSummary, len(summary.Cameras))
+	for _, camera := range summary.Cameras {
+		cameras[camera.CameraID] = camera
+	}
+
+	summary.Zones = []entity.ZoneCountSummary{}
+	for _, zoneID := range tree.breakdown(id) {
+		zone := tree.zones[zoneID]
+		zoneSummary := entity.ZoneCountSummary{
+			ZoneID:    zone.ID,
+			Name:      zone.Name,
+			Kind:      zone.Kind,
+			CameraIDs: tree.cameraIDs(zone.ID),
+		}
+
+		for _, cameraID := range zoneSummary.CameraIDs {
+			camera := cameras[cameraID]
+			zoneSummary.Totals.Male += camera.MaleCount
+			zoneSummary.Totals.Female += camera.FemaleCount
+			zoneSummary.Totals.Child += camera.ChildCount
+			zoneSummary.Totals.Adult += camera.AdultCount
+			zoneSummary.Totals.Elderly += camera.ElderlyCount
+			zoneSummary.Totals.Total += camera.TotalCount
+		}
+
+		summary.Zones = append(summary.Zones, zoneSummary)
+	}
+
+	return summary, nil
 }
 
-// GetCountsTrend retrieves trend data for people counts
-func (s *PeopleCountServiceImpl) GetCountsTrend(ctx context.Context, interval string, areaID, from, to string) (*entity.CountsByTimeResult, error) {
+// GetCountsTrend retrieves trend data for people counts, of the cameras of a zone and of the
+// zones inside it when zoneID is set
+func (s *PeopleCountServiceImpl) GetCountsTrend(ctx context.Context, interval string, zoneID, from, to string) (*entity.CountsByTimeResult, error) {
 	// Validate interval
 	validIntervals := map[string]bool{
 		"hour":  true,
@@ -196,17 +272,12 @@ func (s *PeopleCountServiceImpl) GetCountsTrend(ctx context.Context, interval st
 		return nil, errors.New("invalid interval. Must be hour, day, week, or month")
 	}
 
-	// Process area filter if provided
-
+	// Process zone filter if provided
 	filters := make(map[string]interface{})
-	if areaID != "" {
-		id, err := strconv.ParseUint(areaID, 10, 64)
-		if err != nil {
-			return nil, errors.New("invalid area ID")
+	if zoneID != "" {
+		if _, _, err := zoneCameraFilter(ctx, s.zoneRepository, zoneID, filters); err != nil {
+			return nil, err
 		}
-
-		areaIDUint := uint(id)
-		filters["area_id"] = &areaIDUint
 	}
 
 	if from != "" {
@@ -369,3 +440,137 @@ func (s *PeopleCountServiceImpl) GetPeakHoursAnalysis(ctx context.Context, camer
 
 	return s.peopleCountRepository.GetPeakHoursAnalysis(ctx, filters)
 }
+
+// GetOccupancy computes live occupancy per camera and per zone from the people flow since the
+// last daily reset. Zones of the hierarchy count the people who entered less those who left
+// through their entrance and exit cameras. Cameras in no zone are grouped by location. With
+// zoneID set only that zone, the zones inside it and their cameras are reported, and the total
+// is the occupancy of that zone. Filtering by camera or location leaves the hierarchy out.
+func (s *PeopleCountServiceImpl) GetOccupancy(ctx context.Context, cameraID, zone, zoneID string) (*entity.OccupancySummary, error) {
+	filters := make(map[string]interface{})
+	if cameraID != "" {
+		id, err := strconv.ParseUint(cameraID, 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid camera ID")
+		}
+		filters["camera_id"] = uint(id)
+	}
+
+	if zone != "" {
+		filters["zone"] = zone
+	}
+
+	selected, tree, err := zoneCameraFilter(ctx, s.zoneRepository, zoneID, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	since := s.occupancyReset.LastReset(time.Now())
+
+	flows, err := s.peopleCountRepository.GetFlowSince(ctx, since, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &entity.OccupancySummary{
+		Since:     since,
+		ResetTime: s.occupancyReset.String(),
+		Cameras:   []entity.CameraOccupancy{},
+		Zones:     []entity.ZoneOccupancy{},
+	}
+
+	cameraFlows := make(map[uint]entity.CameraOccupancy, len(flows))
+	locations := make(map[string]*entity.ZoneOccupancy)
+	var locationNames []string
+
+	for _, flow := range flows {
+		// Missed exits must not turn into negative occupancy
+		flow.Occupancy = flow.InCount - flow.OutCount
+		if flow.Occupancy < 0 {
+			flow.Occupancy = 0
+		}
+		summary.Cameras = append(summary.Cameras, flow)
+		summary.Total += flow.Occupancy
+		cameraFlows[flow.CameraID] = flow
+
+		if tree.zoned[flow.CameraID] {
+			continue
+		}
+
+		location, exists := locations[flow.Zone]
+		if !exists {
+			location = &entity.ZoneOccupancy{Zone: flow.Zone, CameraIDs: []uint{}}
+			locations[flow.Zone] = location
+			locationNames = append(locationNames, flow.Zone)
+		}
+
+		location.InCount += flow.InCount
+		location.OutCount += flow.OutCount
+		location.Occupancy += flow.Occupancy
+		location.CameraIDs = append(location.CameraIDs, flow.CameraID)
+		if flow.LastUpdated.After(location.LastUpdated) {
+			location.LastUpdated = flow.LastUpdated
+		}
+	}
+
+	// Zones need the flow of all their cameras
+	zoneIDs := tree.walk()
+	if selected != 0 {
+		zoneIDs = tree.subtree(selected)
+	}
+	if cameraID != "" || zone != "" {
+		zoneIDs = nil
+	}
+
+	for _, id := range zoneIDs {
+		zoneOccupancy := s.zoneOccupancy(tree, id, cameraFlows)
+		summary.Zones = append(summary.Zones, zoneOccupancy)
+
+		if id == selected {
+			summary.Total = zoneOccupancy.Occupancy
+		}
+	}
+
+	sort.Strings(locationNames)
+	for _, name := range locationNames {
+		summary.Zones = append(summary.Zones, *locations[name])
+	}
+
+	return summary, nil
+}
+
+// zoneOccupancy sums the flow through the boundary of a zone of the hierarchy
+func (s *PeopleCountServiceImpl) zoneOccupancy(tree *zoneTree, id uint, flows map[uint]entity.CameraOccupancy) entity.ZoneOccupancy {
+	zone := tree.zones[id]
+	zoneID := zone.ID
+
+	occupancy := entity.ZoneOccupancy{
+		ZoneID:    &zoneID,
+		ParentID:  zone.ParentID,
+		Kind:      zone.Kind,
+		Zone:      zone.Name,
+		Capacity:  zone.Capacity,
+		CameraIDs: tree.cameraIDs(id),
+	}
+
+	for cameraID, membership := range tree.boundary(id) {
+		flow, ok := flows[cameraID]
+		if !ok {
+			continue
+		}
+
+		entered, left := membership.Flow(flow.InCount, flow.OutCount)
+		occupancy.InCount += entered
+		occupancy.OutCount += left
+		if flow.LastUpdated.After(occupancy.LastUpdated) {
+			occupancy.LastUpdated = flow.LastUpdated
+		}
+	}
+
+	occupancy.Occupancy = occupancy.InCount - occupancy.OutCount
+	if occupancy.Occupancy < 0 {
+		occupancy.Occupancy = 0
+	}
+
+	return occupancy
+}
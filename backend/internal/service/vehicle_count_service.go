@@ -14,14 +14,18 @@ import (
 // VehicleCountServiceImpl implements service.VehicleCountService
 type VehicleCountServiceImpl struct {
 	vehicleCountRepository repository.VehicleCountRepository
+	cameraConfigService    service.CameraConfigService
 }
 
-// NewVehicleCountService creates a new vehicle count service
+// NewVehicleCountService creates a new vehicle count service. Counts record the configuration
+// version of their camera from cameraConfigService.
 func NewVehicleCountService(
 	vehicleCountRepository repository.VehicleCountRepository,
+	cameraConfigService service.CameraConfigService,
 ) service.VehicleCountService {
 	return &VehicleCountServiceImpl{
 		vehicleCountRepository: vehicleCountRepository,
+		cameraConfigService:    cameraConfigService,
 	}
 }
 
@@ -83,6 +87,10 @@ func (s *VehicleCountServiceImpl) GetAllCounts(ctx context.Context, page, limit
 }
 
 func (s *VehicleCountServiceImpl) CreateVehicleCount(ctx context.Context, count *entity.VehicleCount) error {
+	if err := s.resolveConfigVersion(ctx, count); err != nil {
+		return err
+	}
+
 	return s.vehicleCountRepository.Create(ctx, count)
 }
 
@@ -97,9 +105,25 @@ func (s *VehicleCountServiceImpl) UpdateVehicleCount(ctx context.Context, count
 		return errors.New("cctv ID is required")
 	}
 
+	if err := s.resolveConfigVersion(ctx, count); err != nil {
+		return err
+	}
+
 	return s.vehicleCountRepository.Update(ctx, count)
 }
 
+// resolveConfigVersion sets the configuration version a count was made with, the version
+// reported by the device when it sent one
+func (s *VehicleCountServiceImpl) resolveConfigVersion(ctx context.Context, count *entity.VehicleCount) error {
+	version, err := resolveConfigVersion(ctx, s.cameraConfigService, count.CctvID, count.ConfigVersion)
+	if err != nil {
+		return err
+	}
+
+	count.ConfigVersion = version
+	return nil
+}
+
 // RecordCount creates a new vehicle count record
 func (s *VehicleCountServiceImpl) RecordCount(ctx context.Context, count *entity.VehicleCount) error {
 	// Validate CCTV ID
@@ -139,6 +163,10 @@ func (s *VehicleCountServiceImpl) RecordCount(ctx context.Context, count *entity
 		count.DeviceTimestampUTC = float64(count.DeviceTimestamp.Unix())
 	}
 
+	if err := s.resolveConfigVersion(ctx, count); err != nil {
+		return err
+	}
+
 	return s.vehicleCountRepository.Create(ctx, count)
 }
 
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/service"
+	"people-counting/pkg/watcher"
+)
+
+// WatcherServiceImpl implements service.WatcherService
+type WatcherServiceImpl struct {
+	watcherManager *watcher.Manager
+}
+
+// NewWatcherService creates a new watcher service
+func NewWatcherService(watcherManager *watcher.Manager) service.WatcherService {
+	return &WatcherServiceImpl{
+		watcherManager: watcherManager,
+	}
+}
+
+// GetStats retrieves the current folder watcher metrics
+func (s *WatcherServiceImpl) GetStats(ctx context.Context) (*entity.WatcherStats, error) {
+	stats := s.watcherManager.Stats()
+
+	result := &entity.WatcherStats{
+		Trigger:             stats.Trigger,
+		Running:             stats.Running,
+		MaxWorkers:          stats.MaxWorkers,
+		MaxWorkersPerFolder: stats.MaxWorkersPerFolder,
+		ActiveWorkers:       stats.ActiveWorkers,
+		Folders:             make([]entity.WatcherFolderStats, 0, len(stats.Folders)),
+	}
+
+	for _, folder := range stats.Folders {
+		result.Folders = append(result.Folders, entity.WatcherFolderStats{
+			Name:            filepath.Base(folder.Path),
+			Path:            folder.Path,
+			Handler:         folder.Handler,
+			InFlight:        folder.InFlight,
+			Pending:         folder.Pending,
+			Retrying:        folder.Retrying,
+			Processed:       folder.Processed,
+			Failed:          folder.Failed,
+			Retried:         folder.Retried,
+			AvgDurationMs:   float64(folder.AvgDuration) / float64(time.Millisecond),
+			LastScanAt:      folder.LastScanAt,
+			LastProcessedAt: folder.LastProcessedAt,
+		})
+	}
+
+	return result, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"encoding/json"
 	"log"
+	"people-counting/internal/domain/entity"
 	"time"
 )
 
@@ -40,6 +41,40 @@ func (ws *WebSocketService) NotifyAlert(alertType, cameraName, message string, d
 	log.Printf("Alert notification sent: %s from %s", alertType, cameraName)
 }
 
+// NotifyOccupancy broadcasts live occupancy to all connected clients
+func (ws *WebSocketService) NotifyOccupancy(data interface{}) {
+	notification := map[string]interface{}{
+		"timestamp": time.Now(),
+		"data":      data,
+	}
+
+	ws.broadcaster.BroadcastMessage("occupancy", notification)
+}
+
+// NotifyAlertEvent broadcasts a change in the lifecycle of an alert to all connected clients
+func (ws *WebSocketService) NotifyAlertEvent(event *entity.AlertEvent) {
+	notification := map[string]interface{}{
+		"alert_id":  event.AlertID,
+		"action":    event.Action,
+		"timestamp": time.Now(),
+		"data":      event,
+	}
+
+	ws.broadcaster.BroadcastMessage("alert_event", notification)
+}
+
+// NotifyCameraStatus broadcasts a change of the status of a camera to all connected clients
+func (ws *WebSocketService) NotifyCameraStatus(event *entity.CameraStatusEvent) {
+	notification := map[string]interface{}{
+		"camera_id": event.CameraID,
+		"status":    event.ToStatus,
+		"timestamp": time.Now(),
+		"data":      event,
+	}
+
+	ws.broadcaster.BroadcastMessage("camera_status", notification)
+}
+
 
 
 // SendPersonalizedMessage sends a message to a specific client
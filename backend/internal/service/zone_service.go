@@ -0,0 +1,570 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"people-counting/internal/domain/entity"
+	"people-counting/internal/domain/repository"
+	"people-counting/internal/domain/service"
+)
+
+// ZoneServiceImpl implements service.ZoneService
+type ZoneServiceImpl struct {
+	zoneRepository     repository.ZoneRepository
+	cameraRepository   repository.CameraRepository
+	floorPlanDirectory string
+}
+
+// floorPlanExtensions maps the image formats accepted as floor plans to their file extensions
+var floorPlanExtensions = map[string]string{
+	"png":  ".png",
+	"jpeg": ".jpg",
+	"gif":  ".gif",
+}
+
+// NewZoneService creates a new zone service storing floor plan images in floorPlanDirectory
+func NewZoneService(
+	zoneRepository repository.ZoneRepository,
+	cameraRepository repository.CameraRepository,
+	floorPlanDirectory string,
+) service.ZoneService {
+	return &ZoneServiceImpl{
+		zoneRepository:     zoneRepository,
+		cameraRepository:   cameraRepository,
+		floorPlanDirectory: floorPlanDirectory,
+	}
+}
+
+// GetAllZones retrieves zones of a kind, or every zone when kind is empty. With tree set the
+// sites are returned with the zones inside them nested as children.
+func (s *ZoneServiceImpl) GetAllZones(ctx context.Context, kind string, tree bool) ([]entity.Zone, error) {
+	if kind != "" && entity.ZoneKindLevel(kind) == 0 {
+		return nil, errors.New("invalid zone kind. Must be site, building, floor or zone")
+	}
+
+	filters := make(map[string]interface{})
+	if kind != "" && !tree {
+		filters["kind"] = kind
+	}
+
+	zones, err := s.zoneRepository.FindAll(ctx, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	if !tree {
+		return zones, nil
+	}
+
+	hierarchy := newZoneTree(zones)
+	nested := make([]entity.Zone, 0, len(hierarchy.roots))
+	for _, id := range hierarchy.roots {
+		nested = append(nested, hierarchy.nest(id))
+	}
+
+	return nested, nil
+}
+
+// GetZoneByID retrieves a zone with its cameras and the zones directly inside it
+func (s *ZoneServiceImpl) GetZoneByID(ctx context.Context, id uint) (*entity.Zone, error) {
+	zone, err := s.zoneRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := s.zoneRepository.FindAll(ctx, map[string]interface{}{"parent_id": id})
+	if err != nil {
+		return nil, err
+	}
+	zone.Children = children
+
+	return zone, nil
+}
+
+// GetZoneCameras retrieves the cameras of a zone and of the zones inside it
+func (s *ZoneServiceImpl) GetZoneCameras(ctx context.Context, id uint) ([]entity.Camera, error) {
+	if _, err := s.zoneRepository.FindByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.cameraRepository.FindByZone(ctx, id)
+}
+
+// CreateZone creates a zone
+func (s *ZoneServiceImpl) CreateZone(ctx context.Context, zone *entity.Zone) error {
+	if err := s.validateZone(ctx, zone); err != nil {
+		return err
+	}
+
+	if _, err := s.zoneRepository.FindByName(ctx, zone.ParentID, zone.Name); err == nil {
+		return errors.New("a zone with the same name already exists in the parent zone")
+	}
+
+	return s.zoneRepository.Create(ctx, zone)
+}
+
+// UpdateZone updates a zone. The zones inside it must stay of a lower kind.
+func (s *ZoneServiceImpl) UpdateZone(ctx context.Context, zone *entity.Zone) error {
+	if _, err := s.zoneRepository.FindByID(ctx, zone.ID); err != nil {
+		return err
+	}
+
+	if err := s.validateZone(ctx, zone); err != nil {
+		return err
+	}
+
+	children, err := s.zoneRepository.FindAll(ctx, map[string]interface{}{"parent_id": zone.ID})
+	if err != nil {
+		return err
+	}
+
+	level := entity.ZoneKindLevel(zone.Kind)
+	for _, child := range children {
+		if entity.ZoneKindLevel(child.Kind) <= level {
+			return fmt.Errorf("invalid zone kind, the %s %s inside it cannot be inside a %s", child.Kind, child.Name, zone.Kind)
+		}
+	}
+
+	if other, err := s.zoneRepository.FindByName(ctx, zone.ParentID, zone.Name); err == nil && other.ID != zone.ID {
+		return errors.New("a zone with the same name already exists in the parent zone")
+	}
+
+	return s.zoneRepository.Update(ctx, zone)
+}
+
+// DeleteZone deletes a zone that has no zones inside it, with its floor plan
+func (s *ZoneServiceImpl) DeleteZone(ctx context.Context, id uint) error {
+	zone, err := s.zoneRepository.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	children, err := s.zoneRepository.CountChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if children > 0 {
+		return errors.New("zone has zones inside it")
+	}
+
+	if err := s.zoneRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.removeFloorPlan(zone.FloorPlan)
+	return nil
+}
+
+// SetFloorPlan stores a PNG, JPEG or GIF image as the floor plan of a zone, replacing the
+// previous one. Cameras placed outside the new image are unplaced.
+func (s *ZoneServiceImpl) SetFloorPlan(ctx context.Context, id uint, data []byte) (*entity.Zone, error) {
+	zone, err := s.zoneRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("invalid floor plan, it must be a PNG, JPEG or GIF image")
+	}
+
+	extension, ok := floorPlanExtensions[format]
+	if !ok || config.Width == 0 || config.Height == 0 {
+		return nil, errors.New("invalid floor plan, it must be a PNG, JPEG or GIF image")
+	}
+
+	if err := os.MkdirAll(s.floorPlanDirectory, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create floor plan directory: %w", err)
+	}
+
+	// A new file name per upload so clients caching the previous image see the change
+	name := fmt.Sprintf("zone-%d-%d%s", id, time.Now().UnixNano(), extension)
+	path := filepath.Join(s.floorPlanDirectory, name)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to save floor plan: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		os.Remove(path + ".tmp")
+		return nil, fmt.Errorf("failed to save floor plan: %w", err)
+	}
+
+	if err := s.zoneRepository.SetFloorPlan(ctx, id, name, config.Width, config.Height); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+
+	s.removeFloorPlan(zone.FloorPlan)
+	return s.GetZoneByID(ctx, id)
+}
+
+// GetFloorPlan returns the path of the floor plan image of a zone
+func (s *ZoneServiceImpl) GetFloorPlan(ctx context.Context, id uint) (string, error) {
+	zone, err := s.zoneRepository.FindByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	if zone.FloorPlan == "" {
+		return "", errors.New("floor plan not found")
+	}
+
+	path := filepath.Join(s.floorPlanDirectory, zone.FloorPlan)
+	if _, err := os.Stat(path); err != nil {
+		return "", errors.New("floor plan not found")
+	}
+
+	return path, nil
+}
+
+// DeleteFloorPlan removes the floor plan of a zone and unplaces its cameras
+func (s *ZoneServiceImpl) DeleteFloorPlan(ctx context.Context, id uint) error {
+	zone, err := s.zoneRepository.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if zone.FloorPlan == "" {
+		return errors.New("floor plan not found")
+	}
+
+	if err := s.zoneRepository.SetFloorPlan(ctx, id, "", 0, 0); err != nil {
+		return err
+	}
+
+	s.removeFloorPlan(zone.FloorPlan)
+	return nil
+}
+
+// removeFloorPlan removes a floor plan image that is no longer used
+func (s *ZoneServiceImpl) removeFloorPlan(name string) {
+	if name == "" {
+		return
+	}
+
+	if err := os.Remove(filepath.Join(s.floorPlanDirectory, name)); err != nil && !os.IsNotExist(err) {
+		log.Printf("Failed to remove floor plan %s: %v", name, err)
+	}
+}
+
+// SetZoneCameras replaces the cameras of a zone
+func (s *ZoneServiceImpl) SetZoneCameras(ctx context.Context, id uint, cameras []entity.ZoneCamera) (*entity.Zone, error) {
+	zone, err := s.zoneRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool, len(cameras))
+	for i := range cameras {
+		if seen[cameras[i].CameraID] {
+			return nil, fmt.Errorf("invalid cameras, camera %d is listed twice", cameras[i].CameraID)
+		}
+		seen[cameras[i].CameraID] = true
+
+		if err := s.validateMembership(ctx, zone, &cameras[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.zoneRepository.SetCameras(ctx, id, cameras); err != nil {
+		return nil, err
+	}
+
+	return s.GetZoneByID(ctx, id)
+}
+
+// AddZoneCamera adds a camera to a zone, or changes its direction and position when it is
+// already in it
+func (s *ZoneServiceImpl) AddZoneCamera(ctx context.Context, membership *entity.ZoneCamera) (*entity.Zone, error) {
+	zone, err := s.zoneRepository.FindByID(ctx, membership.ZoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.validateMembership(ctx, zone, membership); err != nil {
+		return nil, err
+	}
+
+	if err := s.zoneRepository.SaveCamera(ctx, membership); err != nil {
+		return nil, err
+	}
+
+	return s.GetZoneByID(ctx, membership.ZoneID)
+}
+
+// RemoveZoneCamera removes a camera from a zone
+func (s *ZoneServiceImpl) RemoveZoneCamera(ctx context.Context, id, cameraID uint) error {
+	if _, err := s.zoneRepository.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	return s.zoneRepository.RemoveCamera(ctx, id, cameraID)
+}
+
+// validateZone validates a zone and its place in the hierarchy
+func (s *ZoneServiceImpl) validateZone(ctx context.Context, zone *entity.Zone) error {
+	zone.Name = strings.TrimSpace(zone.Name)
+	if zone.Name == "" {
+		return errors.New("name is required")
+	}
+
+	zone.Kind = strings.ToLower(strings.TrimSpace(zone.Kind))
+	level := entity.ZoneKindLevel(zone.Kind)
+	if level == 0 {
+		return errors.New("invalid zone kind. Must be site, building, floor or zone")
+	}
+
+	if zone.Capacity < 0 {
+		return errors.New("invalid capacity, it cannot be negative")
+	}
+
+	if zone.Kind == entity.ZoneKindSite {
+		if zone.ParentID != nil {
+			return errors.New("invalid parent zone, a site cannot be inside another zone")
+		}
+		return nil
+	}
+
+	if zone.ParentID == nil {
+		return fmt.Errorf("invalid parent zone, a %s must be inside a site, building or floor", zone.Kind)
+	}
+
+	parent, err := s.zoneRepository.FindByID(ctx, *zone.ParentID)
+	if err != nil {
+		return errors.New("parent zone not found")
+	}
+
+	if entity.ZoneKindLevel(parent.Kind) >= level {
+		return fmt.Errorf("invalid parent zone, a %s cannot be inside a %s", zone.Kind, parent.Kind)
+	}
+
+	return nil
+}
+
+// validateMembership validates the camera, direction and floor plan position of a camera of a
+// zone. Cameras without a direction are internal.
+func (s *ZoneServiceImpl) validateMembership(ctx context.Context, zone *entity.Zone, membership *entity.ZoneCamera) error {
+	membership.Direction = strings.ToLower(strings.TrimSpace(membership.Direction))
+	if membership.Direction == "" {
+		membership.Direction = entity.ZoneDirectionInternal
+	}
+
+	if !entity.IsZoneDirection(membership.Direction) {
+		return errors.New("invalid direction. Must be entrance, exit or internal")
+	}
+
+	if (membership.PlanX == nil) != (membership.PlanY == nil) {
+		return errors.New("invalid position, plan_x and plan_y must be set together")
+	}
+
+	if membership.PlanX != nil {
+		if zone.FloorPlan == "" {
+			return errors.New("invalid position, the zone has no floor plan")
+		}
+
+		x, y := *membership.PlanX, *membership.PlanY
+		if x < 0 || y < 0 || x > float64(zone.FloorPlanWidth) || y > float64(zone.FloorPlanHeight) {
+			return fmt.Errorf("invalid position, it must be within the %dx%d floor plan", zone.FloorPlanWidth, zone.FloorPlanHeight)
+		}
+	}
+
+	if _, err := s.cameraRepository.FindByID(ctx, membership.CameraID); err != nil {
+		return errors.New("camera not found")
+	}
+
+	return nil
+}
+
+// zoneTree is the zone hierarchy, to walk the zones inside a zone. Zones are small enough to be
+// loaded whole.
+type zoneTree struct {
+	zones    map[uint]*entity.Zone
+	children map[uint][]uint
+	roots    []uint
+	zoned    map[uint]bool // Cameras in at least one zone
+}
+
+// newZoneTree builds the hierarchy of zones ordered by ID. Zones whose parent is missing are
+// taken as roots.
+func newZoneTree(zones []entity.Zone) *zoneTree {
+	t := &zoneTree{
+		zones:    make(map[uint]*entity.Zone, len(zones)),
+		children: make(map[uint][]uint),
+		zoned:    make(map[uint]bool),
+	}
+
+	for i := range zones {
+		t.zones[zones[i].ID] = &zones[i]
+		for _, membership := range zones[i].Cameras {
+			t.zoned[membership.CameraID] = true
+		}
+	}
+
+	for i := range zones {
+		zone := &zones[i]
+		if zone.ParentID != nil && t.zones[*zone.ParentID] != nil {
+			t.children[*zone.ParentID] = append(t.children[*zone.ParentID], zone.ID)
+		} else {
+			t.roots = append(t.roots, zone.ID)
+		}
+	}
+
+	return t
+}
+
+// loadZoneTree loads the whole zone hierarchy
+func loadZoneTree(ctx context.Context, zoneRepository repository.ZoneRepository) (*zoneTree, error) {
+	zones, err := zoneRepository.FindAll(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return newZoneTree(zones), nil
+}
+
+// parseZoneID parses a zone ID and checks the zone exists in the tree
+func (t *zoneTree) parseZoneID(zoneID string) (uint, error) {
+	id, err := strconv.ParseUint(zoneID, 10, 32)
+	if err != nil {
+		return 0, errors.New("invalid zone ID")
+	}
+
+	if t.zones[uint(id)] == nil {
+		return 0, errors.New("zone not found")
+	}
+
+	return uint(id), nil
+}
+
+// subtree returns the IDs of a zone and of the zones inside it, top down
+func (t *zoneTree) subtree(id uint) []uint {
+	ids := []uint{id}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, t.children[ids[i]]...)
+	}
+	return ids
+}
+
+// walk returns the IDs of every zone, each followed by the zones inside it
+func (t *zoneTree) walk() []uint {
+	var ids []uint
+
+	var visit func(id uint)
+	visit = func(id uint) {
+		ids = append(ids, id)
+		for _, child := range t.children[id] {
+			visit(child)
+		}
+	}
+
+	for _, id := range t.roots {
+		visit(id)
+	}
+
+	return ids
+}
+
+// cameraIDs returns the cameras of a zone and of the zones inside it, sorted
+func (t *zoneTree) cameraIDs(id uint) []uint {
+	seen := make(map[uint]bool)
+	ids := []uint{}
+
+	for _, zoneID := range t.subtree(id) {
+		for _, membership := range t.zones[zoneID].Cameras {
+			if !seen[membership.CameraID] {
+				seen[membership.CameraID] = true
+				ids = append(ids, membership.CameraID)
+			}
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// boundary returns the direction of the cameras of a zone and of the zones inside it relative
+// to the zone as a whole. A camera between two zones inside it, the entrance of one and the
+// exit of the other, is internal.
+func (t *zoneTree) boundary(id uint) map[uint]entity.ZoneCamera {
+	sides := make(map[uint]int)
+	for _, zoneID := range t.subtree(id) {
+		for _, membership := range t.zones[zoneID].Cameras {
+			side := sides[membership.CameraID]
+			switch membership.Direction {
+			case entity.ZoneDirectionEntrance:
+				side++
+			case entity.ZoneDirectionExit:
+				side--
+			}
+			sides[membership.CameraID] = side
+		}
+	}
+
+	cameras := make(map[uint]entity.ZoneCamera, len(sides))
+	for cameraID, side := range sides {
+		direction := entity.ZoneDirectionInternal
+		if side > 0 {
+			direction = entity.ZoneDirectionEntrance
+		} else if side < 0 {
+			direction = entity.ZoneDirectionExit
+		}
+		cameras[cameraID] = entity.ZoneCamera{ZoneID: id, CameraID: cameraID, Direction: direction}
+	}
+
+	return cameras
+}
+
+// nest returns a zone with the zones inside it nested as children
+func (t *zoneTree) nest(id uint) entity.Zone {
+	zone := *t.zones[id]
+	zone.Children = make([]entity.Zone, 0, len(t.children[id]))
+	for _, child := range t.children[id] {
+		zone.Children = append(zone.Children, t.nest(child))
+	}
+	return zone
+}
+
+// breakdown returns the zones to break a report down by: the zones directly inside the zone
+// with ID id, or the sites when id is 0
+func (t *zoneTree) breakdown(id uint) []uint {
+	if id == 0 {
+		return t.roots
+	}
+	return t.children[id]
+}
+
+// zoneCameraFilter narrows filters to the cameras of a zone and of the zones inside it. The
+// zone ID is given as a string and may be empty. It returns the zone ID, 0 when none was
+// given, and the zone hierarchy.
+func zoneCameraFilter(ctx context.Context, zoneRepository repository.ZoneRepository, zoneID string, filters map[string]interface{}) (uint, *zoneTree, error) {
+	tree, err := loadZoneTree(ctx, zoneRepository)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if zoneID == "" {
+		return 0, tree, nil
+	}
+
+	id, err := tree.parseZoneID(zoneID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	filters["camera_ids"] = tree.cameraIDs(id)
+	return id, tree, nil
+}
@@ -0,0 +1,199 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// peopleCountAggregate describes a continuous aggregate over people_counts
+type peopleCountAggregate struct {
+	name           string
+	bucket         string
+	startOffset    string
+	endOffset      string
+	scheduleWindow string
+}
+
+// peopleCountAggregates are read by the analytics service. Both expose the same columns:
+// bucket, camera_id, in_count, out_count, the demographic counts, total_count and records.
+var peopleCountAggregates = []peopleCountAggregate{
+	{name: "people_counts_hourly", bucket: "1 hour", startOffset: "3 days", endOffset: "1 hour", scheduleWindow: "1 hour"},
+	{name: "people_counts_daily", bucket: "1 day", startOffset: "30 days", endOffset: "1 day", scheduleWindow: "1 day"},
+}
+
+// ensurePeopleCountAggregates creates the people count continuous aggregates, replacing
+// aggregates created by older schemas without in/out flow. Real-time aggregation is enabled
+// so the current hour and day are included. New aggregates are created empty and materialized
+// in the background, until then queries aggregate the raw rows.
+func ensurePeopleCountAggregates(db *gorm.DB) error {
+	// Buckets follow the session time zone so daily buckets start at local midnight
+	var timezone string
+	if err := db.Raw("SHOW timezone").Scan(&timezone).Error; err != nil || timezone == "" {
+		timezone = "UTC"
+	}
+
+	for _, aggregate := range peopleCountAggregates {
+		exists, current := aggregateState(db, aggregate.name, "bucket", "in_count", "out_count", "total_count", "records")
+		if current {
+			continue
+		}
+
+		if exists {
+			log.Printf("Recreating continuous aggregate %s with people flow columns", aggregate.name)
+			if err := retireAggregate(db, aggregate.name); err != nil {
+				return err
+			}
+		}
+
+		if err := db.Exec(fmt.Sprintf(`
+			CREATE MATERIALIZED VIEW %s
+			WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+			SELECT
+				time_bucket(INTERVAL '%s', timestamp, '%s') AS bucket,
+				camera_id,
+				SUM(in_count) AS in_count,
+				SUM(out_count) AS out_count,
+				SUM(male_count) AS male_count,
+				SUM(female_count) AS female_count,
+				SUM(child_count) AS child_count,
+				SUM(adult_count) AS adult_count,
+				SUM(elderly_count) AS elderly_count,
+				SUM(male_count + female_count) AS total_count,
+				COUNT(*) AS records
+			FROM people_counts
+			GROUP BY bucket, camera_id
+			WITH NO DATA
+		`, aggregate.name, aggregate.bucket, timezone)).Error; err != nil {
+			return err
+		}
+
+		if err := db.Exec(fmt.Sprintf(`
+			SELECT add_continuous_aggregate_policy('%s',
+				start_offset => INTERVAL '%s',
+				end_offset => INTERVAL '%s',
+				schedule_interval => INTERVAL '%s',
+				if_not_exists => TRUE)
+		`, aggregate.name, aggregate.startOffset, aggregate.endOffset, aggregate.scheduleWindow)).Error; err != nil {
+			return err
+		}
+
+		refreshAggregateInBackground(db, aggregate.name)
+	}
+
+	return nil
+}
+
+// ensureAlertAggregate creates the alerts_daily continuous aggregate read by the alert
+// statistics, replacing the aggregate of older schemas that was bucketed in UTC, left out
+// suppressed alerts and only showed materialized days. It is skipped when alerts is not a
+// hypertable. Like the people count aggregates it is created empty and materialized in the
+// background.
+func ensureAlertAggregate(db *gorm.DB) error {
+	var hypertables int64
+	if err := db.Raw("SELECT COUNT(*) FROM timescaledb_information.hypertables WHERE hypertable_name = 'alerts'").Scan(&hypertables).Error; err != nil || hypertables == 0 {
+		log.Println("Alerts is not a hypertable, alert statistics are computed without alerts_daily")
+		return nil
+	}
+
+	exists, current := aggregateState(db, "alerts_daily", "bucket", "alert_count", "suppressed_count")
+	if current {
+		return nil
+	}
+
+	if exists {
+		log.Println("Recreating continuous aggregate alerts_daily with suppressed alerts")
+		if err := retireAggregate(db, "alerts_daily"); err != nil {
+			return err
+		}
+	}
+
+	// Buckets follow the session time zone so daily buckets start at local midnight
+	var timezone string
+	if err := db.Raw("SHOW timezone").Scan(&timezone).Error; err != nil || timezone == "" {
+		timezone = "UTC"
+	}
+
+	if err := db.Exec(fmt.Sprintf(`
+		CREATE MATERIALIZED VIEW alerts_daily
+		WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+		SELECT
+			time_bucket(INTERVAL '1 day', detected_at, '%s') AS bucket,
+			camera_id,
+			alert_type_id,
+			severity,
+			COUNT(*) AS alert_count,
+			SUM(CASE WHEN suppressed THEN 1 ELSE 0 END) AS suppressed_count
+		FROM alerts
+		GROUP BY bucket, camera_id, alert_type_id, severity
+		WITH NO DATA
+	`, timezone)).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`
+		SELECT add_continuous_aggregate_policy('alerts_daily',
+			start_offset => INTERVAL '30 days',
+			end_offset => INTERVAL '1 day',
+			schedule_interval => INTERVAL '1 day',
+			if_not_exists => TRUE)
+	`).Error; err != nil {
+		return err
+	}
+
+	refreshAggregateInBackground(db, "alerts_daily")
+	return nil
+}
+
+// retireAggregate frees the name of an outdated aggregate. It is dropped, or kept under a
+// legacy name when other views depend on it so that they are not dropped with it.
+func retireAggregate(db *gorm.DB, name string) error {
+	if err := db.Exec(fmt.Sprintf("DROP MATERIALIZED VIEW IF EXISTS %s", name)).Error; err == nil {
+		return nil
+	}
+
+	legacy := name + "_legacy"
+	log.Printf("WARNING: Other views depend on %s, keeping it as %s until they are moved to the new aggregate", name, legacy)
+	return db.Exec(fmt.Sprintf("ALTER MATERIALIZED VIEW %s RENAME TO %s", name, legacy)).Error
+}
+
+// refreshAggregateInBackground materializes an aggregate over its whole range without holding
+// up startup
+func refreshAggregateInBackground(db *gorm.DB, name string) {
+	go func() {
+		log.Printf("Materializing continuous aggregate %s", name)
+		if err := db.Exec(fmt.Sprintf("CALL refresh_continuous_aggregate('%s', NULL, NULL)", name)).Error; err != nil {
+			log.Printf("WARNING: Failed to materialize continuous aggregate %s: %v", name, err)
+			return
+		}
+		log.Printf("Materialized continuous aggregate %s", name)
+	}()
+}
+
+// aggregateState reports whether a view exists, and whether it has all the required columns
+func aggregateState(db *gorm.DB, name string, required ...string) (exists bool, current bool) {
+	var columns []string
+	db.Raw(`
+		SELECT column_name
+		FROM information_schema.columns
+		WHERE table_schema = 'public'
+		AND table_name = ?`, name).Scan(&columns)
+
+	if len(columns) == 0 {
+		return false, false
+	}
+
+	found := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		found[column] = true
+	}
+
+	for _, column := range required {
+		if !found[column] {
+			return true, false
+		}
+	}
+
+	return true, true
+}
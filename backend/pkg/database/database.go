@@ -124,9 +124,252 @@ func MigrateDatabase(db *gorm.DB) error {
 		log.Println("Tables already exist, skipping migration")
 	}
 
+	// Apply incremental schema updates, these run on every start and must be idempotent
+	if err := applySchemaUpdates(db); err != nil {
+		return fmt.Errorf("failed to apply schema updates: %w", err)
+	}
+
 	return nil
 }
 
+// applySchemaUpdates creates tables and columns added after the initial schema
+func applySchemaUpdates(db *gorm.DB) error {
+	hasCameraDevices := db.Migrator().HasTable(&entity.CameraDevice{})
+	hasAlertStatus := db.Migrator().HasColumn(&entity.Alert{}, "Status")
+	hasAlertLastSeen := db.Migrator().HasColumn(&entity.Alert{}, "LastSeenAt")
+	hasPolicyAttachedAt := db.Migrator().HasColumn(&entity.AlertType{}, "EscalationPolicyAttachedAt")
+
+	if err := db.AutoMigrate(
+		&entity.IngestionLedgerEntry{},
+		&entity.CameraDevice{},
+		&entity.AlertEvent{},
+		&entity.AlertOccurrence{},
+		&entity.AlertRule{},
+		&entity.NotificationChannel{},
+		&entity.NotificationRoute{},
+		&entity.NotificationDelivery{},
+		&entity.EscalationPolicy{},
+		&entity.EscalationStep{},
+		&entity.AlertEscalation{},
+		&entity.AlertTypeMessage{},
+		&entity.MaintenanceWindow{},
+		&entity.AlertTypeAlias{},
+		&entity.CameraStatusEvent{},
+		&entity.Zone{},
+		&entity.ZoneCamera{},
+		&entity.CameraConfig{},
+	); err != nil {
+		return err
+	}
+
+	// Camera status changes are kept for availability reports, chunked by time like other history
+	if err := db.Exec("SELECT create_hypertable('camera_status_events', 'changed_at', if_not_exists => TRUE, migrate_data => TRUE)").Error; err != nil {
+		return fmt.Errorf("failed to create camera_status_events hypertable: %w", err)
+	}
+
+	// Map existing cameras once, when the mapping table is created
+	if !hasCameraDevices {
+		if err := seedLegacyCameraDevices(db); err != nil {
+			return fmt.Errorf("failed to seed camera devices: %w", err)
+		}
+	}
+
+	// People counts store in/out flow for occupancy. Columns are added one by one as AutoMigrate
+	// would also try to alter the generated total_count column and the sizes of existing columns.
+	if err := addMissingColumns(db, &entity.PeopleCount{}, "InCount", "OutCount"); err != nil {
+		return err
+	}
+
+	if err := addMissingColumns(db, &entity.Camera{}, "Capacity"); err != nil {
+		return err
+	}
+
+	// Cameras are drawn on maps and floor plans, and IP addresses may be IPv6. Widening a varchar
+	// does not rewrite the table.
+	if err := addMissingColumns(db, &entity.Camera{}, "Latitude", "Longitude", "Floor", "Bearing", "FieldOfView"); err != nil {
+		return err
+	}
+
+	if err := db.Exec(`
+		DO $$
+		BEGIN
+			IF (SELECT character_maximum_length FROM information_schema.columns
+				WHERE table_schema = 'public' AND table_name = 'cameras' AND column_name = 'ip_address') < 45 THEN
+				ALTER TABLE cameras ALTER COLUMN ip_address TYPE varchar(45);
+			END IF;
+		END $$`).Error; err != nil {
+		return fmt.Errorf("failed to widen cameras.ip_address: %w", err)
+	}
+
+	// Alerts moved from is_active to an explicit lifecycle status
+	if err := addMissingColumns(db, &entity.Alert{}, "Status", "AssignedTo", "AcknowledgedAt", "AcknowledgedBy"); err != nil {
+		return err
+	}
+
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_alerts_status ON alerts(status)").Error; err != nil {
+		return err
+	}
+
+	if !hasAlertStatus {
+		backfillAlertStatuses(db)
+	}
+
+	// Repeated detections are folded into open alerts by camera, alert type and object
+	if err := addMissingColumns(db, &entity.Alert{}, "ObjectID", "Occurrences", "FirstSeenAt", "LastSeenAt"); err != nil {
+		return err
+	}
+
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_alerts_correlation ON alerts(camera_id, alert_type_id, last_seen_at) WHERE is_active").Error; err != nil {
+		return err
+	}
+
+	if !hasAlertLastSeen {
+		backfillAlertSeenTimes(db)
+	}
+
+	// Alert types escalate unacknowledged alerts through a policy, and the notifications sent by
+	// its steps carry the reason
+	if err := addMissingColumns(db, &entity.AlertType{}, "EscalationPolicyID", "EscalationPolicyAttachedAt"); err != nil {
+		return err
+	}
+
+	// Policies attached before the time was kept count from the last update of their type
+	if !hasPolicyAttachedAt {
+		if err := db.Exec(`
+			UPDATE alert_types
+			SET escalation_policy_attached_at = COALESCE(updated_at, NOW())
+			WHERE escalation_policy_id IS NOT NULL`).Error; err != nil {
+			return fmt.Errorf("failed to backfill alert_types.escalation_policy_attached_at: %w", err)
+		}
+	}
+
+	if err := addMissingColumns(db, &entity.NotificationDelivery{}, "Note"); err != nil {
+		return err
+	}
+
+	// Alert types carry the severity and message templates of their alerts, which keep what the
+	// templates are rendered from
+	if err := addMissingColumns(db, &entity.AlertType{}, "Severity"); err != nil {
+		return err
+	}
+
+	if err := addMissingColumns(db, &entity.Alert{}, "ObjectName", "MissingItem", "Language"); err != nil {
+		return err
+	}
+
+	// Detections in maintenance windows are stored flagged instead of notified
+	if err := addMissingColumns(db, &entity.Alert{}, "Suppressed", "SuppressedBy"); err != nil {
+		return err
+	}
+
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_alerts_suppressed ON alerts(suppressed)").Error; err != nil {
+		return err
+	}
+
+	// Alert types are archived rather than deleted once they have alerts
+	if err := addMissingColumns(db, &entity.AlertType{}, "ArchivedAt"); err != nil {
+		return err
+	}
+
+	// Alerts link the evidence clip assembled from the buffered camera frames
+	if err := addMissingColumns(db, &entity.Alert{}, "ClipPath"); err != nil {
+		return err
+	}
+
+	// Ingested records keep the version of the camera configuration they were detected with
+	if err := addMissingColumns(db, &entity.PeopleCount{}, "ConfigVersion"); err != nil {
+		return err
+	}
+
+	if err := addMissingColumns(db, &entity.VehicleCount{}, "ConfigVersion"); err != nil {
+		return err
+	}
+
+	if err := addMissingColumns(db, &entity.Alert{}, "ConfigVersion"); err != nil {
+		return err
+	}
+
+	if err := addMissingColumns(db, &entity.AlertOccurrence{}, "ConfigVersion"); err != nil {
+		return err
+	}
+
+	// Analytics read the hourly and daily aggregates, recreated when they predate in/out flow
+	if err := ensurePeopleCountAggregates(db); err != nil {
+		return fmt.Errorf("failed to create people count aggregates: %w", err)
+	}
+
+	// Alert statistics read the daily alert aggregate
+	if err := ensureAlertAggregate(db); err != nil {
+		return fmt.Errorf("failed to create alert aggregate: %w", err)
+	}
+
+	return nil
+}
+
+// addMissingColumns adds the columns of the given model fields that do not exist yet
+func addMissingColumns(db *gorm.DB, model interface{}, fields ...string) error {
+	for _, field := range fields {
+		if db.Migrator().HasColumn(model, field) {
+			continue
+		}
+		if err := db.Migrator().AddColumn(model, field); err != nil {
+			return fmt.Errorf("failed to add column %s: %w", field, err)
+		}
+	}
+
+	return nil
+}
+
+// seedLegacyCameraDevices maps the legacy cctv_id of every existing camera. Before the mapping
+// table, cctv_id N was stored as camera N+1, so existing data keeps its camera.
+func seedLegacyCameraDevices(db *gorm.DB) error {
+	result := db.Exec(`
+		INSERT INTO camera_devices (legacy_id, camera_id, status, name, created_at, updated_at)
+		SELECT id - 1, id, ?, name, NOW(), NOW()
+		FROM cameras
+		WHERE id > 0
+		ON CONFLICT DO NOTHING`, entity.CameraDeviceStatusMapped)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	log.Printf("Mapped %d existing cameras to their legacy device IDs", result.RowsAffected)
+	return nil
+}
+
+// backfillAlertStatuses derives the status of alerts stored before statuses existed, the same
+// way the alert list used to derive it from is_active and resolved_at. Compressed chunks cannot
+// be updated on older TimescaleDB versions, so a failure only leaves those alerts new.
+func backfillAlertStatuses(db *gorm.DB) {
+	result := db.Exec(`
+		UPDATE alerts
+		SET status = CASE WHEN resolved_at IS NOT NULL THEN ? ELSE ? END
+		WHERE status = ?
+		AND (resolved_at IS NOT NULL OR is_active = false)`,
+		entity.AlertStatusResolved, entity.AlertStatusAcknowledged, entity.AlertStatusNew)
+	if result.Error != nil {
+		log.Printf("WARNING: Failed to derive the status of existing alerts: %v", result.Error)
+		return
+	}
+
+	log.Printf("Derived the status of %d existing alerts", result.RowsAffected)
+}
+
+// backfillAlertSeenTimes lets open alerts stored before correlation absorb new detections
+func backfillAlertSeenTimes(db *gorm.DB) {
+	result := db.Exec(`
+		UPDATE alerts
+		SET first_seen_at = detected_at, last_seen_at = detected_at
+		WHERE last_seen_at IS NULL
+		AND is_active = true`)
+	if result.Error != nil {
+		log.Printf("WARNING: Failed to set the seen times of open alerts: %v", result.Error)
+		return
+	}
+
+	log.Printf("Set the seen times of %d open alerts", result.RowsAffected)
+}
+
 // tablesExist checks if the required tables already exist in the database
 func tablesExist(db *gorm.DB) bool {
 	var count int64
@@ -202,63 +445,8 @@ func setupTimescaleDBFeatures(db *gorm.DB) error {
 		return err
 	}
 
-	// Create continuous aggregate for hourly data
-	if err := db.Exec(`
-		CREATE MATERIALIZED VIEW IF NOT EXISTS people_counts_hourly WITH (timescaledb.continuous) AS
-		SELECT
-			time_bucket('1 hour', timestamp) AS hour,
-			camera_id,
-			SUM(male_count) AS male_count,
-			SUM(female_count) AS female_count,
-			SUM(child_count) AS child_count,
-			SUM(adult_count) AS adult_count,
-			SUM(elderly_count) AS elderly_count,
-			SUM(total_count) AS total_count
-		FROM people_counts
-		GROUP BY hour, camera_id
-	`).Error; err != nil {
-		return err
-	}
-
-	// Add policy for hourly continuous aggregate
-	if err := db.Exec(`
-		SELECT add_continuous_aggregate_policy('people_counts_hourly',
-			start_offset => INTERVAL '2 days',
-			end_offset => INTERVAL '1 hour',
-			schedule_interval => INTERVAL '1 hour')
-	`).Error; err != nil {
-		return err
-	}
-
-	// Create continuous aggregate for daily data
-	if err := db.Exec(`
-		CREATE MATERIALIZED VIEW IF NOT EXISTS people_counts_daily WITH (timescaledb.continuous) AS
-		SELECT
-			time_bucket('1 day', timestamp) AS day,
-			camera_id,
-			SUM(male_count) AS male_count,
-			SUM(female_count) AS female_count,
-			SUM(child_count) AS child_count,
-			SUM(adult_count) AS adult_count,
-			SUM(elderly_count) AS elderly_count,
-			SUM(total_count) AS total_count
-		FROM people_counts
-		GROUP BY day, camera_id
-	`).Error; err != nil {
-		return err
-	}
-
-	// Add policy for daily continuous aggregate
-	if err := db.Exec(`
-		SELECT add_continuous_aggregate_policy('people_counts_daily',
-			start_offset => INTERVAL '30 days',
-			end_offset => INTERVAL '1 day',
-			schedule_interval => INTERVAL '1 day')
-	`).Error; err != nil {
-		return err
-	}
-
-	return nil
+	// Create continuous aggregates for hourly and daily data
+	return ensurePeopleCountAggregates(db)
 }
 
 // seedInitialData seeds initial data into the database
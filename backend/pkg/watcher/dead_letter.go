@@ -0,0 +1,88 @@
+package watcher
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Folders returns the configuration of every watched folder, ordered by path
+func (m *Manager) Folders() []FolderConfig {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	folders := make([]FolderConfig, 0, len(m.folderConfigs))
+	for _, config := range m.folderConfigs {
+		folders = append(folders, config)
+	}
+
+	sort.Slice(folders, func(i, j int) bool {
+		return folders[i].Path < folders[j].Path
+	})
+
+	return folders
+}
+
+// Requeue moves a file from a folder's failed folder back into the watched folder,
+// removes its error sidecar and clears its ingestion state so it is processed again
+func (m *Manager) Requeue(folderPath, fileName string) error {
+	m.mu.Lock()
+	config, ok := m.folderConfigs[folderPath]
+	ledger := m.ledger
+	m.mu.Unlock()
+
+	if !ok {
+		return errors.New("folder not found")
+	}
+
+	if fileName != filepath.Base(fileName) || IsSidecar(fileName) {
+		return errors.New("invalid file name")
+	}
+
+	srcPath := filepath.Join(config.FailedFolder, fileName)
+	destPath := filepath.Join(config.Path, fileName)
+
+	if _, err := os.Stat(srcPath); err != nil {
+		if os.IsNotExist(err) {
+			return errors.New("failed file not found")
+		}
+		return err
+	}
+
+	if _, err := os.Stat(destPath); err == nil {
+		return errors.New("a file with the same name is already queued")
+	}
+
+	// Forget the previous outcome, otherwise the ledger would send the file straight back
+	if ledger != nil {
+		hash, err := hashFile(srcPath)
+		if err != nil {
+			return fmt.Errorf("failed to hash file: %w", err)
+		}
+		if err := ledger.Forget(destPath, hash); err != nil {
+			return fmt.Errorf("failed to reset ledger entry: %w", err)
+		}
+	}
+
+	m.processedFilesMu.Lock()
+	delete(m.processedFiles, destPath)
+	delete(m.retries, destPath)
+	m.processedFilesMu.Unlock()
+
+	if err := os.Rename(srcPath, destPath); err != nil {
+		if err := m.copyFile(srcPath, destPath); err != nil {
+			return fmt.Errorf("failed to requeue file: %w", err)
+		}
+		if err := os.Remove(srcPath); err != nil {
+			return fmt.Errorf("failed to remove failed file: %w", err)
+		}
+	}
+
+	if err := os.Remove(SidecarPath(config.FailedFolder, fileName)); err != nil && !os.IsNotExist(err) {
+		fmt.Printf("Error removing sidecar for %s: %v\n", srcPath, err)
+	}
+
+	return nil
+}
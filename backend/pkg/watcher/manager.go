@@ -0,0 +1,840 @@
+package watcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Default concurrency limits, kept well below the default database connection pool
+const (
+	DefaultMaxWorkers          = 8
+	DefaultMaxWorkersPerFolder = 2
+)
+
+// fileSettleTime is how long a file must be unmodified before it is processed
+const fileSettleTime = 2 * time.Second
+
+type FileHandler interface {
+	ProcessFile(filePath string) error
+	GetName() string
+}
+
+// Ledger statuses
+const (
+	LedgerStatusProcessing = "processing"
+	LedgerStatusRetrying   = "retrying"
+	LedgerStatusProcessed  = "processed"
+	LedgerStatusFailed     = "failed"
+)
+
+// LedgerEntry is the persisted ingestion state of a file
+type LedgerEntry struct {
+	Status   string
+	Attempts int
+}
+
+// Ledger durably records file ingestion keyed by path and content hash, so that
+// a restart neither reprocesses nor loses files
+type Ledger interface {
+	// Lookup returns the entry for a file, or nil when it has never been seen
+	Lookup(path, hash string) (*LedgerEntry, error)
+	Begin(path, hash, handler string) error
+	Finish(path, hash, status, errText string) error
+	// Forget removes the entry so that the file is processed again
+	Forget(path, hash string) error
+}
+
+type FolderConfig struct {
+	Path            string
+	FilePattern     string
+	Handler         FileHandler
+	ProcessedFolder string
+	FailedFolder    string
+	AddTimestamp    bool
+	FolderOptions
+
+	// workers limits concurrent processing within this folder
+	workers chan struct{}
+}
+
+// FolderOptions holds optional per-folder settings
+type FolderOptions struct {
+	// PollInterval additionally scans the folder at this interval, whatever the trigger
+	PollInterval time.Duration
+	// ProcessedRetention deletes processed files once they are older than this, zero keeps them
+	ProcessedRetention time.Duration
+}
+
+// Manager is the single ingestion engine for watched folders. A Trigger decides when
+// folders are scanned; the manager owns concurrency, retries, the ledger and the
+// processed/failed lifecycle of every file.
+type Manager struct {
+	folderConfigs    map[string]FolderConfig
+	running          bool
+	ctx              context.Context
+	cancel           context.CancelFunc
+	mu               sync.Mutex
+	processedFiles   map[string]time.Time
+	processingFiles  map[string]bool
+	processedFilesMu sync.Mutex
+	trigger          Trigger
+	ledger           Ledger
+	retryPolicy      RetryPolicy
+	retries          map[string]*retryState
+
+	// workers limits concurrent processing across all folders
+	workers             chan struct{}
+	maxWorkersPerFolder int
+
+	// pendingScans coalesces scan requests, which are served by a single dispatcher
+	pendingScans map[string]bool
+	scanWakeups  map[string]time.Time
+	scanSignal   chan struct{}
+	starved      map[string]bool
+	scanMu       sync.Mutex
+
+	stats   map[string]*folderStats
+	statsMu sync.Mutex
+
+	// folderPollers cancels the per-folder interval scans of folders with a PollInterval
+	folderPollers map[string]context.CancelFunc
+}
+
+// NewManager creates a manager driven by trigger with the default concurrency limits
+func NewManager(trigger Trigger) *Manager {
+	return NewManagerWithLimits(trigger, DefaultMaxWorkers, DefaultMaxWorkersPerFolder)
+}
+
+// NewManagerWithLimits creates a manager that processes at most maxWorkers
+// files at once, and at most maxWorkersPerFolder files from any single folder
+func NewManagerWithLimits(trigger Trigger, maxWorkers, maxWorkersPerFolder int) *Manager {
+	if trigger == nil {
+		trigger = NewIntervalTrigger(DefaultPollInterval)
+	}
+	if maxWorkers <= 0 {
+		maxWorkers = DefaultMaxWorkers
+	}
+	if maxWorkersPerFolder <= 0 || maxWorkersPerFolder > maxWorkers {
+		maxWorkersPerFolder = maxWorkers
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		folderConfigs:       make(map[string]FolderConfig),
+		running:             false,
+		ctx:                 ctx,
+		cancel:              cancel,
+		processedFiles:      make(map[string]time.Time),
+		processingFiles:     make(map[string]bool),
+		trigger:             trigger,
+		retryPolicy:         DefaultRetryPolicy(),
+		retries:             make(map[string]*retryState),
+		workers:             make(chan struct{}, maxWorkers),
+		maxWorkersPerFolder: maxWorkersPerFolder,
+		pendingScans:        make(map[string]bool),
+		scanWakeups:         make(map[string]time.Time),
+		scanSignal:          make(chan struct{}, 1),
+		stats:               make(map[string]*folderStats),
+		folderPollers:       make(map[string]context.CancelFunc),
+	}
+}
+
+// SetLedger sets the durable ledger consulted before processing each file
+func (m *Manager) SetLedger(ledger Ledger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ledger = ledger
+}
+
+// SetRetryPolicy sets how transient processing failures are retried
+func (m *Manager) SetRetryPolicy(policy RetryPolicy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	m.retryPolicy = policy
+}
+
+func (m *Manager) AddFolder(path string, pattern string, handler FileHandler) error {
+	return m.AddFolderWithOptions(path, pattern, handler, FolderOptions{})
+}
+
+// AddFolderWithOptions watches a folder with per-folder settings. Adding a folder that is
+// already watched replaces its configuration.
+func (m *Manager) AddFolderWithOptions(path string, pattern string, handler FileHandler, options FolderOptions) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Printf("Creating folder: %s\n", path)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return fmt.Errorf("failed to create folder %s: %w", path, err)
+		}
+	}
+
+	processedFolder := filepath.Join(path, "processed")
+	failedFolder := filepath.Join(path, "failed")
+
+	if err := os.MkdirAll(processedFolder, 0755); err != nil {
+		return fmt.Errorf("failed to create processed folder %s: %w", processedFolder, err)
+	}
+
+	if err := os.MkdirAll(failedFolder, 0755); err != nil {
+		return fmt.Errorf("failed to create failed folder %s: %w", failedFolder, err)
+	}
+
+	m.folderConfigs[path] = FolderConfig{
+		Path:            path,
+		FilePattern:     pattern,
+		Handler:         handler,
+		ProcessedFolder: processedFolder,
+		FailedFolder:    failedFolder,
+		AddTimestamp:    true,
+		FolderOptions:   options,
+		workers:         make(chan struct{}, m.maxWorkersPerFolder),
+	}
+
+	if err := m.trigger.Watch(path); err != nil {
+		return err
+	}
+
+	if m.running {
+		m.startFolderPoller(m.ctx, m.folderConfigs[path])
+		m.requestScan(path)
+	}
+
+	fmt.Printf("Added folder: %s -> processed: %s, failed: %s\n", path, processedFolder, failedFolder)
+	return nil
+}
+
+// RemoveFolder stops watching a folder. Files already being processed are finished.
+func (m *Manager) RemoveFolder(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.folderConfigs[path]; !ok {
+		return errors.New("folder not found")
+	}
+
+	delete(m.folderConfigs, path)
+
+	if cancel, ok := m.folderPollers[path]; ok {
+		cancel()
+		delete(m.folderPollers, path)
+	}
+
+	if err := m.trigger.Unwatch(path); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed folder: %s\n", path)
+	return nil
+}
+
+// startFolderPoller starts the interval scan of a folder with its own PollInterval,
+// replacing any previous one. m.mu must be held.
+func (m *Manager) startFolderPoller(ctx context.Context, config FolderConfig) {
+	if cancel, ok := m.folderPollers[config.Path]; ok {
+		cancel()
+		delete(m.folderPollers, config.Path)
+	}
+
+	if config.PollInterval <= 0 {
+		return
+	}
+
+	pollerCtx, cancel := context.WithCancel(ctx)
+	m.folderPollers[config.Path] = cancel
+
+	poller := NewIntervalTrigger(config.PollInterval)
+	poller.Watch(config.Path)
+	go poller.Run(pollerCtx, m.requestScan)
+}
+
+func (m *Manager) Start() error {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return nil
+	}
+	m.running = true
+	ctx := m.ctx
+	for _, config := range m.folderConfigs {
+		m.startFolderPoller(ctx, config)
+	}
+	m.mu.Unlock()
+
+	fmt.Printf("Starting folder watcher with trigger %s\n", m.trigger.Name())
+	go m.dispatchScans(ctx)
+	go m.runTrigger(ctx)
+	go m.cleanupProcessedFiles(ctx)
+	return nil
+}
+
+func (m *Manager) Stop() error {
+	m.mu.Lock()
+	if !m.running {
+		m.mu.Unlock()
+		return nil
+	}
+	m.running = false
+	m.cancel()
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.mu.Unlock()
+
+	fmt.Println("Stopping folder watcher...")
+	fmt.Println("Folder watcher stopped")
+	return nil
+}
+
+// runTrigger runs the trigger, falling back to polling if it fails
+func (m *Manager) runTrigger(ctx context.Context) {
+	err := m.trigger.Run(ctx, m.requestScan)
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+
+	fmt.Printf("Trigger %s failed, falling back to polling every %v: %v\n", m.trigger.Name(), DefaultPollInterval, err)
+
+	fallback := NewIntervalTrigger(DefaultPollInterval)
+	for _, config := range m.Folders() {
+		fallback.Watch(config.Path)
+	}
+
+	m.mu.Lock()
+	m.trigger = fallback
+	m.mu.Unlock()
+
+	fallback.Run(ctx, m.requestScan)
+}
+
+// requestScan queues a scan of a folder. Requests for a folder already queued are coalesced.
+func (m *Manager) requestScan(folder string) {
+	m.scanMu.Lock()
+	m.pendingScans[folder] = true
+	m.scanMu.Unlock()
+
+	select {
+	case m.scanSignal <- struct{}{}:
+	default:
+	}
+}
+
+// scheduleScan queues a scan of a folder after delay, e.g. once a fresh file has settled
+// or a retry backoff has elapsed. Only the earliest pending wakeup per folder is kept.
+func (m *Manager) scheduleScan(folder string, delay time.Duration) {
+	at := time.Now().Add(delay)
+
+	m.scanMu.Lock()
+	if existing, ok := m.scanWakeups[folder]; ok && !existing.After(at) {
+		m.scanMu.Unlock()
+		return
+	}
+	m.scanWakeups[folder] = at
+	m.scanMu.Unlock()
+
+	time.AfterFunc(delay, func() {
+		m.scanMu.Lock()
+		if m.scanWakeups[folder].Equal(at) {
+			delete(m.scanWakeups, folder)
+		}
+		m.scanMu.Unlock()
+
+		m.requestScan(folder)
+	})
+}
+
+// dispatchScans serves scan requests one at a time, so a folder is never scanned concurrently
+func (m *Manager) dispatchScans(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.scanSignal:
+		}
+
+		m.scanMu.Lock()
+		folders := make([]string, 0, len(m.pendingScans))
+		for folder := range m.pendingScans {
+			folders = append(folders, folder)
+		}
+		m.pendingScans = make(map[string]bool)
+		m.scanMu.Unlock()
+
+		for _, folder := range folders {
+			m.mu.Lock()
+			config, ok := m.folderConfigs[folder]
+			m.mu.Unlock()
+
+			if ok {
+				m.checkFolder(folder, config)
+			}
+		}
+	}
+}
+
+func (m *Manager) checkFolder(folderPath string, config FolderConfig) {
+	m.recordScan(folderPath)
+
+	// Pause scanning while this folder or the whole pool is saturated, a released
+	// worker requests the scan again
+	if m.saturated(config) {
+		m.markStarved(folderPath)
+		return
+	}
+
+	files, err := os.ReadDir(folderPath)
+	if err != nil {
+		fmt.Printf("Error reading directory %s: %v\n", folderPath, err)
+		return
+	}
+
+	type candidate struct {
+		path    string
+		modTime time.Time
+	}
+	var candidates []candidate
+
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		match, err := filepath.Match(config.FilePattern, file.Name())
+		if err != nil || !match {
+			continue
+		}
+
+		filePath := filepath.Join(folderPath, file.Name())
+
+		m.processedFilesMu.Lock()
+		_, processed := m.processedFiles[filePath]
+		processing := m.processingFiles[filePath]
+		retry, retrying := m.retries[filePath]
+		backingOff := retrying && time.Now().Before(retry.NextAttemptAt)
+		m.processedFilesMu.Unlock()
+
+		if backingOff {
+			m.scheduleScan(folderPath, time.Until(retry.NextAttemptAt))
+		}
+
+		if processed || processing || backingOff {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			fmt.Printf("Error getting file info for %s: %v\n", filePath, err)
+			continue
+		}
+
+		// Give writers time to finish, and come back once the file has settled
+		if age := time.Since(info.ModTime()); age < fileSettleTime {
+			m.scheduleScan(folderPath, fileSettleTime-age)
+			continue
+		}
+
+		candidates = append(candidates, candidate{path: filePath, modTime: info.ModTime()})
+	}
+
+	// Process the oldest files first
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].modTime.Before(candidates[j].modTime)
+	})
+
+	for i, c := range candidates {
+		// Stop dispatching once workers are saturated, the rest is picked up when a worker is released
+		if !m.acquireWorker(config) {
+			m.recordPending(folderPath, len(candidates)-i)
+			m.markStarved(folderPath)
+			return
+		}
+
+		m.processedFilesMu.Lock()
+		m.processingFiles[c.path] = true
+		m.processedFilesMu.Unlock()
+
+		go func(path string, cfg FolderConfig) {
+			defer m.releaseWorker(cfg)
+			m.processFile(path, cfg.Handler, cfg)
+		}(c.path, config)
+	}
+
+	m.recordPending(folderPath, 0)
+}
+
+// saturated reports whether no worker slot is available for the folder
+func (m *Manager) saturated(config FolderConfig) bool {
+	return len(m.workers) == cap(m.workers) || len(config.workers) == cap(config.workers)
+}
+
+// acquireWorker reserves a folder and a global worker slot without blocking
+func (m *Manager) acquireWorker(config FolderConfig) bool {
+	select {
+	case config.workers <- struct{}{}:
+	default:
+		return false
+	}
+
+	select {
+	case m.workers <- struct{}{}:
+		return true
+	default:
+		<-config.workers
+		return false
+	}
+}
+
+// releaseWorker frees the slots reserved by acquireWorker and rescans folders
+// that were waiting for a worker
+func (m *Manager) releaseWorker(config FolderConfig) {
+	<-m.workers
+	<-config.workers
+
+	m.scanMu.Lock()
+	starved := m.starved
+	m.starved = nil
+	m.scanMu.Unlock()
+
+	for folder := range starved {
+		m.requestScan(folder)
+	}
+}
+
+// markStarved records that a folder has files waiting for a worker
+func (m *Manager) markStarved(folder string) {
+	m.scanMu.Lock()
+	if m.starved == nil {
+		m.starved = make(map[string]bool)
+	}
+	m.starved[folder] = true
+	m.scanMu.Unlock()
+}
+
+func (m *Manager) processFile(path string, handler FileHandler, cfg FolderConfig) {
+	m.mu.Lock()
+	ledger := m.ledger
+	policy := m.retryPolicy
+	m.mu.Unlock()
+
+	var hash string
+	var entry *LedgerEntry
+	if ledger != nil {
+		var err error
+		hash, err = hashFile(path)
+		if err != nil {
+			fmt.Printf("Error hashing file %s: %v\n", path, err)
+			m.releaseFile(path)
+			return
+		}
+
+		entry, err = ledger.Lookup(path, hash)
+		if err != nil {
+			fmt.Printf("Error reading ledger for %s: %v\n", path, err)
+			m.releaseFile(path)
+			return
+		}
+
+		// The file was already ingested but not moved, e.g. after a crash, or the same content
+		// was dropped again
+		if entry != nil && entry.Status == LedgerStatusProcessed {
+			fmt.Printf("File %s was already processed, moving it to processed\n", path)
+			m.finishFile(path)
+			m.moveFile(path, cfg.ProcessedFolder)
+			return
+		}
+
+		// A failed file dropped again is retried, starting over with a full set of attempts.
+		// The entry is forgotten so the attempts recorded by Begin also start over.
+		if entry != nil && entry.Status == LedgerStatusFailed {
+			fmt.Printf("File %s failed before, processing it again\n", path)
+			if err := ledger.Forget(path, hash); err != nil {
+				fmt.Printf("Error resetting ledger entry for %s: %v\n", path, err)
+				m.releaseFile(path)
+				return
+			}
+			entry = nil
+		}
+
+		if err := ledger.Begin(path, hash, handler.GetName()); err != nil {
+			fmt.Printf("Error recording ledger entry for %s: %v\n", path, err)
+			m.releaseFile(path)
+			return
+		}
+	}
+
+	attempt, firstAttemptAt := m.beginAttempt(path, entry)
+	attemptAt := time.Now()
+
+	err := handler.ProcessFile(path)
+
+	// Transient failures stay in place and are picked up again after a backoff
+	if err != nil && !IsPermanent(err) && attempt < policy.MaxAttempts {
+		delay := policy.Backoff(attempt)
+
+		if ledger != nil {
+			if ledgerErr := ledger.Finish(path, hash, LedgerStatusRetrying, err.Error()); ledgerErr != nil {
+				fmt.Printf("Error updating ledger for %s: %v\n", path, ledgerErr)
+			}
+		}
+
+		m.recordResult(cfg.Path, resultRetried, time.Since(attemptAt))
+		m.scheduleRetry(path, attempt, firstAttemptAt, delay)
+		fmt.Printf("Transient error processing file %s (attempt %d/%d), retrying in %v: %v\n", path, attempt, policy.MaxAttempts, delay, err)
+		return
+	}
+
+	if ledger != nil {
+		status, errText := LedgerStatusProcessed, ""
+		if err != nil {
+			status, errText = LedgerStatusFailed, err.Error()
+		}
+		if ledgerErr := ledger.Finish(path, hash, status, errText); ledgerErr != nil {
+			fmt.Printf("Error updating ledger for %s: %v\n", path, ledgerErr)
+		}
+	}
+
+	m.finishFile(path)
+
+	if err != nil {
+		m.recordResult(cfg.Path, resultFailed, time.Since(attemptAt))
+		fmt.Printf("Error processing file %s after %d attempt(s): %v\n", path, attempt, err)
+		m.moveFile(path, cfg.FailedFolder)
+
+		if cfg.FailedFolder != "" {
+			sidecar := ErrorSidecar{
+				File:           filepath.Base(path),
+				Handler:        handler.GetName(),
+				Error:          err.Error(),
+				ErrorChain:     errorChain(err),
+				Classification: classify(err),
+				Attempts:       attempt,
+				FirstAttemptAt: firstAttemptAt,
+				LastAttemptAt:  attemptAt,
+				FailedAt:       time.Now(),
+			}
+			if sidecarErr := writeSidecar(cfg.FailedFolder, sidecar); sidecarErr != nil {
+				fmt.Printf("Error writing sidecar for %s: %v\n", path, sidecarErr)
+			}
+		}
+	} else {
+		m.recordResult(cfg.Path, resultProcessed, time.Since(attemptAt))
+		m.moveFile(path, cfg.ProcessedFolder)
+	}
+}
+
+// beginAttempt returns the number of the attempt being started and when the first attempt began.
+// After a restart the attempt count is recovered from the ledger entry.
+func (m *Manager) beginAttempt(path string, entry *LedgerEntry) (int, time.Time) {
+	m.processedFilesMu.Lock()
+	defer m.processedFilesMu.Unlock()
+
+	if retry, ok := m.retries[path]; ok {
+		return retry.Attempts + 1, retry.FirstAttemptAt
+	}
+
+	attempt := 1
+	if entry != nil {
+		attempt = entry.Attempts + 1
+	}
+
+	return attempt, time.Now()
+}
+
+// scheduleRetry records a failed attempt and releases the file until its backoff has elapsed
+func (m *Manager) scheduleRetry(path string, attempt int, firstAttemptAt time.Time, delay time.Duration) {
+	m.processedFilesMu.Lock()
+	m.retries[path] = &retryState{
+		Attempts:       attempt,
+		FirstAttemptAt: firstAttemptAt,
+		NextAttemptAt:  time.Now().Add(delay),
+	}
+	delete(m.processingFiles, path)
+	m.processedFilesMu.Unlock()
+
+	m.scheduleScan(filepath.Dir(path), delay)
+}
+
+// finishFile marks a file as processed in memory
+func (m *Manager) finishFile(path string) {
+	m.processedFilesMu.Lock()
+	m.processedFiles[path] = time.Now()
+	delete(m.processingFiles, path)
+	delete(m.retries, path)
+	m.processedFilesMu.Unlock()
+}
+
+// releaseFile clears the in-flight flag so the file is picked up again on a later scan
+func (m *Manager) releaseFile(path string) {
+	m.processedFilesMu.Lock()
+	delete(m.processingFiles, path)
+	m.processedFilesMu.Unlock()
+
+	m.scheduleScan(filepath.Dir(path), DefaultPollInterval)
+}
+
+// hashFile returns the hex-encoded SHA-256 of a file's contents
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+func (m *Manager) moveFile(srcPath, destFolder string) {
+	if destFolder == "" {
+		return
+	}
+
+	fileName := filepath.Base(srcPath)
+
+	destPath := filepath.Join(destFolder, fileName)
+
+	if err := m.copyFile(srcPath, destPath); err != nil {
+		fmt.Printf("Error copying file %s to %s: %v\n", srcPath, destPath, err)
+		return
+	}
+
+	if err := os.Remove(srcPath); err != nil {
+		fmt.Printf("Error removing original file %s: %v\n", srcPath, err)
+		return
+	}
+
+	// The file has left the folder, so a file dropped again under the same name is picked up
+	m.processedFilesMu.Lock()
+	delete(m.processedFiles, srcPath)
+	m.processedFilesMu.Unlock()
+
+	// fmt.Printf("Moved file %s to %s\n", srcPath, destPath)
+}
+
+func (m *Manager) copyFile(src, dst string) error {
+	srcFile, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	dstFile, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer dstFile.Close()
+
+	_, err = io.Copy(dstFile, srcFile)
+	return err
+}
+
+func (m *Manager) cleanupProcessedFiles(ctx context.Context) {
+	ticker := time.NewTicker(30 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.processedFilesMu.Lock()
+			now := time.Now()
+			for file, processTime := range m.processedFiles {
+				if now.Sub(processTime) > 2*time.Hour {
+					delete(m.processedFiles, file)
+				}
+			}
+			for file, retry := range m.retries {
+				if now.Sub(retry.NextAttemptAt) > 2*time.Hour {
+					delete(m.retries, file)
+				}
+			}
+			m.processedFilesMu.Unlock()
+
+			m.purgeProcessedFolders()
+		}
+	}
+}
+
+// purgeProcessedFolders deletes processed files older than their folder's retention
+func (m *Manager) purgeProcessedFolders() {
+	for _, config := range m.Folders() {
+		if config.ProcessedRetention <= 0 {
+			continue
+		}
+
+		entries, err := os.ReadDir(config.ProcessedFolder)
+		if err != nil {
+			fmt.Printf("Error reading directory %s: %v\n", config.ProcessedFolder, err)
+			continue
+		}
+
+		cutoff := time.Now().Add(-config.ProcessedRetention)
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+
+			info, err := entry.Info()
+			if err != nil || info.ModTime().After(cutoff) {
+				continue
+			}
+
+			if err := os.Remove(filepath.Join(config.ProcessedFolder, entry.Name())); err != nil {
+				fmt.Printf("Error removing processed file %s: %v\n", entry.Name(), err)
+			}
+		}
+	}
+}
+
+type JSONHandler struct {
+	name      string
+	processor func(filePath string, data map[string]interface{}) error
+}
+
+func (h *JSONHandler) GetName() string {
+	return h.name
+}
+
+func (h *JSONHandler) ProcessFile(filePath string) error {
+	fileData, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(fileData, &data); err != nil {
+		return fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	if h.processor != nil {
+		if err := h.processor(filePath, data); err != nil {
+			return fmt.Errorf("processor error: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func NewJSONHandler(name string, processor func(filePath string, data map[string]interface{}) error) *JSONHandler {
+	return &JSONHandler{
+		name:      name,
+		processor: processor,
+	}
+}
@@ -0,0 +1,290 @@
+package watcher
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryLedger keeps ledger entries in memory, counting attempts like the database ledger
+type memoryLedger struct {
+	mu      sync.Mutex
+	entries map[string]*LedgerEntry
+}
+
+func newMemoryLedger() *memoryLedger {
+	return &memoryLedger{entries: make(map[string]*LedgerEntry)}
+}
+
+func (l *memoryLedger) key(path, hash string) string {
+	return path + "|" + hash
+}
+
+func (l *memoryLedger) Lookup(path, hash string) (*LedgerEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[l.key(path, hash)]
+	if !ok {
+		return nil, nil
+	}
+	copied := *entry
+	return &copied, nil
+}
+
+func (l *memoryLedger) Begin(path, hash, handler string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[l.key(path, hash)]
+	if !ok {
+		entry = &LedgerEntry{}
+		l.entries[l.key(path, hash)] = entry
+	}
+	entry.Status = LedgerStatusProcessing
+	entry.Attempts++
+	return nil
+}
+
+func (l *memoryLedger) Finish(path, hash, status, errText string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[l.key(path, hash)]
+	if !ok {
+		return errors.New("ledger entry not found")
+	}
+	entry.Status = status
+	return nil
+}
+
+func (l *memoryLedger) Forget(path, hash string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.entries, l.key(path, hash))
+	return nil
+}
+
+// scriptedHandler returns the given errors in turn, then succeeds
+type scriptedHandler struct {
+	errs  []error
+	calls int
+}
+
+func (h *scriptedHandler) ProcessFile(filePath string) error {
+	h.calls++
+	if h.calls <= len(h.errs) {
+		return h.errs[h.calls-1]
+	}
+	return nil
+}
+
+func (h *scriptedHandler) GetName() string {
+	return "scripted"
+}
+
+// newTestFolder creates a watched folder with a file and returns the folder config and the
+// path of the file
+func newTestFolder(t *testing.T, handler FileHandler) (FolderConfig, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	cfg := FolderConfig{
+		Path:            dir,
+		FilePattern:     "*.json",
+		Handler:         handler,
+		ProcessedFolder: filepath.Join(dir, "processed"),
+		FailedFolder:    filepath.Join(dir, "failed"),
+	}
+	for _, folder := range []string{cfg.ProcessedFolder, cfg.FailedFolder} {
+		if err := os.MkdirAll(folder, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := filepath.Join(dir, "detection.json")
+	if err := os.WriteFile(path, []byte(`{"camera_id": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return cfg, path
+}
+
+func newTestManager(ledger Ledger, maxAttempts int) *Manager {
+	m := NewManagerWithLimits(nil, 2, 1)
+	m.SetLedger(ledger)
+	m.SetRetryPolicy(RetryPolicy{MaxAttempts: maxAttempts, InitialBackoff: time.Hour, MaxBackoff: time.Hour})
+	return m
+}
+
+func ledgerEntry(t *testing.T, ledger *memoryLedger, path string) *LedgerEntry {
+	t.Helper()
+
+	hash, err := hashFile(path)
+	if err != nil {
+		t.Fatalf("hash %s: %v", path, err)
+	}
+	entry, _ := ledger.Lookup(path, hash)
+	return entry
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestProcessFileRetriesTransientErrors(t *testing.T) {
+	transient := errors.New("database timeout")
+	handler := &scriptedHandler{errs: []error{transient, transient, transient}}
+	cfg, path := newTestFolder(t, handler)
+	ledger := newMemoryLedger()
+	m := newTestManager(ledger, 3)
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		m.processFile(path, handler, cfg)
+
+		if !fileExists(path) {
+			t.Fatalf("attempt %d: file left the folder before running out of attempts", attempt)
+		}
+		if entry := ledgerEntry(t, ledger, path); entry == nil || entry.Status != LedgerStatusRetrying || entry.Attempts != attempt {
+			t.Fatalf("attempt %d: ledger entry %+v", attempt, entry)
+		}
+		retry := m.retries[path]
+		if retry == nil || retry.Attempts != attempt || time.Until(retry.NextAttemptAt) < 59*time.Minute {
+			t.Fatalf("attempt %d: retry state %+v", attempt, retry)
+		}
+	}
+
+	hash, _ := hashFile(path)
+	m.processFile(path, handler, cfg)
+
+	if fileExists(path) || !fileExists(filepath.Join(cfg.FailedFolder, "detection.json")) {
+		t.Fatal("file was not moved to the failed folder after the last attempt")
+	}
+	if entry, _ := ledger.Lookup(path, hash); entry == nil || entry.Status != LedgerStatusFailed || entry.Attempts != 3 {
+		t.Fatalf("ledger entry %+v, want failed after 3 attempts", entry)
+	}
+
+	data, err := os.ReadFile(SidecarPath(cfg.FailedFolder, "detection.json"))
+	if err != nil {
+		t.Fatalf("sidecar: %v", err)
+	}
+	var sidecar ErrorSidecar
+	if err := json.Unmarshal(data, &sidecar); err != nil {
+		t.Fatalf("sidecar: %v", err)
+	}
+	if sidecar.Attempts != 3 || sidecar.Classification != "transient" || sidecar.Error != "database timeout" {
+		t.Errorf("unexpected sidecar %+v", sidecar)
+	}
+}
+
+func TestProcessFilePermanentErrorIsNotRetried(t *testing.T) {
+	handler := &scriptedHandler{errs: []error{Permanent(errors.New("missing camera_id"))}}
+	cfg, path := newTestFolder(t, handler)
+	ledger := newMemoryLedger()
+	m := newTestManager(ledger, 5)
+
+	hash, _ := hashFile(path)
+	m.processFile(path, handler, cfg)
+
+	if handler.calls != 1 {
+		t.Errorf("handler called %d times, want 1", handler.calls)
+	}
+	if !fileExists(filepath.Join(cfg.FailedFolder, "detection.json")) {
+		t.Fatal("file was not moved to the failed folder")
+	}
+	if entry, _ := ledger.Lookup(path, hash); entry == nil || entry.Status != LedgerStatusFailed || entry.Attempts != 1 {
+		t.Fatalf("ledger entry %+v, want failed after 1 attempt", entry)
+	}
+}
+
+func TestProcessFileResumesAttemptsAfterRestart(t *testing.T) {
+	handler := &scriptedHandler{errs: []error{errors.New("database timeout")}}
+	cfg, path := newTestFolder(t, handler)
+	ledger := newMemoryLedger()
+
+	// Two attempts were made before the restart
+	hash, _ := hashFile(path)
+	ledger.entries[ledger.key(path, hash)] = &LedgerEntry{Status: LedgerStatusRetrying, Attempts: 2}
+
+	m := newTestManager(ledger, 3)
+	m.processFile(path, handler, cfg)
+
+	if !fileExists(filepath.Join(cfg.FailedFolder, "detection.json")) {
+		t.Fatal("the third attempt did not move the file to the failed folder")
+	}
+	if entry, _ := ledger.Lookup(path, hash); entry == nil || entry.Status != LedgerStatusFailed || entry.Attempts != 3 {
+		t.Fatalf("ledger entry %+v, want failed after 3 attempts", entry)
+	}
+}
+
+func TestProcessFileSkipsProcessedContent(t *testing.T) {
+	handler := &scriptedHandler{}
+	cfg, path := newTestFolder(t, handler)
+	ledger := newMemoryLedger()
+
+	hash, _ := hashFile(path)
+	ledger.entries[ledger.key(path, hash)] = &LedgerEntry{Status: LedgerStatusProcessed, Attempts: 1}
+
+	m := newTestManager(ledger, 3)
+	m.processFile(path, handler, cfg)
+
+	if handler.calls != 0 {
+		t.Errorf("handler called %d times for content already processed", handler.calls)
+	}
+	if !fileExists(filepath.Join(cfg.ProcessedFolder, "detection.json")) {
+		t.Fatal("file was not moved to the processed folder")
+	}
+}
+
+func TestProcessFileRetriesFailedFileDroppedAgain(t *testing.T) {
+	transient := errors.New("database timeout")
+	handler := &scriptedHandler{errs: []error{transient}}
+	cfg, path := newTestFolder(t, handler)
+	ledger := newMemoryLedger()
+
+	// The same content failed after every attempt before
+	hash, _ := hashFile(path)
+	ledger.entries[ledger.key(path, hash)] = &LedgerEntry{Status: LedgerStatusFailed, Attempts: 3}
+
+	m := newTestManager(ledger, 3)
+	m.processFile(path, handler, cfg)
+
+	if !fileExists(path) {
+		t.Fatal("a failed file dropped again went straight back to the failed folder")
+	}
+	if entry, _ := ledger.Lookup(path, hash); entry == nil || entry.Status != LedgerStatusRetrying || entry.Attempts != 1 {
+		t.Fatalf("ledger entry %+v, want retrying after 1 attempt", entry)
+	}
+
+	// After a restart the attempts resume from the ledger, not from the earlier failure
+	m = newTestManager(ledger, 3)
+	m.processFile(path, handler, cfg)
+
+	if !fileExists(filepath.Join(cfg.ProcessedFolder, "detection.json")) {
+		t.Fatal("file was not processed on its second attempt")
+	}
+	if entry, _ := ledger.Lookup(path, hash); entry == nil || entry.Status != LedgerStatusProcessed || entry.Attempts != 2 {
+		t.Fatalf("ledger entry %+v, want processed after 2 attempts", entry)
+	}
+}
+
+func TestMovedFileIsPickedUpWhenDroppedAgain(t *testing.T) {
+	handler := &scriptedHandler{}
+	cfg, path := newTestFolder(t, handler)
+	m := newTestManager(nil, 3)
+
+	m.processFile(path, handler, cfg)
+
+	m.processedFilesMu.Lock()
+	_, remembered := m.processedFiles[path]
+	m.processedFilesMu.Unlock()
+	if remembered {
+		t.Error("a file moved out of the folder is still skipped by scans")
+	}
+}
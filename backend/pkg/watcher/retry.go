@@ -0,0 +1,156 @@
+package watcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how transient processing failures are retried
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+}
+
+// DefaultRetryPolicy returns the retry policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 5 * time.Second,
+		MaxBackoff:     5 * time.Minute,
+		Multiplier:     2,
+	}
+}
+
+// Backoff returns the delay before the attempt following the given attempt number
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		delay *= multiplier
+		if p.MaxBackoff > 0 && delay >= float64(p.MaxBackoff) {
+			return p.MaxBackoff
+		}
+	}
+
+	return time.Duration(delay)
+}
+
+// PermanentError marks a processing error that will not succeed on retry,
+// such as malformed JSON or a missing required field
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent wraps err so that it is not retried
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// IsPermanent reports whether err should go straight to the failed folder. Explicitly
+// marked errors, JSON decoding errors, timestamp parse errors and missing files are permanent;
+// everything else, such as database timeouts, is considered transient.
+func IsPermanent(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var permanentErr *PermanentError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var parseErr *time.ParseError
+
+	return errors.As(err, &permanentErr) ||
+		errors.As(err, &syntaxErr) ||
+		errors.As(err, &typeErr) ||
+		errors.As(err, &parseErr) ||
+		errors.Is(err, os.ErrNotExist)
+}
+
+// classify returns the classification name of an error for reporting
+func classify(err error) string {
+	if IsPermanent(err) {
+		return "permanent"
+	}
+	return "transient"
+}
+
+// retryState tracks the attempts made for a file that is waiting to be retried
+type retryState struct {
+	Attempts       int
+	FirstAttemptAt time.Time
+	NextAttemptAt  time.Time
+}
+
+// ErrorSidecar is written next to every file moved to the failed folder
+type ErrorSidecar struct {
+	File           string    `json:"file"`
+	Handler        string    `json:"handler"`
+	Error          string    `json:"error"`
+	ErrorChain     []string  `json:"error_chain"`
+	Classification string    `json:"classification"`
+	Attempts       int       `json:"attempts"`
+	FirstAttemptAt time.Time `json:"first_attempt_at"`
+	LastAttemptAt  time.Time `json:"last_attempt_at"`
+	FailedAt       time.Time `json:"failed_at"`
+}
+
+// SidecarPath returns the path of the error sidecar for a file in the failed folder
+func SidecarPath(failedFolder, fileName string) string {
+	base := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	return filepath.Join(failedFolder, base+".error.json")
+}
+
+// IsSidecar reports whether a file name is an error sidecar
+func IsSidecar(fileName string) bool {
+	return strings.HasSuffix(fileName, ".error.json")
+}
+
+// errorChain returns the message of err and of every error it wraps, skipping
+// wrappers such as PermanentError that add no message of their own
+func errorChain(err error) []string {
+	var chain []string
+	for err != nil {
+		if msg := err.Error(); len(chain) == 0 || chain[len(chain)-1] != msg {
+			chain = append(chain, msg)
+		}
+		err = errors.Unwrap(err)
+	}
+	return chain
+}
+
+// writeSidecar writes the error sidecar for a failed file
+func writeSidecar(failedFolder string, sidecar ErrorSidecar) error {
+	data, err := json.MarshalIndent(sidecar, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode error sidecar: %w", err)
+	}
+
+	path := SidecarPath(failedFolder, sidecar.File)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write error sidecar %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package watcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 10 * time.Second, Multiplier: 3}
+
+	want := []time.Duration{time.Second, 3 * time.Second, 9 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, delay := range want {
+		if got := policy.Backoff(i + 1); got != delay {
+			t.Errorf("Backoff(%d) = %v, want %v", i+1, got, delay)
+		}
+	}
+
+	// A multiplier below 1 would never grow the delay, it doubles instead
+	policy.Multiplier = 0
+	if got := policy.Backoff(2); got != 2*time.Second {
+		t.Errorf("Backoff(2) with no multiplier = %v, want 2s", got)
+	}
+}
+
+func TestIsPermanent(t *testing.T) {
+	var syntaxErr *json.SyntaxError
+	jsonErr := json.Unmarshal([]byte("{"), &struct{}{})
+	if !errors.As(jsonErr, &syntaxErr) {
+		t.Fatalf("expected a JSON syntax error, got %v", jsonErr)
+	}
+
+	_, missingErr := os.Open("/does/not/exist")
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"transient", errors.New("connection refused"), false},
+		{"marked permanent", Permanent(errors.New("bad payload")), true},
+		{"wrapped permanent", fmt.Errorf("processing: %w", Permanent(errors.New("bad payload"))), true},
+		{"json syntax", fmt.Errorf("decoding: %w", jsonErr), true},
+		{"missing file", missingErr, true},
+	}
+
+	for _, tt := range tests {
+		if got := IsPermanent(tt.err); got != tt.want {
+			t.Errorf("%s: IsPermanent = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestErrorChainSkipsRepeatedMessages(t *testing.T) {
+	root := errors.New("connection refused")
+	err := fmt.Errorf("failed to save alert: %w", Permanent(root))
+
+	want := []string{"failed to save alert: connection refused", "connection refused"}
+	if got := errorChain(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("errorChain = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,148 @@
+package watcher
+
+import (
+	"path/filepath"
+	"time"
+)
+
+// Processing outcomes counted per folder
+const (
+	resultProcessed = "processed"
+	resultFailed    = "failed"
+	resultRetried   = "retried"
+)
+
+// folderStats accumulates metrics for a watched folder
+type folderStats struct {
+	processed       int64
+	failed          int64
+	retried         int64
+	pending         int
+	totalDuration   time.Duration
+	lastScanAt      time.Time
+	lastProcessedAt time.Time
+}
+
+// FolderStats is a snapshot of the metrics of a watched folder
+type FolderStats struct {
+	Path            string
+	Handler         string
+	InFlight        int
+	Pending         int
+	Retrying        int
+	Processed       int64
+	Failed          int64
+	Retried         int64
+	AvgDuration     time.Duration
+	LastScanAt      *time.Time
+	LastProcessedAt *time.Time
+}
+
+// Stats is a snapshot of the metrics of the manager
+type Stats struct {
+	Trigger             string
+	Running             bool
+	MaxWorkers          int
+	MaxWorkersPerFolder int
+	ActiveWorkers       int
+	Folders             []FolderStats
+}
+
+// folderStatsLocked returns the stats of a folder, creating them if needed. statsMu must be held.
+func (m *Manager) folderStatsLocked(folder string) *folderStats {
+	stats, ok := m.stats[folder]
+	if !ok {
+		stats = &folderStats{}
+		m.stats[folder] = stats
+	}
+	return stats
+}
+
+// recordScan records that a folder was scanned
+func (m *Manager) recordScan(folder string) {
+	m.statsMu.Lock()
+	m.folderStatsLocked(folder).lastScanAt = time.Now()
+	m.statsMu.Unlock()
+}
+
+// recordPending records how many files of a folder are waiting for a worker
+func (m *Manager) recordPending(folder string, pending int) {
+	m.statsMu.Lock()
+	m.folderStatsLocked(folder).pending = pending
+	m.statsMu.Unlock()
+}
+
+// recordResult records the outcome and duration of a processing attempt
+func (m *Manager) recordResult(folder, result string, duration time.Duration) {
+	m.statsMu.Lock()
+	defer m.statsMu.Unlock()
+
+	stats := m.folderStatsLocked(folder)
+	switch result {
+	case resultProcessed:
+		stats.processed++
+	case resultFailed:
+		stats.failed++
+	case resultRetried:
+		stats.retried++
+	}
+	stats.totalDuration += duration
+	stats.lastProcessedAt = time.Now()
+}
+
+// Stats returns a snapshot of the manager's metrics
+func (m *Manager) Stats() Stats {
+	m.mu.Lock()
+	snapshot := Stats{
+		Trigger:             m.trigger.Name(),
+		Running:             m.running,
+		MaxWorkers:          cap(m.workers),
+		MaxWorkersPerFolder: m.maxWorkersPerFolder,
+		ActiveWorkers:       len(m.workers),
+	}
+	m.mu.Unlock()
+
+	folders := m.Folders()
+
+	m.processedFilesMu.Lock()
+	retrying := make(map[string]int)
+	for path := range m.retries {
+		retrying[filepath.Dir(path)]++
+	}
+	m.processedFilesMu.Unlock()
+
+	m.statsMu.Lock()
+	defer m.statsMu.Unlock()
+
+	for _, config := range folders {
+		folder := FolderStats{
+			Path:     config.Path,
+			Handler:  config.Handler.GetName(),
+			InFlight: len(config.workers),
+			Retrying: retrying[config.Path],
+		}
+
+		if stats, ok := m.stats[config.Path]; ok {
+			folder.Pending = stats.pending
+			folder.Processed = stats.processed
+			folder.Failed = stats.failed
+			folder.Retried = stats.retried
+
+			if attempts := stats.processed + stats.failed + stats.retried; attempts > 0 {
+				folder.AvgDuration = stats.totalDuration / time.Duration(attempts)
+			}
+			if !stats.lastScanAt.IsZero() {
+				lastScanAt := stats.lastScanAt
+				folder.LastScanAt = &lastScanAt
+			}
+			if !stats.lastProcessedAt.IsZero() {
+				lastProcessedAt := stats.lastProcessedAt
+				folder.LastProcessedAt = &lastProcessedAt
+			}
+		}
+
+		snapshot.Folders = append(snapshot.Folders, folder)
+	}
+
+	return snapshot
+}
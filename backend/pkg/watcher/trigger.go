@@ -0,0 +1,294 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Trigger kinds accepted by NewTrigger
+const (
+	TriggerPolling  = "polling"
+	TriggerFSNotify = "fsnotify"
+	TriggerHybrid   = "hybrid"
+)
+
+// DefaultPollInterval is the scan interval used by the polling trigger and as the fallback
+// when filesystem notifications are unavailable
+const DefaultPollInterval = 5 * time.Second
+
+// Trigger decides when the manager scans a watched folder. The manager owns the
+// processed/failed lifecycle, a trigger only requests scans.
+type Trigger interface {
+	// Name identifies the trigger in logs and metrics
+	Name() string
+	// Watch adds a folder to the trigger, it may be called before or while running
+	Watch(folder string) error
+	// Unwatch removes a folder from the trigger
+	Unwatch(folder string) error
+	// Run calls scan whenever a folder should be scanned, until ctx is cancelled
+	Run(ctx context.Context, scan func(folder string)) error
+}
+
+// NewTrigger creates a trigger by kind: polling scans every pollInterval, fsnotify reacts to
+// filesystem events, and hybrid combines fsnotify with a periodic reconciliation scan
+func NewTrigger(kind string, pollInterval, reconcileInterval time.Duration) (Trigger, error) {
+	switch kind {
+	case TriggerPolling:
+		return NewIntervalTrigger(pollInterval), nil
+	case TriggerFSNotify:
+		return NewFSNotifyTrigger(), nil
+	case TriggerHybrid, "":
+		return NewHybridTrigger(reconcileInterval), nil
+	default:
+		return nil, fmt.Errorf("unknown trigger %q, must be polling, fsnotify, or hybrid", kind)
+	}
+}
+
+// folderSet is a concurrency-safe list of watched folders
+type folderSet struct {
+	mu      sync.Mutex
+	folders []string
+}
+
+func (s *folderSet) add(folder string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, f := range s.folders {
+		if f == folder {
+			return false
+		}
+	}
+	s.folders = append(s.folders, folder)
+	return true
+}
+
+func (s *folderSet) remove(folder string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, f := range s.folders {
+		if f == folder {
+			s.folders = append(s.folders[:i], s.folders[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *folderSet) list() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.folders...)
+}
+
+func (s *folderSet) contains(folder string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, f := range s.folders {
+		if f == folder {
+			return true
+		}
+	}
+	return false
+}
+
+// IntervalTrigger scans every folder at a fixed interval
+type IntervalTrigger struct {
+	interval time.Duration
+	folders  folderSet
+}
+
+// NewIntervalTrigger creates a polling trigger
+func NewIntervalTrigger(interval time.Duration) *IntervalTrigger {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	return &IntervalTrigger{interval: interval}
+}
+
+func (t *IntervalTrigger) Name() string {
+	return fmt.Sprintf("%s(%v)", TriggerPolling, t.interval)
+}
+
+func (t *IntervalTrigger) Watch(folder string) error {
+	t.folders.add(folder)
+	return nil
+}
+
+func (t *IntervalTrigger) Unwatch(folder string) error {
+	t.folders.remove(folder)
+	return nil
+}
+
+func (t *IntervalTrigger) Run(ctx context.Context, scan func(folder string)) error {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		for _, folder := range t.folders.list() {
+			scan(folder)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// FSNotifyTrigger scans a folder as soon as a file is created, written or moved into it
+type FSNotifyTrigger struct {
+	folders folderSet
+
+	mu      sync.Mutex
+	watcher *fsnotify.Watcher
+}
+
+// NewFSNotifyTrigger creates a filesystem notification trigger
+func NewFSNotifyTrigger() *FSNotifyTrigger {
+	return &FSNotifyTrigger{}
+}
+
+func (t *FSNotifyTrigger) Name() string {
+	return TriggerFSNotify
+}
+
+func (t *FSNotifyTrigger) Watch(folder string) error {
+	if !t.folders.add(folder) {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.watcher != nil {
+		if err := t.watcher.Add(folder); err != nil {
+			return fmt.Errorf("failed to watch folder %s: %w", folder, err)
+		}
+	}
+	return nil
+}
+
+func (t *FSNotifyTrigger) Unwatch(folder string) error {
+	if !t.folders.remove(folder) {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.watcher != nil {
+		if err := t.watcher.Remove(folder); err != nil {
+			return fmt.Errorf("failed to unwatch folder %s: %w", folder, err)
+		}
+	}
+	return nil
+}
+
+func (t *FSNotifyTrigger) Run(ctx context.Context, scan func(folder string)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	t.mu.Lock()
+	t.watcher = watcher
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		t.watcher = nil
+		t.mu.Unlock()
+		watcher.Close()
+	}()
+
+	for _, folder := range t.folders.list() {
+		if err := watcher.Add(folder); err != nil {
+			return fmt.Errorf("failed to watch folder %s: %w", folder, err)
+		}
+	}
+
+	// Pick up files that arrived before the watcher was started
+	for _, folder := range t.folders.list() {
+		scan(folder)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			// Only interested in files appearing or changing
+			if event.Op&(fsnotify.Create|fsnotify.Write) == 0 {
+				continue
+			}
+
+			folder := filepath.Dir(event.Name)
+			if t.folders.contains(folder) {
+				scan(folder)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			// Events may have been dropped, rescan everything
+			fmt.Printf("Watcher error: %v\n", err)
+			for _, folder := range t.folders.list() {
+				scan(folder)
+			}
+		}
+	}
+}
+
+// HybridTrigger reacts to filesystem events and periodically reconciles every folder,
+// so files missed by notifications (network mounts, dropped events) are still ingested
+type HybridTrigger struct {
+	notify    *FSNotifyTrigger
+	reconcile *IntervalTrigger
+}
+
+// NewHybridTrigger creates a trigger combining fsnotify with a reconciliation scan
+func NewHybridTrigger(reconcileInterval time.Duration) *HybridTrigger {
+	return &HybridTrigger{
+		notify:    NewFSNotifyTrigger(),
+		reconcile: NewIntervalTrigger(reconcileInterval),
+	}
+}
+
+func (t *HybridTrigger) Name() string {
+	return fmt.Sprintf("%s(%s+%s)", TriggerHybrid, t.notify.Name(), t.reconcile.Name())
+}
+
+func (t *HybridTrigger) Watch(folder string) error {
+	if err := t.reconcile.Watch(folder); err != nil {
+		return err
+	}
+	return t.notify.Watch(folder)
+}
+
+func (t *HybridTrigger) Unwatch(folder string) error {
+	if err := t.reconcile.Unwatch(folder); err != nil {
+		return err
+	}
+	return t.notify.Unwatch(folder)
+}
+
+func (t *HybridTrigger) Run(ctx context.Context, scan func(folder string)) error {
+	go t.reconcile.Run(ctx, scan)
+
+	// Keep reconciling even if notifications cannot be set up
+	if err := t.notify.Run(ctx, scan); err != nil {
+		fmt.Printf("Filesystem notifications unavailable, relying on reconciliation every %v: %v\n", t.reconcile.interval, err)
+		<-ctx.Done()
+	}
+	return nil
+}
@@ -22,10 +22,13 @@ CREATE TABLE IF NOT EXISTS "public"."alert_types" (
   "icon" varchar(50) COLLATE "pg_catalog"."default",
   "color" varchar(20) COLLATE "pg_catalog"."default",
   "description" text COLLATE "pg_catalog"."default",
+  "escalation_policy_id" int8,
+  "escalation_policy_attached_at" timestamptz(6),
   "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
   "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
   "display_name" varchar(50) COLLATE "pg_catalog"."default",
-  "severity" varchar(255) COLLATE "pg_catalog"."default",
+  "severity" varchar(255) COLLATE "pg_catalog"."default" DEFAULT 'high'::character varying,
+  "archived_at" timestamptz(6),
   PRIMARY KEY ("id")
 );
 
@@ -41,6 +44,33 @@ VALUES
 (5, 'personal-protective-equipment', 'exclamation-circlewarning-circle', '#1890ff', 'Personal protective equipment violation detected', '2025-08-04 10:11:29.819417+07', '2025-08-04 10:11:29.819417+07', 'Personal Protective Equipment', 'high')
 ON CONFLICT (id) DO NOTHING;
 
+-- ----------------------------
+-- Table structure for alert_type_messages
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."alert_type_messages" (
+  "id" bigserial PRIMARY KEY,
+  "alert_type_id" int8 NOT NULL,
+  "language" varchar(10) COLLATE "pg_catalog"."default" NOT NULL,
+  "template" text COLLATE "pg_catalog"."default" NOT NULL,
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alert_type_messages_language ON alert_type_messages(alert_type_id, language);
+
+-- ----------------------------
+-- Table structure for alert_type_aliases
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."alert_type_aliases" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar(50) COLLATE "pg_catalog"."default" NOT NULL,
+  "alert_type_id" int8 NOT NULL,
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alert_type_aliases_name ON alert_type_aliases(name);
+CREATE INDEX IF NOT EXISTS idx_alert_type_aliases_alert_type_id ON alert_type_aliases(alert_type_id);
+
 -- ----------------------------
 -- Table structure for alerts
 -- ----------------------------
@@ -51,13 +81,28 @@ CREATE TABLE IF NOT EXISTS "public"."alerts" (
   "message" text COLLATE "pg_catalog"."default" NOT NULL,
   "severity" varchar(20) COLLATE "pg_catalog"."default" NOT NULL,
   "image_url" varchar(255) COLLATE "pg_catalog"."default",
+  "clip_path" varchar(255) COLLATE "pg_catalog"."default",
+  "object_id" varchar(100) COLLATE "pg_catalog"."default",
+  "object_name" varchar(100) COLLATE "pg_catalog"."default",
+  "missing_item" varchar(255) COLLATE "pg_catalog"."default",
+  "language" varchar(10) COLLATE "pg_catalog"."default",
+  "suppressed" bool NOT NULL DEFAULT false,
+  "suppressed_by" varchar(150) COLLATE "pg_catalog"."default",
   "is_active" bool DEFAULT true,
+  "status" varchar(20) COLLATE "pg_catalog"."default" DEFAULT 'new'::character varying,
+  "assigned_to" varchar(100) COLLATE "pg_catalog"."default",
+  "acknowledged_at" timestamptz(6),
+  "acknowledged_by" varchar(100) COLLATE "pg_catalog"."default",
   "detected_at" timestamptz(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "occurrence_count" int8 DEFAULT 1,
+  "first_seen_at" timestamptz(6),
+  "last_seen_at" timestamptz(6),
   "resolved_at" timestamptz(6),
   "resolved_by" varchar(100) COLLATE "pg_catalog"."default",
   "resolution_note" text COLLATE "pg_catalog"."default",
   "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
-  "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP
+  "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+  "config_version" int8
 );
 
 -- Convert alerts to TimescaleDB hypertable (if not already)
@@ -79,12 +124,18 @@ CREATE SEQUENCE IF NOT EXISTS cameras_id_seq
 CREATE TABLE IF NOT EXISTS "public"."cameras" (
   "id" int4 NOT NULL DEFAULT nextval('cameras_id_seq'::regclass),
   "name" varchar(100) COLLATE "pg_catalog"."default" NOT NULL,
-  "ip_address" varchar(15) COLLATE "pg_catalog"."default",
+  "ip_address" varchar(45) COLLATE "pg_catalog"."default",
   "location" varchar(100) COLLATE "pg_catalog"."default",
   "status" varchar(20) COLLATE "pg_catalog"."default" DEFAULT 'active'::character varying,
+  "capacity" int4 DEFAULT 0,
   "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
   "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
-  "ws_url" varchar(255) COLLATE "pg_catalog"."default"
+  "ws_url" varchar(255) COLLATE "pg_catalog"."default",
+  "latitude" float8,
+  "longitude" float8,
+  "floor" int8,
+  "bearing" float8,
+  "field_of_view" float8
 );
 
 -- ----------------------------
@@ -115,10 +166,13 @@ CREATE TABLE IF NOT EXISTS "public"."people_counts" (
   "child_count" int4 DEFAULT 0,
   "adult_count" int4 DEFAULT 0,
   "elderly_count" int4 DEFAULT 0,
+  "in_count" int4 DEFAULT 0,
+  "out_count" int4 DEFAULT 0,
   "total_count" int4 GENERATED ALWAYS AS (
 (male_count + female_count)
 ) STORED,
-  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+  "config_version" int8
 );
 
 -- Convert people_counts to TimescaleDB hypertable (if not already)
@@ -143,12 +197,325 @@ CREATE TABLE IF NOT EXISTS "public"."vehicle_counts" (
   "total_vehicle_in_count" int4 GENERATED ALWAYS AS (
 (in_count_car + in_count_truck)
 ) STORED,
-  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+  "config_version" int8
 );
 
 -- Convert vehicle_counts to TimescaleDB hypertable (if not already)
 SELECT create_hypertable('vehicle_counts', 'timestamp', if_not_exists => TRUE);
 
+-- ----------------------------
+-- Table structure for ingestion_ledger
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."ingestion_ledger" (
+  "id" bigserial PRIMARY KEY,
+  "file_path" text COLLATE "pg_catalog"."default" NOT NULL,
+  "content_hash" varchar(64) COLLATE "pg_catalog"."default" NOT NULL,
+  "handler" varchar(100) COLLATE "pg_catalog"."default",
+  "status" varchar(20) COLLATE "pg_catalog"."default" NOT NULL,
+  "attempts" int8 DEFAULT 0,
+  "error" text COLLATE "pg_catalog"."default",
+  "started_at" timestamptz(6),
+  "finished_at" timestamptz(6),
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ingestion_ledger_path_hash ON ingestion_ledger(file_path, content_hash);
+CREATE INDEX IF NOT EXISTS idx_ingestion_ledger_status ON ingestion_ledger(status);
+
+-- ----------------------------
+-- Table structure for camera_devices
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."camera_devices" (
+  "id" bigserial PRIMARY KEY,
+  "serial" varchar(100) COLLATE "pg_catalog"."default",
+  "channel" int8 DEFAULT 0,
+  "legacy_id" int8,
+  "camera_id" int8,
+  "status" varchar(20) COLLATE "pg_catalog"."default" NOT NULL DEFAULT 'mapped'::character varying,
+  "name" varchar(100) COLLATE "pg_catalog"."default",
+  "seen_count" int8 DEFAULT 0,
+  "first_seen_at" timestamptz(6),
+  "last_seen_at" timestamptz(6),
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_camera_devices_serial_channel ON camera_devices(serial, channel) WHERE serial <> '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_camera_devices_legacy_id ON camera_devices(legacy_id);
+CREATE INDEX IF NOT EXISTS idx_camera_devices_camera_id ON camera_devices(camera_id);
+CREATE INDEX IF NOT EXISTS idx_camera_devices_status ON camera_devices(status);
+
+-- ----------------------------
+-- Table structure for alert_events
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."alert_events" (
+  "id" bigserial PRIMARY KEY,
+  "alert_id" uuid NOT NULL,
+  "action" varchar(30) COLLATE "pg_catalog"."default" NOT NULL,
+  "from_status" varchar(20) COLLATE "pg_catalog"."default",
+  "to_status" varchar(20) COLLATE "pg_catalog"."default",
+  "from_severity" varchar(20) COLLATE "pg_catalog"."default",
+  "to_severity" varchar(20) COLLATE "pg_catalog"."default",
+  "assigned_to" varchar(100) COLLATE "pg_catalog"."default",
+  "actor" varchar(100) COLLATE "pg_catalog"."default",
+  "note" text COLLATE "pg_catalog"."default",
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_events_alert_id ON alert_events(alert_id);
+CREATE INDEX IF NOT EXISTS idx_alert_events_created_at ON alert_events(created_at);
+
+-- ----------------------------
+-- Table structure for alert_occurrences
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."alert_occurrences" (
+  "id" bigserial PRIMARY KEY,
+  "alert_id" uuid NOT NULL,
+  "source_id" varchar(100) COLLATE "pg_catalog"."default",
+  "image_url" varchar(255) COLLATE "pg_catalog"."default",
+  "severity" varchar(20) COLLATE "pg_catalog"."default",
+  "detected_at" timestamptz(6) NOT NULL,
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+  "config_version" int8
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_occurrences_alert_id ON alert_occurrences(alert_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alert_occurrences_source_id ON alert_occurrences(source_id) WHERE source_id <> '';
+
+-- ----------------------------
+-- Table structure for alert_rules
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."alert_rules" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar(100) COLLATE "pg_catalog"."default" NOT NULL,
+  "description" text COLLATE "pg_catalog"."default",
+  "enabled" bool NOT NULL DEFAULT true,
+  "camera_id" int8,
+  "zone_id" int8,
+  "zone" varchar(100) COLLATE "pg_catalog"."default",
+  "metric" varchar(30) COLLATE "pg_catalog"."default" NOT NULL,
+  "condition" varchar(20) COLLATE "pg_catalog"."default" NOT NULL,
+  "threshold" float8 NOT NULL DEFAULT 0,
+  "window_minutes" int8 NOT NULL DEFAULT 60,
+  "schedule_days" varchar(50) COLLATE "pg_catalog"."default",
+  "schedule_start" varchar(5) COLLATE "pg_catalog"."default",
+  "schedule_end" varchar(5) COLLATE "pg_catalog"."default",
+  "outside_schedule" bool DEFAULT false,
+  "severity" varchar(20) COLLATE "pg_catalog"."default" NOT NULL DEFAULT 'medium'::character varying,
+  "cooldown_minutes" int8 NOT NULL DEFAULT 0,
+  "alert_type_id" int8,
+  "last_triggered_at" timestamptz(6),
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alert_rules_name ON alert_rules(name);
+CREATE INDEX IF NOT EXISTS idx_alert_rules_camera_id ON alert_rules(camera_id);
+CREATE INDEX IF NOT EXISTS idx_alert_rules_zone_id ON alert_rules(zone_id);
+
+-- ----------------------------
+-- Table structure for notification_channels
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."notification_channels" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar(100) COLLATE "pg_catalog"."default" NOT NULL,
+  "type" varchar(20) COLLATE "pg_catalog"."default" NOT NULL,
+  "enabled" bool NOT NULL DEFAULT true,
+  "url" varchar(500) COLLATE "pg_catalog"."default",
+  "method" varchar(10) COLLATE "pg_catalog"."default",
+  "headers" text COLLATE "pg_catalog"."default",
+  "secret" varchar(255) COLLATE "pg_catalog"."default",
+  "recipients" text COLLATE "pg_catalog"."default",
+  "subject_template" text COLLATE "pg_catalog"."default",
+  "body_template" text COLLATE "pg_catalog"."default",
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_channels_name ON notification_channels(name);
+
+-- ----------------------------
+-- Table structure for notification_routes
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."notification_routes" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar(100) COLLATE "pg_catalog"."default" NOT NULL,
+  "channel_id" int8 NOT NULL,
+  "alert_type_id" int8,
+  "camera_id" int8,
+  "min_severity" varchar(20) COLLATE "pg_catalog"."default",
+  "events" varchar(100) COLLATE "pg_catalog"."default",
+  "enabled" bool NOT NULL DEFAULT true,
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_routes_channel_id ON notification_routes(channel_id);
+
+-- ----------------------------
+-- Table structure for notification_deliveries
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."notification_deliveries" (
+  "id" bigserial PRIMARY KEY,
+  "channel_id" int8 NOT NULL,
+  "route_id" int8,
+  "alert_id" varchar(36) COLLATE "pg_catalog"."default",
+  "event" varchar(20) COLLATE "pg_catalog"."default" NOT NULL,
+  "note" text COLLATE "pg_catalog"."default",
+  "status" varchar(20) COLLATE "pg_catalog"."default" NOT NULL DEFAULT 'pending'::character varying,
+  "attempts" int8 NOT NULL DEFAULT 0,
+  "last_error" text COLLATE "pg_catalog"."default",
+  "response_status" int8,
+  "next_attempt_at" timestamptz(6),
+  "delivered_at" timestamptz(6),
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_channel_id ON notification_deliveries(channel_id);
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_alert_id ON notification_deliveries(alert_id);
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_status ON notification_deliveries(status);
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_next_attempt_at ON notification_deliveries(next_attempt_at);
+
+-- ----------------------------
+-- Table structure for escalation_policies
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."escalation_policies" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar(100) COLLATE "pg_catalog"."default" NOT NULL UNIQUE,
+  "description" text COLLATE "pg_catalog"."default",
+  "enabled" bool NOT NULL,
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ----------------------------
+-- Table structure for escalation_steps
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."escalation_steps" (
+  "id" bigserial PRIMARY KEY,
+  "policy_id" int8 NOT NULL,
+  "position" int8 NOT NULL,
+  "delay_minutes" int8 NOT NULL DEFAULT 0,
+  "target" varchar(100) COLLATE "pg_catalog"."default",
+  "channel_id" int8,
+  "severity" varchar(20) COLLATE "pg_catalog"."default"
+);
+
+CREATE INDEX IF NOT EXISTS idx_escalation_steps_policy_id ON escalation_steps(policy_id);
+
+-- ----------------------------
+-- Table structure for alert_escalations
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."alert_escalations" (
+  "alert_id" uuid PRIMARY KEY,
+  "policy_id" int8 NOT NULL,
+  "status" varchar(20) COLLATE "pg_catalog"."default" NOT NULL,
+  "level" int8 NOT NULL DEFAULT 0,
+  "started_at" timestamptz(6) NOT NULL,
+  "last_step_at" timestamptz(6),
+  "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_escalations_status ON alert_escalations(status);
+
+-- ----------------------------
+-- Table structure for maintenance_windows
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."maintenance_windows" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar(100) COLLATE "pg_catalog"."default" NOT NULL UNIQUE,
+  "description" text COLLATE "pg_catalog"."default",
+  "enabled" bool NOT NULL,
+  "camera_id" int8,
+  "zone" varchar(100) COLLATE "pg_catalog"."default",
+  "alert_type_id" int8,
+  "starts_at" timestamptz(6),
+  "ends_at" timestamptz(6),
+  "schedule_days" varchar(50) COLLATE "pg_catalog"."default",
+  "schedule_start" varchar(5) COLLATE "pg_catalog"."default",
+  "schedule_end" varchar(5) COLLATE "pg_catalog"."default",
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_windows_camera_id ON maintenance_windows(camera_id);
+
+-- ----------------------------
+-- Table structure for camera_status_events
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."camera_status_events" (
+  "id" bigserial,
+  "camera_id" int8 NOT NULL,
+  "from_status" varchar(20) COLLATE "pg_catalog"."default",
+  "to_status" varchar(20) COLLATE "pg_catalog"."default" NOT NULL,
+  "source" varchar(20) COLLATE "pg_catalog"."default" NOT NULL,
+  "reason" text COLLATE "pg_catalog"."default",
+  "changed_at" timestamptz(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id", "changed_at")
+);
+
+SELECT create_hypertable('camera_status_events', 'changed_at', if_not_exists => TRUE);
+
+CREATE INDEX IF NOT EXISTS idx_camera_status_events_camera_id ON camera_status_events(camera_id, changed_at DESC);
+
+-- ----------------------------
+-- Table structure for zones
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."zones" (
+  "id" bigserial PRIMARY KEY,
+  "parent_id" int8,
+  "kind" varchar(20) COLLATE "pg_catalog"."default" NOT NULL,
+  "name" varchar(100) COLLATE "pg_catalog"."default" NOT NULL,
+  "description" text COLLATE "pg_catalog"."default",
+  "capacity" int8 DEFAULT 0,
+  "floor_plan" varchar(255) COLLATE "pg_catalog"."default",
+  "floor_plan_width" int8 DEFAULT 0,
+  "floor_plan_height" int8 DEFAULT 0,
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_zones_parent_id ON zones(parent_id);
+
+-- ----------------------------
+-- Table structure for zone_cameras
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."zone_cameras" (
+  "id" bigserial PRIMARY KEY,
+  "zone_id" int8 NOT NULL,
+  "camera_id" int8 NOT NULL,
+  "direction" varchar(20) COLLATE "pg_catalog"."default" NOT NULL DEFAULT 'internal',
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+  "plan_x" float8,
+  "plan_y" float8
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_zone_cameras_zone_camera ON zone_cameras(zone_id, camera_id);
+CREATE INDEX IF NOT EXISTS idx_zone_cameras_camera_id ON zone_cameras(camera_id);
+
+-- ----------------------------
+-- Table structure for camera_configs
+-- ----------------------------
+CREATE TABLE IF NOT EXISTS "public"."camera_configs" (
+  "id" bigserial PRIMARY KEY,
+  "camera_id" int8 NOT NULL,
+  "version" int8 NOT NULL,
+  "polygons" text COLLATE "pg_catalog"."default",
+  "tripwires" text COLLATE "pg_catalog"."default",
+  "direction" varchar(20) COLLATE "pg_catalog"."default" NOT NULL DEFAULT 'left_to_right',
+  "classes" text COLLATE "pg_catalog"."default",
+  "loitering_seconds" int8 DEFAULT 0,
+  "note" text COLLATE "pg_catalog"."default",
+  "created_by" varchar(100) COLLATE "pg_catalog"."default",
+  "created_at" timestamptz(6) DEFAULT CURRENT_TIMESTAMP,
+  "archived_at" timestamptz(6)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_camera_configs_camera_version ON camera_configs(camera_id, version);
+
 -- ----------------------------
 -- TimescaleDB Compression Policies
 -- ----------------------------
@@ -251,6 +618,18 @@ BEGIN
     CREATE INDEX idx_alerts_is_active ON alerts(is_active);
   END IF;
 
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_alerts_status') THEN
+    CREATE INDEX idx_alerts_status ON alerts(status);
+  END IF;
+
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_alerts_correlation') THEN
+    CREATE INDEX idx_alerts_correlation ON alerts(camera_id, alert_type_id, last_seen_at) WHERE is_active;
+  END IF;
+
+  IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_alerts_suppressed') THEN
+    CREATE INDEX idx_alerts_suppressed ON alerts(suppressed);
+  END IF;
+
   -- Create indexes for face_recognitions
   IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_face_recognitions_camera_id') THEN
     CREATE INDEX idx_face_recognitions_camera_id ON face_recognitions(camera_id);
@@ -270,18 +649,21 @@ DO $$
 BEGIN
   -- Create people_counts_hourly if not exists
   IF NOT EXISTS (SELECT 1 FROM pg_catalog.pg_class c JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace WHERE c.relname = 'people_counts_hourly' AND n.nspname = 'public') THEN
-    -- Hourly people count aggregates
+    -- Hourly people count aggregates, including the current hour
     CREATE MATERIALIZED VIEW people_counts_hourly
-    WITH (timescaledb.continuous) AS
+    WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
     SELECT
-      time_bucket('1 hour', timestamp) AS bucket,
+      time_bucket(INTERVAL '1 hour', timestamp, 'Asia/Jakarta') AS bucket,
       camera_id,
-      SUM(in_count) AS total_in_count,
-      SUM(out_count) AS total_out_count,
-      AVG(in_count) AS avg_in_count,
-      AVG(out_count) AS avg_out_count,
-      MAX(in_count) AS max_in_count,
-      MAX(out_count) AS max_out_count
+      SUM(in_count) AS in_count,
+      SUM(out_count) AS out_count,
+      SUM(male_count) AS male_count,
+      SUM(female_count) AS female_count,
+      SUM(child_count) AS child_count,
+      SUM(adult_count) AS adult_count,
+      SUM(elderly_count) AS elderly_count,
+      SUM(male_count + female_count) AS total_count,
+      COUNT(*) AS records
     FROM people_counts
     GROUP BY bucket, camera_id;
 
@@ -297,6 +679,38 @@ BEGIN
     END;
   END IF;
 
+  -- Create people_counts_daily if not exists
+  IF NOT EXISTS (SELECT 1 FROM pg_catalog.pg_class c JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace WHERE c.relname = 'people_counts_daily' AND n.nspname = 'public') THEN
+    -- Daily people count aggregates, days start at local midnight
+    CREATE MATERIALIZED VIEW people_counts_daily
+    WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+    SELECT
+      time_bucket(INTERVAL '1 day', timestamp, 'Asia/Jakarta') AS bucket,
+      camera_id,
+      SUM(in_count) AS in_count,
+      SUM(out_count) AS out_count,
+      SUM(male_count) AS male_count,
+      SUM(female_count) AS female_count,
+      SUM(child_count) AS child_count,
+      SUM(adult_count) AS adult_count,
+      SUM(elderly_count) AS elderly_count,
+      SUM(male_count + female_count) AS total_count,
+      COUNT(*) AS records
+    FROM people_counts
+    GROUP BY bucket, camera_id;
+
+    -- Add refresh policy
+    BEGIN
+      PERFORM add_continuous_aggregate_policy('people_counts_daily',
+        start_offset => INTERVAL '30 days',
+        end_offset => INTERVAL '1 day',
+        schedule_interval => INTERVAL '1 day',
+        if_not_exists => TRUE);
+    EXCEPTION WHEN OTHERS THEN
+      RAISE NOTICE 'Continuous aggregate policy for people_counts_daily already exists or could not be created';
+    END;
+  END IF;
+
   -- Create vehicle_counts_hourly if not exists
   IF NOT EXISTS (SELECT 1 FROM pg_catalog.pg_class c JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace WHERE c.relname = 'vehicle_counts_hourly' AND n.nspname = 'public') THEN
     -- Hourly vehicle count aggregates
@@ -326,15 +740,16 @@ BEGIN
 
   -- Create alerts_daily if not exists
   IF NOT EXISTS (SELECT 1 FROM pg_catalog.pg_class c JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace WHERE c.relname = 'alerts_daily' AND n.nspname = 'public') THEN
-    -- Daily alert aggregates
+    -- Daily alert aggregates, including the current day
     CREATE MATERIALIZED VIEW alerts_daily
-    WITH (timescaledb.continuous) AS
+    WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
     SELECT
-      time_bucket('1 day', detected_at) AS bucket,
+      time_bucket(INTERVAL '1 day', detected_at, 'Asia/Jakarta') AS bucket,
       camera_id,
       alert_type_id,
       severity,
-      COUNT(*) AS alert_count
+      COUNT(*) AS alert_count,
+      SUM(CASE WHEN suppressed THEN 1 ELSE 0 END) AS suppressed_count
     FROM alerts
     GROUP BY bucket, camera_id, alert_type_id, severity;
 